@@ -3,21 +3,30 @@ package wscreate
 import (
 	"context"
 	"fmt"
+
+	"github.com/tasuku43/kra/internal/core/ticketnotes"
 )
 
 type JiraIssue struct {
-	Key     string
-	Summary string
+	Key        string
+	Summary    string
+	IssueType  string
+	Labels     []string
+	ProjectKey string
 }
 
 type JiraIssuePort interface {
 	FetchIssueByTicketURL(ctx context.Context, ticketURL string) (JiraIssue, error)
+	FetchTicket(ctx context.Context, issueKey string) (ticketnotes.Ticket, error)
 }
 
 type JiraWorkspaceInput struct {
-	ID        string
-	Title     string
-	SourceURL string
+	ID         string
+	Title      string
+	SourceURL  string
+	IssueType  string
+	Labels     []string
+	ProjectKey string
 }
 
 type Service struct {
@@ -40,8 +49,23 @@ func (s *Service) ResolveJiraWorkspaceInput(ctx context.Context, ticketURL strin
 		return JiraWorkspaceInput{}, fmt.Errorf("jira issue key is empty")
 	}
 	return JiraWorkspaceInput{
-		ID:        issue.Key,
-		Title:     issue.Summary,
-		SourceURL: ticketURL,
+		ID:         issue.Key,
+		Title:      issue.Summary,
+		SourceURL:  ticketURL,
+		IssueType:  issue.IssueType,
+		Labels:     issue.Labels,
+		ProjectKey: issue.ProjectKey,
 	}, nil
 }
+
+// ResolveTicketNotes fetches the issue context and renders it as the workspace notes Markdown.
+func (s *Service) ResolveTicketNotes(ctx context.Context, issueKey string) (string, error) {
+	if s.jiraPort == nil {
+		return "", fmt.Errorf("jira issue port is not configured")
+	}
+	ticket, err := s.jiraPort.FetchTicket(ctx, issueKey)
+	if err != nil {
+		return "", err
+	}
+	return ticketnotes.Render(ticket), nil
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/tasuku43/kra/internal/core/ticketnotes"
 )
 
 type JiraIssue struct {
-	Key       string
-	Summary   string
-	TicketURL string
+	Key        string
+	Summary    string
+	TicketURL  string
+	IssueType  string
+	Labels     []string
+	ProjectKey string
 }
 
 type JiraBoard struct {
@@ -33,12 +38,28 @@ type JiraIssueListPort interface {
 	GetSprint(ctx context.Context, sprintID int) (JiraSprint, error)
 	ListBoardProjectKeys(ctx context.Context, boardID int) ([]string, error)
 	ListProjectOpenSprints(ctx context.Context, projectKey string, maxResults int) ([]JiraSprint, error)
+	FetchTicket(ctx context.Context, issueKey string) (ticketnotes.Ticket, error)
 }
 
 type WorkspaceInput struct {
-	ID        string
-	Title     string
-	SourceURL string
+	ID         string
+	Title      string
+	SourceURL  string
+	ParentID   string
+	IssueType  string
+	Labels     []string
+	ProjectKey string
+}
+
+func newWorkspaceInput(issue JiraIssue) WorkspaceInput {
+	return WorkspaceInput{
+		ID:         strings.TrimSpace(issue.Key),
+		Title:      strings.TrimSpace(issue.Summary),
+		SourceURL:  strings.TrimSpace(issue.TicketURL),
+		IssueType:  strings.TrimSpace(issue.IssueType),
+		Labels:     issue.Labels,
+		ProjectKey: strings.TrimSpace(issue.ProjectKey),
+	}
 }
 
 type Service struct {
@@ -63,15 +84,45 @@ func (s *Service) ResolveWorkspaceInputsByJQL(ctx context.Context, jql string, m
 		if key == "" {
 			continue
 		}
-		inputs = append(inputs, WorkspaceInput{
-			ID:        key,
-			Title:     strings.TrimSpace(issue.Summary),
-			SourceURL: strings.TrimSpace(issue.TicketURL),
-		})
+		inputs = append(inputs, newWorkspaceInput(issue))
 	}
 	return inputs, nil
 }
 
+// ResolveParentAndChildrenInputs resolves the parent issue itself and its child issues.
+// Children are resolved by childJQL and tagged with the parent issue key.
+func (s *Service) ResolveParentAndChildrenInputs(ctx context.Context, parentKey string, childJQL string, maxResults int) (WorkspaceInput, []WorkspaceInput, error) {
+	if s.jiraPort == nil {
+		return WorkspaceInput{}, nil, fmt.Errorf("jira issue list port is not configured")
+	}
+	parentKey = strings.ToUpper(strings.TrimSpace(parentKey))
+	if parentKey == "" {
+		return WorkspaceInput{}, nil, fmt.Errorf("parent issue key is required")
+	}
+	parents, err := s.jiraPort.SearchIssuesByJQL(ctx, fmt.Sprintf("key = %s", parentKey), 1)
+	if err != nil {
+		return WorkspaceInput{}, nil, err
+	}
+	if len(parents) == 0 || strings.TrimSpace(parents[0].Key) == "" {
+		return WorkspaceInput{}, nil, fmt.Errorf("jira issue not found: %s", parentKey)
+	}
+	parent := newWorkspaceInput(parents[0])
+
+	children, err := s.ResolveWorkspaceInputsByJQL(ctx, childJQL, maxResults)
+	if err != nil {
+		return WorkspaceInput{}, nil, err
+	}
+	out := make([]WorkspaceInput, 0, len(children))
+	for _, child := range children {
+		if strings.EqualFold(child.ID, parent.ID) {
+			continue
+		}
+		child.ParentID = parent.ID
+		out = append(out, child)
+	}
+	return parent, out, nil
+}
+
 func (s *Service) ListScrumBoards(ctx context.Context) ([]JiraBoard, error) {
 	if s.jiraPort == nil {
 		return nil, fmt.Errorf("jira issue list port is not configured")
@@ -113,3 +164,15 @@ func (s *Service) ListProjectOpenSprints(ctx context.Context, projectKey string,
 	}
 	return s.jiraPort.ListProjectOpenSprints(ctx, projectKey, maxResults)
 }
+
+// ResolveTicketNotes fetches the issue context and renders it as the workspace notes Markdown.
+func (s *Service) ResolveTicketNotes(ctx context.Context, issueKey string) (string, error) {
+	if s.jiraPort == nil {
+		return "", fmt.Errorf("jira issue list port is not configured")
+	}
+	ticket, err := s.jiraPort.FetchTicket(ctx, issueKey)
+	if err != nil {
+		return "", err
+	}
+	return ticketnotes.Render(ticket), nil
+}
@@ -55,24 +55,39 @@ func TestCLIDirectInfraImportsAreAllowlisted(t *testing.T) {
 		"config_bootstrap.go":    {},
 		"context.go":             {},
 		"doctor.go":              {},
+		"doctor_pool.go":         {},
 		"git_allowlist.go":       {},
 		"git_status_snapshot.go": {},
 		"init.go":                {},
+		"jira.go":                {},
 		"repo_add.go":            {},
+		"repo_apply.go":          {},
 		"repo_discover.go":       {},
+		"repo_export.go":         {},
 		"repo_gc.go":             {},
+		"repo_fetch.go":          {},
+		"repo_info.go":           {},
+		"repo_list.go":           {},
 		"repo_pool_add.go":       {},
 		"repo_remove.go":         {},
+		"repo_usage.go":          {},
 		"root.go":                {},
+		"root_sync.go":           {},
 		"state_registry.go":      {},
 		"template_create.go":     {},
 		"template_remove.go":     {},
 		"template_validate.go":   {},
 		"workspace_workstate.go": {},
+		"worktree_content.go":    {},
+		"worktree_sparse.go":     {},
 		"ws_add_repo.go":         {},
+		"ws_branch.go":           {},
+		"ws_branches.go":         {},
 		"ws_close.go":            {},
+		"ws_commit_hooks.go":     {},
 		"ws_create.go":           {},
 		"ws_dashboard.go":        {},
+		"ws_diff.go":             {},
 		"ws_git_helpers.go":      {},
 		"ws_import_jira.go":      {},
 		"ws_insight.go":          {},
@@ -81,9 +96,13 @@ func TestCLIDirectInfraImportsAreAllowlisted(t *testing.T) {
 		"ws_lock.go":             {},
 		"ws_open.go":             {},
 		"ws_open_runtime.go":     {},
+		"ws_pr.go":               {},
 		"ws_purge.go":            {},
+		"ws_push.go":             {},
 		"ws_remove_repo.go":      {},
 		"ws_reopen.go":           {},
+		"ws_stack.go":            {},
+		"ws_status.go":           {},
 	}
 
 	seen := map[string]struct{}{}
@@ -4,8 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"strings"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
 )
 
 const (
@@ -44,6 +47,7 @@ func (c *CLI) Run(args []string) int {
 	var versionFlag bool
 	args, versionFlag = c.consumeGlobalFlags(args)
 	defer c.closeDebugLog()
+	repospec.SetNestedNamespaceHosts(nestedNamespaceHostsFromEnv())
 
 	if versionFlag {
 		fmt.Fprintln(c.Out, c.versionLine())
@@ -84,6 +88,8 @@ func (c *CLI) Run(args []string) int {
 		return c.runWS(args[1:])
 	case "doctor":
 		return c.runDoctor(args[1:])
+	case "jira":
+		return c.runJira(args[1:])
 	default:
 		fmt.Fprintf(c.Err, "unknown command: %q\n", args[0])
 		c.printRootUsage(c.Err)
@@ -134,6 +140,20 @@ func (c *CLI) runWS(args []string) int {
 		return c.runWSList(args[1:])
 	case "dashboard":
 		return c.runWSDashboard(args[1:])
+	case "branches":
+		return c.runWSBranches(args[1:])
+	case "status":
+		return c.runWSStatus(args[1:])
+	case "push":
+		return c.runWSPush(args[1:])
+	case "pr":
+		return c.runWSPR(args[1:])
+	case "diff":
+		return c.runWSDiff(args[1:])
+	case "branch":
+		return c.runWSBranch(args[1:])
+	case "stack":
+		return c.runWSStack(args[1:])
 	case "insight":
 		if !c.isExperimentEnabled(experimentInsightCapture) {
 			fmt.Fprintf(c.Err, "ws insight is experimental (set %s=%s)\n", experimentsEnvKey, experimentInsightCapture)
@@ -190,3 +210,17 @@ func (c *CLI) consumeGlobalFlags(args []string) ([]string, bool) {
 	}
 	return filtered, versionFlag
 }
+
+// nestedNamespaceHostsFromEnv returns the self-hosted GitLab host from KRA_GITLAB_BASE_URL, whose repos
+// may live under nested groups even when the host name has no "gitlab" label.
+func nestedNamespaceHostsFromEnv() []string {
+	raw := strings.TrimSpace(os.Getenv("KRA_GITLAB_BASE_URL"))
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+	return []string{u.Hostname()}
+}
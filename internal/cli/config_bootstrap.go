@@ -45,6 +45,16 @@ func defaultGlobalConfigContent() string {
 workspace:
   # defaults:
   #   template: default
+  #   template_rules: # first match wins (issue_type / label / project)
+  #     - template: bug
+  #       issue_type: Bug
+  # commit: # installed into workspace worktrees by ws add-repo / ws reopen
+  #   prefix: "[{{workspace_id}}]"
+  #   trailer: "Refs: {{source_url}}"
+  # lifecycle_commit: # KRA_ROOT commits of ws create/close/reopen/purge
+  #   message: "{{action}}: {{id}} {{title}}"
+  #   trailers: ["Kra-Action: {{action}}"]
+  #   sign: ssh # gpg | ssh
 
 integration:
   jira:
@@ -52,6 +62,12 @@ integration:
     # defaults:
     #   space: DEMO
     #   type: sprint # sprint | jql
+    # cache:
+    #   issues_ttl: 15m
+    #   boards_ttl: 24h
+    #   sprints_ttl: 1h
+    # notes:
+    #   path: notes/ticket.md
 `
 }
 
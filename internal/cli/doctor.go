@@ -61,6 +61,7 @@ func (c *CLI) runDoctor(args []string) int {
 	withFix := false
 	fixPlan := false
 	fixApply := false
+	withFsck := false
 	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
 		switch args[0] {
 		case "-h", "--help", "help":
@@ -75,6 +76,9 @@ func (c *CLI) runDoctor(args []string) int {
 		case "--apply":
 			fixApply = true
 			args = args[1:]
+		case "--fsck":
+			withFsck = true
+			args = args[1:]
 		case "--format":
 			if len(args) < 2 {
 				fmt.Fprintln(c.Err, "--format requires a value")
@@ -176,9 +180,9 @@ func (c *CLI) runDoctor(args []string) int {
 	if err := c.ensureDebugLog(root, "doctor"); err != nil {
 		fmt.Fprintf(c.Err, "enable debug logging: %v\n", err)
 	}
-	c.debugf("run doctor format=%s", outputFormat)
+	c.debugf("run doctor format=%s fsck=%t", outputFormat, withFsck)
 
-	report := runDoctorChecks(root)
+	report := runDoctorChecks(root, withFsck)
 	if withFix {
 		mode := "plan"
 		if fixApply {
@@ -333,6 +337,17 @@ func runDoctorFix(root string, mode string, report doctorReport) doctorFixResult
 			}
 			result.Actions[i].Status = "applied"
 			result.Summary.Applied++
+		case "prune_pool_worktrees":
+			applyDoctorFixStep(&result, i, "", applyDoctorPoolPruneWorktrees(result.Actions[i].Target))
+		case "set_pool_repo_remote":
+			skipReason, err := applyDoctorPoolSetRemote(root, result.Actions[i].Target)
+			applyDoctorFixStep(&result, i, skipReason, err)
+		case "reclone_pool_repo":
+			skipReason, err := applyDoctorPoolReclone(root, result.Actions[i].Target)
+			applyDoctorFixStep(&result, i, skipReason, err)
+		case "remove_pool_swap_leftover":
+			skipReason, err := applyDoctorPoolRemoveSwapLeftover(result.Actions[i].Target)
+			applyDoctorFixStep(&result, i, skipReason, err)
 		default:
 			result.Actions[i].Status = "skipped"
 			result.Actions[i].Reason = "manual_required"
@@ -342,6 +357,22 @@ func runDoctorFix(root string, mode string, report doctorReport) doctorFixResult
 	return result
 }
 
+func applyDoctorFixStep(result *doctorFixResult, i int, skipReason string, err error) {
+	switch {
+	case err != nil:
+		result.Actions[i].Status = "failed"
+		result.Actions[i].Reason = err.Error()
+		result.Summary.Failed++
+	case skipReason != "":
+		result.Actions[i].Status = "skipped"
+		result.Actions[i].Reason = "manual_required: " + skipReason
+		result.Summary.Skipped++
+	default:
+		result.Actions[i].Status = "applied"
+		result.Summary.Applied++
+	}
+}
+
 func planDoctorFixActions(report doctorReport) []doctorFixAction {
 	actions := make([]doctorFixAction, 0, len(report.Findings))
 	seen := map[string]bool{}
@@ -353,6 +384,14 @@ func planDoctorFixActions(report doctorReport) []doctorFixAction {
 			kind = "remove_stale_lock"
 		case "root_not_registered":
 			kind = "register_root"
+		case "pool_repo_stale_worktree":
+			kind = "prune_pool_worktrees"
+		case "pool_repo_origin_missing", "pool_repo_remote_mismatch":
+			kind = "set_pool_repo_remote"
+		case "pool_repo_partial_clone", "pool_repo_fsck_failed":
+			kind = "reclone_pool_repo"
+		case "pool_repo_swap_leftover":
+			kind = "remove_pool_swap_leftover"
 		default:
 			continue
 		}
@@ -380,7 +419,8 @@ func touchRootRegistry(root string) error {
 	return stateregistry.Touch(cleanRoot, time.Now())
 }
 
-func runDoctorChecks(root string) doctorReport {
+// runDoctorChecks runs every check; withFsck adds the (slow) object connectivity check over the repo pool.
+func runDoctorChecks(root string, withFsck bool) doctorReport {
 	report := doctorReport{
 		Root:     root,
 		Findings: make([]doctorFinding, 0),
@@ -427,6 +467,7 @@ func runDoctorChecks(root string) doctorReport {
 	scanDoctorWorkspaceScope(root, "archive", "archived", false, addOK, addWarn, addError)
 	scanDoctorLocks(root, addOK, addWarn)
 	scanDoctorRegistry(root, addOK, addWarn)
+	scanDoctorRepoPool(root, withFsck, addOK, addWarn, addError)
 
 	slices.SortFunc(report.Findings, func(a, b doctorFinding) int {
 		if a.Severity != b.Severity {
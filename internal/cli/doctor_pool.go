@@ -0,0 +1,381 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/infra/paths"
+	"github.com/tasuku43/kra/internal/repomanifest"
+)
+
+const (
+	// Temp/backup dirs created next to a bare repo while doctor --fix re-clones it.
+	doctorPoolRecloneSuffix = ".kra-reclone-"
+	doctorPoolOldSuffix     = ".kra-old-"
+)
+
+// scanDoctorRepoPool checks every bare repo in the shared pool:
+// interrupted clones, origin remote, remote/path identity, stale worktree admin entries and,
+// with withFsck, object connectivity (it reads every object, so it is opt-in).
+func scanDoctorRepoPool(
+	root string,
+	withFsck bool,
+	addOK func(),
+	addWarn func(code string, target string, message string),
+	addError func(code string, target string, message string),
+) {
+	repoPoolPath, err := paths.DefaultRepoPoolPath()
+	if err != nil {
+		addWarn("repo_pool_path_resolve_failed", "KRA_HOME", err.Error())
+		return
+	}
+	bareDirs, leftovers, err := listDoctorPoolEntries(repoPoolPath)
+	if err != nil {
+		addWarn("repo_pool_read_failed", repoPoolPath, err.Error())
+		return
+	}
+	addOK()
+
+	for _, p := range leftovers {
+		addWarn("pool_repo_swap_leftover", p, "leftover directory from an interrupted re-clone")
+	}
+
+	ctx := context.Background()
+	for _, barePath := range bareDirs {
+		if reason := doctorPoolPartialCloneReason(ctx, barePath); reason != "" {
+			addError("pool_repo_partial_clone", barePath, reason)
+			continue
+		}
+		addOK()
+
+		remoteURL, _ := gitutil.RunBare(ctx, barePath, "config", "--get", "remote.origin.url")
+		remoteURL = strings.TrimSpace(remoteURL)
+		if remoteURL == "" {
+			addError("pool_repo_origin_missing", barePath, "origin remote is not configured")
+		} else {
+			pathUID, _, pathOK := resolveRepoIdentityForGC(repoPoolPath, barePath, "")
+			spec, normErr := repospec.Normalize(remoteURL)
+			switch {
+			case normErr != nil:
+				addWarn("pool_repo_remote_mismatch", barePath, fmt.Sprintf("origin=%q cannot be normalized: %v", remoteURL, normErr))
+			case pathOK && spec.RepoKey != pathUID:
+				addWarn("pool_repo_remote_mismatch", barePath, fmt.Sprintf("origin=%q resolves to %s, pool path expects %s", remoteURL, spec.RepoKey, pathUID))
+			default:
+				addOK()
+			}
+		}
+
+		stale := listDoctorPoolStaleWorktrees(barePath)
+		for _, name := range stale {
+			addWarn("pool_repo_stale_worktree", barePath, fmt.Sprintf("worktrees/%s points to a deleted worktree", name))
+		}
+		if len(stale) == 0 {
+			addOK()
+		}
+
+		if !withFsck {
+			continue
+		}
+		if _, err := gitutil.RunBare(ctx, barePath, "fsck", "--connectivity-only", "--no-dangling", "--no-progress"); err != nil {
+			addError("pool_repo_fsck_failed", barePath, firstDoctorLine(err.Error()))
+			continue
+		}
+		addOK()
+	}
+}
+
+// listDoctorPoolEntries returns <name>.git dirs (including ones without HEAD) and re-clone leftovers.
+func listDoctorPoolEntries(repoPoolPath string) ([]string, []string, error) {
+	bareDirs := make([]string, 0, 32)
+	leftovers := make([]string, 0)
+	err := filepath.WalkDir(repoPoolPath, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if strings.Contains(name, ".git"+doctorPoolRecloneSuffix) || strings.Contains(name, ".git"+doctorPoolOldSuffix) {
+			leftovers = append(leftovers, path)
+			return filepath.SkipDir
+		}
+		if path != repoPoolPath && strings.HasSuffix(name, ".git") {
+			bareDirs = append(bareDirs, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	return bareDirs, leftovers, nil
+}
+
+// doctorPoolPartialCloneReason reports why barePath looks like an interrupted clone ("" when complete).
+func doctorPoolPartialCloneReason(ctx context.Context, barePath string) string {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(barePath, name)); err != nil {
+			return fmt.Sprintf("bare repo is incomplete (missing %s); likely an interrupted clone", name)
+		}
+	}
+	refs, err := gitutil.RunBare(ctx, barePath, "for-each-ref", "--count=1", "--format=%(refname)")
+	if err != nil {
+		return fmt.Sprintf("bare repo is unreadable: %s", firstDoctorLine(err.Error()))
+	}
+	if strings.TrimSpace(refs) == "" && doctorPoolHasObjects(ctx, barePath) {
+		// A clone of an empty remote has neither refs nor objects; objects without refs mean the clone stopped early.
+		return "bare repo has objects but no refs; likely an interrupted clone"
+	}
+	return ""
+}
+
+// doctorPoolHasObjects reports whether barePath stores any loose or packed object.
+func doctorPoolHasObjects(ctx context.Context, barePath string) bool {
+	out, err := gitutil.RunBare(ctx, barePath, "count-objects", "-v")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "count", "in-pack":
+			if v := strings.TrimSpace(value); v != "" && v != "0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// listDoctorPoolStaleWorktrees returns worktrees/<name> admin entries whose gitdir target no longer exists.
+func listDoctorPoolStaleWorktrees(barePath string) []string {
+	entries, err := os.ReadDir(filepath.Join(barePath, "worktrees"))
+	if err != nil {
+		return nil
+	}
+	stale := make([]string, 0)
+	for _, ent := range entries {
+		if !ent.IsDir() {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(barePath, "worktrees", ent.Name(), "gitdir"))
+		if err != nil {
+			stale = append(stale, ent.Name())
+			continue
+		}
+		if _, err := os.Stat(strings.TrimSpace(string(raw))); err != nil {
+			stale = append(stale, ent.Name())
+		}
+	}
+	return stale
+}
+
+func firstDoctorLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+// doctorPoolKnownRemoteURLs maps repo_uid to a clone URL from the repo manifest, then workspace metadata.
+func doctorPoolKnownRemoteURLs(root string) map[string]string {
+	out := map[string]string{}
+	if manifest, ok, err := repomanifest.LoadOptional(repomanifest.Path(root)); err == nil && ok {
+		if resolved, err := manifest.Resolve(); err == nil {
+			for _, r := range resolved {
+				out[r.RepoUID] = r.Spec
+			}
+		}
+	}
+	for _, scope := range []string{"workspaces", "archive"} {
+		entries, err := os.ReadDir(filepath.Join(root, scope))
+		if err != nil {
+			continue
+		}
+		for _, ent := range entries {
+			if !ent.IsDir() {
+				continue
+			}
+			meta, err := loadWorkspaceMetaFile(filepath.Join(root, scope, ent.Name()))
+			if err != nil {
+				continue
+			}
+			for _, r := range meta.ReposRestore {
+				uid := strings.TrimSpace(r.RepoUID)
+				if uid != "" && out[uid] == "" && strings.TrimSpace(r.RemoteURL) != "" {
+					out[uid] = strings.TrimSpace(r.RemoteURL)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// doctorPoolRemoteURLFor returns the URL to restore for barePath: a known URL for the pool path identity,
+// falling back to the current origin when it matches that identity.
+func doctorPoolRemoteURLFor(ctx context.Context, root string, barePath string) string {
+	repoPoolPath, err := paths.DefaultRepoPoolPath()
+	if err != nil {
+		return ""
+	}
+	pathUID, _, ok := resolveRepoIdentityForGC(repoPoolPath, barePath, "")
+	if !ok {
+		return ""
+	}
+	if url := doctorPoolKnownRemoteURLs(root)[pathUID]; url != "" {
+		return url
+	}
+	current, _ := gitutil.RunBare(ctx, barePath, "config", "--get", "remote.origin.url")
+	current = strings.TrimSpace(current)
+	if spec, err := repospec.Normalize(current); err == nil && spec.RepoKey == pathUID {
+		return current
+	}
+	return ""
+}
+
+func applyDoctorPoolPruneWorktrees(barePath string) error {
+	_, err := gitutil.RunBare(context.Background(), barePath, "worktree", "prune")
+	return err
+}
+
+func applyDoctorPoolSetRemote(root string, barePath string) (string, error) {
+	ctx := context.Background()
+	url := doctorPoolRemoteURLFor(ctx, root, barePath)
+	if url == "" {
+		return "no_known_remote_url (declare the repo in .kra/repos.yaml)", nil
+	}
+	current, _ := gitutil.RunBare(ctx, barePath, "config", "--get", "remote.origin.url")
+	if strings.TrimSpace(current) == "" {
+		if _, err := gitutil.RunBare(ctx, barePath, "remote", "add", "origin", url); err != nil {
+			return "", err
+		}
+	} else if _, err := gitutil.RunBare(ctx, barePath, "remote", "set-url", "origin", url); err != nil {
+		return "", err
+	}
+	_, err := gitutil.RunBare(ctx, barePath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	return "", err
+}
+
+// applyDoctorPoolReclone clones a fresh bare repo next to barePath (keeping its partial clone filter) and swaps it in.
+// Local branches, worktrees/ admin entries and extensions.worktreeConfig are carried over so existing workspace
+// worktrees keep working (including their sparse checkout and commit hooks); when branches cannot be recovered
+// while worktrees still use the repo, the swap is aborted.
+func applyDoctorPoolReclone(root string, barePath string) (string, error) {
+	ctx := context.Background()
+	url := doctorPoolRemoteURLFor(ctx, root, barePath)
+	if url == "" {
+		return "no_known_remote_url (declare the repo in .kra/repos.yaml)", nil
+	}
+	release, err := acquireRepoPoolFetchLock(ctx, barePath)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	stamp := time.Now().UTC().Format("20060102T150405")
+	tmpPath := barePath + doctorPoolRecloneSuffix + stamp
+	oldPath := barePath + doctorPoolOldSuffix + stamp
+	cloneArgs := []string{"clone", "--bare"}
+	if filter := repoPoolCloneFilter(ctx, barePath); filter != "" {
+		cloneArgs = append(cloneArgs, "--filter="+filter)
+	}
+	if _, err := gitutil.Run(ctx, "", append(cloneArgs, url, tmpPath)...); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return "", fmt.Errorf("clone: %w", err)
+	}
+	cleanupTmp := func() { _ = os.RemoveAll(tmpPath) }
+	if _, err := gitutil.RunBare(ctx, tmpPath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		cleanupTmp()
+		return "", err
+	}
+	if _, err := gitutil.RunBare(ctx, tmpPath, "fetch", "origin", "--prune", "--no-tags"); err != nil {
+		cleanupTmp()
+		return "", fmt.Errorf("fetch: %w", err)
+	}
+	_, _ = gitutil.RunBare(ctx, tmpPath, "remote", "set-head", "origin", "-a")
+
+	_, _ = gitutil.RunBare(ctx, barePath, "worktree", "prune")
+	worktreesDir := filepath.Join(barePath, "worktrees")
+	_, statErr := os.Stat(worktreesDir)
+	hasWorktrees := statErr == nil
+	worktreeConfig, _ := gitutil.RunBare(ctx, barePath, "config", "--bool", "--get", "extensions.worktreeConfig")
+	if strings.TrimSpace(worktreeConfig) == "true" {
+		if err := enableWorktreeConfig(ctx, tmpPath); err != nil {
+			cleanupTmp()
+			return "", err
+		}
+	}
+	if _, err := gitutil.RunBare(ctx, tmpPath, "fetch", "--no-tags", barePath, "+refs/heads/*:refs/heads/*"); err != nil && hasWorktrees {
+		cleanupTmp()
+		return "", fmt.Errorf("recover local branches (worktrees still use this repo, re-clone manually): %s", firstDoctorLine(err.Error()))
+	}
+	if hasWorktrees {
+		if err := os.Rename(worktreesDir, filepath.Join(tmpPath, "worktrees")); err != nil {
+			cleanupTmp()
+			return "", fmt.Errorf("move worktrees metadata: %w", err)
+		}
+	}
+	restoreWorktrees := func() {
+		if hasWorktrees {
+			_ = os.Rename(filepath.Join(tmpPath, "worktrees"), worktreesDir)
+		}
+	}
+	if err := os.Rename(barePath, oldPath); err != nil {
+		restoreWorktrees()
+		cleanupTmp()
+		return "", fmt.Errorf("swap: %w", err)
+	}
+	if err := os.Rename(tmpPath, barePath); err != nil {
+		_ = os.Rename(oldPath, barePath)
+		restoreWorktrees()
+		cleanupTmp()
+		return "", fmt.Errorf("swap: %w", err)
+	}
+	_ = os.RemoveAll(oldPath)
+	if err := recordRepoPoolFetch(barePath, time.Now()); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// applyDoctorPoolRemoveSwapLeftover removes a re-clone leftover only while the bare repo it belongs to is intact.
+// When both swap renames failed, the leftover may be the only copy of the repo, so it is kept for manual recovery.
+func applyDoctorPoolRemoveSwapLeftover(leftover string) (string, error) {
+	barePath, ok := doctorPoolSwapLeftoverBarePath(leftover)
+	if !ok {
+		return "unrecognized leftover name", nil
+	}
+	if fi, err := os.Stat(barePath); err != nil || !fi.IsDir() {
+		return fmt.Sprintf("bare repo %s is missing; the leftover may be the only copy", barePath), nil
+	}
+	if reason := doctorPoolPartialCloneReason(context.Background(), barePath); reason != "" {
+		return fmt.Sprintf("bare repo %s is not valid (%s); the leftover may be the only copy", barePath, reason), nil
+	}
+	return "", os.RemoveAll(leftover)
+}
+
+// doctorPoolSwapLeftoverBarePath maps <name>.git.kra-reclone-* / <name>.git.kra-old-* to <name>.git.
+func doctorPoolSwapLeftoverBarePath(leftover string) (string, bool) {
+	for _, suffix := range []string{doctorPoolRecloneSuffix, doctorPoolOldSuffix} {
+		if i := strings.LastIndex(leftover, ".git"+suffix); i > 0 {
+			return leftover[:i+len(".git")], true
+		}
+	}
+	return "", false
+}
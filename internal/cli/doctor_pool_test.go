@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
+	"github.com/tasuku43/kra/internal/core/repostore"
+	"github.com/tasuku43/kra/internal/repomanifest"
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_Doctor_RepoPool_DetectsAndRepairs(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var errBuf bytes.Buffer
+		code := New(&out, &errBuf).Run(args)
+		return code, out.String()
+	}
+	findingCodes := func(raw string) map[string][]string {
+		t.Helper()
+		var resp struct {
+			Result doctorReport `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+			t.Fatalf("json unmarshal error: %v (raw=%q)", err, raw)
+		}
+		codes := map[string][]string{}
+		for _, f := range resp.Result.Findings {
+			if strings.HasPrefix(f.Code, "pool_repo_") {
+				codes[f.Code] = append(codes[f.Code], f.Target)
+			}
+		}
+		return codes
+	}
+
+	e := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, e.Root)
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	docsSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "docs")
+	if code, out := run("repo", "add", apiSpec, docsSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (%s)", code, out)
+	}
+	if code, out := run("doctor", "--fix", "--apply", "--format", "json"); code != exitOK {
+		t.Fatalf("initial doctor fix = %d (%s)", code, out)
+	}
+	// A clone of an empty remote has neither refs nor objects and is not an interrupted clone.
+	emptyBare := filepath.Join(e.RepoPoolPath(), "github.com", "example-org", "empty.git")
+	runGit("", "init", "--bare", "--quiet", emptyBare)
+	runGit("", "--git-dir", emptyBare, "remote", "add", "origin", "https://github.com/example-org/empty.git")
+	if _, out := run("doctor", "--fsck", "--format", "json"); len(findingCodes(out)) != 0 {
+		t.Fatalf("healthy pool should have no pool findings: %s", out)
+	}
+
+	apiSpecN, _ := repospec.Normalize(apiSpec)
+	docsSpecN, _ := repospec.Normalize(docsSpec)
+	apiBare := repostore.StorePath(e.RepoPoolPath(), apiSpecN)
+	docsBare := repostore.StorePath(e.RepoPoolPath(), docsSpecN)
+
+	// api: missing objects (fsck) + stale worktree admin entry.
+	if err := os.RemoveAll(filepath.Join(apiBare, "objects")); err != nil {
+		t.Fatalf("remove objects: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(apiBare, "objects", "pack"), 0o755); err != nil {
+		t.Fatalf("mkdir objects: %v", err)
+	}
+	staleAdmin := filepath.Join(apiBare, "worktrees", "gone")
+	if err := os.MkdirAll(staleAdmin, 0o755); err != nil {
+		t.Fatalf("mkdir worktree admin: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(staleAdmin, "gitdir"), []byte(filepath.Join(t.TempDir(), "missing", ".git")+"\n"), 0o644); err != nil {
+		t.Fatalf("write gitdir: %v", err)
+	}
+	// docs: origin removed; URL is recoverable from the repo manifest.
+	runGit("", "--git-dir", docsBare, "remote", "remove", "origin")
+	manifestPath := repomanifest.Path(e.Root)
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, []byte(fmt.Sprintf("repos:\n  - spec: %s\n", docsSpec)), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	// half: interrupted clone with no known URL; leftover: interrupted re-clone swap.
+	halfBare := filepath.Join(e.RepoPoolPath(), "github.com", "example-org", "half.git")
+	if err := os.MkdirAll(halfBare, 0o755); err != nil {
+		t.Fatalf("mkdir half: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(halfBare, "HEAD"), []byte("ref: refs/heads/main\n"), 0o644); err != nil {
+		t.Fatalf("write HEAD: %v", err)
+	}
+	leftover := apiBare + doctorPoolOldSuffix + "20260101T000000"
+	if err := os.MkdirAll(leftover, 0o755); err != nil {
+		t.Fatalf("mkdir leftover: %v", err)
+	}
+
+	// Connectivity is only checked with --fsck.
+	if _, out := run("doctor", "--format", "json"); len(findingCodes(out)["pool_repo_fsck_failed"]) != 0 {
+		t.Fatalf("fsck should not run without --fsck: %s", out)
+	}
+	code, out := run("doctor", "--fsck", "--format", "json")
+	if code != exitError {
+		t.Fatalf("doctor exit code = %d, want %d (%s)", code, exitError, out)
+	}
+	codes := findingCodes(out)
+	for code, target := range map[string]string{
+		"pool_repo_fsck_failed":    apiBare,
+		"pool_repo_stale_worktree": apiBare,
+		"pool_repo_origin_missing": docsBare,
+		"pool_repo_partial_clone":  halfBare,
+		"pool_repo_swap_leftover":  leftover,
+	} {
+		if len(codes[code]) != 1 || codes[code][0] != target {
+			t.Fatalf("finding %s = %v, want [%s] (all=%v)", code, codes[code], target, codes)
+		}
+	}
+
+	code, out = run("doctor", "--fix", "--apply", "--fsck", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("doctor fix exit code = %d (%s)", code, out)
+	}
+	if !strings.Contains(out, `"kind":"reclone_pool_repo","target":"`+halfBare+`","status":"skipped"`) {
+		t.Fatalf("half clone without known URL should be skipped: %s", out)
+	}
+
+	_, out = run("doctor", "--fsck", "--format", "json")
+	codes = findingCodes(out)
+	if len(codes) != 1 || len(codes["pool_repo_partial_clone"]) != 1 {
+		t.Fatalf("only the unrecoverable partial clone should remain: %v", codes)
+	}
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Fatalf("leftover should be removed, stat err=%v", err)
+	}
+}
+
+func TestCLI_Doctor_RepoPool_RecloneKeepsWorktreeConfig(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	gitOut := func(dir string, args ...string) string {
+		out, _ := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		return strings.TrimSpace(string(out))
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var errBuf bytes.Buffer
+		code := New(&out, &errBuf).Run(args)
+		return code, out.String(), errBuf.String()
+	}
+
+	e := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, e.Root)
+	if err := os.WriteFile(filepath.Join(e.Root, ".kra", "config.yaml"), []byte(`
+workspace:
+  commit:
+    template: "{{workspace_id}}: "
+    prefix: "[{{workspace_id}}]"
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	if code, _, stderr := run("repo", "add", apiSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	for _, add := range []struct {
+		id    string
+		extra []string
+	}{
+		{id: "WS1", extra: []string{"--sparse", "app/"}},
+		{id: "WS2"},
+	} {
+		if code, _, stderr := run("ws", "create", "--no-prompt", add.id); code != exitOK {
+			t.Fatalf("ws create %s exit code = %d (stderr=%q)", add.id, code, stderr)
+		}
+		args := append([]string{"ws", "add-repo", "--format", "json", "--id", add.id, "--repo", "example-org/api", "--yes"}, add.extra...)
+		if code, out, stderr := run(args...); code != exitOK {
+			t.Fatalf("ws add-repo %s exit code = %d (stdout=%q stderr=%q)", add.id, code, out, stderr)
+		}
+	}
+	sparseRepo := filepath.Join(e.Root, "workspaces", "WS1", "repos", "api")
+	hookedRepo := filepath.Join(e.Root, "workspaces", "WS2", "repos", "api")
+	wantHooksPath := gitOut(hookedRepo, "config", "--get", "core.hooksPath")
+	wantTemplate := gitOut(hookedRepo, "config", "--get", "commit.template")
+	if wantHooksPath == "" || wantTemplate == "" || gitOut(sparseRepo, "config", "--get", "core.sparseCheckout") != "true" {
+		t.Fatalf("setup: hooksPath=%q template=%q sparse=%q", wantHooksPath, wantTemplate, gitOut(sparseRepo, "config", "--get", "core.sparseCheckout"))
+	}
+
+	// A remote-tracking ref to a missing object fails fsck but keeps the local branches fetchable,
+	// so the re-clone carries them over.
+	spec, _ := repospec.Normalize(apiSpec)
+	barePath := repostore.StorePath(e.RepoPoolPath(), spec)
+	corrupt := filepath.Join(barePath, "refs", "remotes", "origin", "broken")
+	if err := os.MkdirAll(filepath.Dir(corrupt), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(corrupt, []byte("0123456789012345678901234567890123456789\n"), 0o644); err != nil {
+		t.Fatalf("write broken ref: %v", err)
+	}
+	code, out, _ := run("doctor", "--fix", "--apply", "--fsck", "--format", "json")
+	if code != exitOK || !strings.Contains(out, `"kind":"reclone_pool_repo","target":"`+barePath+`","status":"applied"`) {
+		t.Fatalf("doctor fix exit code = %d, want reclone applied (%s)", code, out)
+	}
+	if _, err := os.Stat(corrupt); !os.IsNotExist(err) {
+		t.Fatalf("pool repo should be re-cloned, stat err=%v", err)
+	}
+
+	if got := gitOut(sparseRepo, "config", "--get", "core.sparseCheckout"); got != "true" {
+		t.Fatalf("core.sparseCheckout after reclone = %q, want true", got)
+	}
+	if got := gitOut(sparseRepo, "sparse-checkout", "list"); got != "app" {
+		t.Fatalf("sparse-checkout list after reclone = %q, want app", got)
+	}
+	if got := gitOut(hookedRepo, "config", "--get", "core.hooksPath"); got != wantHooksPath {
+		t.Fatalf("core.hooksPath after reclone = %q, want %q", got, wantHooksPath)
+	}
+	if got := gitOut(hookedRepo, "config", "--get", "commit.template"); got != wantTemplate {
+		t.Fatalf("commit.template after reclone = %q, want %q", got, wantTemplate)
+	}
+	if got := gitOut(barePath, "config", "--get", "core.bare"); got != "true" {
+		t.Fatalf("pool repo core.bare after reclone = %q, want true", got)
+	}
+	if got := gitOut(hookedRepo, "rev-parse", "--is-bare-repository"); got != "false" {
+		t.Fatalf("worktree should not read core.bare from the pool repo: %q", got)
+	}
+}
+
+func TestApplyDoctorPoolRemoveSwapLeftover_KeepsOnlyCopy(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	pool := t.TempDir()
+	barePath := filepath.Join(pool, "github.com", "example-org", "api.git")
+	leftover := barePath + doctorPoolOldSuffix + "20260101T000000"
+	if out, err := exec.Command("git", "init", "--bare", "--quiet", leftover).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v (%s)", err, out)
+	}
+
+	// Both swap renames failed: the bare path is gone and the leftover is the only copy.
+	skipReason, err := applyDoctorPoolRemoveSwapLeftover(leftover)
+	if err != nil || !strings.Contains(skipReason, "missing") {
+		t.Fatalf("missing bare repo: skipReason=%q err=%v, want skip", skipReason, err)
+	}
+	// A bare path that is not a valid repo does not count either.
+	if err := os.MkdirAll(barePath, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	skipReason, err = applyDoctorPoolRemoveSwapLeftover(leftover)
+	if err != nil || !strings.Contains(skipReason, "not valid") {
+		t.Fatalf("invalid bare repo: skipReason=%q err=%v, want skip", skipReason, err)
+	}
+	if _, err := os.Stat(leftover); err != nil {
+		t.Fatalf("leftover must be kept: %v", err)
+	}
+}
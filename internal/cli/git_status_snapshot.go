@@ -29,7 +29,8 @@ func inspectGitRepoSnapshot(ctx context.Context, dir string) gitRepoSnapshot {
 		return gitRepoSnapshot{Status: workspacerisk.RepoStatus{Error: err}}
 	}
 
-	out, err := gitutil.Run(ctx, dir, "status", "--porcelain=v2", "--branch")
+	// --ignore-submodules=none keeps modified/untracked submodule content visible regardless of user config.
+	out, err := gitutil.Run(ctx, dir, "status", "--porcelain=v2", "--branch", "--ignore-submodules=none")
 	if err != nil {
 		return gitRepoSnapshot{Status: workspacerisk.RepoStatus{Error: err}}
 	}
@@ -38,9 +39,39 @@ func inspectGitRepoSnapshot(ctx context.Context, dir string) gitRepoSnapshot {
 	if parseErr != nil {
 		snapshot.Status.Error = parseErr
 	}
+	if _, err := os.Stat(filepath.Join(dir, ".gitmodules")); err == nil && snapshot.Status.Error == nil {
+		unpushed, err := listSubmodulesWithUnpushedCommits(ctx, dir)
+		if err != nil {
+			snapshot.Status.Error = err
+			return snapshot
+		}
+		// Submodule commits live only in the worktree's module dir; removing the worktree would lose them.
+		for _, p := range unpushed {
+			snapshot.Status.Dirty = true
+			snapshot.Files = append(snapshot.Files, fmt.Sprintf("S  %s (unpushed submodule commits)", p))
+		}
+	}
 	return snapshot
 }
 
+// listSubmodulesWithUnpushedCommits returns initialized submodules (recursive) whose HEAD is not on any remote ref.
+func listSubmodulesWithUnpushedCommits(ctx context.Context, dir string) ([]string, error) {
+	out, err := gitutil.Run(ctx, dir, "submodule", "foreach", "--recursive", "--quiet",
+		`echo "$(git rev-list -n 1 HEAD --not --remotes)|$displaypath"`)
+	if err != nil {
+		return nil, fmt.Errorf("inspect submodules: %w", err)
+	}
+	paths := make([]string, 0, 2)
+	for _, line := range strings.Split(out, "\n") {
+		// "<sha>|<path>" when HEAD has unpushed commits, "|<path>" otherwise.
+		sha, path, ok := strings.Cut(strings.TrimSpace(line), "|")
+		if ok && sha != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
 func parseGitRepoSnapshot(raw string) (gitRepoSnapshot, error) {
 	snapshot := gitRepoSnapshot{}
 	lines := strings.Split(raw, "\n")
@@ -211,6 +242,11 @@ func inspectWorkspaceRepoRisk(ctx context.Context, root string, workspaceID stri
 			worktreePath := filepath.Join(root, "workspaces", workspaceID, "repos", r.Alias)
 			snapshot := inspectGitRepoSnapshot(ctx, worktreePath)
 			state = workspacerisk.ClassifyRepoStatus(snapshot.Status)
+			if r.Reference {
+				state = workspacerisk.ClassifyReferenceRepoStatus(snapshot.Status)
+			} else if len(r.Stack) > 0 {
+				state = stackRiskState(state, inspectWSStack(ctx, worktreePath, r.BaseRef, r.Stack, snapshot.Branch))
+			}
 		}
 		states = append(states, state)
 		items = append(items, repoRiskItem{alias: r.Alias, state: state})
@@ -318,29 +318,37 @@ func ensureRootAgents(root string) error {
 	return nil
 }
 
+// rootGitignorePatterns keep machine-local content out of the KRA_ROOT repo: git worktrees and
+// the .kra/state / .kra/locks runtime caches.
+var rootGitignorePatterns = []string{
+	"workspaces/**/repos/**",
+	".kra/state/",
+	".kra/locks/",
+}
+
 func ensureRootGitignore(root string) error {
 	path := filepath.Join(root, gitignoreFilename)
-	const pattern = "workspaces/**/repos/**"
 
 	b, err := os.ReadFile(path)
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("read %s: %w", gitignoreFilename, err)
 	}
 
-	if hasGitignoreLine(string(b), pattern) {
-		return nil
-	}
-
-	var out string
-	if len(b) == 0 {
-		out = "# kra\n" + pattern + "\n"
-	} else {
-		out = string(b)
-		if !strings.HasSuffix(out, "\n") {
+	out := string(b)
+	for _, pattern := range rootGitignorePatterns {
+		if hasGitignoreLine(out, pattern) {
+			continue
+		}
+		if out == "" {
+			out = "# kra\n"
+		} else if !strings.HasSuffix(out, "\n") {
 			out += "\n"
 		}
 		out += pattern + "\n"
 	}
+	if out == string(b) {
+		return nil
+	}
 
 	if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
 		return fmt.Errorf("write %s: %w", gitignoreFilename, err)
@@ -486,8 +494,9 @@ Notes vs artifacts:
 
 ## Git policy
 
-- Track: everything except workspaces/**/repos/**
-- Ignore: workspaces/**/repos/**
+- Track: everything except the ignored paths below
+- Ignore: workspaces/**/repos/**, .kra/state/, .kra/locks/
+- Share across machines: kra root sync
 `
 }
 
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/kra/internal/config"
+	"github.com/tasuku43/kra/internal/infra/jira"
+	"github.com/tasuku43/kra/internal/infra/paths"
+)
+
+func (c *CLI) runJira(args []string) int {
+	if len(args) == 0 {
+		c.printJiraUsage(c.Err)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "-h", "--help", "help":
+		c.printJiraUsage(c.Out)
+		return exitOK
+	case "cache":
+		return c.runJiraCache(args[1:])
+	default:
+		fmt.Fprintf(c.Err, "unknown command: %q\n", strings.Join(append([]string{"jira"}, args[0]), " "))
+		c.printJiraUsage(c.Err)
+		return exitUsage
+	}
+}
+
+func (c *CLI) runJiraCache(args []string) int {
+	if len(args) == 0 {
+		c.printJiraCacheUsage(c.Err)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "-h", "--help", "help":
+		c.printJiraCacheUsage(c.Out)
+		return exitOK
+	case "show":
+		return c.runJiraCacheShow(args[1:])
+	case "prune":
+		return c.runJiraCachePrune(args[1:])
+	default:
+		fmt.Fprintf(c.Err, "unknown command: %q\n", strings.Join(append([]string{"jira", "cache"}, args[0]), " "))
+		c.printJiraCacheUsage(c.Err)
+		return exitUsage
+	}
+}
+
+type jiraCacheOptions struct {
+	format string
+	all    bool
+}
+
+// parseJiraCacheOptions parses `jira cache show|prune` flags. On error the returned options still carry
+// the requested --format (scanning continues past the bad argument), so the error is rendered in it.
+func parseJiraCacheOptions(args []string, name string, allowAll bool) (jiraCacheOptions, error) {
+	opts := jiraCacheOptions{format: "human"}
+	var firstErr error
+	var unexpected []string
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			return opts, errHelpRequested
+		case arg == "--all" && allowAll:
+			opts.all = true
+		case strings.HasPrefix(arg, "--format="):
+			opts.format = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case arg == "--format":
+			if i+1 >= len(args) {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("--format requires a value")
+				}
+				continue
+			}
+			opts.format = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "-"):
+			if firstErr == nil {
+				firstErr = fmt.Errorf("unknown flag for jira cache %s: %q", name, arg)
+			}
+		default:
+			unexpected = append(unexpected, arg)
+		}
+	}
+	if firstErr == nil && len(unexpected) > 0 {
+		firstErr = fmt.Errorf("unexpected args for jira cache %s: %q", name, strings.Join(unexpected, " "))
+	}
+	switch opts.format {
+	case "human", "json":
+	default:
+		if firstErr == nil {
+			firstErr = fmt.Errorf("unsupported --format: %q (supported: human, json)", opts.format)
+		}
+		opts.format = "human"
+	}
+	return opts, firstErr
+}
+
+func (c *CLI) runJiraCacheShow(args []string) int {
+	opts, err := parseJiraCacheOptions(args, "show", false)
+	if err != nil {
+		return c.writeJiraCacheOptionsError(err, opts.format, "jira.cache.show")
+	}
+	cache, err := c.openJiraCache()
+	if err != nil {
+		return c.writeJiraCacheRuntimeError(opts.format, "jira.cache.show", err)
+	}
+	stats, err := cache.Stats()
+	if err != nil {
+		return c.writeJiraCacheRuntimeError(opts.format, "jira.cache.show", fmt.Errorf("read jira cache: %w", err))
+	}
+
+	if opts.format == "json" {
+		kinds := make([]map[string]any, 0, len(stats))
+		for _, s := range stats {
+			item := map[string]any{
+				"kind":        string(s.Kind),
+				"ttl_seconds": int64(s.TTL / time.Second),
+				"entries":     s.Entries,
+				"stale":       s.Stale,
+				"bytes":       s.Bytes,
+			}
+			if !s.Oldest.IsZero() {
+				item["oldest_fetched_at"] = s.Oldest.Unix()
+				item["newest_fetched_at"] = s.Newest.Unix()
+			}
+			kinds = append(kinds, item)
+		}
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:     true,
+			Action: "jira.cache.show",
+			Result: map[string]any{
+				"path":  cache.Dir(),
+				"kinds": kinds,
+			},
+		})
+		return exitOK
+	}
+
+	useColor := writerSupportsColor(c.Out)
+	body := []string{fmt.Sprintf("%s%s %s %s", uiIndent, styleMuted("•", useColor), styleAccent("path:", useColor), cache.Dir())}
+	now := time.Now()
+	for _, s := range stats {
+		line := fmt.Sprintf("%s%s %s entries=%d stale=%d size=%dB ttl=%s", uiIndent, styleMuted("•", useColor), styleAccent(string(s.Kind)+":", useColor), s.Entries, s.Stale, s.Bytes, s.TTL)
+		if !s.Newest.IsZero() {
+			line += styleMuted(fmt.Sprintf(" newest=%s ago", jira.FormatCacheAge(now.Sub(s.Newest))), useColor)
+		}
+		body = append(body, line)
+	}
+	printSection(c.Out, styleBold("Jira cache:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+	return exitOK
+}
+
+func (c *CLI) runJiraCachePrune(args []string) int {
+	opts, err := parseJiraCacheOptions(args, "prune", true)
+	if err != nil {
+		return c.writeJiraCacheOptionsError(err, opts.format, "jira.cache.prune")
+	}
+	cache, err := c.openJiraCache()
+	if err != nil {
+		return c.writeJiraCacheRuntimeError(opts.format, "jira.cache.prune", err)
+	}
+	result, err := cache.Prune(opts.all)
+	if err != nil {
+		return c.writeJiraCacheRuntimeError(opts.format, "jira.cache.prune", fmt.Errorf("prune jira cache: %w", err))
+	}
+
+	if opts.format == "json" {
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:     true,
+			Action: "jira.cache.prune",
+			Result: map[string]any{
+				"path":    cache.Dir(),
+				"all":     opts.all,
+				"removed": result.Removed,
+				"kept":    result.Kept,
+			},
+		})
+		return exitOK
+	}
+	useColor := writerSupportsColor(c.Out)
+	printResultSection(
+		c.Out,
+		useColor,
+		styleSuccess(fmt.Sprintf("Pruned %d entries", result.Removed), useColor),
+		styleMuted(fmt.Sprintf("kept: %d", result.Kept), useColor),
+	)
+	return exitOK
+}
+
+func (c *CLI) writeJiraCacheOptionsError(err error, format string, action string) int {
+	if err == errHelpRequested {
+		c.printJiraCacheUsage(c.Out)
+		return exitOK
+	}
+	if format == "json" {
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:     false,
+			Action: action,
+			Error:  &cliJSONError{Code: "invalid_argument", Message: err.Error()},
+		})
+		return exitUsage
+	}
+	fmt.Fprintln(c.Err, err.Error())
+	c.printJiraCacheUsage(c.Err)
+	return exitUsage
+}
+
+func (c *CLI) writeJiraCacheRuntimeError(format string, action string, err error) int {
+	if format == "json" {
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:     false,
+			Action: action,
+			Error:  &cliJSONError{Code: "internal_error", Message: err.Error()},
+		})
+		return exitError
+	}
+	fmt.Fprintln(c.Err, err.Error())
+	return exitError
+}
+
+// openJiraCache resolves TTLs from root+global config when a root is available,
+// and from global config otherwise (cache commands do not require KRA_ROOT).
+func (c *CLI) openJiraCache() (*jira.Cache, error) {
+	cfg, err := c.loadJiraCacheConfig()
+	if err != nil {
+		return nil, err
+	}
+	return newJiraCacheFromConfig(cfg)
+}
+
+func (c *CLI) loadJiraCacheConfig() (config.Config, error) {
+	if wd, err := os.Getwd(); err == nil {
+		if root, err := paths.ResolveExistingRoot(wd); err == nil {
+			cfg, err := c.loadMergedConfig(root)
+			if err != nil {
+				return config.Config{}, fmt.Errorf("load config: %w", err)
+			}
+			return cfg, nil
+		}
+	}
+	globalPath, err := paths.ConfigPath()
+	if err != nil {
+		return config.Config{}, fmt.Errorf("resolve global config path: %w", err)
+	}
+	cfg, err := config.LoadFile(globalPath)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("load global config %s: %w", globalPath, err)
+	}
+	return cfg, nil
+}
+
+func newJiraCacheFromConfig(cfg config.Config) (*jira.Cache, error) {
+	dir, err := paths.JiraCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("resolve jira cache dir: %w", err)
+	}
+	parse := func(raw string) time.Duration {
+		// Values are validated on config load; unset stays zero and falls back to defaults.
+		d, _ := time.ParseDuration(strings.TrimSpace(raw))
+		return d
+	}
+	return jira.NewCache(dir, jira.CacheTTLs{
+		Issues:  parse(cfg.Integration.Jira.Cache.IssuesTTL),
+		Boards:  parse(cfg.Integration.Jira.Cache.BoardsTTL),
+		Sprints: parse(cfg.Integration.Jira.Cache.SprintsTTL),
+	}), nil
+}
+
+// newJiraClient builds a cache-backed Jira client. Warnings (offline/stale-while-error)
+// are appended to warnings so callers can render them for human or JSON output.
+func (c *CLI) newJiraClient(cfg config.Config, offline bool, warnings *[]string) *jira.Client {
+	cache, err := newJiraCacheFromConfig(cfg)
+	if err != nil {
+		c.debugf("jira cache disabled: %v", err)
+		cache = nil
+	}
+	return jira.NewClientWithOptions(cfg.Integration.Jira.BaseURL, jira.ClientOptions{
+		Cache:   cache,
+		Offline: offline,
+		Warnf: func(format string, args ...any) {
+			msg := fmt.Sprintf(format, args...)
+			c.debugf("jira warning: %s", msg)
+			if warnings != nil {
+				*warnings = append(*warnings, msg)
+			}
+		},
+	})
+}
+
+func (c *CLI) printJiraWarnings(warnings []string) {
+	useColor := writerSupportsColor(c.Err)
+	for _, w := range warnings {
+		fmt.Fprintf(c.Err, "%s %s\n", styleWarn("warning:", useColor), w)
+	}
+}
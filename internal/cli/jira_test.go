@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_Jira_Cache_OfflineImportAndShowPrune(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-201","fields":{"summary":"Cached issue"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		code := New(&out, &err).Run(args)
+		return code, out.String(), err.String()
+	}
+
+	importArgs := []string{"ws", "import", "jira", "--jql", "assignee=currentUser()", "--no-prompt", "--format", "json"}
+	if code, out, stderr := run(importArgs...); code != exitOK {
+		t.Fatalf("online import exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	code, out, stderr := run(append(importArgs, "--offline")...)
+	if code != exitOK {
+		t.Fatalf("offline import exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	if requests != 1 {
+		t.Fatalf("requests = %d, want 1 (offline must not hit jira)", requests)
+	}
+	if !strings.Contains(out, `"PROJ-201"`) {
+		t.Fatalf("offline import should list cached issue: %q", out)
+	}
+
+	code, out, _ = run("jira", "cache", "show", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("cache show exit code = %d (stdout=%q)", code, out)
+	}
+	resp := decodeJSONResponse(t, out)
+	if resp.Action != "jira.cache.show" || !strings.HasSuffix(resp.Result["path"].(string), "/cache/jira") {
+		t.Fatalf("unexpected cache show response: %+v", resp)
+	}
+	kinds, _ := resp.Result["kinds"].([]any)
+	if len(kinds) != 3 {
+		t.Fatalf("kinds = %#v", resp.Result["kinds"])
+	}
+	issues, _ := kinds[0].(map[string]any)
+	if issues["kind"] != "issues" || issues["entries"] != float64(1) || issues["stale"] != float64(0) {
+		t.Fatalf("issues stats = %#v", issues)
+	}
+
+	code, out, _ = run("jira", "cache", "prune", "--format", "json")
+	if code != exitOK || !strings.Contains(out, `"removed":0`) || !strings.Contains(out, `"kept":1`) {
+		t.Fatalf("cache prune: code=%d stdout=%q", code, out)
+	}
+	code, out, _ = run("jira", "cache", "prune", "--all", "--format", "json")
+	if code != exitOK || !strings.Contains(out, `"removed":1`) {
+		t.Fatalf("cache prune --all: code=%d stdout=%q", code, out)
+	}
+
+	code, out, _ = run(append(importArgs, "--offline")...)
+	if code != exitError || !strings.Contains(out, "no cached issues") {
+		t.Fatalf("offline import after prune: code=%d stdout=%q", code, out)
+	}
+}
+
+func TestCLI_Jira_Cache_ArgumentErrorsFollowFormat(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	cases := []struct {
+		name     string
+		args     []string
+		wantJSON bool
+	}{
+		{name: "json before bad flag", args: []string{"jira", "cache", "show", "--format", "json", "--bogus"}, wantJSON: true},
+		{name: "json after bad flag", args: []string{"jira", "cache", "prune", "--bogus", "--format=json"}, wantJSON: true},
+		{name: "json flag is not a jira cache flag", args: []string{"jira", "cache", "show", "--json"}},
+		{name: "human", args: []string{"jira", "cache", "show", "extra"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var errOut bytes.Buffer
+			code := New(&out, &errOut).Run(tc.args)
+			if code != exitUsage {
+				t.Fatalf("exit code = %d, want %d (stdout=%q stderr=%q)", code, exitUsage, out.String(), errOut.String())
+			}
+			if !tc.wantJSON {
+				if out.Len() != 0 || errOut.Len() == 0 {
+					t.Fatalf("human error expected on stderr: stdout=%q stderr=%q", out.String(), errOut.String())
+				}
+				return
+			}
+			resp := decodeJSONResponse(t, out.String())
+			if resp.OK || resp.Error.Code != "invalid_argument" || !strings.Contains(resp.Error.Message, "--bogus") {
+				t.Fatalf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestCLI_WS_Create_OfflineRequiresJira(t *testing.T) {
+	prepareCurrentRootForTest(t)
+	var out bytes.Buffer
+	var err bytes.Buffer
+	code := New(&out, &err).Run([]string{"ws", "create", "--offline", "DEMO-1"})
+	if code != exitUsage {
+		t.Fatalf("exit code = %d, want %d", code, exitUsage)
+	}
+	if !strings.Contains(err.String(), "--offline requires --jira") {
+		t.Fatalf("stderr missing offline usage error: %q", err.String())
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/config"
+	"github.com/tasuku43/kra/internal/core/commitmsg"
+)
+
+const defaultLifecycleCommitMessage = "{{action}}: {{id}}"
+
+// lifecycleCommit builds the `git commit` invocation of one workspace's lifecycle commits in the
+// KRA_ROOT repo from workspace.lifecycle_commit. The zero value (besides workspaceID) keeps the
+// built-in `<action>: <id>` message with the default git identity.
+type lifecycleCommit struct {
+	cfg         config.LifecycleCommit
+	workspaceID string
+	title       string
+	sourceURL   string
+	// defaultMessage replaces the built-in message when no message is configured (root-level commits).
+	defaultMessage string
+}
+
+// loadLifecycleCommit resolves config and the workspace title / source_url up front, since the
+// workspace directory moves (close/reopen) or disappears (purge) before the post commit.
+func (c *CLI) loadLifecycleCommit(root string, workspaceID string) (lifecycleCommit, error) {
+	cfg, err := c.loadMergedConfig(root)
+	if err != nil {
+		return lifecycleCommit{}, fmt.Errorf("load config: %w", err)
+	}
+	lc := lifecycleCommit{cfg: cfg.Workspace.LifecycleCommit, workspaceID: workspaceID}
+	for _, dir := range []string{filepath.Join(root, "workspaces", workspaceID), filepath.Join(root, "archive", workspaceID)} {
+		if _, err := os.Stat(filepath.Join(dir, workspaceMetaFilename)); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		meta, err := loadWorkspaceMetaFile(dir)
+		if err != nil {
+			return lifecycleCommit{}, fmt.Errorf("load %s: %w", workspaceMetaFilename, err)
+		}
+		lc.title = strings.TrimSpace(meta.Workspace.Title)
+		lc.sourceURL = strings.TrimSpace(meta.Workspace.SourceURL)
+		break
+	}
+	return lc, nil
+}
+
+// loadRootLifecycleCommit is loadLifecycleCommit for commits that belong to no workspace (root sync):
+// {{id}}, {{title}} and {{source_url}} render empty, and defaultMessage is used without a configured message.
+func (c *CLI) loadRootLifecycleCommit(root string, defaultMessage string) (lifecycleCommit, error) {
+	cfg, err := c.loadMergedConfig(root)
+	if err != nil {
+		return lifecycleCommit{}, fmt.Errorf("load config: %w", err)
+	}
+	return lifecycleCommit{cfg: cfg.Workspace.LifecycleCommit, defaultMessage: defaultMessage}, nil
+}
+
+// gitArgs returns the full git arguments for a lifecycle commit of action (e.g. `archive`).
+// flags go right after `commit` (e.g. --allow-empty --only); paths follow `--`.
+func (l lifecycleCommit) gitArgs(action string, flags []string, paths ...string) []string {
+	values := map[string]string{
+		"action":     action,
+		"id":         l.workspaceID,
+		"title":      l.title,
+		"source_url": l.sourceURL,
+	}
+	message := l.cfg.Message
+	if message == "" {
+		message = l.defaultMessage
+	}
+	if message == "" {
+		message = defaultLifecycleCommitMessage
+	}
+
+	args := make([]string, 0, 16+len(paths))
+	if l.cfg.Committer.Name != "" {
+		args = append(args, "-c", "committer.name="+l.cfg.Committer.Name, "-c", "committer.email="+l.cfg.Committer.Email)
+	}
+	switch l.cfg.Sign {
+	case config.LifecycleSignGPG:
+		args = append(args, "-c", "gpg.format=openpgp")
+	case config.LifecycleSignSSH:
+		args = append(args, "-c", "gpg.format=ssh")
+	}
+	if l.cfg.SigningKey != "" {
+		args = append(args, "-c", "user.signingkey="+l.cfg.SigningKey)
+	}
+	args = append(args, "commit")
+	args = append(args, flags...)
+	if l.cfg.Sign != "" {
+		args = append(args, "--gpg-sign")
+	}
+	args = append(args, "-m", strings.TrimSpace(commitmsg.RenderFields(message, values)))
+	for _, t := range l.cfg.Trailers {
+		if rendered := strings.TrimSpace(commitmsg.RenderFields(t, values)); commitmsg.TrailerHasValue(rendered) {
+			args = append(args, "--trailer", rendered)
+		}
+	}
+	args = append(args, "--")
+	return append(args, paths...)
+}
+
+// gitAddPathSkipped reports `git add` failures for a path that does not exist or is ignored
+// (.kra/state caches are ignored by the root .gitignore); such a path is left out of the commit.
+func gitAddPathSkipped(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "did not match any file") || strings.Contains(msg, "ignored by one of your .gitignore files")
+}
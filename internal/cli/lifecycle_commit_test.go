@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/config"
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestLifecycleCommitGitArgs(t *testing.T) {
+	lc := lifecycleCommit{workspaceID: "WS1"}
+	got := lc.gitArgs("archive", []string{"--only"}, "archive/WS1")
+	want := []string{"commit", "--only", "-m", "archive: WS1", "--", "archive/WS1"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("default gitArgs = %q, want %q", got, want)
+	}
+
+	lc = lifecycleCommit{
+		cfg: config.LifecycleCommit{
+			Message:    "{{action}}({{id}}): {{title}}",
+			Trailers:   []string{"Kra-Action: {{action}}", "Kra-Source: {{source_url}}"},
+			Sign:       config.LifecycleSignSSH,
+			SigningKey: "~/.ssh/kra.pub",
+			Committer:  config.LifecycleCommitter{Name: "kra-bot", Email: "kra@example.com"},
+		},
+		workspaceID: "WS1",
+	}
+	got = lc.gitArgs("close-pre", []string{"--allow-empty", "--only"}, "workspaces/WS1")
+	want = []string{
+		"-c", "committer.name=kra-bot", "-c", "committer.email=kra@example.com",
+		"-c", "gpg.format=ssh", "-c", "user.signingkey=~/.ssh/kra.pub",
+		"commit", "--allow-empty", "--only", "--gpg-sign",
+		"-m", "close-pre(WS1):",
+		"--trailer", "Kra-Action: close-pre",
+		"--", "workspaces/WS1",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("configured gitArgs = %q, want %q", got, want)
+	}
+}
+
+func TestCLI_WS_LifecycleCommit_MessageTrailersCommitter(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+	gitLog := func(root string, rev string, format string) string {
+		t.Helper()
+		out, err := exec.Command("git", "-C", root, "log", "-1", "--format="+format, rev).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git log %s: %v (%s)", rev, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	// GIT_COMMITTER_* (set for all tests) would take precedence over committer.* config.
+	for _, key := range []string{"GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "")
+		_ = os.Unsetenv(key)
+	}
+	if err := os.WriteFile(filepath.Join(env.Root, ".kra", "config.yaml"), []byte(`
+workspace:
+  lifecycle_commit:
+    message: "{{action}}({{id}}): {{title}}"
+    trailers:
+      - "Kra-Action: {{action}}"
+      - "Kra-Source: {{source_url}}"
+    committer:
+      name: kra-bot
+      email: kra@example.com
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "--title", "Fix login", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	if got := gitLog(env.Root, "HEAD", "%B"); got != "create(WS1): Fix login\n\nKra-Action: create" {
+		t.Fatalf("create commit message = %q", got)
+	}
+
+	wsPath := filepath.Join(env.Root, "workspaces", "WS1")
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		t.Fatalf("load %s: %v", workspaceMetaFilename, err)
+	}
+	meta.Workspace.SourceURL = "https://jira.example.com/browse/WS1"
+	if err := writeWorkspaceMetaFile(wsPath, meta); err != nil {
+		t.Fatalf("write %s: %v", workspaceMetaFilename, err)
+	}
+	if code, _, stderr := run("ws", "close", "WS1"); code != exitOK {
+		t.Fatalf("ws close exit code = %d (stderr=%q)", code, stderr)
+	}
+	for rev, action := range map[string]string{"HEAD~1": "close-pre", "HEAD": "archive"} {
+		want := action + "(WS1): Fix login\n\nKra-Action: " + action + "\nKra-Source: https://jira.example.com/browse/WS1"
+		if got := gitLog(env.Root, rev, "%B"); got != want {
+			t.Fatalf("%s commit message = %q, want %q", action, got, want)
+		}
+		if got := gitLog(env.Root, rev, "%cn <%ce> / %an"); got != "kra-bot <kra@example.com> / kra-test" {
+			t.Fatalf("%s committer / author = %q", action, got)
+		}
+	}
+	out, err := exec.Command("git", "-C", env.Root, "log", "--format=%s", "--grep=^Kra-Action: archive$").CombinedOutput()
+	if err != nil || strings.TrimSpace(string(out)) != "archive(WS1): Fix login" {
+		t.Fatalf("grep by trailer = %q (err=%v)", out, err)
+	}
+}
+
+func TestCLI_WS_LifecycleCommit_SSHSigning(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+	testutil.RequireCommand(t, "ssh-keygen")
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	keyPath := filepath.Join(t.TempDir(), "kra_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v (%s)", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(env.Root, ".kra", "config.yaml"), []byte(`
+workspace:
+  lifecycle_commit:
+    sign: ssh
+    signing_key: `+keyPath+`
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	if code := New(&out, &errOut).Run([]string{"ws", "create", "--no-prompt", "WS1"}); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, errOut.String())
+	}
+	raw, err := exec.Command("git", "-C", env.Root, "cat-file", "commit", "HEAD").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git cat-file: %v (%s)", err, raw)
+	}
+	if !strings.Contains(string(raw), "-----BEGIN SSH SIGNATURE-----") || !strings.Contains(string(raw), "create: WS1") {
+		t.Fatalf("create commit should be ssh-signed:\n%s", raw)
+	}
+}
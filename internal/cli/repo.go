@@ -17,10 +17,22 @@ func (c *CLI) runRepo(args []string) int {
 		return exitOK
 	case "add":
 		return c.runRepoAdd(args[1:])
+	case "list":
+		return c.runRepoList(args[1:])
+	case "info":
+		return c.runRepoInfo(args[1:])
+	case "fetch":
+		return c.runRepoFetch(args[1:])
+	case "usage":
+		return c.runRepoUsage(args[1:])
 	case "discover":
 		return c.runRepoDiscover(args[1:])
 	case "remove":
 		return c.runRepoRemove(args[1:])
+	case "apply":
+		return c.runRepoApply(args[1:])
+	case "export":
+		return c.runRepoExport(args[1:])
 	case "gc":
 		return c.runRepoGC(args[1:])
 	default:
@@ -7,11 +7,13 @@ import (
 	"strings"
 
 	"github.com/tasuku43/kra/internal/app/repocmd"
+	"github.com/tasuku43/kra/internal/core/repospec"
 	"github.com/tasuku43/kra/internal/infra/appports"
 )
 
 func (c *CLI) runRepoAdd(args []string) int {
 	outputFormat := "human"
+	filter := ""
 	repoSpecs := make([]string, 0, len(args))
 	for i := 0; i < len(args); i++ {
 		arg := strings.TrimSpace(args[i])
@@ -27,11 +29,23 @@ func (c *CLI) runRepoAdd(args []string) int {
 			}
 			outputFormat = strings.TrimSpace(args[i+1])
 			i++
+		case "--filter":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--filter requires a value")
+				c.printRepoAddUsage(c.Err)
+				return exitUsage
+			}
+			filter = strings.TrimSpace(args[i+1])
+			i++
 		default:
 			if strings.HasPrefix(arg, "--format=") {
 				outputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
 				continue
 			}
+			if strings.HasPrefix(arg, "--filter=") {
+				filter = strings.TrimSpace(strings.TrimPrefix(arg, "--filter="))
+				continue
+			}
 			if strings.HasPrefix(arg, "-") {
 				fmt.Fprintf(c.Err, "unknown flag for repo add: %q\n", arg)
 				c.printRepoAddUsage(c.Err)
@@ -47,6 +61,11 @@ func (c *CLI) runRepoAdd(args []string) int {
 		c.printRepoAddUsage(c.Err)
 		return exitUsage
 	}
+	if err := repospec.ValidateCloneFilter(filter); err != nil {
+		fmt.Fprintf(c.Err, "invalid --filter: %v\n", err)
+		c.printRepoAddUsage(c.Err)
+		return exitUsage
+	}
 	if len(repoSpecs) == 0 {
 		if outputFormat == "json" {
 			_ = writeCLIJSON(c.Out, cliJSONResponse{
@@ -91,11 +110,11 @@ func (c *CLI) runRepoAdd(args []string) int {
 		fmt.Fprintf(c.Err, "%v\n", err)
 		return exitError
 	}
-	c.debugf("run repo add count=%d", len(repoSpecs))
+	c.debugf("run repo add count=%d filter=%s", len(repoSpecs), filter)
 
 	requests := make([]repoPoolAddRequest, 0, len(repoSpecs))
 	for _, arg := range repoSpecs {
-		requests = append(requests, repoPoolAddRequest{RepoSpecInput: strings.TrimSpace(arg)})
+		requests = append(requests, repoPoolAddRequest{RepoSpecInput: strings.TrimSpace(arg), Filter: filter})
 	}
 	if outputFormat == "json" {
 		outcomes := applyRepoPoolAdds(ctx, session.RepoPoolPath, requests, repoPoolAddDefaultWorkers, c.debugf, nil)
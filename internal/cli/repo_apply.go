@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/app/repocmd"
+	"github.com/tasuku43/kra/internal/infra/appports"
+	"github.com/tasuku43/kra/internal/repomanifest"
+)
+
+type repoApplyOptions struct {
+	File   string
+	Prune  bool
+	DryRun bool
+	Format string
+}
+
+type repoApplyPruneItem struct {
+	RepoUID           string
+	RepoKey           string
+	WorkspaceRefCount int
+}
+
+type repoApplyPlan struct {
+	Add   []repomanifest.Resolved
+	Keep  []repomanifest.Resolved
+	Prune []repoApplyPruneItem
+}
+
+func parseRepoApplyOptions(args []string) (repoApplyOptions, error) {
+	opts := repoApplyOptions{Format: "human"}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			return repoApplyOptions{}, errHelpRequested
+		case arg == "--prune":
+			opts.Prune = true
+		case arg == "--dry-run":
+			opts.DryRun = true
+		case arg == "-f" || arg == "--file":
+			if i+1 >= len(args) {
+				return repoApplyOptions{}, fmt.Errorf("%s requires a value", arg)
+			}
+			opts.File = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--file="):
+			opts.File = strings.TrimSpace(strings.TrimPrefix(arg, "--file="))
+		case arg == "--format":
+			if i+1 >= len(args) {
+				return repoApplyOptions{}, fmt.Errorf("--format requires a value")
+			}
+			opts.Format = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			opts.Format = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		default:
+			return repoApplyOptions{}, fmt.Errorf("unknown flag for repo apply: %q", arg)
+		}
+	}
+	switch opts.Format {
+	case "human", "json":
+	default:
+		return repoApplyOptions{}, fmt.Errorf("unsupported --format: %q (supported: human, json)", opts.Format)
+	}
+	return opts, nil
+}
+
+func (c *CLI) runRepoApply(args []string) int {
+	opts, err := parseRepoApplyOptions(args)
+	if err != nil {
+		if errors.Is(err, errHelpRequested) {
+			c.printRepoApplyUsage(c.Out)
+			return exitOK
+		}
+		fmt.Fprintf(c.Err, "%v\n", err)
+		c.printRepoApplyUsage(c.Err)
+		return exitUsage
+	}
+	jsonMode := opts.Format == "json"
+	fail := func(code int, errCode string, msg string, result any) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:     false,
+				Action: "repo.apply",
+				Result: result,
+				Error:  &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("get working dir: %v", err), nil)
+	}
+	ctx := context.Background()
+	repoUC := repocmd.NewService(appports.NewRepoPort(c.ensureDebugLog, c.touchStateRegistry))
+	session, err := repoUC.Run(ctx, repocmd.Request{
+		CWD:           wd,
+		DebugTag:      "repo-apply",
+		RequireGit:    true,
+		TouchRegistry: true,
+	})
+	if err != nil {
+		return fail(exitError, "internal_error", err.Error(), nil)
+	}
+
+	manifestPath := opts.File
+	if manifestPath == "" {
+		manifestPath = repomanifest.Path(session.Root)
+	} else if !filepath.IsAbs(manifestPath) {
+		manifestPath = filepath.Join(wd, manifestPath)
+	}
+	manifest, err := repomanifest.Load(manifestPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fail(exitError, "not_found", fmt.Sprintf("repo manifest not found: %s (create one with: kra repo export --output %s)", manifestPath, manifestPath), nil)
+		}
+		return fail(exitError, "invalid_argument", fmt.Sprintf("load repo manifest %s: %v", manifestPath, err), nil)
+	}
+	c.debugf("run repo apply manifest=%s prune=%t dry_run=%t", manifestPath, opts.Prune, opts.DryRun)
+
+	plan, err := buildRepoApplyPlan(ctx, session.Root, session.RepoPoolPath, manifest, opts.Prune)
+	if err != nil {
+		return fail(exitError, "internal_error", err.Error(), nil)
+	}
+
+	// Same policy as repo remove: refuse to detach repos that workspaces still use.
+	blocked := make([]string, 0)
+	for _, it := range plan.Prune {
+		if it.WorkspaceRefCount > 0 {
+			blocked = append(blocked, fmt.Sprintf("%s (workspace refs: %d)", it.RepoKey, it.WorkspaceRefCount))
+		}
+	}
+	if len(blocked) > 0 {
+		if jsonMode {
+			return fail(exitError, "conflict", "cannot prune repos that are still bound to workspaces", map[string]any{"blocked": blocked})
+		}
+		fmt.Fprintln(c.Err, "cannot prune repos that are still bound to workspaces:")
+		for _, line := range blocked {
+			fmt.Fprintf(c.Err, "%s- %s\n", uiIndent, line)
+		}
+		return exitError
+	}
+
+	useColorOut := writerSupportsColor(c.Out)
+	if !jsonMode {
+		printRepoApplyPlan(c.Out, manifestPath, plan, opts.Prune, useColorOut)
+	}
+	if opts.DryRun {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:     true,
+				Action: "repo.apply",
+				Result: repoApplyJSONResult(manifestPath, plan, true, nil),
+			})
+		}
+		return exitOK
+	}
+
+	requests := make([]repoPoolAddRequest, 0, len(plan.Add))
+	for _, it := range plan.Add {
+		requests = append(requests, repoPoolAddRequest{RepoSpecInput: it.Spec, DisplayName: it.RepoKey, Filter: it.Filter})
+	}
+	var outcomes []repoPoolAddOutcome
+	if len(requests) > 0 {
+		if jsonMode {
+			outcomes = applyRepoPoolAdds(ctx, session.RepoPoolPath, requests, repoPoolAddDefaultWorkers, c.debugf, nil)
+		} else {
+			outcomes = applyRepoPoolAddsWithProgress(ctx, session.RepoPoolPath, requests, repoPoolAddDefaultWorkers, c.debugf, c.Out, useColorOut)
+		}
+	}
+
+	failed := 0
+	for _, o := range outcomes {
+		if !o.Success {
+			failed++
+		}
+	}
+	if jsonMode {
+		result := repoApplyJSONResult(manifestPath, plan, false, outcomes)
+		if failed > 0 {
+			return fail(exitError, "conflict", fmt.Sprintf("failed to add %d repo(s)", failed), result)
+		}
+		_ = writeCLIJSON(c.Out, cliJSONResponse{OK: true, Action: "repo.apply", Result: result})
+		return exitOK
+	}
+
+	lines := []string{fmt.Sprintf("Added %d / %d", len(outcomes)-failed, len(outcomes))}
+	if opts.Prune {
+		lines = append(lines, fmt.Sprintf("Pruned %d", len(plan.Prune)))
+	}
+	lines = append(lines, fmt.Sprintf("Unchanged %d", len(plan.Keep)))
+	for _, o := range outcomes {
+		if !o.Success {
+			lines = append(lines, fmt.Sprintf("%s %s (reason: %s)", styleError("!", useColorOut), o.RepoKey, o.Reason))
+		}
+	}
+	if useColorOut {
+		if failed > 0 {
+			lines[0] = styleWarn(lines[0], useColorOut)
+		} else {
+			lines[0] = styleSuccess(lines[0], useColorOut)
+		}
+	}
+	printResultSection(c.Out, useColorOut, lines...)
+	if failed > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+func buildRepoApplyPlan(ctx context.Context, root string, repoPoolPath string, manifest repomanifest.Manifest, prune bool) (repoApplyPlan, error) {
+	desired, err := manifest.Resolve()
+	if err != nil {
+		return repoApplyPlan{}, err
+	}
+	registered, err := listRootRepoCandidatesFromFilesystem(ctx, root, repoPoolPath)
+	if err != nil {
+		return repoApplyPlan{}, fmt.Errorf("list repos: %w", err)
+	}
+	registeredUIDs := make(map[string]bool, len(registered))
+	for _, it := range registered {
+		registeredUIDs[it.RepoUID] = true
+	}
+
+	plan := repoApplyPlan{}
+	desiredUIDs := make(map[string]bool, len(desired))
+	for _, it := range desired {
+		desiredUIDs[it.RepoUID] = true
+		if registeredUIDs[it.RepoUID] {
+			plan.Keep = append(plan.Keep, it)
+			continue
+		}
+		plan.Add = append(plan.Add, it)
+	}
+	if prune {
+		for _, it := range registered {
+			if desiredUIDs[it.RepoUID] {
+				continue
+			}
+			plan.Prune = append(plan.Prune, repoApplyPruneItem{
+				RepoUID:           it.RepoUID,
+				RepoKey:           it.RepoKey,
+				WorkspaceRefCount: it.WorkspaceRefCount,
+			})
+		}
+	}
+	return plan, nil
+}
+
+func printRepoApplyPlan(out io.Writer, manifestPath string, plan repoApplyPlan, prune bool, useColor bool) {
+	bullet := styleMuted("•", useColor)
+	body := []string{
+		fmt.Sprintf("%s%s manifest: %s", uiIndent, bullet, manifestPath),
+		fmt.Sprintf("%s%s add %d, keep %d", uiIndent, bullet, len(plan.Add), len(plan.Keep)),
+	}
+	if prune {
+		body[1] += fmt.Sprintf(", prune %d", len(plan.Prune))
+	}
+	rows := make([]string, 0, len(plan.Add)+len(plan.Prune))
+	for _, it := range plan.Add {
+		rows = append(rows, fmt.Sprintf("%s %s", styleSuccess("+", useColor), it.RepoKey))
+	}
+	for _, it := range plan.Prune {
+		rows = append(rows, fmt.Sprintf("%s %s", styleWarn("-", useColor), it.RepoKey))
+	}
+	for i, row := range rows {
+		connector := "├─ "
+		if i == len(rows)-1 {
+			connector = "└─ "
+		}
+		body = append(body, fmt.Sprintf("%s%s%s", uiIndent+uiIndent, styleMuted(connector, useColor), row))
+	}
+	printSection(out, styleBold("Plan:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+}
+
+func repoApplyJSONResult(manifestPath string, plan repoApplyPlan, dryRun bool, outcomes []repoPoolAddOutcome) map[string]any {
+	add := make([]map[string]any, 0, len(plan.Add))
+	for i, it := range plan.Add {
+		item := map[string]any{
+			"repo_key": it.RepoKey,
+			"repo_uid": it.RepoUID,
+			"spec":     it.Spec,
+		}
+		if i < len(outcomes) {
+			item["success"] = outcomes[i].Success
+			item["reason"] = strings.TrimSpace(outcomes[i].Reason)
+		}
+		add = append(add, item)
+	}
+	keep := make([]string, 0, len(plan.Keep))
+	for _, it := range plan.Keep {
+		keep = append(keep, it.RepoKey)
+	}
+	prune := make([]string, 0, len(plan.Prune))
+	for _, it := range plan.Prune {
+		prune = append(prune, it.RepoKey)
+	}
+	added := 0
+	for _, o := range outcomes {
+		if o.Success {
+			added++
+		}
+	}
+	return map[string]any{
+		"manifest": manifestPath,
+		"dry_run":  dryRun,
+		"add":      add,
+		"keep":     keep,
+		"prune":    prune,
+		"added":    added,
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/repomanifest"
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_RepoApply_DryRunApplyPruneAndExport(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		code := New(&out, &err).Run(args)
+		return code, out.String(), err.String()
+	}
+
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+	legacySpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "legacy")
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	if code, _, stderr := run("repo", "add", legacySpec); code != exitOK {
+		t.Fatalf("repo add legacy exit code = %d (stderr=%q)", code, stderr)
+	}
+
+	manifestPath := repomanifest.Path(env.Root)
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	manifestBody := fmt.Sprintf("version: 1\nrepos:\n  - spec: %s\n    alias: api-svc\n    base_ref: main\n    groups: [backend]\n", apiSpec)
+	if err := os.WriteFile(manifestPath, []byte(manifestBody), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	code, out, stderr := run("repo", "apply", "--prune", "--dry-run", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("dry-run exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	resp := decodeJSONResponse(t, out)
+	if resp.Result["dry_run"] != true || !strings.Contains(out, `"repo_key":"example-org/api"`) || !strings.Contains(out, `"prune":["example-org/legacy"]`) {
+		t.Fatalf("unexpected dry-run result: %s", out)
+	}
+	if _, err := os.Stat(filepath.Join(env.RepoPoolPath(), "github.com", "example-org", "api.git")); !os.IsNotExist(err) {
+		t.Fatalf("dry-run must not add repos to pool: err=%v", err)
+	}
+
+	code, out, stderr = run("repo", "apply", "--prune")
+	if code != exitOK {
+		t.Fatalf("apply exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	for _, want := range []string{"Plan:", "+ example-org/api", "- example-org/legacy", "Added 1 / 1", "Pruned 1"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("apply output missing %q: %q", want, out)
+		}
+	}
+
+	code, out, stderr = run("repo", "export")
+	if code != exitOK {
+		t.Fatalf("export exit code = %d (stderr=%q)", code, stderr)
+	}
+	exported, err := repomanifest.Parse([]byte(out))
+	if err != nil {
+		t.Fatalf("exported manifest invalid: %v\n%s", err, out)
+	}
+	api, ok := exported.Lookup("github.com/example-org/api")
+	if !ok || api.Alias != "api-svc" || api.BaseRef != "origin/main" || strings.Join(api.Groups, ",") != "backend" {
+		t.Fatalf("export should keep manifest metadata for api: %+v (ok=%t)\n%s", api, ok, out)
+	}
+	if _, ok := exported.Lookup("github.com/example-org/legacy"); !ok {
+		t.Fatalf("export should list every registered repo:\n%s", out)
+	}
+}
+
+func TestCLI_RepoApply_MissingManifest(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	code := New(&out, &err).Run([]string{"repo", "apply", "--format", "json"})
+	if code != exitError {
+		t.Fatalf("exit code = %d, want %d", code, exitError)
+	}
+	resp := decodeJSONResponse(t, out.String())
+	if resp.OK || resp.Error.Code != "not_found" || !strings.Contains(resp.Error.Message, ".kra/repos.yaml") {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/tasuku43/kra/internal/app/repocmd"
 	"github.com/tasuku43/kra/internal/infra/appports"
@@ -21,13 +22,26 @@ var promptRepoDiscoverSelection = func(c *CLI, candidates []workspaceSelectorCan
 type repoDiscoverOptions struct {
 	Org      string
 	Provider string
+	Filter   repodiscovery.Filter
+	All      bool
+	Yes      bool
+	Format   string
 }
 
 func parseRepoDiscoverOptions(args []string) (repoDiscoverOptions, error) {
-	opts := repoDiscoverOptions{Provider: "github"}
+	opts := repoDiscoverOptions{Provider: "github", Format: "human"}
 	rest := append([]string{}, args...)
+	takeValue := func(flag string) (string, error) {
+		if len(rest) < 2 {
+			return "", fmt.Errorf("%s requires a value", flag)
+		}
+		v := strings.TrimSpace(rest[1])
+		rest = rest[2:]
+		return v, nil
+	}
 	for len(rest) > 0 {
 		arg := rest[0]
+		var err error
 		switch {
 		case arg == "-h" || arg == "--help" || arg == "help":
 			return repoDiscoverOptions{}, errHelpRequested
@@ -35,23 +49,57 @@ func parseRepoDiscoverOptions(args []string) (repoDiscoverOptions, error) {
 			opts.Org = strings.TrimSpace(strings.TrimPrefix(arg, "--org="))
 			rest = rest[1:]
 		case arg == "--org":
-			if len(rest) < 2 {
-				return repoDiscoverOptions{}, fmt.Errorf("--org requires a value")
-			}
-			opts.Org = strings.TrimSpace(rest[1])
-			rest = rest[2:]
+			opts.Org, err = takeValue(arg)
 		case strings.HasPrefix(arg, "--provider="):
 			opts.Provider = strings.TrimSpace(strings.TrimPrefix(arg, "--provider="))
 			rest = rest[1:]
 		case arg == "--provider":
-			if len(rest) < 2 {
-				return repoDiscoverOptions{}, fmt.Errorf("--provider requires a value")
-			}
-			opts.Provider = strings.TrimSpace(rest[1])
-			rest = rest[2:]
+			opts.Provider, err = takeValue(arg)
+		case strings.HasPrefix(arg, "--topic="):
+			opts.Filter.Topics = appendRepoDiscoverTopics(opts.Filter.Topics, strings.TrimPrefix(arg, "--topic="))
+			rest = rest[1:]
+		case arg == "--topic":
+			var v string
+			v, err = takeValue(arg)
+			opts.Filter.Topics = appendRepoDiscoverTopics(opts.Filter.Topics, v)
+		case strings.HasPrefix(arg, "--language="):
+			opts.Filter.Language = strings.TrimSpace(strings.TrimPrefix(arg, "--language="))
+			rest = rest[1:]
+		case arg == "--language":
+			opts.Filter.Language, err = takeValue(arg)
+		case strings.HasPrefix(arg, "--match="):
+			opts.Filter.Match = strings.TrimSpace(strings.TrimPrefix(arg, "--match="))
+			rest = rest[1:]
+		case arg == "--match":
+			opts.Filter.Match, err = takeValue(arg)
+		case arg == "--exclude-archived":
+			opts.Filter.ExcludeArchived = true
+			rest = rest[1:]
+		case arg == "--exclude-forks":
+			opts.Filter.ExcludeForks = true
+			rest = rest[1:]
+		case arg == "--all":
+			opts.All = true
+			rest = rest[1:]
+		case arg == "--yes":
+			opts.Yes = true
+			rest = rest[1:]
+		case strings.HasPrefix(arg, "--format="):
+			opts.Format = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+			rest = rest[1:]
+		case arg == "--format":
+			opts.Format, err = takeValue(arg)
 		default:
 			return repoDiscoverOptions{}, fmt.Errorf("unknown flag for repo discover: %q", arg)
 		}
+		if err != nil {
+			return repoDiscoverOptions{}, err
+		}
+	}
+	switch opts.Format {
+	case "human", "json":
+	default:
+		return repoDiscoverOptions{}, fmt.Errorf("unsupported --format: %q (supported: human, json)", opts.Format)
 	}
 	if opts.Org == "" {
 		return repoDiscoverOptions{}, fmt.Errorf("--org is required")
@@ -59,9 +107,25 @@ func parseRepoDiscoverOptions(args []string) (repoDiscoverOptions, error) {
 	if opts.Provider == "" {
 		opts.Provider = "github"
 	}
+	if err := opts.Filter.Validate(); err != nil {
+		return repoDiscoverOptions{}, err
+	}
+	if opts.Yes && !opts.All {
+		return repoDiscoverOptions{}, fmt.Errorf("--yes requires --all")
+	}
 	return opts, nil
 }
 
+// appendRepoDiscoverTopics accepts repeated --topic flags and comma-separated values.
+func appendRepoDiscoverTopics(topics []string, raw string) []string {
+	for _, t := range strings.Split(raw, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			topics = append(topics, t)
+		}
+	}
+	return topics
+}
+
 func (c *CLI) runRepoDiscover(args []string) int {
 	opts, err := parseRepoDiscoverOptions(args)
 	if err != nil {
@@ -73,17 +137,34 @@ func (c *CLI) runRepoDiscover(args []string) int {
 		c.printRepoDiscoverUsage(c.Err)
 		return exitUsage
 	}
+	jsonMode := opts.Format == "json"
+	fail := func(code int, errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:     false,
+				Action: "repo.discover",
+				Error:  &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+	if jsonMode && !opts.All {
+		return fail(exitUsage, "invalid_argument", "repo discover --format json requires --all")
+	}
+	if jsonMode && !opts.Yes {
+		return fail(exitUsage, "invalid_argument", "--yes is required in --format json mode")
+	}
 
 	provider, err := newRepoDiscoveryProvider(opts.Provider)
 	if err != nil {
-		fmt.Fprintf(c.Err, "load provider: %v\n", err)
-		return exitUsage
+		return fail(exitUsage, "invalid_argument", fmt.Sprintf("load provider: %v", err))
 	}
 
 	wd, err := os.Getwd()
 	if err != nil {
-		fmt.Fprintf(c.Err, "get working dir: %v\n", err)
-		return exitError
+		return fail(exitError, "internal_error", fmt.Sprintf("get working dir: %v", err))
 	}
 	ctx := context.Background()
 	repoUC := repocmd.NewService(appports.NewRepoPort(c.ensureDebugLog, c.touchStateRegistry))
@@ -94,62 +175,72 @@ func (c *CLI) runRepoDiscover(args []string) int {
 		TouchRegistry: true,
 	})
 	if err != nil {
-		fmt.Fprintf(c.Err, "%v\n", err)
-		return exitError
+		return fail(exitError, "internal_error", err.Error())
 	}
-	c.debugf("run repo discover org=%s provider=%s", opts.Org, provider.Name())
+	c.debugf("run repo discover org=%s provider=%s all=%t", opts.Org, provider.Name(), opts.All)
 
 	if err := provider.CheckAuth(ctx); err != nil {
-		fmt.Fprintf(c.Err, "provider auth: %v\n", err)
-		return exitError
+		return fail(exitError, "auth_failed", fmt.Sprintf("provider auth: %v", err))
 	}
 
 	discovered, err := provider.ListOrgRepos(ctx, opts.Org)
 	if err != nil {
-		fmt.Fprintf(c.Err, "discover repos: %v\n", err)
-		return exitError
+		return fail(exitError, "internal_error", fmt.Sprintf("discover repos: %v", err))
 	}
+	filtered := repodiscovery.FilterRepos(discovered, opts.Filter)
+	c.debugf("repo discover discovered=%d filtered=%d", len(discovered), len(filtered))
 
 	existingRepoUIDs, err := listRepoUIDsFromRepoPool(ctx, session.RepoPoolPath)
 	if err != nil {
-		fmt.Fprintf(c.Err, "list existing repos from pool: %v\n", err)
-		return exitError
+		return fail(exitError, "internal_error", fmt.Sprintf("list existing repos from pool: %v", err))
 	}
 	existingSet := map[string]bool{}
 	for _, repoUID := range existingRepoUIDs {
 		existingSet[repoUID] = true
 	}
 
-	candidates := make([]repodiscovery.Repo, 0, len(discovered))
-	for _, r := range discovered {
+	candidates := make([]repodiscovery.Repo, 0, len(filtered))
+	for _, r := range filtered {
 		if existingSet[r.RepoUID] {
 			continue
 		}
 		candidates = append(candidates, r)
 	}
+	if len(candidates) == 0 && jsonMode {
+		// Automation treats "everything is already registered" as success (idempotent --all).
+		return c.writeRepoDiscoverJSON(opts, len(discovered), len(filtered), nil, nil, nil)
+	}
 	if len(candidates) == 0 {
-		fmt.Fprintf(c.Err, "no undiscovered repos found for org: %s\n", opts.Org)
-		return exitError
+		return fail(exitError, "not_found", fmt.Sprintf("no undiscovered repos found for org: %s", opts.Org))
 	}
 
-	selectorCandidates := make([]workspaceSelectorCandidate, 0, len(candidates))
 	repoByKey := make(map[string]repodiscovery.Repo, len(candidates))
 	for _, cand := range candidates {
-		selectorCandidates = append(selectorCandidates, workspaceSelectorCandidate{
-			ID:    cand.RepoKey,
-			Title: "",
-		})
 		repoByKey[cand.RepoKey] = cand
 	}
 
-	selectedIDs, err := promptRepoDiscoverSelection(c, selectorCandidates)
-	if err != nil {
-		if errors.Is(err, errSelectorCanceled) {
-			fmt.Fprintln(c.Err, "aborted")
+	var selectedIDs []string
+	if opts.All {
+		for _, cand := range candidates {
+			selectedIDs = append(selectedIDs, cand.RepoKey)
+		}
+	} else {
+		selectorCandidates := make([]workspaceSelectorCandidate, 0, len(candidates))
+		for _, cand := range candidates {
+			selectorCandidates = append(selectorCandidates, workspaceSelectorCandidate{
+				ID:    cand.RepoKey,
+				Title: formatRepoDiscoverMetadata(cand),
+			})
+		}
+		selectedIDs, err = promptRepoDiscoverSelection(c, selectorCandidates)
+		if err != nil {
+			if errors.Is(err, errSelectorCanceled) {
+				fmt.Fprintln(c.Err, "aborted")
+				return exitError
+			}
+			fmt.Fprintf(c.Err, "select repos: %v\n", err)
 			return exitError
 		}
-		fmt.Fprintf(c.Err, "select repos: %v\n", err)
-		return exitError
 	}
 	if len(selectedIDs) == 0 {
 		fmt.Fprintln(c.Err, "aborted")
@@ -162,13 +253,36 @@ func (c *CLI) runRepoDiscover(args []string) int {
 		if !ok {
 			continue
 		}
-		requests = append(requests, repoPoolAddRequest{RepoSpecInput: repo.RemoteURL, DisplayName: repo.RepoKey})
+		requests = append(requests, repoPoolAddRequest{RepoSpecInput: repo.RemoteURL, DisplayName: repo.RepoKey, CloneURL: repo.CloneURL})
 	}
 	if len(requests) == 0 {
 		fmt.Fprintln(c.Err, "aborted")
 		return exitError
 	}
+
+	if jsonMode {
+		outcomes := applyRepoPoolAdds(ctx, session.RepoPoolPath, requests, repoPoolAddDefaultWorkers, c.debugf, nil)
+		return c.writeRepoDiscoverJSON(opts, len(discovered), len(filtered), requests, repoByKey, outcomes)
+	}
+
 	useColorOut := writerSupportsColor(c.Out)
+	if opts.All {
+		printRepoPoolSection(c.Out, requests, useColorOut)
+		if !opts.Yes {
+			line, err := c.promptLine(fmt.Sprintf("%sadd %d repo(s) to pool? (y/N): ", uiIndent, len(requests)))
+			if err != nil {
+				fmt.Fprintf(c.Err, "read confirmation: %v\n", err)
+				return exitError
+			}
+			switch strings.ToLower(strings.TrimSpace(line)) {
+			case "y", "yes":
+			default:
+				fmt.Fprintln(c.Err, "aborted")
+				return exitError
+			}
+		}
+		fmt.Fprintln(c.Out)
+	}
 	outcomes := applyRepoPoolAddsWithProgress(ctx, session.RepoPoolPath, requests, repoPoolAddDefaultWorkers, c.debugf, c.Out, useColorOut)
 	printRepoPoolAddResult(c.Out, outcomes, useColorOut)
 	if repoPoolAddHadFailure(outcomes) {
@@ -177,6 +291,76 @@ func (c *CLI) runRepoDiscover(args []string) int {
 	return exitOK
 }
 
+func (c *CLI) writeRepoDiscoverJSON(opts repoDiscoverOptions, discovered int, filtered int, requests []repoPoolAddRequest, repoByKey map[string]repodiscovery.Repo, outcomes []repoPoolAddOutcome) int {
+	items := make([]map[string]any, 0, len(outcomes))
+	success := 0
+	for i, o := range outcomes {
+		if o.Success {
+			success++
+		}
+		repo := repoByKey[requests[i].DisplayName]
+		item := map[string]any{
+			"repo_key":   repo.RepoKey,
+			"repo_uid":   repo.RepoUID,
+			"remote_url": repo.RemoteURL,
+			"archived":   repo.Archived,
+			"fork":       repo.Fork,
+			"topics":     repo.Topics,
+			"language":   repo.Language,
+			"success":    o.Success,
+			"reason":     strings.TrimSpace(o.Reason),
+		}
+		if repo.DefaultBranch != "" {
+			item["default_branch"] = repo.DefaultBranch
+		}
+		if !repo.PushedAt.IsZero() {
+			item["pushed_at"] = repo.PushedAt.UTC().Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+	result := map[string]any{
+		"provider":   opts.Provider,
+		"org":        opts.Org,
+		"discovered": discovered,
+		"filtered":   filtered,
+		"added":      success,
+		"total":      len(outcomes),
+		"items":      items,
+	}
+	if success == len(outcomes) {
+		_ = writeCLIJSON(c.Out, cliJSONResponse{OK: true, Action: "repo.discover", Result: result})
+		return exitOK
+	}
+	_ = writeCLIJSON(c.Out, cliJSONResponse{
+		OK:     false,
+		Action: "repo.discover",
+		Result: result,
+		Error: &cliJSONError{
+			Code:    "conflict",
+			Message: fmt.Sprintf("failed to add %d repo(s)", len(outcomes)-success),
+		},
+	})
+	return exitError
+}
+
+// formatRepoDiscoverMetadata renders a short selector title such as "Go · archived · fork".
+func formatRepoDiscoverMetadata(r repodiscovery.Repo) string {
+	parts := make([]string, 0, 4)
+	if r.Language != "" {
+		parts = append(parts, r.Language)
+	}
+	if r.Archived {
+		parts = append(parts, "archived")
+	}
+	if r.Fork {
+		parts = append(parts, "fork")
+	}
+	if len(r.Topics) > 0 {
+		parts = append(parts, "#"+strings.Join(r.Topics, " #"))
+	}
+	return strings.Join(parts, " · ")
+}
+
 func listRepoUIDsFromRepoPool(ctx context.Context, repoPoolPath string) ([]string, error) {
 	bareRepos, err := listRepoPoolBareRepos(repoPoolPath)
 	if err != nil {
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/app/repocmd"
+	"github.com/tasuku43/kra/internal/infra/appports"
+	"github.com/tasuku43/kra/internal/repomanifest"
+)
+
+func (c *CLI) runRepoExport(args []string) int {
+	output := ""
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			c.printRepoExportUsage(c.Out)
+			return exitOK
+		case arg == "-o" || arg == "--output":
+			if i+1 >= len(args) {
+				fmt.Fprintf(c.Err, "%s requires a value\n", arg)
+				c.printRepoExportUsage(c.Err)
+				return exitUsage
+			}
+			output = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimSpace(strings.TrimPrefix(arg, "--output="))
+		default:
+			fmt.Fprintf(c.Err, "unknown flag for repo export: %q\n", arg)
+			c.printRepoExportUsage(c.Err)
+			return exitUsage
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(c.Err, "get working dir: %v\n", err)
+		return exitError
+	}
+	ctx := context.Background()
+	repoUC := repocmd.NewService(appports.NewRepoPort(c.ensureDebugLog, c.touchStateRegistry))
+	session, err := repoUC.Run(ctx, repocmd.Request{
+		CWD:        wd,
+		DebugTag:   "repo-export",
+		RequireGit: true,
+	})
+	if err != nil {
+		fmt.Fprintf(c.Err, "%v\n", err)
+		return exitError
+	}
+
+	manifest, err := buildRepoExportManifest(ctx, session.Root, session.RepoPoolPath)
+	if err != nil {
+		fmt.Fprintf(c.Err, "%v\n", err)
+		return exitError
+	}
+	b, err := repomanifest.Render(manifest)
+	if err != nil {
+		fmt.Fprintf(c.Err, "%v\n", err)
+		return exitError
+	}
+	if output == "" || output == "-" {
+		_, _ = c.Out.Write(b)
+		return exitOK
+	}
+
+	target := output
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(wd, target)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		fmt.Fprintf(c.Err, "create manifest dir: %v\n", err)
+		return exitError
+	}
+	if err := os.WriteFile(target, b, 0o644); err != nil {
+		fmt.Fprintf(c.Err, "write repo manifest: %v\n", err)
+		return exitError
+	}
+	useColorOut := writerSupportsColor(c.Out)
+	printResultSection(c.Out, useColorOut, fmt.Sprintf("Exported %d repo(s) to %s", len(manifest.Repos), target))
+	return exitOK
+}
+
+// buildRepoExportManifest lists registered repos and keeps alias/base_ref/groups
+// (and the original spec) from the existing root manifest. Partial clone filters come from the bare repos.
+func buildRepoExportManifest(ctx context.Context, root string, repoPoolPath string) (repomanifest.Manifest, error) {
+	existing, _, err := repomanifest.LoadOptional(repomanifest.Path(root))
+	if err != nil {
+		return repomanifest.Manifest{}, fmt.Errorf("load repo manifest: %w", err)
+	}
+	registered, err := listRootRepoCandidatesFromFilesystem(ctx, root, repoPoolPath)
+	if err != nil {
+		return repomanifest.Manifest{}, fmt.Errorf("list repos: %w", err)
+	}
+
+	barePaths, err := listRepoPoolBarePathsByUID(ctx, repoPoolPath)
+	if err != nil {
+		return repomanifest.Manifest{}, fmt.Errorf("list repos: %w", err)
+	}
+
+	out := repomanifest.Manifest{Version: repomanifest.CurrentVersion, Repos: make([]repomanifest.Entry, 0, len(registered))}
+	for _, it := range registered {
+		entry, ok := existing.Lookup(it.RepoUID)
+		if !ok {
+			entry = repomanifest.Entry{Spec: it.RemoteURL}
+		}
+		if entry.Filter == "" {
+			entry.Filter = repoPoolCloneFilter(ctx, barePaths[it.RepoUID])
+		}
+		if strings.TrimSpace(entry.Spec) == "" {
+			continue
+		}
+		out.Repos = append(out.Repos, entry)
+	}
+	return out, nil
+}
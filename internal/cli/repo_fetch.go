@@ -0,0 +1,360 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tasuku43/kra/internal/app/repocmd"
+	"github.com/tasuku43/kra/internal/infra/appports"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+)
+
+const (
+	repoFetchDefaultParallel = 4
+	repoFetchDefaultInterval = 10 * time.Minute
+)
+
+type repoFetchOptions struct {
+	All      bool
+	Parallel int
+	Watch    bool
+	Interval time.Duration
+	Format   string
+	Targets  []string
+}
+
+type repoFetchTarget struct {
+	RepoUID  string
+	RepoKey  string
+	BarePath string
+}
+
+type repoFetchResult struct {
+	Target        repoFetchTarget
+	Err           error
+	LastFetchedAt time.Time
+}
+
+func parseRepoFetchOptions(args []string) (repoFetchOptions, error) {
+	opts := repoFetchOptions{Parallel: repoFetchDefaultParallel, Format: "human"}
+	intervalSet := false
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			return repoFetchOptions{}, errHelpRequested
+		case arg == "--all":
+			opts.All = true
+		case arg == "--watch":
+			opts.Watch = true
+		case arg == "--parallel" || strings.HasPrefix(arg, "--parallel="):
+			raw := strings.TrimPrefix(arg, "--parallel=")
+			if arg == "--parallel" {
+				if i+1 >= len(args) {
+					return repoFetchOptions{}, fmt.Errorf("--parallel requires a value")
+				}
+				raw = args[i+1]
+				i++
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil || n < 1 {
+				return repoFetchOptions{}, fmt.Errorf("invalid --parallel: %q (must be >= 1)", raw)
+			}
+			opts.Parallel = n
+		case arg == "--interval" || strings.HasPrefix(arg, "--interval="):
+			raw := strings.TrimPrefix(arg, "--interval=")
+			if arg == "--interval" {
+				if i+1 >= len(args) {
+					return repoFetchOptions{}, fmt.Errorf("--interval requires a value")
+				}
+				raw = args[i+1]
+				i++
+			}
+			d, err := time.ParseDuration(strings.TrimSpace(raw))
+			if err != nil || d < time.Minute {
+				return repoFetchOptions{}, fmt.Errorf("invalid --interval: %q (duration >= 1m, e.g. 10m)", raw)
+			}
+			opts.Interval = d
+			intervalSet = true
+		case arg == "--format":
+			if i+1 >= len(args) {
+				return repoFetchOptions{}, fmt.Errorf("--format requires a value")
+			}
+			opts.Format = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			opts.Format = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case strings.HasPrefix(arg, "-"):
+			return repoFetchOptions{}, fmt.Errorf("unknown flag for repo fetch: %q", arg)
+		default:
+			opts.Targets = append(opts.Targets, arg)
+		}
+	}
+	switch opts.Format {
+	case "human", "json":
+	default:
+		return repoFetchOptions{}, fmt.Errorf("unsupported --format: %q (supported: human, json)", opts.Format)
+	}
+	if opts.All && len(opts.Targets) > 0 {
+		return repoFetchOptions{}, fmt.Errorf("--all cannot be combined with repo args")
+	}
+	if intervalSet && !opts.Watch {
+		return repoFetchOptions{}, fmt.Errorf("--interval requires --watch")
+	}
+	if opts.Watch && opts.Format == "json" {
+		return repoFetchOptions{}, fmt.Errorf("--watch cannot be combined with --format json")
+	}
+	if opts.Interval == 0 {
+		opts.Interval = repoFetchDefaultInterval
+	}
+	return opts, nil
+}
+
+func (c *CLI) runRepoFetch(args []string) int {
+	opts, err := parseRepoFetchOptions(args)
+	if err != nil {
+		if errors.Is(err, errHelpRequested) {
+			c.printRepoFetchUsage(c.Out)
+			return exitOK
+		}
+		fmt.Fprintf(c.Err, "%v\n", err)
+		c.printRepoFetchUsage(c.Err)
+		return exitUsage
+	}
+	jsonMode := opts.Format == "json"
+	fail := func(code int, errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:     false,
+				Action: "repo.fetch",
+				Error:  &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("get working dir: %v", err))
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	repoUC := repocmd.NewService(appports.NewRepoPort(c.ensureDebugLog, c.touchStateRegistry))
+	session, err := repoUC.Run(ctx, repocmd.Request{
+		CWD:        wd,
+		DebugTag:   "repo-fetch",
+		RequireGit: true,
+	})
+	if err != nil {
+		return fail(exitError, "internal_error", err.Error())
+	}
+	c.debugf("run repo fetch all=%t parallel=%d watch=%t interval=%s targets=%v", opts.All, opts.Parallel, opts.Watch, opts.Interval, opts.Targets)
+
+	useColor := writerSupportsColor(c.Out)
+	for {
+		// Targets are resolved per cycle so --watch picks up repos added to the pool meanwhile.
+		targets, err := resolveRepoFetchTargets(ctx, session.Root, session.RepoPoolPath, opts)
+		if err != nil {
+			if !opts.Watch {
+				if errors.Is(err, errRepoFetchTargetNotFound) {
+					return fail(exitError, "not_found", err.Error())
+				}
+				return fail(exitError, "internal_error", err.Error())
+			}
+			// A transient failure (e.g. pool being rewritten) must not end a long-running watch.
+			fmt.Fprintf(c.Err, "resolve repos: %v (retrying in %s)\n", err, opts.Interval)
+			c.debugf("repo fetch watch resolve failed: %v", err)
+			if !repoFetchWatchWait(ctx, opts.Interval) {
+				return exitOK
+			}
+			continue
+		}
+		results := runRepoFetchTargets(ctx, targets, opts.Parallel)
+		failed := 0
+		for _, r := range results {
+			if r.Err != nil {
+				failed++
+			}
+		}
+		if jsonMode {
+			resp := cliJSONResponse{
+				OK:     failed == 0,
+				Action: "repo.fetch",
+				Result: repoFetchJSONResult(results),
+			}
+			if failed > 0 {
+				resp.Error = &cliJSONError{Code: "internal_error", Message: fmt.Sprintf("failed to fetch %d repo(s)", failed)}
+			}
+			_ = writeCLIJSON(c.Out, resp)
+		} else {
+			printRepoFetchResult(c.Out, results, opts.Watch, time.Now(), useColor)
+		}
+		if !opts.Watch {
+			if failed > 0 {
+				return exitError
+			}
+			return exitOK
+		}
+		if !repoFetchWatchWait(ctx, opts.Interval) {
+			return exitOK
+		}
+	}
+}
+
+// repoFetchWatchWait blocks until the next --watch cycle and reports false when interrupted.
+var repoFetchWatchWait = func(ctx context.Context, interval time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(interval):
+		return true
+	}
+}
+
+var errRepoFetchTargetNotFound = errors.New("repo not found")
+
+// resolveRepoFetchTargets returns current-root repos (default), explicit args, or every bare repo in the pool (--all).
+func resolveRepoFetchTargets(ctx context.Context, root string, repoPoolPath string, opts repoFetchOptions) ([]repoFetchTarget, error) {
+	targets := make([]repoFetchTarget, 0, 16)
+	if opts.All {
+		bareRepos, err := listRepoPoolBareRepos(repoPoolPath)
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		for _, barePath := range bareRepos {
+			remoteURL, _ := gitutil.RunBare(ctx, barePath, "config", "--get", "remote.origin.url")
+			repoUID, repoKey, ok := resolveRepoIdentityForGC(repoPoolPath, barePath, strings.TrimSpace(remoteURL))
+			if !ok || seen[repoUID] {
+				continue
+			}
+			seen[repoUID] = true
+			targets = append(targets, repoFetchTarget{RepoUID: repoUID, RepoKey: repoKey, BarePath: barePath})
+		}
+	} else {
+		repos, err := listRootRepoCandidatesFromFilesystem(ctx, root, repoPoolPath)
+		if err != nil {
+			return nil, err
+		}
+		barePaths, err := listRepoPoolBarePathsByUID(ctx, repoPoolPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range repos {
+			if barePaths[r.RepoUID] == "" {
+				continue
+			}
+			targets = append(targets, repoFetchTarget{RepoUID: r.RepoUID, RepoKey: r.RepoKey, BarePath: barePaths[r.RepoUID]})
+		}
+		if len(opts.Targets) > 0 {
+			selected := make([]repoFetchTarget, 0, len(opts.Targets))
+			for _, want := range opts.Targets {
+				idx := slices.IndexFunc(targets, func(t repoFetchTarget) bool { return t.RepoKey == want || t.RepoUID == want })
+				if idx < 0 {
+					return nil, fmt.Errorf("%w: %s", errRepoFetchTargetNotFound, want)
+				}
+				if !slices.ContainsFunc(selected, func(t repoFetchTarget) bool { return t.RepoUID == targets[idx].RepoUID }) {
+					selected = append(selected, targets[idx])
+				}
+			}
+			return selected, nil
+		}
+	}
+	slices.SortFunc(targets, func(a, b repoFetchTarget) int { return strings.Compare(a.RepoKey, b.RepoKey) })
+	return targets, nil
+}
+
+// runRepoFetchTargets fetches targets with at most parallel concurrent fetches; results keep target order.
+func runRepoFetchTargets(ctx context.Context, targets []repoFetchTarget, parallel int) []repoFetchResult {
+	results := make([]repoFetchResult, len(targets))
+	sem := make(chan struct{}, max(parallel, 1))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t repoFetchTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			err := runAddRepoFetchWithPolicy(ctx, t.BarePath)
+			results[i] = repoFetchResult{Target: t, Err: err, LastFetchedAt: repoPoolLastFetchedAt(t.BarePath)}
+		}(i, t)
+	}
+	wg.Wait()
+	return results
+}
+
+func repoFetchJSONResult(results []repoFetchResult) map[string]any {
+	items := make([]map[string]any, 0, len(results))
+	fetched := 0
+	for _, r := range results {
+		item := map[string]any{
+			"repo_uid":        r.Target.RepoUID,
+			"repo_key":        r.Target.RepoKey,
+			"ok":              r.Err == nil,
+			"last_fetched_at": formatRepoLastFetchRFC3339(r.LastFetchedAt),
+		}
+		if r.Err != nil {
+			item["error"] = r.Err.Error()
+		} else {
+			fetched++
+		}
+		items = append(items, item)
+	}
+	return map[string]any{
+		"fetched": fetched,
+		"total":   len(results),
+		"items":   items,
+	}
+}
+
+func printRepoFetchResult(out io.Writer, results []repoFetchResult, watch bool, now time.Time, useColor bool) {
+	fetched := 0
+	for _, r := range results {
+		if r.Err == nil {
+			fetched++
+		}
+	}
+	summary := fmt.Sprintf("Fetched %d / %d", fetched, len(results))
+	if useColor {
+		switch {
+		case fetched == len(results):
+			summary = styleSuccess(summary, useColor)
+		case fetched == 0:
+			summary = styleError(summary, useColor)
+		default:
+			summary = styleWarn(summary, useColor)
+		}
+	}
+	if watch {
+		summary += " " + styleMuted("("+now.Format("15:04:05")+")", useColor)
+	}
+	lines := []string{summary}
+	for _, r := range results {
+		if r.Err != nil {
+			prefix := "!"
+			if useColor {
+				prefix = styleError(prefix, useColor)
+			}
+			lines = append(lines, fmt.Sprintf("%s %s (%v)", prefix, r.Target.RepoKey, r.Err))
+			continue
+		}
+		prefix := "✔"
+		if useColor {
+			prefix = styleSuccess(prefix, useColor)
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", prefix, r.Target.RepoKey))
+	}
+	printResultSection(out, useColor, lines...)
+}
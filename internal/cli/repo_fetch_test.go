@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
+	"github.com/tasuku43/kra/internal/core/repostore"
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_RepoFetch_RecordsLastFetchReusedByAddRepo(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		code := New(&out, &err).Run(args)
+		return code, out.String(), err.String()
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	docsSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "docs")
+	if code, _, stderr := run("repo", "add", apiSpec, docsSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	spec, err := repospec.Normalize(apiSpec)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	barePath := repostore.StorePath(env.RepoPoolPath(), spec)
+	if _, err := os.Stat(filepath.Join(barePath, repoPoolLastFetchFilename)); err != nil {
+		t.Fatalf("repo add should record the fetch: %v", err)
+	}
+	if code, _, stderr := run("repo", "add", apiSpec); code != exitOK {
+		t.Fatalf("repo add (existing) exit code = %d (stderr=%q)", code, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(barePath, repoPoolFetchLockFilename)); !os.IsNotExist(err) {
+		t.Fatalf("fetch lock should be released after repo add, stat err=%v", err)
+	}
+
+	code, out, stderr := run("repo", "fetch", "--format", "json", "--parallel", "2")
+	if code != exitOK {
+		t.Fatalf("repo fetch exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	resp := decodeJSONResponse(t, out)
+	if !resp.OK || resp.Result["fetched"] != float64(2) || resp.Result["total"] != float64(2) {
+		t.Fatalf("repo fetch json = %s", out)
+	}
+
+	if _, err := os.Stat(filepath.Join(barePath, repoPoolLastFetchFilename)); err != nil {
+		t.Fatalf("last fetch marker not recorded: %v", err)
+	}
+	decision, err := evaluateAddRepoFetchDecision(context.Background(), addRepoPlanItem{
+		Candidate:   addRepoPoolCandidate{RepoKey: "example-org/api", BarePath: barePath},
+		BaseRefUsed: "origin/main",
+		Branch:      "WS1",
+	}, addRepoFetchOptions{})
+	if err != nil || decision.ShouldFetch {
+		t.Fatalf("decision after repo fetch = %+v, %v; want fresh skip", decision, err)
+	}
+
+	code, out, _ = run("repo", "fetch", "--format", "json", "example-org/missing")
+	if code != exitError || decodeJSONResponse(t, out).Error.Code != "not_found" {
+		t.Fatalf("repo fetch missing = %d %q", code, out)
+	}
+}
+
+func TestCLI_RepoFetch_WatchRetriesResolveFailure(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+
+	waits := 0
+	origWait := repoFetchWatchWait
+	repoFetchWatchWait = func(ctx context.Context, interval time.Duration) bool {
+		waits++
+		return waits < 2
+	}
+	t.Cleanup(func() { repoFetchWatchWait = origWait })
+
+	var out bytes.Buffer
+	var errBuf bytes.Buffer
+	code := New(&out, &errBuf).Run([]string{"repo", "fetch", "--watch", "example-org/missing"})
+	if code != exitOK {
+		t.Fatalf("repo fetch --watch exit code = %d, want %d (stderr=%q)", code, exitOK, errBuf.String())
+	}
+	if waits != 2 {
+		t.Fatalf("watch cycles = %d, want 2 (resolve failure should be retried)", waits)
+	}
+	if got := strings.Count(errBuf.String(), "resolve repos:"); got != 2 {
+		t.Fatalf("resolve failure should be logged per cycle, got %d: %q", got, errBuf.String())
+	}
+}
+
+func TestRunAddRepoFetchWithPolicy_ConcurrentCallsShareOneFetch(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	src := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = src
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v (%s)", args, err, out)
+		}
+	}
+	barePath := filepath.Join(t.TempDir(), "repo.git")
+	if out, err := exec.Command("git", "clone", "--bare", src, barePath).CombinedOutput(); err != nil {
+		t.Fatalf("clone --bare: %v (%s)", err, out)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = runAddRepoFetchWithPolicy(context.Background(), barePath)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("fetch %d: %v", i, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(barePath, repoPoolFetchLockFilename)); !os.IsNotExist(err) {
+		t.Fatalf("fetch lock should be released, stat err=%v", err)
+	}
+	if repoPoolLastFetchedAt(barePath).IsZero() {
+		t.Fatalf("last fetch should be recorded")
+	}
+}
+
+func TestRepoPoolLastFetchedAt_ReturnsLaterOfRecordAndFetchHead(t *testing.T) {
+	barePath := t.TempDir()
+	fetchHead := filepath.Join(barePath, "FETCH_HEAD")
+	if err := os.WriteFile(fetchHead, nil, 0o644); err != nil {
+		t.Fatalf("write FETCH_HEAD: %v", err)
+	}
+	older := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	newer := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+
+	if err := recordRepoPoolFetch(barePath, older); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if err := os.Chtimes(fetchHead, newer, newer); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if got := repoPoolLastFetchedAt(barePath); !got.Equal(newer) {
+		t.Fatalf("last fetched = %v, want newer FETCH_HEAD mtime %v", got, newer)
+	}
+
+	if err := os.Chtimes(fetchHead, older, older); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if err := recordRepoPoolFetch(barePath, newer); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if got := repoPoolLastFetchedAt(barePath); !got.Equal(newer) {
+		t.Fatalf("last fetched = %v, want newer record %v", got, newer)
+	}
+}
+
+func TestEnsureRepoPoolFetched_LocksExistingBareAndRecords(t *testing.T) {
+	barePath := t.TempDir()
+	lockPath := filepath.Join(barePath, repoPoolFetchLockFilename)
+	_, err := ensureRepoPoolFetched(context.Background(), barePath, func() (string, error) {
+		if _, err := os.Stat(lockPath); err != nil {
+			t.Fatalf("fetch should run under the pool fetch lock: %v", err)
+		}
+		return "origin/main", nil
+	})
+	if err != nil {
+		t.Fatalf("ensureRepoPoolFetched: %v", err)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("fetch lock should be released, stat err=%v", err)
+	}
+	if repoPoolLastFetchedAt(barePath).IsZero() {
+		t.Fatalf("fetch should be recorded")
+	}
+
+	newBare := filepath.Join(t.TempDir(), "new.git")
+	if _, err := ensureRepoPoolFetched(context.Background(), newBare, func() (string, error) {
+		return "origin/main", os.Mkdir(newBare, 0o755)
+	}); err != nil {
+		t.Fatalf("ensureRepoPoolFetched (clone): %v", err)
+	}
+	if repoPoolLastFetchedAt(newBare).IsZero() {
+		t.Fatalf("clone should be recorded")
+	}
+}
+
+func TestAcquireRepoPoolFetchLock_RecoversStaleLock(t *testing.T) {
+	barePath := t.TempDir()
+	lockPath := filepath.Join(barePath, repoPoolFetchLockFilename)
+	if err := os.WriteFile(lockPath, []byte("pid=999999999\n"), 0o644); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	release, err := acquireRepoPoolFetchLock(ctx, barePath)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	release()
+}
+
+func TestParseRepoFetchOptions_Validation(t *testing.T) {
+	opts, err := parseRepoFetchOptions([]string{"--watch", "--interval=15m", "--parallel", "8", "--all"})
+	if err != nil || !opts.Watch || opts.Interval != 15*time.Minute || opts.Parallel != 8 || !opts.All {
+		t.Fatalf("opts = %+v, err = %v", opts, err)
+	}
+	for _, args := range [][]string{
+		{"--interval", "10m"},
+		{"--watch", "--format", "json"},
+		{"--all", "example-org/api"},
+		{"--parallel", "0"},
+		{"--watch", "--interval", "5s"},
+	} {
+		if _, err := parseRepoFetchOptions(args); err == nil {
+			t.Fatalf("parseRepoFetchOptions(%v) should fail", args)
+		}
+	}
+}
@@ -477,17 +477,18 @@ func resolveRepoIdentityForGC(repoPoolPath string, barePath string, remoteURL st
 	}
 	rel = filepath.ToSlash(filepath.Clean(rel))
 	parts := strings.Split(rel, "/")
-	if len(parts) != 3 {
+	if len(parts) < 3 {
 		return "", "", false
 	}
+	// Owner may be a nested namespace only on hosts that allow it (e.g. GitLab subgroups).
 	host := strings.TrimSpace(parts[0])
-	owner := strings.TrimSpace(parts[1])
-	repoGit := strings.TrimSpace(parts[2])
+	owner := strings.TrimSpace(strings.Join(parts[1:len(parts)-1], "/"))
+	repoGit := strings.TrimSpace(parts[len(parts)-1])
 	if host == "" || owner == "" || !strings.HasSuffix(repoGit, ".git") {
 		return "", "", false
 	}
 	repo := strings.TrimSuffix(repoGit, ".git")
-	if strings.TrimSpace(repo) == "" {
+	if err := repospec.ValidateIdentity(host, owner, repo); err != nil {
 		return "", "", false
 	}
 	return fmt.Sprintf("%s/%s/%s", host, owner, repo), fmt.Sprintf("%s/%s", owner, repo), true
@@ -532,38 +533,43 @@ func listRepoPoolBareRepos(repoPoolPath string) ([]string, error) {
 }
 
 func collectRegistryRepoRefCountsFromMetadata(currentRoot string) (map[string]int, map[string]int, error) {
-	registryPath, err := stateregistry.Path()
-	if err != nil {
-		return nil, nil, fmt.Errorf("resolve root registry path: %w", err)
-	}
-	entries, err := stateregistry.Load(registryPath)
+	byRoot, err := collectRegistryRepoRefsByRootFromMetadata(currentRoot)
 	if err != nil {
 		return nil, nil, err
 	}
-	seenRoot := map[string]bool{}
-	currentMarked := map[string]bool{}
 	counts := map[string]int{}
+	for _, refs := range byRoot {
+		for uid, n := range refs {
+			counts[uid] += n
+		}
+	}
 	currentCounts := map[string]int{}
+	for uid, n := range byRoot[strings.TrimSpace(currentRoot)] {
+		currentCounts[uid] = n
+	}
+	return counts, currentCounts, nil
+}
 
-	addRoot := func(rootPath string, collectCurrent bool) error {
+// collectRegistryRepoRefsByRootFromMetadata scans every registered root (and currentRoot) once
+// and returns root -> repo_uid -> workspace ref count. Roots that no longer exist are skipped.
+func collectRegistryRepoRefsByRootFromMetadata(currentRoot string) (map[string]map[string]int, error) {
+	registryPath, err := stateregistry.Path()
+	if err != nil {
+		return nil, fmt.Errorf("resolve root registry path: %w", err)
+	}
+	entries, err := stateregistry.Load(registryPath)
+	if err != nil {
+		return nil, err
+	}
+	byRoot := map[string]map[string]int{}
+	addRoot := func(rootPath string) error {
 		rootPath = strings.TrimSpace(rootPath)
 		if rootPath == "" {
 			return nil
 		}
-		if seenRoot[rootPath] {
-			if collectCurrent && !currentMarked[rootPath] {
-				refs, err := scanRootRepoRefsFromMetadata(rootPath)
-				if err != nil {
-					return fmt.Errorf("scan root repo refs from metadata %s: %w", rootPath, err)
-				}
-				for uid, n := range refs {
-					currentCounts[uid] += n
-				}
-				currentMarked[rootPath] = true
-			}
+		if _, seen := byRoot[rootPath]; seen {
 			return nil
 		}
-		seenRoot[rootPath] = true
 		if _, statErr := os.Stat(rootPath); statErr != nil {
 			if errors.Is(statErr, os.ErrNotExist) {
 				return nil
@@ -574,27 +580,19 @@ func collectRegistryRepoRefCountsFromMetadata(currentRoot string) (map[string]in
 		if err != nil {
 			return fmt.Errorf("scan root repo refs from metadata %s: %w", rootPath, err)
 		}
-		for uid, n := range refs {
-			counts[uid] += n
-			if collectCurrent {
-				currentCounts[uid] += n
-			}
-		}
-		if collectCurrent {
-			currentMarked[rootPath] = true
-		}
+		byRoot[rootPath] = refs
 		return nil
 	}
 
 	for _, e := range entries {
-		if err := addRoot(e.RootPath, false); err != nil {
-			return nil, nil, err
+		if err := addRoot(e.RootPath); err != nil {
+			return nil, err
 		}
 	}
-	if err := addRoot(currentRoot, true); err != nil {
-		return nil, nil, err
+	if err := addRoot(currentRoot); err != nil {
+		return nil, err
 	}
-	return counts, currentCounts, nil
+	return byRoot, nil
 }
 
 func scanRootRepoRefsFromMetadata(root string) (map[string]int, error) {
@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/kra/internal/app/repocmd"
+	"github.com/tasuku43/kra/internal/infra/appports"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/repomanifest"
+)
+
+type repoInfoWorktree struct {
+	Path     string
+	Branch   string
+	Detached bool
+	Prunable bool
+}
+
+type repoInfoRootRef struct {
+	Root    string
+	Refs    int
+	Current bool
+}
+
+func (c *CLI) runRepoInfo(args []string) int {
+	outputFormat := "human"
+	target := ""
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			c.printRepoInfoUsage(c.Out)
+			return exitOK
+		case arg == "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				c.printRepoInfoUsage(c.Err)
+				return exitUsage
+			}
+			outputFormat = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(c.Err, "unknown flag for repo info: %q\n", arg)
+			c.printRepoInfoUsage(c.Err)
+			return exitUsage
+		default:
+			if target != "" {
+				fmt.Fprintf(c.Err, "unexpected args for repo info: %q\n", arg)
+				c.printRepoInfoUsage(c.Err)
+				return exitUsage
+			}
+			target = arg
+		}
+	}
+	switch outputFormat {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", outputFormat)
+		c.printRepoInfoUsage(c.Err)
+		return exitUsage
+	}
+	jsonMode := outputFormat == "json"
+	fail := func(code int, errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:     false,
+				Action: "repo.info",
+				Error:  &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+	if target == "" {
+		if !jsonMode {
+			fmt.Fprintln(c.Err, "repo info requires <repo-key>")
+			c.printRepoInfoUsage(c.Err)
+			return exitUsage
+		}
+		return fail(exitUsage, "invalid_argument", "repo info requires <repo-key>")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("get working dir: %v", err))
+	}
+	ctx := context.Background()
+	repoUC := repocmd.NewService(appports.NewRepoPort(c.ensureDebugLog, c.touchStateRegistry))
+	session, err := repoUC.Run(ctx, repocmd.Request{
+		CWD:        wd,
+		DebugTag:   "repo-info",
+		RequireGit: true,
+	})
+	if err != nil {
+		return fail(exitError, "internal_error", err.Error())
+	}
+	manifest, _, err := repomanifest.LoadOptional(repomanifest.Path(session.Root))
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("load repo manifest: %v", err))
+	}
+	items, err := buildRepoListItems(ctx, session.Root, session.RepoPoolPath, manifest)
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("list repos: %v", err))
+	}
+	idx := slices.IndexFunc(items, func(it repoListItem) bool {
+		return it.RepoKey == target || it.RepoUID == target
+	})
+	if idx < 0 {
+		return fail(exitError, "not_found", fmt.Sprintf("repo not found: %s", target))
+	}
+	item := items[idx]
+	c.debugf("run repo info repo_uid=%s", item.RepoUID)
+
+	worktrees, err := listRepoBareWorktrees(ctx, item.BarePath)
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("list worktrees: %v", err))
+	}
+	roots, err := listRepoReferencingRoots(session.Root, item.RepoUID)
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("scan root references: %v", err))
+	}
+
+	if jsonMode {
+		result := repoListItemJSON(item)
+		wtRows := make([]map[string]any, 0, len(worktrees))
+		for _, wt := range worktrees {
+			wtRows = append(wtRows, map[string]any{
+				"path":     wt.Path,
+				"branch":   wt.Branch,
+				"detached": wt.Detached,
+				"prunable": wt.Prunable,
+			})
+		}
+		rootRows := make([]map[string]any, 0, len(roots))
+		for _, r := range roots {
+			rootRows = append(rootRows, map[string]any{
+				"root":    r.Root,
+				"refs":    r.Refs,
+				"current": r.Current,
+			})
+		}
+		result["worktrees"] = wtRows
+		result["roots"] = rootRows
+		_ = writeCLIJSON(c.Out, cliJSONResponse{OK: true, Action: "repo.info", Result: result})
+		return exitOK
+	}
+	printRepoInfo(c.Out, item, worktrees, roots, time.Now(), writerSupportsColor(c.Out))
+	return exitOK
+}
+
+// listRepoBareWorktrees returns linked worktrees of a bare repo (the bare entry itself is skipped).
+func listRepoBareWorktrees(ctx context.Context, barePath string) ([]repoInfoWorktree, error) {
+	if strings.TrimSpace(barePath) == "" {
+		return nil, nil
+	}
+	out, err := gitutil.RunBare(ctx, barePath, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	return parseRepoWorktreeListPorcelain(out), nil
+}
+
+func parseRepoWorktreeListPorcelain(out string) []repoInfoWorktree {
+	worktrees := make([]repoInfoWorktree, 0, 4)
+	var cur *repoInfoWorktree
+	bare := false
+	flush := func() {
+		if cur != nil && !bare {
+			worktrees = append(worktrees, *cur)
+		}
+		cur = nil
+		bare = false
+	}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "worktree "):
+			flush()
+			cur = &repoInfoWorktree{Path: strings.TrimPrefix(line, "worktree ")}
+		case cur == nil:
+		case line == "bare":
+			bare = true
+		case line == "detached":
+			cur.Detached = true
+		case strings.HasPrefix(line, "branch "):
+			cur.Branch = strings.TrimPrefix(strings.TrimPrefix(line, "branch "), "refs/heads/")
+		case line == "prunable" || strings.HasPrefix(line, "prunable "):
+			cur.Prunable = true
+		}
+	}
+	flush()
+	return worktrees
+}
+
+// listRepoReferencingRoots lists known roots whose workspace metadata references repoUID.
+func listRepoReferencingRoots(currentRoot string, repoUID string) ([]repoInfoRootRef, error) {
+	byRoot, err := collectRegistryRepoRefsByRootFromMetadata(currentRoot)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]repoInfoRootRef, 0, len(byRoot))
+	for root, refs := range byRoot {
+		if refs[repoUID] == 0 {
+			continue
+		}
+		out = append(out, repoInfoRootRef{
+			Root:    root,
+			Refs:    refs[repoUID],
+			Current: root == strings.TrimSpace(currentRoot),
+		})
+	}
+	slices.SortFunc(out, func(a, b repoInfoRootRef) int { return strings.Compare(a.Root, b.Root) })
+	return out, nil
+}
+
+func printRepoInfo(out io.Writer, item repoListItem, worktrees []repoInfoWorktree, roots []repoInfoRootRef, now time.Time, useColor bool) {
+	bullet := styleMuted("•", useColor)
+	heading := fmt.Sprintf("%s%s %s", uiIndent, bullet, item.RepoKey)
+	if len(item.Groups) > 0 {
+		heading += " " + styleMuted("["+strings.Join(item.Groups, ", ")+"]", useColor)
+	}
+	details := append([]string{
+		fmt.Sprintf("%s %s", styleAccent("repo_uid:", useColor), item.RepoUID),
+		fmt.Sprintf("%s %s", styleAccent("bare_path:", useColor), item.BarePath),
+	}, renderRepoListDetailLines(item, now, useColor)...)
+	printSection(out, styleBold("Repo:", useColor), appendRepoTreeLines([]string{heading}, details, useColor), sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+
+	body := make([]string, 0, len(worktrees))
+	for _, wt := range worktrees {
+		line := fmt.Sprintf("%s%s %s", uiIndent, bullet, wt.Path)
+		switch {
+		case wt.Branch != "":
+			line += " " + styleMuted("("+wt.Branch+")", useColor)
+		case wt.Detached:
+			line += " " + styleMuted("(detached)", useColor)
+		}
+		if wt.Prunable {
+			line += " " + styleWarn("prunable", useColor)
+		}
+		body = append(body, line)
+	}
+	if len(body) == 0 {
+		body = append(body, fmt.Sprintf("%s(none)", uiIndent))
+	}
+	printSection(out, styleBold("Worktrees:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+
+	body = make([]string, 0, len(roots))
+	for _, r := range roots {
+		line := fmt.Sprintf("%s%s %s %s", uiIndent, bullet, r.Root, styleMuted(fmt.Sprintf("(refs: %d)", r.Refs), useColor))
+		if r.Current {
+			line += " " + styleAccent("current", useColor)
+		}
+		body = append(body, line)
+	}
+	if len(body) == 0 {
+		body = append(body, fmt.Sprintf("%s(none)", uiIndent))
+	}
+	printSection(out, styleBold("Roots:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     false,
+	})
+}
@@ -0,0 +1,442 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/kra/internal/app/repocmd"
+	"github.com/tasuku43/kra/internal/core/gitref"
+	"github.com/tasuku43/kra/internal/infra/appports"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/repomanifest"
+)
+
+type repoListOptions struct {
+	Format     string
+	GroupsOnly bool
+}
+
+// repoListItem is one registered repo with its pool health fields.
+type repoListItem struct {
+	RepoUID       string
+	RepoKey       string
+	RemoteURL     string
+	BarePath      string
+	SizeBytes     int64
+	LastFetchedAt time.Time
+	DefaultBranch string
+	// Filter is the partial clone filter of the bare repo ("" for full clones).
+	Filter string
+	// UsageCount is the number of workspaces (active + archived) in this root that reference the repo.
+	UsageCount int
+	// Workspaces are the active workspaces currently bound to the repo.
+	Workspaces []string
+	Groups     []string
+}
+
+func parseRepoListOptions(args []string) (repoListOptions, error) {
+	opts := repoListOptions{Format: "human"}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			return repoListOptions{}, errHelpRequested
+		case arg == "--groups":
+			opts.GroupsOnly = true
+		case arg == "--format":
+			if i+1 >= len(args) {
+				return repoListOptions{}, fmt.Errorf("--format requires a value")
+			}
+			opts.Format = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			opts.Format = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		default:
+			return repoListOptions{}, fmt.Errorf("unknown flag for repo list: %q", arg)
+		}
+	}
+	switch opts.Format {
+	case "human", "tsv", "json":
+	default:
+		return repoListOptions{}, fmt.Errorf("unsupported --format: %q (supported: human, tsv, json)", opts.Format)
+	}
+	if opts.GroupsOnly && opts.Format != "human" {
+		return repoListOptions{}, fmt.Errorf("--groups cannot be combined with --format %s", opts.Format)
+	}
+	return opts, nil
+}
+
+func (c *CLI) runRepoList(args []string) int {
+	opts, err := parseRepoListOptions(args)
+	if err != nil {
+		if errors.Is(err, errHelpRequested) {
+			c.printRepoListUsage(c.Out)
+			return exitOK
+		}
+		fmt.Fprintf(c.Err, "%v\n", err)
+		c.printRepoListUsage(c.Err)
+		return exitUsage
+	}
+	jsonMode := opts.Format == "json"
+	fail := func(msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:     false,
+				Action: "repo.list",
+				Error:  &cliJSONError{Code: "internal_error", Message: msg},
+			})
+			return exitError
+		}
+		fmt.Fprintln(c.Err, msg)
+		return exitError
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(fmt.Sprintf("get working dir: %v", err))
+	}
+	ctx := context.Background()
+	repoUC := repocmd.NewService(appports.NewRepoPort(c.ensureDebugLog, c.touchStateRegistry))
+	req := repocmd.Request{CWD: wd, DebugTag: "repo-list", RequireGit: true}
+	if opts.GroupsOnly {
+		// Used by shell completion: no git, no debug log.
+		req = repocmd.Request{CWD: wd}
+	}
+	session, err := repoUC.Run(ctx, req)
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	manifest, _, err := repomanifest.LoadOptional(repomanifest.Path(session.Root))
+	if err != nil {
+		return fail(fmt.Sprintf("load repo manifest: %v", err))
+	}
+	groups, err := manifest.Groups()
+	if err != nil {
+		return fail(fmt.Sprintf("load repo manifest: %v", err))
+	}
+	if opts.GroupsOnly {
+		for _, g := range groups {
+			fmt.Fprintln(c.Out, g.Name)
+		}
+		return exitOK
+	}
+
+	items, err := buildRepoListItems(ctx, session.Root, session.RepoPoolPath, manifest)
+	if err != nil {
+		return fail(fmt.Sprintf("list repos: %v", err))
+	}
+	c.debugf("repo list count=%d", len(items))
+	switch opts.Format {
+	case "tsv":
+		printRepoListTSV(c.Out, items)
+	case "json":
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:     true,
+			Action: "repo.list",
+			Result: repoListJSONResult(items, groups),
+		})
+	default:
+		printRepoList(c.Out, items, groups, time.Now(), writerSupportsColor(c.Out))
+	}
+	return exitOK
+}
+
+func buildRepoListItems(ctx context.Context, root string, repoPoolPath string, manifest repomanifest.Manifest) ([]repoListItem, error) {
+	repos, err := listRootRepoCandidatesFromFilesystem(ctx, root, repoPoolPath)
+	if err != nil {
+		return nil, err
+	}
+	barePaths, err := listRepoPoolBarePathsByUID(ctx, repoPoolPath)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := scanRootRepoRefsFromMetadata(root)
+	if err != nil {
+		return nil, err
+	}
+	bound, err := listActiveWorkspacesByRepoUID(root)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]repoListItem, 0, len(repos))
+	for _, it := range repos {
+		item := repoListItem{
+			RepoUID:    it.RepoUID,
+			RepoKey:    it.RepoKey,
+			RemoteURL:  it.RemoteURL,
+			BarePath:   barePaths[it.RepoUID],
+			UsageCount: usage[it.RepoUID],
+			Workspaces: bound[it.RepoUID],
+		}
+		if entry, ok := manifest.Lookup(it.RepoUID); ok {
+			item.Groups = entry.Groups
+		}
+		if item.BarePath != "" {
+			item.SizeBytes = repoPoolBareSize(item.BarePath)
+			item.LastFetchedAt = repoPoolLastFetchedAt(item.BarePath)
+			item.DefaultBranch = repoPoolDefaultBranch(ctx, item.BarePath)
+			item.Filter = repoPoolCloneFilter(ctx, item.BarePath)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// listRepoPoolBarePathsByUID maps repo_uid to its bare repo path (same identity rules as repo gc).
+func listRepoPoolBarePathsByUID(ctx context.Context, repoPoolPath string) (map[string]string, error) {
+	bareRepos, err := listRepoPoolBareRepos(repoPoolPath)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(bareRepos))
+	for _, barePath := range bareRepos {
+		remoteURL, _ := gitutil.RunBare(ctx, barePath, "config", "--get", "remote.origin.url")
+		repoUID, _, ok := resolveRepoIdentityForGC(repoPoolPath, barePath, strings.TrimSpace(remoteURL))
+		if !ok || out[repoUID] != "" {
+			continue
+		}
+		out[repoUID] = barePath
+	}
+	return out, nil
+}
+
+// listActiveWorkspacesByRepoUID returns sorted active workspace IDs per bound repo_uid.
+func listActiveWorkspacesByRepoUID(root string) (map[string][]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, "workspaces"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string][]string{}, nil
+		}
+		return nil, err
+	}
+	out := map[string][]string{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		meta, err := loadWorkspaceMetaFile(filepath.Join(root, "workspaces", e.Name()))
+		if err != nil {
+			continue
+		}
+		for _, r := range meta.ReposRestore {
+			uid := strings.TrimSpace(r.RepoUID)
+			if uid == "" || slices.Contains(out[uid], e.Name()) {
+				continue
+			}
+			out[uid] = append(out[uid], e.Name())
+		}
+	}
+	for uid := range out {
+		slices.Sort(out[uid])
+	}
+	return out, nil
+}
+
+func repoPoolBareSize(barePath string) int64 {
+	var total int64
+	_ = filepath.WalkDir(barePath, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+// repoPoolDefaultBranch prefers origin/HEAD and falls back to the bare HEAD
+// (clone --bare points it at the remote default branch).
+func repoPoolDefaultBranch(ctx context.Context, barePath string) string {
+	if ref, err := gitutil.RunBare(ctx, barePath, "symbolic-ref", "--quiet", "refs/remotes/origin/HEAD"); err == nil {
+		if branch, ok := gitref.ParseOriginHeadRef(ref); ok {
+			return branch
+		}
+	}
+	ref, err := gitutil.RunBare(ctx, barePath, "symbolic-ref", "--quiet", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(ref), "refs/heads/")
+}
+
+func formatRepoPoolSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	value := float64(n) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if value < unit || suffix == "GiB" {
+			return fmt.Sprintf("%.1f%s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
+func formatRepoLastFetch(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	age := now.Sub(t)
+	if age < 0 {
+		age = 0
+	}
+	return fmt.Sprintf("%s ago", age.Truncate(time.Second))
+}
+
+func formatRepoLastFetchRFC3339(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func printRepoListTSV(out io.Writer, items []repoListItem) {
+	fmt.Fprintln(out, "repo_key\tremote_url\tdefault_branch\tsize_bytes\tlast_fetched_at\tusage_count\tworkspaces")
+	for _, it := range items {
+		fmt.Fprintf(
+			out,
+			"%s\t%s\t%s\t%d\t%s\t%d\t%s\n",
+			it.RepoKey,
+			it.RemoteURL,
+			it.DefaultBranch,
+			it.SizeBytes,
+			formatRepoLastFetchRFC3339(it.LastFetchedAt),
+			it.UsageCount,
+			strings.Join(it.Workspaces, ","),
+		)
+	}
+}
+
+func repoListItemJSON(it repoListItem) map[string]any {
+	workspaces := it.Workspaces
+	if workspaces == nil {
+		workspaces = []string{}
+	}
+	groups := it.Groups
+	if groups == nil {
+		groups = []string{}
+	}
+	return map[string]any{
+		"repo_uid":        it.RepoUID,
+		"repo_key":        it.RepoKey,
+		"remote_url":      it.RemoteURL,
+		"bare_path":       it.BarePath,
+		"size_bytes":      it.SizeBytes,
+		"last_fetched_at": formatRepoLastFetchRFC3339(it.LastFetchedAt),
+		"default_branch":  it.DefaultBranch,
+		"filter":          it.Filter,
+		"usage_count":     it.UsageCount,
+		"workspaces":      workspaces,
+		"groups":          groups,
+	}
+}
+
+func repoListJSONResult(items []repoListItem, groups []repomanifest.Group) map[string]any {
+	rows := make([]map[string]any, 0, len(items))
+	for _, it := range items {
+		rows = append(rows, repoListItemJSON(it))
+	}
+	groupRows := make([]map[string]any, 0, len(groups))
+	for _, g := range groups {
+		repos := make([]string, 0, len(g.Repos))
+		for _, r := range g.Repos {
+			repos = append(repos, r.RepoKey)
+		}
+		groupRows = append(groupRows, map[string]any{"name": g.Name, "repos": repos})
+	}
+	return map[string]any{
+		"items":  rows,
+		"groups": groupRows,
+	}
+}
+
+func renderRepoListDetailLines(it repoListItem, now time.Time, useColor bool) []string {
+	defaultBranch := it.DefaultBranch
+	if defaultBranch == "" {
+		defaultBranch = styleWarn("unknown", useColor)
+	}
+	workspaces := "-"
+	if len(it.Workspaces) > 0 {
+		workspaces = strings.Join(it.Workspaces, ", ")
+	}
+	lines := []string{
+		fmt.Sprintf("%s %s", styleAccent("remote:", useColor), it.RemoteURL),
+		fmt.Sprintf("%s %s", styleAccent("default_branch:", useColor), defaultBranch),
+	}
+	if it.Filter != "" {
+		lines = append(lines, fmt.Sprintf("%s %s", styleAccent("filter:", useColor), it.Filter))
+	}
+	return append(lines,
+		fmt.Sprintf("%s %s", styleAccent("size:", useColor), formatRepoPoolSize(it.SizeBytes)),
+		fmt.Sprintf("%s %s", styleAccent("last_fetch:", useColor), formatRepoLastFetch(it.LastFetchedAt, now)),
+		fmt.Sprintf("%s %d", styleAccent("usage:", useColor), it.UsageCount),
+		fmt.Sprintf("%s %s", styleAccent("workspaces:", useColor), workspaces),
+	)
+}
+
+func appendRepoTreeLines(body []string, details []string, useColor bool) []string {
+	for i, line := range details {
+		connector := "├─ "
+		if i == len(details)-1 {
+			connector = "└─ "
+		}
+		body = append(body, fmt.Sprintf("%s%s%s", uiIndent+uiIndent, styleMuted(connector, useColor), line))
+	}
+	return body
+}
+
+func printRepoList(out io.Writer, items []repoListItem, groups []repomanifest.Group, now time.Time, useColor bool) {
+	bullet := styleMuted("•", useColor)
+	registered := make(map[string]bool, len(items))
+	body := make([]string, 0, len(items)*7)
+	for _, it := range items {
+		registered[it.RepoUID] = true
+		line := fmt.Sprintf("%s%s %s", uiIndent, bullet, it.RepoKey)
+		if len(it.Groups) > 0 {
+			line += " " + styleMuted("["+strings.Join(it.Groups, ", ")+"]", useColor)
+		}
+		body = append(body, line)
+		body = appendRepoTreeLines(body, renderRepoListDetailLines(it, now, useColor), useColor)
+	}
+	if len(body) == 0 {
+		body = append(body, fmt.Sprintf("%s(none)", uiIndent))
+	}
+	printSection(out, styleBold("Repos:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     len(groups) > 0,
+	})
+	if len(groups) == 0 {
+		return
+	}
+
+	body = make([]string, 0, len(groups))
+	for _, g := range groups {
+		members := make([]string, 0, len(g.Repos))
+		for _, r := range g.Repos {
+			if registered[r.RepoUID] {
+				members = append(members, r.RepoKey)
+				continue
+			}
+			members = append(members, r.RepoKey+styleMuted(" (not in pool)", useColor))
+		}
+		body = append(body, fmt.Sprintf("%s%s %s: %s", uiIndent, bullet, g.Name, strings.Join(members, ", ")))
+	}
+	printSection(out, styleBold("Groups:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     false,
+	})
+}
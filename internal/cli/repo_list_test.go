@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_RepoListAndInfo_ShowPoolHealthWorktreesAndRoots(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		code := New(&out, &err).Run(args)
+		return code, out.String(), err.String()
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	docsSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "docs")
+	if code, _, stderr := run("repo", "add", apiSpec, docsSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--yes"); code != exitOK {
+		t.Fatalf("ws add-repo exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+
+	code, out, stderr := run("repo", "list", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("repo list json exit code = %d (stderr=%q)", code, stderr)
+	}
+	resp := decodeJSONResponse(t, out)
+	items, _ := resp.Result["items"].([]any)
+	if !resp.OK || len(items) != 2 {
+		t.Fatalf("repo list json = %s", out)
+	}
+	api, _ := items[0].(map[string]any)
+	if api["repo_key"] != "example-org/api" || api["default_branch"] != "main" || api["usage_count"] != float64(1) {
+		t.Fatalf("api item = %v", api)
+	}
+	if ws, _ := api["workspaces"].([]any); len(ws) != 1 || ws[0] != "WS1" {
+		t.Fatalf("api workspaces = %v", api["workspaces"])
+	}
+	if size, _ := api["size_bytes"].(float64); size <= 0 || api["last_fetched_at"] == "" {
+		t.Fatalf("api pool health = %v", api)
+	}
+
+	code, out, _ = run("repo", "list", "--format", "tsv")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if code != exitOK || len(lines) != 3 || !strings.HasPrefix(lines[0], "repo_key\tremote_url\tdefault_branch") {
+		t.Fatalf("repo list tsv = %d %q", code, out)
+	}
+	if cols := strings.Split(lines[2], "\t"); cols[0] != "example-org/docs" || cols[2] != "main" || cols[5] != "0" {
+		t.Fatalf("docs tsv row = %q", lines[2])
+	}
+
+	code, out, stderr = run("repo", "info", "--format", "json", "example-org/api")
+	if code != exitOK {
+		t.Fatalf("repo info exit code = %d (stderr=%q)", code, stderr)
+	}
+	resp = decodeJSONResponse(t, out)
+	worktrees, _ := resp.Result["worktrees"].([]any)
+	if len(worktrees) != 1 {
+		t.Fatalf("worktrees = %v", resp.Result["worktrees"])
+	}
+	wt, _ := worktrees[0].(map[string]any)
+	wantPath, _ := filepath.EvalSymlinks(filepath.Join(env.Root, "workspaces", "WS1", "repos", "api"))
+	if gotPath, _ := filepath.EvalSymlinks(wt["path"].(string)); gotPath != wantPath || wt["branch"] != "WS1" {
+		t.Fatalf("worktree = %v, want path %s", wt, wantPath)
+	}
+	roots, _ := resp.Result["roots"].([]any)
+	if len(roots) != 1 || roots[0].(map[string]any)["current"] != true {
+		t.Fatalf("roots = %v", resp.Result["roots"])
+	}
+
+	code, out, _ = run("repo", "info", "--format", "json", "example-org/missing")
+	if code != exitError || decodeJSONResponse(t, out).Error.Code != "not_found" {
+		t.Fatalf("repo info missing = %d %q", code, out)
+	}
+}
+
+func TestParseRepoWorktreeListPorcelain_SkipsBareEntry(t *testing.T) {
+	got := parseRepoWorktreeListPorcelain(strings.Join([]string{
+		"worktree /pool/acme/api.git",
+		"bare",
+		"",
+		"worktree /root/workspaces/WS1/repos/api",
+		"HEAD 0123456789abcdef",
+		"branch refs/heads/feature/x",
+		"",
+		"worktree /root/workspaces/WS2/repos/api",
+		"HEAD 0123456789abcdef",
+		"detached",
+		"prunable gitdir file points to non-existent location",
+		"",
+	}, "\n"))
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2 (%+v)", len(got), got)
+	}
+	if got[0].Branch != "feature/x" || got[0].Detached || got[0].Prunable {
+		t.Fatalf("got[0] = %+v", got[0])
+	}
+	if !got[1].Detached || !got[1].Prunable {
+		t.Fatalf("got[1] = %+v", got[1])
+	}
+}
@@ -19,6 +19,11 @@ const repoPoolAddDefaultWorkers = 4
 type repoPoolAddRequest struct {
 	RepoSpecInput string
 	DisplayName   string
+	// CloneURL, when set, is cloned instead of RepoSpecInput; remote.origin.url is then
+	// switched to RepoSpecInput so the pool entry matches `repo add <RepoSpecInput>`.
+	CloneURL string
+	// Filter is the partial clone filter for the bare repo (empty = full clone).
+	Filter string
 }
 
 type repoPoolAddOutcome struct {
@@ -118,7 +123,11 @@ func applyOneRepoPoolAdd(ctx context.Context, repoPoolPath string, reqIndex int,
 	repoKey := fmt.Sprintf("%s/%s", spec.Owner, spec.Repo)
 	outcome.RepoKey = repoKey
 
-	defaultBranch, err := gitutil.DefaultBranchFromRemote(ctx, specInput)
+	cloneURL := specInput
+	if u := strings.TrimSpace(req.CloneURL); u != "" {
+		cloneURL = u
+	}
+	defaultBranch, err := gitutil.DefaultBranchFromRemote(ctx, cloneURL)
 	if err != nil {
 		outcome.Success = false
 		outcome.Reason = err.Error()
@@ -135,15 +144,25 @@ func applyOneRepoPoolAdd(ctx context.Context, repoPoolPath string, reqIndex int,
 			return outcome
 		}
 	}
-	if _, err := gitutil.EnsureBareRepoFetched(ctx, specInput, barePath, defaultBranch); err != nil {
+	if _, err := ensureRepoPoolFetched(ctx, barePath, func() (string, error) {
+		return gitutil.EnsureBareRepoFetchedWithFilter(ctx, cloneURL, barePath, defaultBranch, req.Filter)
+	}); err != nil {
 		outcome.Success = false
 		outcome.Reason = err.Error()
 		emitRepoPoolDone(onProgress, reqIndex, outcome)
 		return outcome
 	}
+	if cloneURL != specInput {
+		if _, err := gitutil.RunBare(ctx, barePath, "config", "remote.origin.url", specInput); err != nil {
+			outcome.Success = false
+			outcome.Reason = err.Error()
+			emitRepoPoolDone(onProgress, reqIndex, outcome)
+			return outcome
+		}
+	}
 
 	if debugf != nil {
-		debugf("repo pool upsert success repo_uid=%s bare_path=%s", repoUID, barePath)
+		debugf("repo pool upsert success repo_uid=%s bare_path=%s filter=%s", repoUID, barePath, req.Filter)
 	}
 	outcome.Success = true
 	emitRepoPoolDone(onProgress, reqIndex, outcome)
@@ -394,3 +413,15 @@ func repoPoolAddHadFailure(outcomes []repoPoolAddOutcome) bool {
 	}
 	return false
 }
+
+// repoPoolCloneFilter returns the partial clone filter recorded in a bare repo ("" for full clones).
+func repoPoolCloneFilter(ctx context.Context, barePath string) string {
+	if strings.TrimSpace(barePath) == "" {
+		return ""
+	}
+	out, err := gitutil.RunBare(ctx, barePath, "config", "--get", "remote.origin.partialclonefilter")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// repoPoolLastFetchFilename is written inside the bare repo after every successful kra fetch.
+	repoPoolLastFetchFilename = "kra-last-fetch"
+	// repoPoolFetchLockFilename serializes fetches of one bare repo across kra processes.
+	repoPoolFetchLockFilename = "kra-fetch.lock"
+
+	repoPoolFetchLockPollInterval = 200 * time.Millisecond
+	repoPoolFetchLockTimeout      = 10 * time.Minute
+)
+
+// repoPoolLastFetchedAt returns the later of the last recorded kra fetch time and the FETCH_HEAD mtime,
+// so fetches run outside kra are not hidden by an older record. Zero means never fetched.
+func repoPoolLastFetchedAt(barePath string) time.Time {
+	var last time.Time
+	if raw, err := os.ReadFile(filepath.Join(barePath, repoPoolLastFetchFilename)); err == nil {
+		if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(raw))); err == nil {
+			last = t
+		}
+	}
+	if info, err := os.Stat(filepath.Join(barePath, "FETCH_HEAD")); err == nil && info.ModTime().After(last) {
+		last = info.ModTime()
+	}
+	return last
+}
+
+// ensureRepoPoolFetched runs fetch (a gitutil clone-or-fetch of barePath) under the pool fetch lock and
+// records the fetch. A bare repo that does not exist yet is cloned without the lock, which lives inside it.
+func ensureRepoPoolFetched(ctx context.Context, barePath string, fetch func() (string, error)) (string, error) {
+	if fi, err := os.Stat(barePath); err == nil && fi.IsDir() {
+		release, err := acquireRepoPoolFetchLock(ctx, barePath)
+		if err != nil {
+			return "", err
+		}
+		defer release()
+	}
+	out, err := fetch()
+	if err != nil {
+		return "", err
+	}
+	if err := recordRepoPoolFetch(barePath, time.Now()); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+func recordRepoPoolFetch(barePath string, at time.Time) error {
+	path := filepath.Join(barePath, repoPoolLastFetchFilename)
+	tmp, err := os.CreateTemp(barePath, repoPoolLastFetchFilename+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("record last fetch: %w", err)
+	}
+	if _, err := tmp.WriteString(at.UTC().Format(time.RFC3339Nano) + "\n"); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("record last fetch: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("record last fetch: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("record last fetch: %w", err)
+	}
+	return nil
+}
+
+// acquireRepoPoolFetchLock waits until no other kra process is fetching barePath.
+// Locks left behind by dead processes are recovered the same way as workspace add-repo locks.
+func acquireRepoPoolFetchLock(ctx context.Context, barePath string) (func(), error) {
+	lockPath := filepath.Join(barePath, repoPoolFetchLockFilename)
+	deadline := time.Now().Add(repoPoolFetchLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			if writeErr := writeWorkspaceAddRepoLockMetadata(f); writeErr != nil {
+				_ = f.Close()
+				_ = os.Remove(lockPath)
+				return nil, fmt.Errorf("write fetch lock metadata: %w", writeErr)
+			}
+			released := false
+			return func() {
+				if released {
+					return
+				}
+				released = true
+				_ = f.Close()
+				_ = os.Remove(lockPath)
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("acquire fetch lock: %w", err)
+		}
+
+		// The holder writes its pid right after creating the file; do not mistake that window for a stale lock.
+		if info, statErr := os.Stat(lockPath); statErr == nil && info.Size() == 0 && time.Since(info.ModTime()) < 2*time.Second {
+			if waitErr := waitRepoPoolFetchLockPoll(ctx); waitErr != nil {
+				return nil, waitErr
+			}
+			continue
+		}
+		stale, inspectErr := isWorkspaceAddRepoLockStale(lockPath)
+		if inspectErr != nil {
+			return nil, fmt.Errorf("inspect fetch lock: %w", inspectErr)
+		}
+		if stale {
+			if rmErr := os.Remove(lockPath); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+				return nil, fmt.Errorf("remove stale fetch lock: %w", rmErr)
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("bare repo is locked by another fetch: %s", barePath)
+		}
+		if err := waitRepoPoolFetchLockPoll(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func waitRepoPoolFetchLockPoll(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(repoPoolFetchLockPollInterval):
+		return nil
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -285,6 +286,186 @@ func TestCLI_RepoDiscover_ExcludesExistingAndAddsSelected(t *testing.T) {
 	}
 }
 
+func TestCLI_RepoDiscover_AllYesJSON_AppliesFilters(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+	newRepo := func(name string, meta repodiscovery.Repo) repodiscovery.Repo {
+		spec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", name)
+		normalized, _ := repospec.Normalize(spec)
+		meta.RepoUID = fmt.Sprintf("%s/%s/%s", normalized.Host, normalized.Owner, normalized.Repo)
+		meta.RepoKey = fmt.Sprintf("%s/%s", normalized.Owner, normalized.Repo)
+		meta.RemoteURL = spec
+		return meta
+	}
+	provider := &fakeDiscoveryProvider{repos: []repodiscovery.Repo{
+		newRepo("svc-api", repodiscovery.Repo{Language: "Go", Topics: []string{"backend"}, DefaultBranch: "main"}),
+		newRepo("svc-old", repodiscovery.Repo{Language: "Go", Topics: []string{"backend"}, Archived: true}),
+		newRepo("svc-web", repodiscovery.Repo{Language: "TypeScript", Topics: []string{"backend"}}),
+		newRepo("tools", repodiscovery.Repo{Language: "Go", Topics: []string{"backend"}}),
+	}}
+	origFactory := newRepoDiscoveryProvider
+	newRepoDiscoveryProvider = func(name string) (repodiscovery.Provider, error) {
+		return provider, nil
+	}
+	defer func() {
+		newRepoDiscoveryProvider = origFactory
+	}()
+	origPrompt := promptRepoDiscoverSelection
+	promptRepoDiscoverSelection = func(c *CLI, candidates []workspaceSelectorCandidate) ([]string, error) {
+		t.Fatalf("selector must not be used with --all")
+		return nil, nil
+	}
+	defer func() {
+		promptRepoDiscoverSelection = origPrompt
+	}()
+
+	{
+		var out bytes.Buffer
+		var err bytes.Buffer
+		code := New(&out, &err).Run([]string{"repo", "discover", "--org", "example-org", "--all", "--format", "json"})
+		if code != exitUsage {
+			t.Fatalf("json without --yes exit code = %d, want %d", code, exitUsage)
+		}
+		if resp := decodeJSONResponse(t, out.String()); resp.OK || resp.Error.Code != "invalid_argument" {
+			t.Fatalf("unexpected json response: %+v", resp)
+		}
+	}
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	code := New(&out, &err).Run([]string{
+		"repo", "discover", "--org", "example-org",
+		"--topic", "backend", "--language", "go", "--exclude-archived", "--match", "svc-*",
+		"--all", "--yes", "--format", "json",
+	})
+	if code != exitOK {
+		t.Fatalf("repo discover exit code = %d, want %d (stdout=%q stderr=%q)", code, exitOK, out.String(), err.String())
+	}
+	resp := decodeJSONResponse(t, out.String())
+	if !resp.OK || resp.Action != "repo.discover" {
+		t.Fatalf("unexpected json response: %+v", resp)
+	}
+	if resp.Result["discovered"] != float64(4) || resp.Result["filtered"] != float64(1) || resp.Result["added"] != float64(1) {
+		t.Fatalf("unexpected counts: %+v", resp.Result)
+	}
+	items, _ := resp.Result["items"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("items = %#v", resp.Result["items"])
+	}
+	item, _ := items[0].(map[string]any)
+	if item["repo_key"] != "example-org/svc-api" || item["default_branch"] != "main" || item["success"] != true {
+		t.Fatalf("item = %#v", item)
+	}
+
+	// Re-running with everything already registered is an idempotent success.
+	out.Reset()
+	err.Reset()
+	code = New(&out, &err).Run([]string{
+		"repo", "discover", "--org", "example-org",
+		"--topic", "backend", "--language", "go", "--exclude-archived", "--match", "svc-*",
+		"--all", "--yes", "--format", "json",
+	})
+	if code != exitOK {
+		t.Fatalf("repo discover (rerun) exit code = %d, want %d (stdout=%q stderr=%q)", code, exitOK, out.String(), err.String())
+	}
+	resp = decodeJSONResponse(t, out.String())
+	if !resp.OK || resp.Result["added"] != float64(0) || resp.Result["total"] != float64(0) {
+		t.Fatalf("unexpected rerun response: %+v", resp)
+	}
+	if items, ok := resp.Result["items"].([]any); !ok || len(items) != 0 {
+		t.Fatalf("rerun items should be an empty list: %#v", resp.Result["items"])
+	}
+}
+
+func TestCLI_RepoDiscover_LocalProviderAddsBareReposFromDirectory(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+	repoSpec := prepareRemoteRepoSpecWithName(t, runGit, "git.example.com", "team", "mirror")
+	scanRoot := filepath.Dir(filepath.Dir(filepath.Dir(strings.TrimPrefix(repoSpec, "file://"))))
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	code := New(&out, &err).Run([]string{"repo", "discover", "--provider", "local", "--org", scanRoot, "--all", "--yes", "--format", "json"})
+	if code != exitOK {
+		t.Fatalf("repo discover exit code = %d, want %d (stdout=%q stderr=%q)", code, exitOK, out.String(), err.String())
+	}
+	if !strings.Contains(out.String(), `"repo_uid":"git.example.com/team/mirror"`) || !strings.Contains(out.String(), `"added":1`) {
+		t.Fatalf("unexpected json output: %q", out.String())
+	}
+}
+
+func TestCLI_RepoDiscover_LocalProviderClonesLocallyForNetworkOrigin(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+	// A local checkout whose origin is a network URL that is never contacted.
+	scanRoot := t.TempDir()
+	checkout := filepath.Join(scanRoot, "checkouts", "svc")
+	runGit("", "init", "-b", "main", checkout)
+	runGit(checkout, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "init")
+	origin := "https://git.invalid/example-org/svc.git"
+	runGit(checkout, "remote", "add", "origin", origin)
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	code := New(&out, &err).Run([]string{"repo", "discover", "--provider", "local", "--org", scanRoot, "--all", "--yes", "--format", "json"})
+	if code != exitOK {
+		t.Fatalf("repo discover exit code = %d, want %d (stdout=%q stderr=%q)", code, exitOK, out.String(), err.String())
+	}
+	if !strings.Contains(out.String(), `"repo_uid":"git.invalid/example-org/svc"`) || !strings.Contains(out.String(), `"added":1`) {
+		t.Fatalf("unexpected json output: %q", out.String())
+	}
+	barePath := filepath.Join(env.RepoPoolPath(), "git.invalid", "example-org", "svc.git")
+	got, gitErr := exec.Command("git", "--git-dir", barePath, "config", "--get", "remote.origin.url").Output()
+	if gitErr != nil || strings.TrimSpace(string(got)) != origin {
+		t.Fatalf("bare remote.origin.url = %q (err=%v), want %q", strings.TrimSpace(string(got)), gitErr, origin)
+	}
+	if gitErr := exec.Command("git", "--git-dir", barePath, "rev-parse", "--verify", "refs/remotes/origin/main").Run(); gitErr != nil {
+		t.Fatalf("objects should be copied from the local checkout: %v", gitErr)
+	}
+}
+
 func TestParseRepoDiscoverOptions_DefaultProvider(t *testing.T) {
 	opts, err := parseRepoDiscoverOptions([]string{"--org", "example-org"})
 	if err != nil {
@@ -858,3 +1039,37 @@ func prepareRemoteRepoSpecWithName(t *testing.T, runGit func(dir string, args ..
 	runGit("", "clone", "--bare", src, remoteBare)
 	return "file://" + remoteBare
 }
+
+func TestNestedNamespaceHostsFromEnv(t *testing.T) {
+	cases := map[string][]string{
+		"":                              nil,
+		"https://code.example.com/":     {"code.example.com"},
+		"https://code.example.com:8443": {"code.example.com"},
+		"not a url":                     nil,
+	}
+	for raw, want := range cases {
+		t.Setenv("KRA_GITLAB_BASE_URL", raw)
+		if got := nestedNamespaceHostsFromEnv(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("nestedNamespaceHostsFromEnv(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestResolveRepoIdentityForGC_PoolPathFallback(t *testing.T) {
+	pool := filepath.Join(t.TempDir(), "repo-pool")
+	cases := map[string]bool{
+		"github.com/example-org/api.git":   true,
+		"gitlab.com/group/sub/api.git":     true,
+		"github.com/o/r/tree/main.git":     false,
+		"github.com/example-org/..git":     false,
+		"github.com/example-org/.git":      false,
+		"../outside/example-org/api.git":   false,
+		"github.com/example-org/api-noext": false,
+	}
+	for rel, want := range cases {
+		_, _, ok := resolveRepoIdentityForGC(pool, filepath.Join(pool, filepath.FromSlash(rel)), "")
+		if ok != want {
+			t.Fatalf("resolveRepoIdentityForGC(%q) ok = %v, want %v", rel, ok, want)
+		}
+	}
+}
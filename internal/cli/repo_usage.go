@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/app/repocmd"
+	"github.com/tasuku43/kra/internal/infra/appports"
+)
+
+// repoUsageItem groups the workspace branch refs of one repo.
+type repoUsageItem struct {
+	RepoUID string
+	RepoKey string
+	Refs    []workspaceBranchRef
+}
+
+func (c *CLI) runRepoUsage(args []string) int {
+	outputFormat := "human"
+	target := ""
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			c.printRepoUsageUsage(c.Out)
+			return exitOK
+		case arg == "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				c.printRepoUsageUsage(c.Err)
+				return exitUsage
+			}
+			outputFormat = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(c.Err, "unknown flag for repo usage: %q\n", arg)
+			c.printRepoUsageUsage(c.Err)
+			return exitUsage
+		default:
+			if target != "" {
+				fmt.Fprintf(c.Err, "unexpected args for repo usage: %q\n", arg)
+				c.printRepoUsageUsage(c.Err)
+				return exitUsage
+			}
+			target = arg
+		}
+	}
+	switch outputFormat {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", outputFormat)
+		c.printRepoUsageUsage(c.Err)
+		return exitUsage
+	}
+	jsonMode := outputFormat == "json"
+	fail := func(code int, errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:     false,
+				Action: "repo.usage",
+				Error:  &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("get working dir: %v", err))
+	}
+	ctx := context.Background()
+	repoUC := repocmd.NewService(appports.NewRepoPort(c.ensureDebugLog, c.touchStateRegistry))
+	session, err := repoUC.Run(ctx, repocmd.Request{
+		CWD:        wd,
+		DebugTag:   "repo-usage",
+		RequireGit: true,
+	})
+	if err != nil {
+		return fail(exitError, "internal_error", err.Error())
+	}
+	items, refs, err := buildRepoUsageItems(ctx, session.Root, session.RepoPoolPath)
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("scan repo usage: %v", err))
+	}
+	if target != "" {
+		idx := slices.IndexFunc(items, func(it repoUsageItem) bool {
+			return it.RepoKey == target || it.RepoUID == target
+		})
+		if idx < 0 {
+			return fail(exitError, "not_found", fmt.Sprintf("repo not found: %s", target))
+		}
+		items = items[idx : idx+1]
+		refs = items[0].Refs
+	}
+	dups := findWorkspaceBranchDuplicates(refs)
+	c.debugf("run repo usage target=%q repos=%d duplicates=%d", target, len(items), len(dups))
+
+	if jsonMode {
+		rows := make([]map[string]any, 0, len(items))
+		for _, it := range items {
+			wsRows := make([]map[string]any, 0, len(it.Refs))
+			for _, r := range it.Refs {
+				wsRows = append(wsRows, workspaceBranchRefJSON(r))
+			}
+			rows = append(rows, map[string]any{
+				"repo_uid":   it.RepoUID,
+				"repo_key":   it.RepoKey,
+				"workspaces": wsRows,
+			})
+		}
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:     true,
+			Action: "repo.usage",
+			Result: map[string]any{
+				"items":      rows,
+				"duplicates": workspaceBranchDuplicatesJSON(dups),
+			},
+		})
+		return exitOK
+	}
+	useColor := writerSupportsColor(c.Out)
+	printRepoUsage(c.Out, items, useColor)
+	printWorkspaceBranchWarnings(c.Out, refs, dups, useColor)
+	return exitOK
+}
+
+// buildRepoUsageItems groups workspace branch refs by repo. Repos in the pool without references are
+// included with no refs; referenced repos missing from the pool are included as well.
+func buildRepoUsageItems(ctx context.Context, root string, repoPoolPath string) ([]repoUsageItem, []workspaceBranchRef, error) {
+	candidates, err := listRootRepoCandidatesFromFilesystem(ctx, root, repoPoolPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs, err := scanWorkspaceBranchRefs(ctx, root, repoPoolPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	items := make([]repoUsageItem, 0, len(candidates))
+	indexByUID := make(map[string]int, len(candidates))
+	for _, cand := range candidates {
+		indexByUID[cand.RepoUID] = len(items)
+		items = append(items, repoUsageItem{RepoUID: cand.RepoUID, RepoKey: cand.RepoKey})
+	}
+	for _, r := range refs {
+		idx, ok := indexByUID[r.RepoUID]
+		if !ok {
+			idx = len(items)
+			indexByUID[r.RepoUID] = idx
+			items = append(items, repoUsageItem{RepoUID: r.RepoUID, RepoKey: r.RepoKey})
+		}
+		items[idx].Refs = append(items[idx].Refs, r)
+	}
+	slices.SortFunc(items, func(a, b repoUsageItem) int { return strings.Compare(a.RepoKey, b.RepoKey) })
+	return items, refs, nil
+}
+
+func printRepoUsage(out io.Writer, items []repoUsageItem, useColor bool) {
+	bullet := styleMuted("•", useColor)
+	body := make([]string, 0, len(items)*2)
+	for _, it := range items {
+		heading := fmt.Sprintf("%s%s %s", uiIndent, bullet, it.RepoKey)
+		if len(it.Refs) == 0 {
+			body = append(body, heading+" "+styleMuted("(unused)", useColor))
+			continue
+		}
+		body = append(body, heading)
+		details := make([]string, 0, len(it.Refs))
+		for _, r := range it.Refs {
+			details = append(details, fmt.Sprintf("%s %s%s", r.WorkspaceID, styleAccent(r.Branch, useColor), renderWorkspaceBranchMarkers(r, true, useColor)))
+		}
+		body = appendRepoTreeLines(body, details, useColor)
+	}
+	if len(body) == 0 {
+		body = append(body, fmt.Sprintf("%s(none)", uiIndent))
+	}
+	printSection(out, styleBold("Usage:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+}
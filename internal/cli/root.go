@@ -26,6 +26,8 @@ func (c *CLI) runRoot(args []string) int {
 		return c.runRootCurrent(args[1:])
 	case "open":
 		return c.runRootOpen(args[1:])
+	case "sync":
+		return c.runRootSync(args[1:])
 	default:
 		fmt.Fprintf(c.Err, "unknown command: %q\n", strings.Join(append([]string{"root"}, args[0]), " "))
 		c.printRootCommandUsage(c.Err)
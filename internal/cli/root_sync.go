@@ -0,0 +1,581 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/infra/paths"
+	"github.com/tasuku43/kra/internal/repomanifest"
+)
+
+const rootSyncCommitMessage = "sync: local changes"
+
+// rootSyncLocalPaths are machine-local caches that were committed by older lifecycle commits; sync
+// untracks them so they never reach the remote (the root .gitignore keeps them out afterwards).
+var rootSyncLocalPaths = []string{".kra/state", ".kra/locks"}
+
+// rootSyncManagedPaths are the root paths kra itself writes. Sync stages only these, so unrelated
+// files in KRA_ROOT never end up in a sync commit.
+var rootSyncManagedPaths = []string{
+	"workspaces",
+	"archive",
+	workspaceTemplatesDirName,
+	gitignoreFilename,
+	rootAgentsFilename,
+	".kra/config.yaml",
+	repomanifest.RelPath,
+}
+
+type rootSyncOptions struct {
+	pull   bool
+	push   bool
+	remote string
+	format string
+}
+
+// rootSyncConflict is one path left unmerged by the rebase onto the remote branch.
+type rootSyncConflict struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+}
+
+type rootSyncResult struct {
+	Root      string   `json:"root"`
+	Remote    string   `json:"remote"`
+	Branch    string   `json:"branch"`
+	Committed bool     `json:"committed"`
+	Untracked []string `json:"untracked,omitempty"`
+	Pulled    int      `json:"pulled"`
+	Pushed    bool     `json:"pushed"`
+	Restored  []string `json:"restored,omitempty"`
+	Failed    []string `json:"failed,omitempty"`
+	// Removed / Orphaned are worktrees whose workspace (or repo) no longer exists in the synced metadata:
+	// removed from the pool, or kept with the reason.
+	Removed  []string `json:"removed,omitempty"`
+	Orphaned []string `json:"orphaned,omitempty"`
+	Repaired int      `json:"repaired"`
+}
+
+func (c *CLI) runRootSync(args []string) int {
+	opts := rootSyncOptions{pull: true, push: true, format: "human"}
+	pullOnly := false
+	pushOnly := false
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch arg {
+		case "-h", "--help", "help":
+			c.printRootCommandUsage(c.Out)
+			return exitOK
+		case "--pull":
+			pullOnly = true
+		case "--push":
+			pushOnly = true
+		case "--remote":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--remote requires a value")
+				c.printRootCommandUsage(c.Err)
+				return exitUsage
+			}
+			opts.remote = strings.TrimSpace(args[i+1])
+			i++
+		case "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				c.printRootCommandUsage(c.Err)
+				return exitUsage
+			}
+			opts.format = strings.TrimSpace(args[i+1])
+			i++
+		default:
+			if strings.HasPrefix(arg, "--remote=") {
+				opts.remote = strings.TrimSpace(strings.TrimPrefix(arg, "--remote="))
+				continue
+			}
+			if strings.HasPrefix(arg, "--format=") {
+				opts.format = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+				continue
+			}
+			if strings.HasPrefix(arg, "-") {
+				fmt.Fprintf(c.Err, "unknown flag for root sync: %q\n", arg)
+				c.printRootCommandUsage(c.Err)
+				return exitUsage
+			}
+			rest = append(rest, arg)
+		}
+	}
+	switch opts.format {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", opts.format)
+		c.printRootCommandUsage(c.Err)
+		return exitUsage
+	}
+	usageErr := ""
+	switch {
+	case len(rest) > 0:
+		usageErr = fmt.Sprintf("unexpected args for root sync: %q", strings.Join(rest, " "))
+	case pullOnly && pushOnly:
+		usageErr = "--pull and --push cannot be used together"
+	}
+	if usageErr != "" {
+		if opts.format == "json" {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:     false,
+				Action: "root.sync",
+				Error: &cliJSONError{
+					Code:    "invalid_argument",
+					Message: usageErr,
+				},
+			})
+			return exitUsage
+		}
+		fmt.Fprintln(c.Err, usageErr)
+		c.printRootCommandUsage(c.Err)
+		return exitUsage
+	}
+	opts.pull = !pushOnly
+	opts.push = !pullOnly
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return c.writeRootRuntimeError(opts.format, "root.sync", "internal_error", fmt.Sprintf("get working dir: %v", err))
+	}
+	root, err := paths.ResolveExistingRoot(wd)
+	if err != nil {
+		return c.writeRootRuntimeError(opts.format, "root.sync", "not_found", fmt.Sprintf("resolve KRA_ROOT: %v", err))
+	}
+	if err := c.ensureDebugLog(root, "root-sync"); err != nil {
+		fmt.Fprintf(c.Err, "enable debug logging: %v\n", err)
+	}
+	c.debugf("run root sync pull=%t push=%t remote=%s", opts.pull, opts.push, opts.remote)
+
+	ctx := context.Background()
+	result, conflicts, code, err := c.syncRoot(ctx, root, opts)
+	if err != nil {
+		if len(conflicts) > 0 {
+			return c.writeRootSyncConflicts(opts.format, result, conflicts, err.Error())
+		}
+		return c.writeRootRuntimeError(opts.format, "root.sync", code, err.Error())
+	}
+
+	exitCode := exitOK
+	if len(result.Failed) > 0 {
+		exitCode = exitError
+	}
+	if opts.format == "json" {
+		resp := cliJSONResponse{
+			OK:     exitCode == exitOK,
+			Action: "root.sync",
+			Result: result,
+		}
+		if exitCode != exitOK {
+			resp.Error = &cliJSONError{
+				Code:    "restore_failed",
+				Message: fmt.Sprintf("failed to recreate worktrees: %s", strings.Join(result.Failed, "; ")),
+			}
+		}
+		_ = writeCLIJSON(c.Out, resp)
+		return exitCode
+	}
+	c.printRootSyncResult(result)
+	return exitCode
+}
+
+// syncRoot commits local root changes, rebases them onto <remote>/<branch>, pushes, and then brings the
+// machine-local side (worktrees, work-state caches) in line with the synced metadata.
+func (c *CLI) syncRoot(ctx context.Context, root string, opts rootSyncOptions) (rootSyncResult, []rootSyncConflict, string, error) {
+	result := rootSyncResult{Root: root}
+	if err := ensureRootGitWorktree(ctx, root); err != nil {
+		return result, nil, "invalid_state", err
+	}
+	branch, err := gitutil.Run(ctx, root, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil || strings.TrimSpace(branch) == "" {
+		return result, nil, "invalid_state", fmt.Errorf("KRA_ROOT is not on a branch (detached HEAD)")
+	}
+	result.Branch = strings.TrimSpace(branch)
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		gitPath, err := gitutil.Run(ctx, root, "rev-parse", "--path-format=absolute", "--git-path", name)
+		if err != nil {
+			return result, nil, "internal_error", err
+		}
+		if _, err := os.Stat(gitPath); err == nil {
+			return result, nil, "invalid_state", fmt.Errorf("a rebase is in progress in KRA_ROOT; finish or abort it first")
+		}
+	}
+
+	remote := opts.remote
+	if remote == "" {
+		if configured, err := gitutil.Run(ctx, root, "config", "--get", "branch."+result.Branch+".remote"); err == nil && strings.TrimSpace(configured) != "" {
+			remote = strings.TrimSpace(configured)
+		} else {
+			remote = "origin"
+		}
+	}
+	result.Remote = remote
+	if _, err := gitutil.Run(ctx, root, "remote", "get-url", remote); err != nil {
+		return result, nil, "remote_not_configured", fmt.Errorf("git remote %q is not configured for KRA_ROOT (add one with: git -C %s remote add %s <url>)", remote, root, remote)
+	}
+
+	unrelated, err := listRootSyncUnrelatedChanges(ctx, root)
+	if err != nil {
+		return result, nil, "internal_error", err
+	}
+	if len(unrelated) > 0 {
+		return result, nil, "conflict", fmt.Errorf("KRA_ROOT has changes outside kra-managed paths: %s (commit, stash or revert them first)", strings.Join(unrelated, ", "))
+	}
+	lc, err := c.loadRootLifecycleCommit(root, rootSyncCommitMessage)
+	if err != nil {
+		return result, nil, "internal_error", err
+	}
+
+	if err := ensureRootGitignore(root); err != nil {
+		return result, nil, "internal_error", err
+	}
+	tracked, err := gitutil.Run(ctx, root, append([]string{"ls-files", "--"}, rootSyncLocalPaths...)...)
+	if err != nil {
+		return result, nil, "internal_error", err
+	}
+	if strings.TrimSpace(tracked) != "" {
+		if _, err := gitutil.Run(ctx, root, append([]string{"rm", "-r", "--cached", "--quiet", "--ignore-unmatch", "--"}, rootSyncLocalPaths...)...); err != nil {
+			return result, nil, "internal_error", fmt.Errorf("untrack local state: %w", err)
+		}
+		result.Untracked = slices.Clone(rootSyncLocalPaths)
+	}
+	for _, p := range rootSyncManagedPaths {
+		if _, err := gitutil.Run(ctx, root, "add", "-A", "--", p); err != nil && !gitAddPathSkipped(err) {
+			return result, nil, "internal_error", fmt.Errorf("stage %s: %w", p, err)
+		}
+	}
+	staged, err := gitutil.Run(ctx, root, "diff", "--cached", "--name-only")
+	if err != nil {
+		return result, nil, "internal_error", err
+	}
+	if strings.TrimSpace(staged) != "" {
+		if _, err := gitutil.Run(ctx, root, lc.gitArgs("sync", []string{"-q"})...); err != nil {
+			return result, nil, "internal_error", fmt.Errorf("commit local changes: %w", err)
+		}
+		result.Committed = true
+	}
+
+	if opts.pull {
+		if _, err := gitutil.Run(ctx, root, "fetch", "--quiet", remote); err != nil {
+			return result, nil, "fetch_failed", fmt.Errorf("fetch %s: %w", remote, err)
+		}
+		upstream := remote + "/" + result.Branch
+		// A remote without the branch yet (first sync from this root) has nothing to pull.
+		if _, err := gitutil.Run(ctx, root, "rev-parse", "--verify", "--quiet", "refs/remotes/"+upstream); err == nil {
+			count, err := gitutil.Run(ctx, root, "rev-list", "--count", "HEAD.."+upstream)
+			if err != nil {
+				return result, nil, "internal_error", err
+			}
+			result.Pulled, _ = strconv.Atoi(strings.TrimSpace(count))
+			if _, err := gitutil.Run(ctx, root, "rebase", "--quiet", upstream); err != nil {
+				conflicts := listRootSyncConflicts(ctx, root)
+				_, _ = gitutil.Run(ctx, root, "rebase", "--abort")
+				if len(conflicts) == 0 {
+					return result, nil, "rebase_failed", fmt.Errorf("rebase onto %s: %w", upstream, err)
+				}
+				return result, conflicts, "conflict", fmt.Errorf("rebase onto %s stopped on conflicts; local commits are kept, resolve with: git -C %s pull --rebase %s %s", upstream, root, remote, result.Branch)
+			}
+		}
+	}
+
+	if opts.push {
+		if _, err := gitutil.Run(ctx, root, "push", "--quiet", "--set-upstream", remote, result.Branch); err != nil {
+			return result, nil, "push_failed", fmt.Errorf("push %s %s: %w", remote, result.Branch, err)
+		}
+		result.Pushed = true
+	}
+
+	if opts.pull {
+		removed, orphaned, err := removeRootSyncOrphanWorktrees(ctx, root)
+		if err != nil {
+			return result, nil, "internal_error", err
+		}
+		result.Removed = removed
+		result.Orphaned = orphaned
+		restored, failed, err := c.restoreRootSyncWorktrees(ctx, root)
+		if err != nil {
+			return result, nil, "internal_error", err
+		}
+		result.Restored = restored
+		result.Failed = failed
+		if err := c.touchStateRegistry(root); err != nil {
+			return result, nil, "internal_error", fmt.Errorf("update root registry: %w", err)
+		}
+		rows, _, err := buildWSListRows(ctx, root, "active", time.Now().Unix(), false)
+		if err != nil {
+			return result, nil, "internal_error", fmt.Errorf("repair workspace index: %w", err)
+		}
+		result.Repaired = len(rows)
+	}
+	return result, nil, "", nil
+}
+
+// restoreRootSyncWorktrees recreates worktrees that active workspaces list in repos_restore but that
+// are missing on this machine (e.g. right after the root was synced to it). One failing workspace
+// does not stop the others.
+func (c *CLI) restoreRootSyncWorktrees(ctx context.Context, root string) (restored []string, failed []string, err error) {
+	entries, err := os.ReadDir(filepath.Join(root, "workspaces"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read workspaces/: %w", err)
+	}
+	repoPoolPath, err := paths.DefaultRepoPoolPath()
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve repo pool path: %w", err)
+	}
+	for _, e := range entries {
+		id := strings.TrimSpace(e.Name())
+		if !e.IsDir() || validateWorkspaceID(id) != nil {
+			continue
+		}
+		wsPath := filepath.Join(root, "workspaces", id)
+		if _, err := os.Stat(filepath.Join(wsPath, workspaceMetaFilename)); err != nil {
+			continue
+		}
+		meta, err := loadWorkspaceMetaFile(wsPath)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: load %s: %v", id, workspaceMetaFilename, err))
+			continue
+		}
+		missing := make([]workspaceMetaRepoRestore, 0, len(meta.ReposRestore))
+		for _, r := range meta.ReposRestore {
+			if strings.TrimSpace(r.Alias) == "" {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(wsPath, "repos", r.Alias)); os.IsNotExist(err) {
+				missing = append(missing, r)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		commitHooks, err := c.loadWorkspaceCommitHooks(root, id, meta.Workspace.SourceURL)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: resolve commit hooks: %v", id, err))
+			continue
+		}
+		if err := recreateWorkspaceWorktreesFromMeta(ctx, root, repoPoolPath, id, missing, commitHooks); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", id, err))
+			continue
+		}
+		for _, r := range missing {
+			restored = append(restored, id+"/"+r.Alias)
+		}
+		if _, err := os.Stat(workspaceBaselinePath(root, id)); os.IsNotExist(err) {
+			if err := createOrRefreshWorkspaceBaseline(ctx, root, id, time.Now().Unix()); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: create work-state baseline: %v", id, err))
+			}
+		}
+	}
+	return restored, failed, nil
+}
+
+// removeRootSyncOrphanWorktrees removes worktrees under workspaces/<id>/repos/ that the synced metadata no
+// longer lists (workspace closed/purged or repo removed on another machine). They would otherwise stay
+// registered in the pool bare repo and keep their branch checked out, so a later reopen fails. Worktrees
+// with uncommitted changes are kept and reported; branches stay in the bare repo either way.
+func removeRootSyncOrphanWorktrees(ctx context.Context, root string) (removed []string, orphaned []string, err error) {
+	entries, err := os.ReadDir(filepath.Join(root, "workspaces"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read workspaces/: %w", err)
+	}
+	for _, e := range entries {
+		id := strings.TrimSpace(e.Name())
+		if !e.IsDir() || validateWorkspaceID(id) != nil {
+			continue
+		}
+		wsPath := filepath.Join(root, "workspaces", id)
+		reposDir := filepath.Join(wsPath, "repos")
+		repoEntries, err := os.ReadDir(reposDir)
+		if err != nil || len(repoEntries) == 0 {
+			continue
+		}
+		active := map[string]bool{}
+		hasMeta := true
+		if _, err := os.Stat(filepath.Join(wsPath, workspaceMetaFilename)); os.IsNotExist(err) {
+			hasMeta = false
+		} else {
+			meta, err := loadWorkspaceMetaFile(wsPath)
+			if err != nil {
+				// Unreadable metadata is reported by the restore step; never guess what is orphaned.
+				continue
+			}
+			for _, r := range meta.ReposRestore {
+				active[strings.TrimSpace(r.Alias)] = true
+			}
+		}
+		for _, re := range repoEntries {
+			alias := re.Name()
+			if !re.IsDir() || active[alias] {
+				continue
+			}
+			label := id + "/" + alias
+			if reason := removeRootSyncOrphanWorktree(ctx, filepath.Join(reposDir, alias)); reason != "" {
+				orphaned = append(orphaned, label+": "+reason)
+				continue
+			}
+			removed = append(removed, label)
+		}
+		if !hasMeta {
+			// The workspace was closed/purged elsewhere; drop the skeleton dirs git does not track.
+			removeEmptyDirsUnder(wsPath)
+		}
+	}
+	return removed, orphaned, nil
+}
+
+// removeEmptyDirsUnder removes dir and its subdirectories bottom-up as long as they are empty.
+func removeEmptyDirsUnder(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			removeEmptyDirsUnder(filepath.Join(dir, e.Name()))
+		}
+	}
+	_ = os.Remove(dir)
+}
+
+// removeRootSyncOrphanWorktree removes one clean worktree from its pool bare repo and returns why it was kept otherwise.
+func removeRootSyncOrphanWorktree(ctx context.Context, worktreePath string) string {
+	if fi, err := os.Stat(filepath.Join(worktreePath, ".git")); err != nil || fi.IsDir() {
+		return "not a linked worktree (kept)"
+	}
+	status, err := gitutil.Run(ctx, worktreePath, "status", "--porcelain")
+	if err != nil {
+		return fmt.Sprintf("inspect: %v (kept)", err)
+	}
+	if strings.TrimSpace(status) != "" {
+		return "uncommitted changes (kept)"
+	}
+	commonDir, err := worktreeCommonDir(ctx, worktreePath)
+	if err != nil {
+		return fmt.Sprintf("%v (kept)", err)
+	}
+	if _, err := gitutil.RunBare(ctx, commonDir, "worktree", "remove", worktreePath); err != nil {
+		return fmt.Sprintf("worktree remove: %v (kept)", err)
+	}
+	return ""
+}
+
+// listRootSyncUnrelatedChanges returns tracked paths changed (staged or not) outside the kra-managed
+// and machine-local paths. Untracked files outside them are left alone and never staged.
+func listRootSyncUnrelatedChanges(ctx context.Context, root string) ([]string, error) {
+	out, err := gitutil.Output(ctx, root, "status", "--porcelain", "-z", "--no-renames", "--untracked-files=no")
+	if err != nil {
+		return nil, err
+	}
+	allowed := append(slices.Clone(rootSyncManagedPaths), rootSyncLocalPaths...)
+	unrelated := make([]string, 0)
+	for _, entry := range strings.Split(out, "\x00") {
+		if len(entry) < 4 {
+			continue
+		}
+		p := entry[3:]
+		if !slices.ContainsFunc(allowed, func(a string) bool { return p == a || strings.HasPrefix(p, a+"/") }) {
+			unrelated = append(unrelated, p)
+		}
+	}
+	return unrelated, nil
+}
+
+func listRootSyncConflicts(ctx context.Context, root string) []rootSyncConflict {
+	out, err := gitutil.Run(ctx, root, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil
+	}
+	conflicts := make([]rootSyncConflict, 0)
+	for _, p := range strings.Split(out, "\n") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		conflicts = append(conflicts, rootSyncConflict{Path: p, Kind: rootSyncConflictKind(p)})
+	}
+	return conflicts
+}
+
+// rootSyncConflictKind tells workspace metadata and notes apart from other root files, since those two
+// are where concurrent edits from several machines usually collide.
+func rootSyncConflictKind(path string) string {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	switch {
+	case parts[len(parts)-1] == workspaceMetaFilename:
+		return "meta"
+	case slices.Contains(parts[:len(parts)-1], "notes"):
+		return "notes"
+	default:
+		return "other"
+	}
+}
+
+func (c *CLI) writeRootSyncConflicts(format string, result rootSyncResult, conflicts []rootSyncConflict, message string) int {
+	if format == "json" {
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:     false,
+			Action: "root.sync",
+			Result: map[string]any{
+				"root":      result.Root,
+				"remote":    result.Remote,
+				"branch":    result.Branch,
+				"committed": result.Committed,
+				"conflicts": conflicts,
+			},
+			Error: &cliJSONError{
+				Code:    "conflict",
+				Message: message,
+			},
+		})
+		return exitError
+	}
+	useColor := writerSupportsColor(c.Err)
+	body := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		body = append(body, fmt.Sprintf("%s%s %s %s", uiIndent, styleError("!", useColor), conflict.Path, styleMuted("("+conflict.Kind+")", useColor)))
+	}
+	printSection(c.Err, styleBold("Conflicts:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+	fmt.Fprintf(c.Err, "root: %s\n", message)
+	return exitError
+}
+
+func (c *CLI) printRootSyncResult(result rootSyncResult) {
+	useColor := writerSupportsColor(c.Out)
+	lines := []string{fmt.Sprintf("%s %s %s", styleSuccess("✔", useColor), result.Root, styleMuted("("+result.Remote+"/"+result.Branch+")", useColor))}
+	if len(result.Untracked) > 0 {
+		lines = append(lines, styleMuted("untracked: "+strings.Join(result.Untracked, ", "), useColor))
+	}
+	if result.Committed {
+		lines = append(lines, styleMuted("committed: local changes", useColor))
+	}
+	lines = append(lines, styleMuted(fmt.Sprintf("pulled: %d commit(s)", result.Pulled), useColor))
+	if result.Pushed {
+		lines = append(lines, styleMuted("pushed: yes", useColor))
+	}
+	for _, r := range result.Restored {
+		lines = append(lines, fmt.Sprintf("%s restored %s", styleSuccess("✔", useColor), r))
+	}
+	for _, r := range result.Removed {
+		lines = append(lines, fmt.Sprintf("%s removed orphaned %s", styleSuccess("✔", useColor), r))
+	}
+	for _, o := range result.Orphaned {
+		lines = append(lines, fmt.Sprintf("%s orphaned %s", styleWarn("!", useColor), o))
+	}
+	for _, f := range result.Failed {
+		lines = append(lines, fmt.Sprintf("%s %s", styleError("✖", useColor), f))
+	}
+	printResultSection(c.Out, useColor, lines...)
+}
@@ -0,0 +1,316 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
+	"github.com/tasuku43/kra/internal/core/repostore"
+	"github.com/tasuku43/kra/internal/paths"
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestRootSyncConflictKind(t *testing.T) {
+	cases := map[string]string{
+		"workspaces/WS1/.kra.meta.json": "meta",
+		"archive/WS1/.kra.meta.json":    "meta",
+		"workspaces/WS1/notes/todo.md":  "notes",
+		"workspaces/WS1/notes/a/b.md":   "notes",
+		"workspaces/WS1/artifacts/x":    "other",
+		"AGENTS.md":                     "other",
+	}
+	for path, want := range cases {
+		if got := rootSyncConflictKind(path); got != want {
+			t.Fatalf("rootSyncConflictKind(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestCLI_Root_Sync_PushThenRestoreOnSecondMachine(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+	writeNote := func(root string, body string) {
+		t.Helper()
+		path := filepath.Join(root, "workspaces", "WS1", "notes", "todo.md")
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("write note: %v", err)
+		}
+	}
+
+	remote := filepath.Join(t.TempDir(), "kra-root.git")
+	runGit("", "init", "--bare", remote)
+
+	// Machine A: create a workspace with a repo and push the root.
+	envA := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, envA.Root)
+	runGit(envA.Root, "remote", "add", "origin", remote)
+	repoSpec := prepareRemoteRepoSpecWithName(t, func(dir string, args ...string) { runGit(dir, args...) }, "github.com", "example-org", "api")
+	if code, _, stderr := run("repo", "add", repoSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--yes"); code != exitOK {
+		t.Fatalf("ws add-repo exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	writeNote(envA.Root, "from A\n")
+
+	code, out, stderr := run("root", "sync", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("root sync (A) exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	var resp struct {
+		OK     bool           `json:"ok"`
+		Result rootSyncResult `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("unmarshal: %v (out=%q)", err, out)
+	}
+	if !resp.OK || !resp.Result.Committed || !resp.Result.Pushed || resp.Result.Remote != "origin" {
+		t.Fatalf("unexpected sync result: %+v", resp)
+	}
+	synced := runGit("", "--git-dir", remote, "ls-tree", "-r", "--name-only", resp.Result.Branch)
+	if !strings.Contains(synced, "workspaces/WS1/.kra.meta.json") || !strings.Contains(synced, "workspaces/WS1/notes/todo.md") {
+		t.Fatalf("synced content misses workspace files:\n%s", synced)
+	}
+	if strings.Contains(synced, "/repos/") || strings.Contains(synced, ".kra/state") {
+		t.Fatalf("worktrees and .kra/state must not be synced:\n%s", synced)
+	}
+
+	// Machine B: a fresh clone gets the missing worktree recreated from repos_restore.
+	envB := testutil.NewEnv(t)
+	runGit("", "clone", "--quiet", remote, envB.Root)
+	initAndConfigureRootRepo(t, envB.Root)
+	if code, out, stderr := run("root", "sync", "--pull"); code != exitOK {
+		t.Fatalf("root sync (B) exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	} else if !strings.Contains(out, "restored WS1/api") {
+		t.Fatalf("root sync (B) should report the restored worktree: %q", out)
+	}
+	worktreeB := filepath.Join(envB.Root, "workspaces", "WS1", "repos", "api")
+	if got := runGit(worktreeB, "rev-parse", "--abbrev-ref", "HEAD"); got != "WS1" {
+		t.Fatalf("restored worktree branch = %q, want WS1", got)
+	}
+	if _, err := os.Stat(workspaceBaselinePath(envB.Root, "WS1")); err != nil {
+		t.Fatalf("work-state baseline should be created for the restored workspace: %v", err)
+	}
+
+	// Concurrent note edits: B pushes first, A stops on the conflict with its commits intact.
+	writeNote(envB.Root, "from B\n")
+	if code, _, stderr := run("root", "sync"); code != exitOK {
+		t.Fatalf("root sync (B push) exit code = %d (stderr=%q)", code, stderr)
+	}
+	if err := paths.WriteCurrentContext(envA.Root); err != nil {
+		t.Fatalf("switch context: %v", err)
+	}
+	writeNote(envA.Root, "from A again\n")
+	code, out, _ = run("root", "sync", "--format", "json")
+	if code != exitError {
+		t.Fatalf("root sync (A conflict) exit code = %d, want %d (stdout=%q)", code, exitError, out)
+	}
+	if !strings.Contains(out, `"code":"conflict"`) || !strings.Contains(out, `{"path":"workspaces/WS1/notes/todo.md","kind":"notes"}`) {
+		t.Fatalf("conflict should be reported with its kind: %q", out)
+	}
+	if got := runGit(envA.Root, "log", "-1", "--format=%s"); got != rootSyncCommitMessage {
+		t.Fatalf("local commit should be kept after abort, HEAD = %q", got)
+	}
+	if got := runGit(envA.Root, "status", "--porcelain"); got != "" {
+		t.Fatalf("root should be clean after the aborted rebase: %q", got)
+	}
+}
+
+func TestCLI_Root_Sync_StagesOnlyManagedPathsWithLifecycleCommit(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		code := New(&out, &err).Run(args)
+		return code, out.String(), err.String()
+	}
+
+	remote := filepath.Join(t.TempDir(), "kra-root.git")
+	runGit("", "init", "--bare", remote)
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	runGit(env.Root, "remote", "add", "origin", remote)
+	// GIT_COMMITTER_* (set for all tests) would take precedence over committer.* config.
+	for _, key := range []string{"GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "")
+		_ = os.Unsetenv(key)
+	}
+	if err := os.WriteFile(filepath.Join(env.Root, ".kra", "config.yaml"), []byte(`
+workspace:
+  lifecycle_commit:
+    trailers:
+      - "Kra-Action: {{action}}"
+    committer:
+      name: kra-bot
+      email: kra@example.com
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	if err := os.WriteFile(filepath.Join(env.Root, "workspaces", "WS1", "notes", "todo.md"), []byte("note\n"), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+	// An untracked file outside kra-managed paths is left alone.
+	if err := os.WriteFile(filepath.Join(env.Root, "scratch.txt"), []byte("private\n"), 0o644); err != nil {
+		t.Fatalf("write scratch: %v", err)
+	}
+
+	if code, out, stderr := run("root", "sync"); code != exitOK {
+		t.Fatalf("root sync exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	if got := runGit(env.Root, "log", "-1", "--format=%B"); got != rootSyncCommitMessage+"\n\nKra-Action: sync" {
+		t.Fatalf("sync commit message = %q", got)
+	}
+	if got := runGit(env.Root, "log", "-1", "--format=%cn <%ce>"); got != "kra-bot <kra@example.com>" {
+		t.Fatalf("sync committer = %q", got)
+	}
+	synced := runGit(env.Root, "ls-tree", "-r", "--name-only", "HEAD")
+	if !strings.Contains(synced, "workspaces/WS1/notes/todo.md") || !strings.Contains(synced, ".kra/config.yaml") {
+		t.Fatalf("managed paths should be synced:\n%s", synced)
+	}
+	if strings.Contains(synced, "scratch.txt") {
+		t.Fatalf("unrelated untracked file must not be synced:\n%s", synced)
+	}
+
+	// A tracked change outside kra-managed paths stops the sync before anything is committed.
+	runGit(env.Root, "add", "scratch.txt")
+	code, out, _ := run("root", "sync", "--format", "json")
+	if code != exitError || !strings.Contains(out, `"code":"conflict"`) || !strings.Contains(out, "outside kra-managed paths: scratch.txt") {
+		t.Fatalf("root sync with unrelated change: code=%d out=%q", code, out)
+	}
+	if got := runGit(env.Root, "log", "-1", "--format=%s"); got != rootSyncCommitMessage {
+		t.Fatalf("nothing should be committed, HEAD = %q", got)
+	}
+}
+
+func TestCLI_Root_Sync_RemovesOrphanWorktreesAfterPull(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		code := New(&out, &err).Run(args)
+		return code, out.String(), err.String()
+	}
+
+	remote := filepath.Join(t.TempDir(), "kra-root.git")
+	runGit("", "init", "--bare", remote)
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	runGit(env.Root, "remote", "add", "origin", remote)
+	repoSpec := prepareRemoteRepoSpecWithName(t, func(dir string, args ...string) { runGit(dir, args...) }, "github.com", "example-org", "api")
+	if code, _, stderr := run("repo", "add", repoSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	for _, id := range []string{"WS1", "WS2"} {
+		if code, _, stderr := run("ws", "create", "--no-prompt", id); code != exitOK {
+			t.Fatalf("ws create %s exit code = %d (stderr=%q)", id, code, stderr)
+		}
+		if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", id, "--repo", "example-org/api", "--yes"); code != exitOK {
+			t.Fatalf("ws add-repo %s exit code = %d (stdout=%q stderr=%q)", id, code, out, stderr)
+		}
+	}
+	if code, _, stderr := run("root", "sync"); code != exitOK {
+		t.Fatalf("root sync exit code = %d (stderr=%q)", code, stderr)
+	}
+
+	// Another machine closed both workspaces: their metadata disappears from workspaces/ with the pull.
+	other := filepath.Join(t.TempDir(), "other")
+	runGit("", "clone", "--quiet", remote, other)
+	runGit(other, "rm", "-r", "--quiet", "workspaces/WS1", "workspaces/WS2")
+	runGit(other, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "close elsewhere")
+	runGit(other, "push", "--quiet", "origin", "HEAD")
+	// WS2's worktree has local edits, so it must be kept.
+	if err := os.WriteFile(filepath.Join(env.Root, "workspaces", "WS2", "repos", "api", "wip.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatalf("write wip: %v", err)
+	}
+
+	code, out, stderr := run("root", "sync", "--pull", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("root sync --pull exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	var resp struct {
+		OK     bool           `json:"ok"`
+		Result rootSyncResult `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("unmarshal: %v (out=%q)", err, out)
+	}
+	if len(resp.Result.Removed) != 1 || resp.Result.Removed[0] != "WS1/api" {
+		t.Fatalf("removed = %v, want [WS1/api]", resp.Result.Removed)
+	}
+	if len(resp.Result.Orphaned) != 1 || !strings.HasPrefix(resp.Result.Orphaned[0], "WS2/api: uncommitted changes") {
+		t.Fatalf("orphaned = %v, want WS2/api kept", resp.Result.Orphaned)
+	}
+	if _, err := os.Stat(filepath.Join(env.Root, "workspaces", "WS1")); !os.IsNotExist(err) {
+		t.Fatalf("closed workspace dir should be gone, stat err=%v", err)
+	}
+	spec, err := repospec.Normalize(repoSpec)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	worktrees := runGit("", "--git-dir", repostore.StorePath(env.RepoPoolPath(), spec), "worktree", "list", "--porcelain")
+	if strings.Contains(worktrees, filepath.Join("workspaces", "WS1", "repos", "api")) {
+		t.Fatalf("pool should no longer register the WS1 worktree:\n%s", worktrees)
+	}
+	if !strings.Contains(worktrees, filepath.Join("workspaces", "WS2", "repos", "api")) {
+		t.Fatalf("kept WS2 worktree should stay registered:\n%s", worktrees)
+	}
+}
@@ -13,6 +13,7 @@ var kraCompletionRootCommands = []string{
 	"template",
 	"shell",
 	"ws",
+	"jira",
 	"doctor",
 	"version",
 	"help",
@@ -32,20 +33,29 @@ var kraCompletionSubcommandOrder = []string{
 	"template",
 	"shell",
 	"ws",
+	"jira",
 }
 
 var kraCompletionSubcommands = map[string][]string{
 	"context":  {"current", "list", "create", "use", "rename", "rm", "help"},
-	"root":     {"current", "open", "help"},
-	"repo":     {"add", "discover", "remove", "gc", "help"},
+	"root":     {"current", "open", "sync", "help"},
+	"repo":     {"add", "list", "info", "fetch", "usage", "discover", "remove", "apply", "export", "gc", "help"},
 	"template": {"create", "remove", "rm", "validate", "help"},
 	"shell":    {"init", "completion", "help"},
+	"jira":     {"cache", "help"},
 	"ws": {
 		"create",
 		"import",
 		"list",
 		"ls",
 		"dashboard",
+		"branches",
+		"branch",
+		"status",
+		"push",
+		"pr",
+		"diff",
+		"stack",
 		"lock",
 		"unlock",
 		"open",
@@ -60,10 +70,16 @@ var kraCompletionSubcommands = map[string][]string{
 
 var kraCompletionPathSubcommandOrder = []string{
 	"ws import",
+	"ws pr",
+	"ws stack",
+	"jira cache",
 }
 
 var kraCompletionPathSubcommands = map[string][]string{
-	"ws import": {"jira", "help"},
+	"ws import":  {"jira", "help"},
+	"ws pr":      {"create", "help"},
+	"ws stack":   {"list", "push", "rebase", "help"},
+	"jira cache": {"show", "prune", "help"},
 }
 
 var kraCompletionCommandFlagOrder = []string{
@@ -75,7 +91,7 @@ var kraCompletionCommandFlagOrder = []string{
 
 var kraCompletionCommandFlags = map[string][]string{
 	"init":    {"--root", "--context", "--format", "--help", "-h"},
-	"doctor":  {"--format", "--fix", "--plan", "--apply", "--help", "-h"},
+	"doctor":  {"--format", "--fix", "--plan", "--apply", "--fsck", "--help", "-h"},
 	"version": {"--help", "-h"},
 	"ws":      {"--id", "--current", "--select", "--help", "-h"},
 }
@@ -89,9 +105,16 @@ var kraCompletionPathFlagOrder = []string{
 	"context rm",
 	"root current",
 	"root open",
+	"root sync",
 	"repo add",
+	"repo list",
+	"repo info",
+	"repo fetch",
+	"repo usage",
 	"repo discover",
 	"repo remove",
+	"repo apply",
+	"repo export",
 	"repo gc",
 	"template create",
 	"template remove",
@@ -105,6 +128,15 @@ var kraCompletionPathFlagOrder = []string{
 	"ws list",
 	"ws ls",
 	"ws dashboard",
+	"ws branches",
+	"ws branch",
+	"ws status",
+	"ws push",
+	"ws pr create",
+	"ws diff",
+	"ws stack list",
+	"ws stack push",
+	"ws stack rebase",
 	"ws open",
 	"ws add-repo",
 	"ws remove-repo",
@@ -113,6 +145,9 @@ var kraCompletionPathFlagOrder = []string{
 	"ws purge",
 	"ws lock",
 	"ws unlock",
+	"jira cache",
+	"jira cache show",
+	"jira cache prune",
 }
 
 var kraCompletionPathFlags = map[string][]string{
@@ -124,9 +159,16 @@ var kraCompletionPathFlags = map[string][]string{
 	"context rm":        {"--format", "--help", "-h"},
 	"root current":      {"--format", "--help", "-h"},
 	"root open":         {"--format", "--help", "-h"},
-	"repo add":          {"--format", "--help", "-h"},
-	"repo discover":     {"--org", "--provider", "--help", "-h"},
+	"root sync":         {"--pull", "--push", "--remote", "--format", "--help", "-h"},
+	"repo add":          {"--format", "--filter", "--help", "-h"},
+	"repo list":         {"--format", "--groups", "--help", "-h"},
+	"repo info":         {"--format", "--help", "-h"},
+	"repo fetch":        {"--all", "--parallel", "--watch", "--interval", "--format", "--help", "-h"},
+	"repo usage":        {"--format", "--help", "-h"},
+	"repo discover":     {"--org", "--provider", "--topic", "--language", "--exclude-archived", "--exclude-forks", "--match", "--all", "--yes", "--format", "--help", "-h"},
 	"repo remove":       {"--format", "--help", "-h"},
+	"repo apply":        {"--file", "--prune", "--dry-run", "--format", "--help", "-h"},
+	"repo export":       {"--output", "--help", "-h"},
 	"repo gc":           {"--format", "--yes", "--help", "-h"},
 	"template create":   {"--name", "--from", "--help", "-h"},
 	"template remove":   {"--name", "--help", "-h"},
@@ -134,20 +176,41 @@ var kraCompletionPathFlags = map[string][]string{
 	"template validate": {"--name", "--help", "-h"},
 	"shell init":        {"--with-completion", "--help", "-h"},
 	"shell completion":  {"--help", "-h"},
-	"ws create":         {"--no-prompt", "--template", "--format", "--id", "--title", "--jira", "--help", "-h"},
+	"ws create":         {"--no-prompt", "--template", "--format", "--id", "--title", "--jira", "--offline", "--no-notes", "--help", "-h"},
 	"ws import":         {"--help", "-h"},
-	"ws import jira":    {"--sprint", "--space", "--project", "--jql", "--limit", "--apply", "--no-prompt", "--format", "--help", "-h"},
+	"ws import jira":    {"--sprint", "--space", "--project", "--jql", "--epic", "--subtasks-of", "--no-parent", "--mine", "--exclude-done", "--template", "--limit", "--offline", "--no-notes", "--apply", "--no-prompt", "--format", "--help", "-h"},
 	"ws list":           {"--archived", "--tree", "--format", "--help", "-h"},
 	"ws ls":             {"--archived", "--tree", "--format", "--help", "-h"},
 	"ws dashboard":      {"--archived", "--workspace", "--format", "--help", "-h"},
+	"ws branches":       {"--format", "--help", "-h"},
+	"ws branch":         {"--id", "--current", "--switch", "--new", "--from", "--format", "--help", "-h"},
+	"ws status":         {"--id", "--current", "--format", "--help", "-h"},
+	"ws push":           {"--id", "--current", "--repo", "--force-with-lease", "--dry-run", "--format", "--help", "-h"},
+	"ws pr create":      {"--id", "--current", "--draft", "--format", "--help", "-h"},
+	"ws diff":           {"--id", "--current", "--against", "--stat", "--output", "--save", "--format", "--help", "-h"},
+	"ws stack list":     {"--id", "--current", "--format", "--help", "-h"},
+	"ws stack push":     {"--id", "--current", "--format", "--help", "-h"},
+	"ws stack rebase":   {"--id", "--current", "--format", "--help", "-h"},
 	"ws open":           {"--id", "--current", "--select", "--multi", "--concurrency", "--format", "--help", "-h"},
-	"ws add-repo":       {"--id", "--current", "--select", "--format", "--repo", "--branch", "--base-ref", "--yes", "--refresh", "--no-fetch", "--help", "-h"},
+	"ws add-repo":       {"--id", "--current", "--select", "--format", "--repo", "--group", "--branch", "--base-ref", "--alias", "--reference", "--sparse", "--submodules", "--lfs", "--yes", "--refresh", "--no-fetch", "--help", "-h"},
 	"ws remove-repo":    {"--id", "--current", "--select", "--format", "--repo", "--yes", "--force", "--help", "-h"},
 	"ws close":          {"--id", "--current", "--select", "--force", "--format", "--no-commit", "--dry-run", "--help", "-h"},
 	"ws reopen":         {"--id", "--current", "--select", "--format", "--no-commit", "--dry-run", "--help", "-h"},
 	"ws purge":          {"--id", "--current", "--select", "--no-prompt", "--force", "--format", "--no-commit", "--dry-run", "--help", "-h"},
 	"ws lock":           {"--format", "--help", "-h"},
 	"ws unlock":         {"--format", "--help", "-h"},
+	"jira cache":        {"--help", "-h"},
+	"jira cache show":   {"--format", "--help", "-h"},
+	"jira cache prune":  {"--all", "--format", "--help", "-h"},
+}
+
+var kraCompletionFlagValueOrder = []string{
+	"--group",
+}
+
+// kraCompletionFlagValueCommands completes flag values from kra itself (errors are silenced).
+var kraCompletionFlagValueCommands = map[string]string{
+	"--group": "kra repo list --groups",
 }
 
 var kraCompletionTargetRequiredPaths = []string{
@@ -190,6 +253,10 @@ _kra_completion() {
     prev="${COMP_WORDS[COMP_CWORD-1]}"
   fi
 
+  case "${prev}" in
+%s
+  esac
+
   cmd=""
   subcmd=""
   subcmd2=""
@@ -247,7 +314,20 @@ _kra_completion() {
   return 0
 }
 complete -o default -F _kra_completion kra
-`, strings.Join(kraCompletionTopWords(), " "), renderBashTargetSelectorGateCases(), renderBashCommandFlagCases(), renderBashPathFlagCases(), renderBashSubcommandCases(), renderBashPathSubcommandCases())
+`, renderBashFlagValueCases(), strings.Join(kraCompletionTopWords(), " "), renderBashTargetSelectorGateCases(), renderBashCommandFlagCases(), renderBashPathFlagCases(), renderBashSubcommandCases(), renderBashPathSubcommandCases())
+}
+
+func renderBashFlagValueCases() string {
+	lines := make([]string, 0, len(kraCompletionFlagValueOrder)*4)
+	for _, flag := range kraCompletionFlagValueOrder {
+		lines = append(lines,
+			fmt.Sprintf("    %q)", flag),
+			fmt.Sprintf("      COMPREPLY=( $(compgen -W \"$(%s 2>/dev/null)\" -- \"${cur}\") )", kraCompletionFlagValueCommands[flag]),
+			"      return 0",
+			"      ;;",
+		)
+	}
+	return strings.Join(lines, "\n")
 }
 
 func renderBashCommandFlagCases() string {
@@ -343,6 +423,10 @@ func renderBashTargetSelectorGateCases() string {
 func renderZshCompletionScript() string {
 	return fmt.Sprintf(`# kra completion (zsh)
 _kra_completion() {
+  case "${words[CURRENT-1]}" in
+%s
+  esac
+
   local -a top sub sub2 flags
   local cmd="" subcmd="" subcmd2="" path="" i j has_target
   local current_word="${words[CURRENT]}"
@@ -411,7 +495,21 @@ _kra_completion() {
   fi
 }
 compdef _kra_completion kra
-`, zshQuotedWords(kraCompletionTopWords()), renderZshTargetSelectorGateCases(), renderZshCommandFlagCases(), renderZshPathFlagCases(), renderZshSubcommandCases(), renderZshPathSubcommandCases())
+`, renderZshFlagValueCases(), zshQuotedWords(kraCompletionTopWords()), renderZshTargetSelectorGateCases(), renderZshCommandFlagCases(), renderZshPathFlagCases(), renderZshSubcommandCases(), renderZshPathSubcommandCases())
+}
+
+// renderZshFlagValueCases runs before the function's locals: `local path` would shadow $PATH.
+func renderZshFlagValueCases() string {
+	lines := make([]string, 0, len(kraCompletionFlagValueOrder)*4)
+	for _, flag := range kraCompletionFlagValueOrder {
+		lines = append(lines,
+			fmt.Sprintf("    %q)", flag),
+			fmt.Sprintf("      compadd -V kra_values -- ${(f)\"$(%s 2>/dev/null)\"}", kraCompletionFlagValueCommands[flag]),
+			"      return 0",
+			"      ;;",
+		)
+	}
+	return strings.Join(lines, "\n")
 }
 
 func renderZshCommandFlagCases() string {
@@ -579,6 +677,9 @@ func renderFishCompletionScript() string {
 
 func renderFishFlagCompletionLine(cond string, flag string) string {
 	if strings.HasPrefix(flag, "--") {
+		if cmd, ok := kraCompletionFlagValueCommands[flag]; ok {
+			return fmt.Sprintf("complete -c kra -n %q -l %s -x -a %q\n", cond, strings.TrimPrefix(flag, "--"), "("+cmd+" 2>/dev/null)")
+		}
 		return fmt.Sprintf("complete -c kra -n %q -l %s\n", cond, strings.TrimPrefix(flag, "--"))
 	}
 	return ""
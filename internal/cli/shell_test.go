@@ -145,9 +145,12 @@ func TestCLI_Shell_Completion_Zsh_PrintsCompdef(t *testing.T) {
 	if !strings.Contains(text, `flags=("--id" "--current" "--select" "--help")`) {
 		t.Fatalf("missing selector-first candidates: %q", text)
 	}
-	if !strings.Contains(text, `flags=("--format" "--repo" "--branch" "--base-ref" "--yes" "--refresh" "--no-fetch" "--help")`) {
+	if !strings.Contains(text, `flags=("--format" "--repo" "--group" "--branch" "--base-ref" "--alias" "--reference" "--sparse" "--submodules" "--lfs" "--yes" "--refresh" "--no-fetch" "--help")`) {
 		t.Fatalf("missing post-selector candidates: %q", text)
 	}
+	if !strings.Contains(text, `compadd -V kra_values -- ${(f)"$(kra repo list --groups 2>/dev/null)"}`) {
+		t.Fatalf("missing --group value completion: %q", text)
+	}
 	if strings.Contains(text, "\"-h\"") {
 		t.Fatalf("short help alias should not be suggested in completion: %q", text)
 	}
@@ -186,9 +189,12 @@ func TestCLI_Shell_Completion_Bash_PrintsCompleteHook(t *testing.T) {
 	if !strings.Contains(text, `"--id --current --select --help"`) {
 		t.Fatalf("missing selector-first candidates: %q", text)
 	}
-	if !strings.Contains(text, `"--format --repo --branch --base-ref --yes --refresh --no-fetch --help"`) {
+	if !strings.Contains(text, `"--format --repo --group --branch --base-ref --alias --reference --sparse --submodules --lfs --yes --refresh --no-fetch --help"`) {
 		t.Fatalf("missing post-selector candidates: %q", text)
 	}
+	if !strings.Contains(text, `COMPREPLY=( $(compgen -W "$(kra repo list --groups 2>/dev/null)" -- "${cur}") )`) {
+		t.Fatalf("missing --group value completion: %q", text)
+	}
 	if strings.Contains(text, " -h") {
 		t.Fatalf("short help alias should not be suggested in completion: %q", text)
 	}
@@ -16,6 +16,7 @@ func (c *CLI) printRootUsage(w io.Writer) {
 		"  shell             Shell integration commands",
 		"  ws                Workspace commands",
 		"  doctor            Diagnose KRA_ROOT health",
+		"  jira              Jira integration commands",
 	}
 	commands = append(commands,
 		"  version           Print version",
@@ -62,6 +63,8 @@ Subcommands:
                    Print conceptual KRA_ROOT resolved for current execution context
   open [--format human|json]
                    Open KRA_ROOT as a cmux workspace (single target)
+  sync [--pull|--push] [--remote <name>] [--format human|json]
+                   Commit, rebase onto and push the KRA_ROOT git repo, then restore missing worktrees
   help              Show this help
 `)
 }
@@ -72,8 +75,14 @@ func (c *CLI) printRepoUsage(w io.Writer) {
 
 Subcommands:
   add               Add repositories into shared repo pool
+  list              List registered repositories, pool health and repo groups
+  info              Show one repository with worktrees and referencing roots
+  fetch             Fetch bare repos in the shared pool (optionally in watch mode)
+  usage             Show which workspaces (active/archived) use a repository and on which branch
   discover          Discover repositories from provider and add selected
   remove            Remove repositories from current root registration
+  apply             Reconcile registered repos with the repo manifest
+  export            Print the repo manifest from current registration
   gc                Garbage-collect removable bare repos from shared pool
   help              Show this help
 `)
@@ -157,17 +166,19 @@ Options:
 
 func (c *CLI) printDoctorUsage(w io.Writer) {
 	fmt.Fprint(w, `Usage:
-  kra doctor [--format human|json]
-  kra doctor --fix --plan [--format human|json]
-  kra doctor --fix --apply [--format human|json]
+  kra doctor [--fsck] [--format human|json]
+  kra doctor --fix --plan [--fsck] [--format human|json]
+  kra doctor --fix --apply [--fsck] [--format human|json]
 
-Diagnose current KRA_ROOT health and optionally run staged remediation.
+Diagnose current KRA_ROOT health and the shared repo pool, and optionally run staged remediation.
+Pool repair actions: prune stale worktree metadata, re-set origin, re-clone damaged bare repos (atomic swap).
 
 Options:
   --format          Output format (default: human)
   --fix             Enable remediation mode
   --plan            Print remediation actions without mutation (requires --fix)
   --apply           Apply remediation actions (requires --fix)
+  --fsck            Also run git fsck --connectivity-only on every pool repo (slow on large pools)
 `)
 }
 
@@ -205,6 +216,13 @@ func (c *CLI) printWSUsage(w io.Writer) {
   kra ws purge [--id <id> | --current | --select] [action-args...]
   kra ws list|ls [--archived] [--tree] [--format human|tsv|json]
   kra ws dashboard [--archived] [--workspace <id>] [--format human|json]
+  kra ws branches [--format human|json]
+  kra ws status [--id <id> | --current] [--format human|json]
+  kra ws push [--id <id> | --current] [--repo <alias>]... [--force-with-lease] [--dry-run] [--format human|json]
+  kra ws pr create [--id <id> | --current] [--draft] [--format human|json]
+  kra ws diff [--id <id> | --current] [--against base|upstream] [--stat] [-o <file> | --save] [--format human|json]
+  kra ws branch [--id <id> | --current] <alias> [--switch <name> | --new <name> [--from <ref>]] [--format human|json]
+  kra ws stack list|push|rebase [--id <id> | --current] [args...] [--format human|json]
   kra ws lock <id> [--format human|json]
   kra ws unlock <id> [--format human|json]
 
@@ -238,6 +256,181 @@ Enable purge guard for the target workspace.
 `)
 }
 
+func (c *CLI) printWSBranchesUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws branches [--format human|json]
+
+List the branch of every repo in every workspace (active and archived, from repos_restore).
+Warns about branches recorded by more than one workspace and branches that no longer exist upstream.
+Upstream state reflects the last fetch of the pool bare repo (see: kra repo fetch).
+
+Options:
+  --format          Output format (default: human)
+`)
+}
+
+func (c *CLI) printWSStatusUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws status [--id <id> | --current] [--format human|json] [<id>]
+
+Show git status of every repo bound to an active workspace: branch, upstream and base_ref ahead/behind,
+staged/unstaged/untracked files, and stash count, after a one-line workspace summary.
+Without a target, the workspace is resolved from the current path.
+
+Options:
+  --id              Workspace id
+  --current         Resolve the workspace from the current path
+  --format          Output format (default: human)
+`)
+}
+
+func (c *CLI) printWSPushUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws push [--id <id> | --current] [--repo <alias>]... [--force-with-lease] [--dry-run] [--format human|json] [<id>]
+
+Push the branch of every repo bound to an active workspace to origin/<branch>.
+The upstream is set to origin/<branch> on first push. Repos diverged from origin/<branch> are refused
+unless --force-with-lease is given. Without a target, the workspace is resolved from the current path.
+
+Options:
+  --id                Workspace id
+  --current           Resolve the workspace from the current path
+  --repo              Push only this repo alias (repeatable)
+  --force-with-lease  Force-push diverged repos (lease on the last fetched origin/<branch>)
+  --dry-run           Show what would be pushed without pushing
+  --format            Output format (default: human)
+`)
+}
+
+func (c *CLI) printWSPRUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws pr <subcommand> [args]
+
+Subcommands:
+  create            Open pull requests for every repo in a workspace
+  help              Show this help
+`)
+}
+
+func (c *CLI) printWSPRCreateUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws pr create [--id <id> | --current] [--draft] [--format human|json] [<id>]
+
+Open a pull request (GitLab: merge request) from the workspace branch into base_ref for every repo
+with commits beyond base_ref. Branches must be pushed first (see: kra ws push).
+Title and body come from the workspace title and source_url; the PRs are cross-linked in each body.
+PR URLs are recorded in .kra.meta.json; repos with a recorded PR are not opened again.
+
+Forge (selected by matching the repo host against the API base URL host):
+  github            KRA_GITHUB_TOKEN, KRA_GITHUB_BASE_URL (default: https://api.github.com)
+  gitlab            KRA_GITLAB_TOKEN, KRA_GITLAB_BASE_URL (default: https://gitlab.com)
+
+Options:
+  --id              Workspace id
+  --current         Resolve the workspace from the current path
+  --draft           Open draft pull requests
+  --format          Output format (default: human)
+`)
+}
+
+func (c *CLI) printWSDiffUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws diff [--id <id> | --current] [--against base|upstream] [--stat] [-o <file> | --save] [--format human|json] [<id>]
+
+Print one combined diff of every repo bound to an active workspace, with a "# repo:" header per repo.
+Each repo is diffed from the merge-base of HEAD and base_ref (or the upstream branch) to the worktree,
+so uncommitted changes to tracked files are included; untracked files are only counted.
+Patch paths are prefixed with the repo alias; written files use --binary so they apply with git apply.
+Without a target, the workspace is resolved from the current path.
+
+Options:
+  --id              Workspace id
+  --current         Resolve the workspace from the current path
+  --against         Compare against base (base_ref, default) or upstream
+  --stat            Show diffstat instead of the patch
+  -o, --output      Write the combined diff to a file ("-" = stdout)
+  --save            Write to artifacts/diffs/<timestamp>.patch in the workspace (kept by ws close)
+  --format          Output format (default: human; json returns per-repo stats)
+`)
+}
+
+func (c *CLI) printWSBranchUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws branch [--id <id> | --current] <alias> [--switch <name> | --new <name> [--from <ref>]] [--format human|json]
+
+Show or change the branch checked out in one workspace repo without recreating its worktree.
+Changing branch requires a clean worktree and a branch not checked out by another workspace;
+repos_restore branch/base_ref in .kra.meta.json is updated. Without a target, the workspace is
+resolved from the current path.
+
+Options:
+  --id              Workspace id
+  --current         Resolve the workspace from the current path
+  --switch          Check out an existing branch (local, or origin/<name> as a tracking branch)
+  --new             Create and check out a new branch
+  --from            Start point for --new, origin/<branch> (default: current base_ref); becomes base_ref
+  --format          Output format (default: human)
+`)
+}
+
+func (c *CLI) printWSStackUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws stack <subcommand> [--id <id> | --current] [args] [--format human|json]
+
+Manage stacked branches per workspace repo (recorded bottom-up in repos_restore[].stack).
+
+Subcommands:
+  list              Show stacks with commits per branch and restack state
+  push              Create a branch on top of a repo's stack and check it out
+  rebase            Restack every branch onto its parent after base_ref moved
+  help              Show this help
+`)
+}
+
+func (c *CLI) printWSStackListUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws stack list [--id <id> | --current] [<alias>] [--format human|json]
+
+Show each stacked branch with its parent, commits beyond the parent, push state, and whether it
+needs a restack (parent tip is not an ancestor).
+
+Options:
+  --id              Workspace id
+  --current         Resolve the workspace from the current path
+  --format          Output format (default: human)
+`)
+}
+
+func (c *CLI) printWSStackPushUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws stack push [--id <id> | --current] <alias> <branch> [--format human|json]
+
+Create <branch> from the top of the repo's stack and check it out. Without a stack yet, the
+checked-out branch becomes the bottom of the stack. Requires a clean worktree on the stack top.
+
+Options:
+  --id              Workspace id
+  --current         Resolve the workspace from the current path
+  --format          Output format (default: human)
+`)
+}
+
+func (c *CLI) printWSStackRebaseUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra ws stack rebase [--id <id> | --current] [<alias>] [--format human|json]
+
+Rebase the bottom branch onto base_ref and every other branch onto the branch below it, for all
+stacked repos (or one alias). Requires clean worktrees; on a conflict the rebase is aborted and the
+whole stack of that repo is restored. Rebased branches that were pushed need
+kra ws push --force-with-lease. Fetch first (kra repo fetch) to pick up a moved base_ref.
+
+Options:
+  --id              Workspace id
+  --current         Resolve the workspace from the current path
+  --format          Output format (default: human)
+`)
+}
+
 func (c *CLI) printWSInsightUsage(w io.Writer) {
 	fmt.Fprint(w, `Usage:
   kra ws insight <subcommand> [args]
@@ -276,7 +469,7 @@ func (c *CLI) printWSCreateUsage(w io.Writer) {
 	fmt.Fprint(w, `Usage:
   kra ws create [--no-prompt] [--template <name>] [--format human|json] <id>
   kra ws create [--no-prompt] [--template <name>] [--format human|json] --id <id> [--title "<title>"]
-  kra ws create --jira <ticket-url> [--template <name>] [--format human|json]
+  kra ws create --jira <ticket-url> [--offline] [--no-notes] [--template <name>] [--format human|json]
 
 Create a workspace directory from template and write .kra.meta.json.
 
@@ -285,7 +478,10 @@ Options:
   --id               Explicit workspace id (automation-friendly alternative to positional <id>)
   --title            Workspace title for non-Jira create (skips title prompt)
   --template         Template name under <current-root>/templates (default: default)
+                     With --jira and no --template, workspace.defaults.template_rules pick the template by issue type/label/project
   --jira             Resolve workspace id/title from Jira issue URL (email/token env required; base URL supports config)
+  --offline          With --jira, resolve the issue from the local Jira cache only
+  --no-notes         With --jira, do not write ticket notes (default path: notes/ticket.md)
   --format           Output format (human or json; default: human)
 `)
 }
@@ -302,9 +498,11 @@ Sources:
 
 func (c *CLI) printWSImportJiraUsage(w io.Writer) {
 	fmt.Fprint(w, `Usage:
-  kra ws import jira --sprint [<id|name>] --space <key> [--limit <n>] [--apply] [--no-prompt] [--format human|json]
-  kra ws import jira --sprint [<id|name>] --project <key> [--limit <n>] [--apply] [--no-prompt] [--format human|json]
-  kra ws import jira --jql "<expr>" [--limit <n>] [--apply] [--no-prompt] [--format human|json]
+  kra ws import jira --sprint [<id|name>] --space <key> [--template <name>] [--limit <n>] [--apply] [--no-prompt] [--format human|json]
+  kra ws import jira --sprint [<id|name>] --project <key> [--template <name>] [--limit <n>] [--apply] [--no-prompt] [--format human|json]
+  kra ws import jira --jql "<expr>" [--template <name>] [--limit <n>] [--apply] [--no-prompt] [--format human|json]
+  kra ws import jira --epic <key> [--no-parent] [--mine] [--exclude-done] [--template <name>] [--limit <n>] [--apply] [--no-prompt] [--format human|json]
+  kra ws import jira --subtasks-of <key> [--no-parent] [--mine] [--exclude-done] [--template <name>] [--limit <n>] [--apply] [--no-prompt] [--format human|json]
 
 Plan-first bulk workspace creation from Jira.
 
@@ -314,32 +512,185 @@ Rules:
   --space (or --project) is required with --sprint.
   --space and --project cannot be combined.
   --board is not supported (use --space/--project with --sprint).
+  --epic/--subtasks-of import child issues and record parent_id in each child workspace.
+  The parent issue is planned as a coordination workspace unless --no-parent is set.
+  --epic also includes issues linked to the epic.
+  Children of any assignee/status are included; --mine and --exclude-done narrow them down.
+  --epic/--subtasks-of cannot be combined with --sprint, --jql, or --space/--project.
   --limit default is 30 (range: 1..200).
+  --offline reads Jira data only from the local cache ($KRA_HOME/cache/jira/).
+  When Jira is unreachable, cached results are used with a warning (stale-while-error).
+  On apply, ticket details are written to notes/ticket.md (integration.jira.notes.path); --no-notes skips them.
+  Template per issue: --template > workspace.defaults.template_rules (issue_type/label/project) > workspace.defaults.template > default.
+`)
+}
+
+func (c *CLI) printJiraUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra jira <subcommand> [args]
+
+Subcommands:
+  cache             Offline Jira cache commands
+  help              Show this help
+`)
+}
+
+func (c *CLI) printJiraCacheUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra jira cache show [--format human|json]
+  kra jira cache prune [--all] [--format human|json]
+
+Inspect or prune the offline Jira cache under $KRA_HOME/cache/jira/ (issues, boards, sprints).
+
+Options:
+  --all              prune: remove every entry (default: only entries past their TTL)
+  --format           Output format (human or json; default: human)
+
+TTLs default to issues=15m boards=24h sprints=1h and are configurable via integration.jira.cache.*_ttl.
 `)
 }
 
 func (c *CLI) printRepoAddUsage(w io.Writer) {
 	fmt.Fprint(w, `Usage:
-  kra repo add [--format human|json] <repo-spec>...
+  kra repo add [--format human|json] [--filter <filter>] <repo-spec>...
 
 Add one or more repositories into the shared repo pool and register them in the current root index.
 
+Options:
+  --format          Output format (default: human)
+  --filter          Partial clone filter for the bare repo: blob:none, blob:limit=<n>[k|m|g], tree:<depth>
+                    (missing objects are fetched on demand; kept by repo apply/export via repos.yaml "filter")
+
 Accepted repo-spec formats:
   - git@<host>:<owner>/<repo>.git
+  - ssh://[user@]<host>[:port]/<owner>/<repo>.git
   - https://<host>/<owner>/<repo>[.git]
   - file://.../<host>/<owner>/<repo>.git
+
+<owner> may be a nested namespace (e.g. GitLab subgroups: group/subgroup).
+`)
+}
+
+func (c *CLI) printRepoListUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra repo list [--format human|tsv|json]
+  kra repo list --groups
+
+List repositories registered in the current root with pool health:
+remote URL, default branch (origin/HEAD), bare repo size, last fetch time,
+usage count (workspaces referencing the repo) and active workspaces bound to it.
+Groups are declared per repo in <root>/.kra/repos.yaml (groups: [...]).
+
+Options:
+  --format          Output format (default: human)
+  --groups          Print only group names (one per line)
+`)
+}
+
+func (c *CLI) printRepoInfoUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra repo info [--format human|json] <repo-key|repo-uid>
+
+Show one registered repository: the repo list fields, linked worktrees of the bare repo,
+and known roots (root registry) whose workspaces reference it.
+`)
+}
+
+func (c *CLI) printRepoUsageUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra repo usage [--format human|json] [<repo-key|repo-uid>]
+
+Show which workspaces (active and archived, from repos_restore) use each registered repository,
+with the branch recorded for each workspace. Without an argument, every registered repo is listed.
+Warns about branches recorded by more than one workspace and branches that no longer exist upstream.
+Upstream state reflects the last fetch of the pool bare repo (see: kra repo fetch).
+
+Options:
+  --format          Output format (default: human)
+`)
+}
+
+func (c *CLI) printRepoFetchUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra repo fetch [--all] [--parallel N] [--format human|json] [<repo-key|repo-uid>...]
+  kra repo fetch [--all] [--parallel N] --watch [--interval 10m]
+
+Fetch bare repos in the shared repo pool and record the last-fetch time.
+ws add-repo reuses that time for its fetch TTL, so a recent repo fetch keeps attach off the network.
+Fetches of the same bare repo are serialized across kra processes (repo fetch, ws add-repo).
+
+Options:
+  --all             Fetch every bare repo in the shared pool (default: repos registered in the current root)
+  --parallel        Max concurrent fetches (default: 4)
+  --watch           Keep running and fetch again every --interval (Ctrl-C to stop)
+  --interval        Watch interval (default: 10m; minimum: 1m)
+  --format          Output format (default: human; json cannot be combined with --watch)
 `)
 }
 
 func (c *CLI) printRepoDiscoverUsage(w io.Writer) {
 	fmt.Fprint(w, `Usage:
-  kra repo discover --org <org> [--provider github]
+  kra repo discover --org <org> [--provider github|gitlab|bitbucket|bitbucket-server|local] [filters] [--all [--yes]] [--format human|json]
 
 Discover repositories from provider, select multiple repos, and add them into the shared repo pool.
 
 Options:
-  --org             Organization name (required)
+  --org             Organization / group / workspace / project (required)
+                    github: org or user, gitlab: group path (subgroups included, e.g. acme/platform),
+                    bitbucket: workspace slug, bitbucket-server: project key (or ~user),
+                    local: directory to scan for bare/non-bare git repos
   --provider        Provider name (default: github)
+  --topic           Require topic (repeatable or comma-separated; all must match)
+  --language        Require primary language (case-insensitive)
+  --exclude-archived  Skip archived repos
+  --exclude-forks   Skip forks
+  --match           Glob matched against owner/repo or repo name (e.g. 'svc-*')
+  --all             Select all matching repos without the interactive selector
+  --yes             Skip confirmation (requires --all)
+  --format          Output format (default: human; json requires --all --yes)
+
+Provider credentials (env):
+  github            gh CLI login (gh auth login)
+  gitlab            KRA_GITLAB_TOKEN (KRA_GITLAB_BASE_URL, default: https://gitlab.com)
+  bitbucket         KRA_BITBUCKET_USERNAME + KRA_BITBUCKET_APP_PASSWORD, or KRA_BITBUCKET_TOKEN
+                    (KRA_BITBUCKET_BASE_URL, default: https://api.bitbucket.org)
+  bitbucket-server  KRA_BITBUCKET_SERVER_URL + KRA_BITBUCKET_SERVER_TOKEN
+  local             none (offline; reads the filesystem only)
+`)
+}
+
+func (c *CLI) printRepoApplyUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra repo apply [-f <path>] [--prune] [--dry-run] [--format human|json]
+
+Reconcile the current root's registered repos with a repo manifest.
+Repos listed in the manifest but missing from the pool are added (same path as repo add).
+
+Options:
+  -f, --file        Manifest path (default: <root>/.kra/repos.yaml)
+  --prune           Also detach registered repos not listed in the manifest (same policy as repo remove)
+  --dry-run         Print the plan without applying
+  --format          Output format (default: human)
+
+Manifest format:
+  version: 1
+  repos:
+    - spec: git@github.com:acme/api.git
+      alias: api            # optional: worktree dir name for ws add-repo
+      base_ref: main        # optional: default base_ref for ws add-repo (origin/ is implied)
+      groups: [backend]     # optional: repo groups
+`)
+}
+
+func (c *CLI) printRepoExportUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  kra repo export [--output <path>]
+
+Print a repo manifest built from the current root's registered repos.
+alias/base_ref/groups from the existing <root>/.kra/repos.yaml are kept.
+
+Options:
+  -o, --output      Write to path instead of stdout ("-" = stdout)
 `)
 }
 
@@ -397,28 +748,37 @@ Options:
 
 func (c *CLI) printWSAddRepoUsage(w io.Writer) {
 	fmt.Fprint(w, `Usage:
-  kra ws add-repo [--id <workspace-id> | --current | --select] [<workspace-id>] [--format human|json] [--refresh] [--no-fetch]
-  kra ws add-repo --format json --id <workspace-id> --repo <repo-key> [--repo <repo-key> ...] [--branch <name>] [--base-ref <origin/branch>] [--refresh] [--no-fetch] [--yes]
+  kra ws add-repo [--id <workspace-id> | --current | --select] [<workspace-id>] [--group <name> ...] [--sparse <dir> ...] [--submodules] [--lfs] [--format human|json] [--refresh] [--no-fetch]
+  kra ws add-repo --format json --id <workspace-id> (--repo <repo-key> | --group <name>) [--repo <repo-key> | --group <name> ...] [--branch <name> | --reference] [--base-ref <origin/branch>] [--alias <name>] [--sparse <dir> ...] [--submodules] [--lfs] [--refresh] [--no-fetch] [--yes]
 
 Add repositories from the repo pool to a workspace.
 
 Inputs:
   workspace-id       Existing active workspace ID (optional when running under workspaces/<id>/)
   --id               Explicit workspace ID
+  --group            Add every repo of a repo group (repeatable; see kra repo list)
+  --sparse           Check out only this directory (repeatable; cone-mode sparse-checkout for every added repo)
+  --submodules       Initialize submodules recursively (also per repo: submodules: true in .kra/repos.yaml)
+  --lfs              Pull Git LFS objects (also per repo: lfs: true in .kra/repos.yaml)
 
 Behavior:
-  - Select one or more repos from the existing bare repo pool.
+  - Select one or more repos (or @group rows) from the existing bare repo pool.
   - For each selected repo, input base_ref and branch.
+  - --group skips selection and prompts; each repo uses its own default base_ref/branch.
   - base_ref accepts: origin/<branch>, <branch>, /<branch>.
   - Smart fetch runs for selected repos only (TTL=5m; --refresh forces, --no-fetch skips).
   - Show Plan, ask final confirmation, then create worktrees and bindings atomically.
+  - --sparse patterns and submodules/lfs options are stored in repos_restore and re-applied by ws reopen.
+  - Submodules registered in the repo pool are cloned from the pool (--reference --dissociate).
+  - --alias (with exactly one --repo) binds a repo again under another alias, e.g. main next to the feature branch.
+  - --reference checks out base_ref detached and read-only; reference repos are excluded from risk gates, ws push and ws pr.
 `)
 }
 
 func (c *CLI) printWSRemoveRepoUsage(w io.Writer) {
 	fmt.Fprint(w, `Usage:
   kra ws remove-repo [--id <workspace-id> | --current | --select] [<workspace-id>] [--format human|json]
-  kra ws remove-repo --format json --id <workspace-id> --repo <repo-key> [--repo <repo-key> ...] [--yes] [--force]
+  kra ws remove-repo --format json --id <workspace-id> --repo <repo-key|alias> [--repo <repo-key|alias> ...] [--yes] [--force]
 
 Remove repositories from a workspace (binding + worktree).
 
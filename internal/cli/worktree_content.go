@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
+	"github.com/tasuku43/kra/internal/core/repostore"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+)
+
+// worktreeContentOptions controls what is populated after a worktree checkout.
+type worktreeContentOptions struct {
+	// Submodules initializes submodules recursively.
+	Submodules bool
+	// LFS downloads Git LFS objects for the checked-out revision.
+	LFS bool
+}
+
+func populateWorktreeContent(ctx context.Context, repoPoolPath string, worktreePath string, opts worktreeContentOptions) error {
+	if opts.Submodules {
+		if err := initWorktreeSubmodules(ctx, repoPoolPath, worktreePath); err != nil {
+			return fmt.Errorf("init submodules: %w", err)
+		}
+	}
+	if opts.LFS {
+		if _, err := gitutil.Run(ctx, worktreePath, "lfs", "version"); err != nil {
+			return fmt.Errorf("git-lfs is not available (required for lfs): %w", err)
+		}
+		if _, err := gitutil.Run(ctx, worktreePath, "lfs", "pull"); err != nil {
+			return fmt.Errorf("lfs pull: %w", err)
+		}
+	}
+	return nil
+}
+
+// initWorktreeSubmodules initializes the submodules declared in dir/.gitmodules, then recurses into each.
+// Submodules whose URL is registered in the repo pool are cloned with --reference <bare> --dissociate,
+// so objects come from the pool and the clone stays independent of it afterwards.
+func initWorktreeSubmodules(ctx context.Context, repoPoolPath string, dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".gitmodules")); err != nil {
+		return nil
+	}
+	out, err := gitutil.Run(ctx, dir, "config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.path$`)
+	if err != nil {
+		// Exit status 1: no submodule entries.
+		return nil
+	}
+	for _, line := range strings.Split(out, "\n") {
+		key, subPath, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || strings.TrimSpace(subPath) == "" {
+			continue
+		}
+		subPath = strings.TrimSpace(subPath)
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "submodule."), ".path")
+		url, _ := gitutil.Run(ctx, dir, "config", "--file", ".gitmodules", "--get", "submodule."+name+".url")
+
+		args := []string{"submodule", "update", "--init"}
+		if barePath := repoPoolBareForURL(repoPoolPath, strings.TrimSpace(url)); barePath != "" {
+			args = append(args, "--reference", barePath, "--dissociate")
+		}
+		args = append(args, "--", subPath)
+		if _, err := gitutil.Run(ctx, dir, args...); err != nil {
+			return fmt.Errorf("%s: %w", subPath, err)
+		}
+		if err := initWorktreeSubmodules(ctx, repoPoolPath, filepath.Join(dir, subPath)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repoPoolBareForURL returns the pool bare repo for a remote URL ("" when unregistered or not a repo spec).
+func repoPoolBareForURL(repoPoolPath string, url string) string {
+	if url == "" || strings.TrimSpace(repoPoolPath) == "" {
+		return ""
+	}
+	spec, err := repospec.Normalize(url)
+	if err != nil {
+		return ""
+	}
+	barePath := repostore.StorePath(repoPoolPath, spec)
+	if fi, err := os.Stat(barePath); err != nil || !fi.IsDir() {
+		return ""
+	}
+	return barePath
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+)
+
+// normalizeSparsePatterns validates sparse-checkout directories (cone mode) and drops duplicates.
+// Patterns are repo-relative directories; leading/trailing slashes are ignored.
+func normalizeSparsePatterns(patterns []string) ([]string, error) {
+	out := make([]string, 0, len(patterns))
+	seen := map[string]bool{}
+	for _, raw := range patterns {
+		p := strings.TrimSpace(raw)
+		if strings.HasPrefix(p, "/") {
+			return nil, fmt.Errorf("invalid sparse pattern %q: must be a repo-relative directory", raw)
+		}
+		p = strings.Trim(p, "/")
+		if p == "" {
+			return nil, fmt.Errorf("invalid sparse pattern %q: must not be empty", raw)
+		}
+		if cleaned := path.Clean(p); cleaned != p || cleaned == ".." || strings.HasPrefix(cleaned, "../") || cleaned == "." {
+			return nil, fmt.Errorf("invalid sparse pattern %q: must be a clean repo-relative directory", raw)
+		}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// addWorktreeWithSparse creates a worktree for branch; with sparse patterns it checks out only those
+// directories (plus top-level files) via cone-mode sparse-checkout.
+func addWorktreeWithSparse(ctx context.Context, barePath string, worktreePath string, branch string, sparse []string) error {
+	return addWorktreeAt(ctx, barePath, worktreePath, branch, false, sparse)
+}
+
+// addReferenceWorktreeWithSparse creates a reference worktree with HEAD detached at ref (no branch).
+func addReferenceWorktreeWithSparse(ctx context.Context, barePath string, worktreePath string, ref string, sparse []string) error {
+	return addWorktreeAt(ctx, barePath, worktreePath, ref, true, sparse)
+}
+
+func addWorktreeAt(ctx context.Context, barePath string, worktreePath string, commitish string, detach bool, sparse []string) error {
+	addArgs := []string{"worktree", "add"}
+	if detach {
+		addArgs = append(addArgs, "--detach")
+	}
+	if len(sparse) == 0 {
+		_, err := gitutil.RunBare(ctx, barePath, append(addArgs, worktreePath, commitish)...)
+		return err
+	}
+	if _, err := gitutil.RunBare(ctx, barePath, append(addArgs, "--no-checkout", worktreePath, commitish)...); err != nil {
+		return err
+	}
+	// Drop the half-created worktree so callers only need to roll back what they created.
+	removeWorktree := func() {
+		_, _ = gitutil.RunBare(ctx, barePath, "worktree", "remove", "--force", worktreePath)
+		_ = os.RemoveAll(worktreePath)
+	}
+	args := append([]string{"sparse-checkout", "set", "--cone"}, sparse...)
+	if _, err := gitutil.Run(ctx, worktreePath, args...); err != nil {
+		removeWorktree()
+		return fmt.Errorf("sparse-checkout set: %w", err)
+	}
+	if _, err := gitutil.Run(ctx, worktreePath, "checkout"); err != nil {
+		removeWorktree()
+		return fmt.Errorf("checkout: %w", err)
+	}
+	return nil
+}
+
+// detectWorktreeSparse returns the cone-mode sparse directories of a worktree (nil when not sparse).
+func detectWorktreeSparse(ctx context.Context, worktreePath string) []string {
+	enabled, err := gitutil.Run(ctx, worktreePath, "config", "--get", "core.sparseCheckout")
+	if err != nil || strings.TrimSpace(enabled) != "true" {
+		return nil
+	}
+	out, err := gitutil.Run(ctx, worktreePath, "sparse-checkout", "list")
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			dirs = append(dirs, line)
+		}
+	}
+	return dirs
+}
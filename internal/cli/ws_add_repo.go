@@ -22,6 +22,7 @@ import (
 	"github.com/tasuku43/kra/internal/core/repostore"
 	"github.com/tasuku43/kra/internal/infra/gitutil"
 	"github.com/tasuku43/kra/internal/infra/paths"
+	"github.com/tasuku43/kra/internal/repomanifest"
 )
 
 type addRepoPoolCandidate struct {
@@ -30,6 +31,13 @@ type addRepoPoolCandidate struct {
 	RemoteURL string
 	Alias     string
 	BarePath  string
+	// BaseRef is the repo manifest default base_ref (empty: detect from bare).
+	BaseRef string
+	// Groups are the repo manifest groups this repo belongs to.
+	Groups []string
+	// Submodules / LFS are the repo manifest worktree content options.
+	Submodules bool
+	LFS        bool
 }
 
 type addRepoPlanItem struct {
@@ -40,6 +48,11 @@ type addRepoPlanItem struct {
 	BaseRefUsed    string
 	Branch         string
 	WorktreePath   string
+	Sparse         []string
+	Submodules     bool
+	LFS            bool
+	// Reference binds the repo read-only, detached at BaseRefUsed (Branch is empty).
+	Reference bool
 
 	LocalBranchExists  bool
 	RemoteBranchExists bool
@@ -112,8 +125,13 @@ func (c *CLI) runWSAddRepo(args []string) int {
 	refreshFetch := false
 	noFetch := false
 	repoKeysFromFlag := make([]string, 0, 4)
+	groupsFromFlag := make([]string, 0, 2)
 	branchFromFlag := ""
 	baseRefFromFlag := ""
+	aliasFromFlag := ""
+	reference := false
+	sparseFromFlag := make([]string, 0, 2)
+	content := worktreeContentOptions{}
 	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
 		switch args[0] {
 		case "-h", "--help", "help":
@@ -135,6 +153,14 @@ func (c *CLI) runWSAddRepo(args []string) int {
 			}
 			repoKeysFromFlag = append(repoKeysFromFlag, strings.TrimSpace(args[1]))
 			args = args[2:]
+		case "--group":
+			if len(args) < 2 {
+				fmt.Fprintln(c.Err, "--group requires a value")
+				c.printWSAddRepoUsage(c.Err)
+				return exitUsage
+			}
+			groupsFromFlag = append(groupsFromFlag, strings.TrimSpace(args[1]))
+			args = args[2:]
 		case "--branch":
 			if len(args) < 2 {
 				fmt.Fprintln(c.Err, "--branch requires a value")
@@ -151,9 +177,34 @@ func (c *CLI) runWSAddRepo(args []string) int {
 			}
 			baseRefFromFlag = strings.TrimSpace(args[1])
 			args = args[2:]
+		case "--alias":
+			if len(args) < 2 {
+				fmt.Fprintln(c.Err, "--alias requires a value")
+				c.printWSAddRepoUsage(c.Err)
+				return exitUsage
+			}
+			aliasFromFlag = strings.TrimSpace(args[1])
+			args = args[2:]
+		case "--reference":
+			reference = true
+			args = args[1:]
+		case "--sparse":
+			if len(args) < 2 {
+				fmt.Fprintln(c.Err, "--sparse requires a value")
+				c.printWSAddRepoUsage(c.Err)
+				return exitUsage
+			}
+			sparseFromFlag = append(sparseFromFlag, args[1])
+			args = args[2:]
 		case "--yes":
 			forceApply = true
 			args = args[1:]
+		case "--submodules":
+			content.Submodules = true
+			args = args[1:]
+		case "--lfs":
+			content.LFS = true
+			args = args[1:]
 		case "--refresh":
 			refreshFetch = true
 			args = args[1:]
@@ -184,6 +235,11 @@ func (c *CLI) runWSAddRepo(args []string) int {
 				args = args[1:]
 				continue
 			}
+			if strings.HasPrefix(args[0], "--group=") {
+				groupsFromFlag = append(groupsFromFlag, strings.TrimSpace(strings.TrimPrefix(args[0], "--group=")))
+				args = args[1:]
+				continue
+			}
 			if strings.HasPrefix(args[0], "--branch=") {
 				branchFromFlag = strings.TrimSpace(strings.TrimPrefix(args[0], "--branch="))
 				args = args[1:]
@@ -194,6 +250,16 @@ func (c *CLI) runWSAddRepo(args []string) int {
 				args = args[1:]
 				continue
 			}
+			if strings.HasPrefix(args[0], "--alias=") {
+				aliasFromFlag = strings.TrimSpace(strings.TrimPrefix(args[0], "--alias="))
+				args = args[1:]
+				continue
+			}
+			if strings.HasPrefix(args[0], "--sparse=") {
+				sparseFromFlag = append(sparseFromFlag, strings.TrimPrefix(args[0], "--sparse="))
+				args = args[1:]
+				continue
+			}
 			if args[0] == "--refresh" {
 				refreshFetch = true
 				args = args[1:]
@@ -221,8 +287,31 @@ func (c *CLI) runWSAddRepo(args []string) int {
 		c.printWSAddRepoUsage(c.Err)
 		return exitUsage
 	}
-	if outputFormat == "human" && (len(repoKeysFromFlag) > 0 || branchFromFlag != "" || baseRefFromFlag != "" || forceApply) {
-		fmt.Fprintln(c.Err, "--repo/--branch/--base-ref/--yes are only supported with --format json")
+	sparse, err := normalizeSparsePatterns(sparseFromFlag)
+	if err != nil {
+		fmt.Fprintf(c.Err, "%v\n", err)
+		c.printWSAddRepoUsage(c.Err)
+		return exitUsage
+	}
+	if outputFormat == "human" && (len(repoKeysFromFlag) > 0 || branchFromFlag != "" || baseRefFromFlag != "" || aliasFromFlag != "" || reference || forceApply) {
+		fmt.Fprintln(c.Err, "--repo/--branch/--base-ref/--alias/--reference/--yes are only supported with --format json")
+		c.printWSAddRepoUsage(c.Err)
+		return exitUsage
+	}
+	if aliasFromFlag != "" {
+		if len(repoKeysFromFlag) != 1 || len(groupsFromFlag) > 0 {
+			fmt.Fprintln(c.Err, "--alias requires exactly one --repo and cannot be used with --group")
+			c.printWSAddRepoUsage(c.Err)
+			return exitUsage
+		}
+		if !isValidWorkspaceRepoAlias(aliasFromFlag) {
+			fmt.Fprintf(c.Err, "invalid --alias: %q\n", aliasFromFlag)
+			c.printWSAddRepoUsage(c.Err)
+			return exitUsage
+		}
+	}
+	if reference && branchFromFlag != "" {
+		fmt.Fprintln(c.Err, "--reference and --branch cannot be used together")
 		c.printWSAddRepoUsage(c.Err)
 		return exitUsage
 	}
@@ -304,7 +393,7 @@ func (c *CLI) runWSAddRepo(args []string) int {
 		return exitUsage
 	}
 	if outputFormat == "json" {
-		return c.runWSAddRepoJSON(workspaceID, root, repoPoolPath, repoKeysFromFlag, baseRefFromFlag, branchFromFlag, branchTemplate, forceApply, addRepoFetchOptions{
+		return c.runWSAddRepoJSON(workspaceID, root, repoPoolPath, repoKeysFromFlag, groupsFromFlag, baseRefFromFlag, branchFromFlag, branchTemplate, aliasFromFlag, reference, sparse, content, forceApply, addRepoFetchOptions{
 			Refresh: refreshFetch,
 			NoFetch: noFetch,
 		})
@@ -332,7 +421,7 @@ func (c *CLI) runWSAddRepo(args []string) int {
 	}
 	defer releaseLock()
 
-	candidates, err := listAddRepoPoolCandidates(ctx, root, repoPoolPath, workspaceID, time.Now(), c.debugf)
+	candidates, err := listAddRepoPoolCandidates(ctx, root, repoPoolPath, workspaceID, false, time.Now(), c.debugf)
 	if err != nil {
 		fmt.Fprintf(c.Err, "list repo pool candidates: %v\n", err)
 		return exitError
@@ -342,7 +431,14 @@ func (c *CLI) runWSAddRepo(args []string) int {
 		return exitError
 	}
 
-	selected, err := c.promptAddRepoPoolSelection(candidates)
+	// --group skips the selector and the per-repo prompts: every member uses its own defaults.
+	promptInputs := len(groupsFromFlag) == 0
+	var selected []addRepoPoolCandidate
+	if promptInputs {
+		selected, err = c.promptAddRepoPoolSelection(candidates)
+	} else {
+		selected, err = expandAddRepoGroups(root, candidates, groupsFromFlag)
+	}
 	if err != nil {
 		if errors.Is(err, errSelectorCanceled) {
 			fmt.Fprintln(c.Err, "aborted")
@@ -366,8 +462,11 @@ func (c *CLI) runWSAddRepo(args []string) int {
 	for i, cand := range selected {
 		progress[i] = addRepoInputProgress{RepoKey: cand.RepoKey}
 	}
-	c.debugf("add-repo inputs render stage=initial active_index=%d prev_lines=%d prompt_closed=%t show_pending_branch=%t keep_base_ref_open=%t", 0, 0, false, true, true)
-	renderedInputLines := renderAddRepoInputsProgress(c.Err, workspaceID, progress, 0, useColorErr, 0, true, true)
+	renderedInputLines := 0
+	if promptInputs {
+		c.debugf("add-repo inputs render stage=initial active_index=%d prev_lines=%d prompt_closed=%t show_pending_branch=%t keep_base_ref_open=%t", 0, 0, false, true, true)
+		renderedInputLines = renderAddRepoInputsProgress(c.Err, workspaceID, progress, 0, useColorErr, 0, true, true)
+	}
 
 	plan := make([]addRepoPlanItem, 0, len(selected))
 	prefetchRows := make([]addRepoFetchProgressRow, 0, len(selected))
@@ -377,59 +476,69 @@ func (c *CLI) runWSAddRepo(args []string) int {
 	var prefetchMu sync.Mutex
 	var prefetchWG sync.WaitGroup
 	for i, cand := range selected {
-		if i > 0 {
+		if promptInputs && i > 0 {
 			c.debugf("add-repo inputs render stage=next-repo active_index=%d prev_lines=%d prompt_closed=%t show_pending_branch=%t keep_base_ref_open=%t", i, renderedInputLines, false, true, true)
 			renderedInputLines = renderAddRepoInputsProgress(c.Err, workspaceID, progress, i, useColorErr, renderedInputLines, true, true)
 		}
-		defaultBaseRef, err := detectDefaultBaseRefFromBare(ctx, cand.BarePath)
+		defaultBaseRef, err := resolveAddRepoDefaultBaseRef(ctx, cand)
 		if err != nil {
 			fmt.Fprintf(c.Err, "detect default base_ref for %s: %v\n", cand.RepoKey, err)
 			return exitError
 		}
-		baseRefInput, baseRefEdited, err := c.promptAddRepoEditableInput(addRepoInputDetailPromptPrefix(useColorErr), "base_ref", defaultBaseRef, useColorErr)
-		if err != nil {
-			fmt.Fprintf(c.Err, "read base_ref: %v\n", err)
-			return exitError
-		}
-		c.debugf("add-repo base_ref input repo=%s raw=%q edited=%t", cand.RepoKey, baseRefInput, baseRefEdited)
-		baseRefUsed, err := resolveBaseRefInput(baseRefInput, defaultBaseRef)
-		if err != nil {
-			fmt.Fprintf(c.Err, "invalid base_ref (must be origin/<branch>): %q\n", baseRefInput)
-			return exitError
-		}
 		baseRefRecord := ""
-		if baseRefEdited {
-			baseRefRecord = baseRefUsed
+		if promptInputs {
+			baseRefInput, baseRefEdited, err := c.promptAddRepoEditableInput(addRepoInputDetailPromptPrefix(useColorErr), "base_ref", defaultBaseRef, useColorErr)
+			if err != nil {
+				fmt.Fprintf(c.Err, "read base_ref: %v\n", err)
+				return exitError
+			}
+			c.debugf("add-repo base_ref input repo=%s raw=%q edited=%t", cand.RepoKey, baseRefInput, baseRefEdited)
+			baseRefUsed, err := resolveBaseRefInput(baseRefInput, defaultBaseRef)
+			if err != nil {
+				fmt.Fprintf(c.Err, "invalid base_ref (must be origin/<branch>): %q\n", baseRefInput)
+				return exitError
+			}
+			if baseRefEdited {
+				baseRefRecord = baseRefUsed
+			}
+			progress[i].BaseRef = baseRefUsed
+			c.debugf("add-repo inputs render stage=after-base-ref active_index=%d prev_lines=%d prompt_closed=%t show_pending_branch=%t keep_base_ref_open=%t", i, renderedInputLines, true, false, true)
+			renderedInputLines = renderAddRepoInputsProgress(c.Err, workspaceID, progress, i, useColorErr, renderedInputLines, false, true)
 		}
-		progress[i].BaseRef = baseRefUsed
-		c.debugf("add-repo inputs render stage=after-base-ref active_index=%d prev_lines=%d prompt_closed=%t show_pending_branch=%t keep_base_ref_open=%t", i, renderedInputLines, true, false, true)
-		renderedInputLines = renderAddRepoInputsProgress(c.Err, workspaceID, progress, i, useColorErr, renderedInputLines, false, true)
 
 		branchDisplayDefault, err := renderAddRepoDefaultBranch(branchTemplate, workspaceID, cand.RepoKey)
 		if err != nil {
 			fmt.Fprintf(c.Err, "invalid workspace.branch.template: %v\n", err)
 			return exitError
 		}
-		branchInput, _, err := c.promptAddRepoEditableInput(addRepoInputDetailPromptPrefix(useColorErr), "branch", branchDisplayDefault, useColorErr)
-		if err != nil {
-			fmt.Fprintf(c.Err, "read branch: %v\n", err)
-			return exitError
+		branch := branchDisplayDefault
+		if promptInputs {
+			branchInput, _, err := c.promptAddRepoEditableInput(addRepoInputDetailPromptPrefix(useColorErr), "branch", branchDisplayDefault, useColorErr)
+			if err != nil {
+				fmt.Fprintf(c.Err, "read branch: %v\n", err)
+				return exitError
+			}
+			c.debugf("add-repo branch input repo=%s raw=%q", cand.RepoKey, branchInput)
+			branch = resolveBranchInput(branchInput, branchDisplayDefault)
 		}
-		c.debugf("add-repo branch input repo=%s raw=%q", cand.RepoKey, branchInput)
-		branch := resolveBranchInput(branchInput, branchDisplayDefault)
 		if err := gitutil.CheckRefFormat(ctx, "refs/heads/"+branch); err != nil {
 			fmt.Fprintf(c.Err, "invalid branch name for %s: %v\n", cand.RepoKey, err)
 			return exitError
 		}
-		progress[i].Branch = branch
-		c.debugf("add-repo inputs render stage=after-branch active_index=%d prev_lines=%d prompt_closed=%t show_pending_branch=%t keep_base_ref_open=%t", i, renderedInputLines, true, false, false)
-		renderedInputLines = renderAddRepoInputsProgress(c.Err, workspaceID, progress, i, useColorErr, renderedInputLines, false, false)
+		if promptInputs {
+			progress[i].Branch = branch
+			c.debugf("add-repo inputs render stage=after-branch active_index=%d prev_lines=%d prompt_closed=%t show_pending_branch=%t keep_base_ref_open=%t", i, renderedInputLines, true, false, false)
+			renderedInputLines = renderAddRepoInputsProgress(c.Err, workspaceID, progress, i, useColorErr, renderedInputLines, false, false)
+		}
 
 		plan = append(plan, addRepoPlanItem{
 			Candidate:      cand,
 			BaseRefInput:   baseRefRecord,
 			DefaultBaseRef: defaultBaseRef,
 			Branch:         branch,
+			Sparse:         sparse,
+			Submodules:     content.Submodules || cand.Submodules,
+			LFS:            content.LFS || cand.LFS,
 		})
 		planItem := plan[len(plan)-1]
 		decision, err := evaluateAddRepoFetchDecision(ctx, planItem, fetchOpts)
@@ -522,7 +631,12 @@ func (c *CLI) runWSAddRepo(args []string) int {
 		return exitError
 	}
 
-	applied, err := applyAddRepoPlanAllOrNothing(ctx, plan, c.debugf)
+	commitHooks, err := c.loadAddRepoCommitHooks(root, workspaceID)
+	if err != nil {
+		fmt.Fprintf(c.Err, "resolve commit hooks: %v\n", err)
+		return exitError
+	}
+	applied, err := applyAddRepoPlanAllOrNothing(ctx, repoPoolPath, plan, commitHooks, c.debugf)
 	if err != nil {
 		fmt.Fprintf(c.Err, "apply add-repo: %v\n", err)
 		return exitError
@@ -540,16 +654,16 @@ func (c *CLI) runWSAddRepo(args []string) int {
 	return exitOK
 }
 
-func (c *CLI) runWSAddRepoJSON(workspaceID string, root string, repoPoolPath string, repoKeys []string, baseRefInput string, branchInput string, branchTemplate string, yes bool, fetchOpts addRepoFetchOptions) int {
+func (c *CLI) runWSAddRepoJSON(workspaceID string, root string, repoPoolPath string, repoKeys []string, groups []string, baseRefInput string, branchInput string, branchTemplate string, aliasInput string, reference bool, sparse []string, content worktreeContentOptions, yes bool, fetchOpts addRepoFetchOptions) int {
 	ctx := context.Background()
-	if len(repoKeys) == 0 {
+	if len(repoKeys) == 0 && len(groups) == 0 {
 		_ = writeCLIJSON(c.Out, cliJSONResponse{
 			OK:          false,
 			Action:      "add-repo",
 			WorkspaceID: workspaceID,
 			Error: &cliJSONError{
 				Code:    "invalid_argument",
-				Message: "--repo is required in --format json mode",
+				Message: "--repo or --group is required in --format json mode",
 			},
 		})
 		return exitUsage
@@ -620,7 +734,8 @@ func (c *CLI) runWSAddRepoJSON(workspaceID string, root string, repoPoolPath str
 	}
 	defer releaseLock()
 
-	candidates, err := listAddRepoPoolCandidates(ctx, root, repoPoolPath, workspaceID, time.Now(), c.debugf)
+	// An explicit --alias may bind a repo that is already bound under another alias.
+	candidates, err := listAddRepoPoolCandidates(ctx, root, repoPoolPath, workspaceID, aliasInput != "", time.Now(), c.debugf)
 	if err != nil {
 		_ = writeCLIJSON(c.Out, cliJSONResponse{
 			OK:          false,
@@ -637,6 +752,22 @@ func (c *CLI) runWSAddRepoJSON(workspaceID string, root string, repoPoolPath str
 	for _, cand := range candidates {
 		byRepoKey[cand.RepoKey] = cand
 	}
+	if len(groups) > 0 {
+		members, err := expandAddRepoGroups(root, candidates, groups)
+		if err != nil {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:          false,
+				Action:      "add-repo",
+				WorkspaceID: workspaceID,
+				Error: &cliJSONError{
+					Code:    "invalid_argument",
+					Message: err.Error(),
+				},
+			})
+			return exitUsage
+		}
+		repoKeys = append(slices.Clone(repoKeys), addRepoCandidateKeys(members)...)
+	}
 	plan := make([]addRepoPlanItem, 0, len(repoKeys))
 	seen := map[string]bool{}
 	for _, repoKey := range repoKeys {
@@ -658,7 +789,10 @@ func (c *CLI) runWSAddRepoJSON(workspaceID string, root string, repoPoolPath str
 			})
 			return exitUsage
 		}
-		defaultBaseRef, err := detectDefaultBaseRefFromBare(ctx, cand.BarePath)
+		if aliasInput != "" {
+			cand.Alias = aliasInput
+		}
+		defaultBaseRef, err := resolveAddRepoDefaultBaseRef(ctx, cand)
 		if err != nil {
 			_ = writeCLIJSON(c.Out, cliJSONResponse{
 				OK:          false,
@@ -684,6 +818,19 @@ func (c *CLI) runWSAddRepoJSON(workspaceID string, root string, repoPoolPath str
 			})
 			return exitUsage
 		}
+		if reference {
+			plan = append(plan, addRepoPlanItem{
+				Candidate:      cand,
+				BaseRefInput:   baseRefInput,
+				DefaultBaseRef: defaultBaseRef,
+				BaseRefUsed:    baseRefUsed,
+				Sparse:         sparse,
+				Submodules:     content.Submodules || cand.Submodules,
+				LFS:            content.LFS || cand.LFS,
+				Reference:      true,
+			})
+			continue
+		}
 		defaultBranch, err := renderAddRepoDefaultBranch(branchTemplate, workspaceID, cand.RepoKey)
 		if err != nil {
 			_ = writeCLIJSON(c.Out, cliJSONResponse{
@@ -716,6 +863,9 @@ func (c *CLI) runWSAddRepoJSON(workspaceID string, root string, repoPoolPath str
 			DefaultBaseRef: defaultBaseRef,
 			BaseRefUsed:    baseRefUsed,
 			Branch:         branch,
+			Sparse:         sparse,
+			Submodules:     content.Submodules || cand.Submodules,
+			LFS:            content.LFS || cand.LFS,
 		})
 	}
 
@@ -743,7 +893,20 @@ func (c *CLI) runWSAddRepoJSON(workspaceID string, root string, repoPoolPath str
 		})
 		return exitError
 	}
-	applied, err := applyAddRepoPlanAllOrNothing(ctx, plan, c.debugf)
+	commitHooks, err := c.loadAddRepoCommitHooks(root, workspaceID)
+	if err != nil {
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:          false,
+			Action:      "add-repo",
+			WorkspaceID: workspaceID,
+			Error: &cliJSONError{
+				Code:    "internal_error",
+				Message: fmt.Sprintf("resolve commit hooks: %v", err),
+			},
+		})
+		return exitError
+	}
+	applied, err := applyAddRepoPlanAllOrNothing(ctx, repoPoolPath, plan, commitHooks, c.debugf)
 	if err != nil {
 		_ = writeCLIJSON(c.Out, cliJSONResponse{
 			OK:          false,
@@ -938,7 +1101,7 @@ func isProcessAlive(pid int) bool {
 	return true
 }
 
-func listAddRepoPoolCandidates(ctx context.Context, root string, repoPoolPath string, workspaceID string, now time.Time, debugf func(string, ...any)) ([]addRepoPoolCandidate, error) {
+func listAddRepoPoolCandidates(ctx context.Context, root string, repoPoolPath string, workspaceID string, includeBound bool, now time.Time, debugf func(string, ...any)) ([]addRepoPoolCandidate, error) {
 	_ = now
 	baseCandidates, err := scanRepoPoolCandidatesFromFilesystem(ctx, repoPoolPath, debugf)
 	if err != nil {
@@ -954,9 +1117,14 @@ func listAddRepoPoolCandidates(ctx context.Context, root string, repoPoolPath st
 		boundRepoUID[r.RepoUID] = true
 	}
 
+	manifest, _, err := repomanifest.LoadOptional(repomanifest.Path(root))
+	if err != nil && debugf != nil {
+		debugf("ws add-repo: repo manifest ignored err=%v", err)
+	}
+
 	out := make([]addRepoPoolCandidate, 0, len(baseCandidates))
 	for _, it := range baseCandidates {
-		if boundRepoUID[it.RepoUID] {
+		if boundRepoUID[it.RepoUID] && !includeBound {
 			continue
 		}
 		spec, err := repospec.Normalize(it.RemoteURL)
@@ -973,13 +1141,23 @@ func listAddRepoPoolCandidates(ctx context.Context, root string, repoPoolPath st
 			}
 			continue
 		}
-		out = append(out, addRepoPoolCandidate{
+		cand := addRepoPoolCandidate{
 			RepoUID:   it.RepoUID,
 			RepoKey:   it.RepoKey,
 			RemoteURL: it.RemoteURL,
 			Alias:     deriveAliasFromRepoKey(it.RepoKey),
 			BarePath:  barePath,
-		})
+		}
+		if entry, ok := manifest.Lookup(it.RepoUID); ok {
+			if entry.Alias != "" {
+				cand.Alias = entry.Alias
+			}
+			cand.BaseRef = entry.BaseRef
+			cand.Groups = entry.Groups
+			cand.Submodules = entry.Submodules
+			cand.LFS = entry.LFS
+		}
+		out = append(out, cand)
 	}
 	return out, nil
 }
@@ -1058,6 +1236,108 @@ func deriveAliasFromRepoKey(repoKey string) string {
 	return parts[len(parts)-1]
 }
 
+// isValidWorkspaceRepoAlias reports whether alias can name a directory under workspaces/<id>/repos.
+func isValidWorkspaceRepoAlias(alias string) bool {
+	return alias != "" && !strings.ContainsAny(alias, `/\`) && !strings.HasPrefix(alias, ".")
+}
+
+// expandAddRepoGroups resolves repo manifest group names to available candidates in manifest order.
+// Members already bound to the workspace are skipped; a group without available members is an error.
+func expandAddRepoGroups(root string, candidates []addRepoPoolCandidate, groups []string) ([]addRepoPoolCandidate, error) {
+	manifest, _, err := repomanifest.LoadOptional(repomanifest.Path(root))
+	if err != nil {
+		return nil, fmt.Errorf("load repo manifest: %w", err)
+	}
+	byRepoUID := make(map[string]addRepoPoolCandidate, len(candidates))
+	for _, cand := range candidates {
+		byRepoUID[cand.RepoUID] = cand
+	}
+	out := make([]addRepoPoolCandidate, 0, len(candidates))
+	seen := map[string]bool{}
+	for _, name := range groups {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		group, ok, err := manifest.FindGroup(name)
+		if err != nil {
+			return nil, fmt.Errorf("load repo manifest: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown repo group: %s (declare groups in %s)", name, repomanifest.RelPath)
+		}
+		available := 0
+		for _, member := range group.Repos {
+			cand, ok := byRepoUID[member.RepoUID]
+			if !ok {
+				continue
+			}
+			available++
+			if seen[cand.RepoUID] {
+				continue
+			}
+			seen[cand.RepoUID] = true
+			out = append(out, cand)
+		}
+		if available == 0 {
+			return nil, fmt.Errorf("no repos available in group %s for this workspace", name)
+		}
+	}
+	return out, nil
+}
+
+func addRepoCandidateKeys(candidates []addRepoPoolCandidate) []string {
+	out := make([]string, 0, len(candidates))
+	for _, cand := range candidates {
+		out = append(out, cand.RepoKey)
+	}
+	return out
+}
+
+type addRepoSelectionGroup struct {
+	Name    string
+	Members []addRepoPoolCandidate
+}
+
+// listAddRepoSelectionGroups groups candidates by their repo manifest groups, sorted by name.
+func listAddRepoSelectionGroups(candidates []addRepoPoolCandidate) []addRepoSelectionGroup {
+	byName := map[string]*addRepoSelectionGroup{}
+	for _, cand := range candidates {
+		for _, name := range cand.Groups {
+			if byName[name] == nil {
+				byName[name] = &addRepoSelectionGroup{Name: name}
+			}
+			byName[name].Members = append(byName[name].Members, cand)
+		}
+	}
+	out := make([]addRepoSelectionGroup, 0, len(byName))
+	for _, g := range byName {
+		out = append(out, *g)
+	}
+	slices.SortFunc(out, func(a, b addRepoSelectionGroup) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+func addRepoSelectionGroupID(name string) string {
+	return "@" + name
+}
+
+func addRepoSelectionGroupTitle(g addRepoSelectionGroup) string {
+	return fmt.Sprintf("group: %s", strings.Join(addRepoCandidateKeys(g.Members), ", "))
+}
+
+// appendAddRepoSelection appends candidates not selected yet, keeping selection order.
+func appendAddRepoSelection(selected []addRepoPoolCandidate, seen map[string]bool, cands ...addRepoPoolCandidate) []addRepoPoolCandidate {
+	for _, cand := range cands {
+		if seen[cand.RepoKey] {
+			continue
+		}
+		seen[cand.RepoKey] = true
+		selected = append(selected, cand)
+	}
+	return selected
+}
+
 func (c *CLI) promptAddRepoPoolSelection(candidates []addRepoPoolCandidate) ([]addRepoPoolCandidate, error) {
 	if len(candidates) == 0 {
 		return nil, errSelectorCanceled
@@ -1065,8 +1345,18 @@ func (c *CLI) promptAddRepoPoolSelection(candidates []addRepoPoolCandidate) ([]a
 
 	inFile, ok := c.In.(*os.File)
 	if ok && isatty.IsTerminal(inFile.Fd()) {
-		selectorCandidates := make([]workspaceSelectorCandidate, 0, len(candidates))
+		groups := listAddRepoSelectionGroups(candidates)
+		selectorCandidates := make([]workspaceSelectorCandidate, 0, len(candidates)+len(groups))
 		candidateByID := make(map[string]addRepoPoolCandidate, len(candidates))
+		groupByID := make(map[string]addRepoSelectionGroup, len(groups))
+		for _, g := range groups {
+			id := addRepoSelectionGroupID(g.Name)
+			selectorCandidates = append(selectorCandidates, workspaceSelectorCandidate{
+				ID:    id,
+				Title: addRepoSelectionGroupTitle(g),
+			})
+			groupByID[id] = g
+		}
 		for _, it := range candidates {
 			selectorCandidates = append(selectorCandidates, workspaceSelectorCandidate{
 				ID:    it.RepoKey,
@@ -1079,12 +1369,17 @@ func (c *CLI) promptAddRepoPoolSelection(candidates []addRepoPoolCandidate) ([]a
 			return nil, err
 		}
 		selected := make([]addRepoPoolCandidate, 0, len(selectedIDs))
+		seen := map[string]bool{}
 		for _, id := range selectedIDs {
+			if g, exists := groupByID[id]; exists {
+				selected = appendAddRepoSelection(selected, seen, g.Members...)
+				continue
+			}
 			cand, exists := candidateByID[id]
 			if !exists {
 				continue
 			}
-			selected = append(selected, cand)
+			selected = appendAddRepoSelection(selected, seen, cand)
 		}
 		if len(selected) == 0 {
 			return nil, errSelectorCanceled
@@ -1101,17 +1396,27 @@ func (c *CLI) promptAddRepoPoolSelectionFallback(candidates []addRepoPoolCandida
 	}
 
 	useColorErr := writerSupportsColor(c.Err)
+	groups := listAddRepoSelectionGroups(candidates)
 	filter := ""
 	for {
 		visible := filterAddRepoPoolCandidates(candidates, filter)
+		visibleGroups := make([]addRepoSelectionGroup, 0, len(groups))
+		for _, g := range groups {
+			if strings.TrimSpace(filter) == "" || fuzzyFilterMatch(addRepoSelectionGroupID(g.Name), filter) {
+				visibleGroups = append(visibleGroups, g)
+			}
+		}
 		fmt.Fprintln(c.Err, styleBold("Repos(pool):", useColorErr))
 		fmt.Fprintln(c.Err)
-		if len(visible) == 0 {
+		if len(visible) == 0 && len(visibleGroups) == 0 {
 			fmt.Fprintf(c.Err, "%s(none)\n", uiIndent)
 		} else {
 			for i, it := range visible {
 				fmt.Fprintf(c.Err, "%s[%d] %s\n", uiIndent, i+1, it.RepoKey)
 			}
+			for i, g := range visibleGroups {
+				fmt.Fprintf(c.Err, "%s[%d] %s %s\n", uiIndent, len(visible)+i+1, addRepoSelectionGroupID(g.Name), styleMuted("("+addRepoSelectionGroupTitle(g)+")", useColorErr))
+			}
 		}
 		fmt.Fprintln(c.Err)
 		fmt.Fprintf(c.Err, "%s%s %s\n", uiIndent, styleMuted("filter:", useColorErr), filter)
@@ -1129,14 +1434,19 @@ func (c *CLI) promptAddRepoPoolSelectionFallback(candidates []addRepoPoolCandida
 			continue
 		}
 
-		indices, err := parseMultiSelectIndices(line, len(visible))
+		indices, err := parseMultiSelectIndices(line, len(visible)+len(visibleGroups))
 		if err != nil {
 			fmt.Fprintf(c.Err, "%sinvalid selection: %v\n", uiIndent, err)
 			continue
 		}
 		selected := make([]addRepoPoolCandidate, 0, len(indices))
+		seen := map[string]bool{}
 		for _, idx := range indices {
-			selected = append(selected, visible[idx])
+			if idx >= len(visible) {
+				selected = appendAddRepoSelection(selected, seen, visibleGroups[idx-len(visible)].Members...)
+				continue
+			}
+			selected = appendAddRepoSelection(selected, seen, visible[idx])
 		}
 		return selected, nil
 	}
@@ -1189,6 +1499,13 @@ func parseMultiSelectIndices(input string, max int) ([]int, error) {
 	return out, nil
 }
 
+func resolveAddRepoDefaultBaseRef(ctx context.Context, cand addRepoPoolCandidate) (string, error) {
+	if cand.BaseRef != "" {
+		return cand.BaseRef, nil
+	}
+	return detectDefaultBaseRefFromBare(ctx, cand.BarePath)
+}
+
 func detectDefaultBaseRefFromBare(ctx context.Context, barePath string) (string, error) {
 	if ref, err := gitutil.RunBare(ctx, barePath, "symbolic-ref", "--quiet", "refs/remotes/origin/HEAD"); err == nil {
 		ref = strings.TrimSpace(ref)
@@ -1255,18 +1572,14 @@ func evaluateAddRepoFetchDecision(ctx context.Context, p addRepoPlanItem, opts a
 		return addRepoFetchDecision{}, err
 	}
 	_ = okBranchRef
-	fetchHeadPath := filepath.Join(p.Candidate.BarePath, "FETCH_HEAD")
-	info, err := os.Stat(fetchHeadPath)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return addRepoFetchDecision{
-				ShouldFetch: true,
-				Reason:      "required (stale, age=unknown)",
-			}, nil
-		}
-		return addRepoFetchDecision{}, err
+	lastFetchedAt := repoPoolLastFetchedAt(p.Candidate.BarePath)
+	if lastFetchedAt.IsZero() {
+		return addRepoFetchDecision{
+			ShouldFetch: true,
+			Reason:      "required (stale, age=unknown)",
+		}, nil
 	}
-	age := time.Since(info.ModTime())
+	age := time.Since(lastFetchedAt)
 	if age <= addRepoFetchTTL {
 		return addRepoFetchDecision{
 			ShouldFetch: false,
@@ -1279,7 +1592,19 @@ func evaluateAddRepoFetchDecision(ctx context.Context, p addRepoPlanItem, opts a
 	}, nil
 }
 
+// runAddRepoFetchWithPolicy fetches one bare repo under the pool fetch lock.
+// When another process (repo fetch, a concurrent add-repo) finished a fetch while we waited,
+// that fetch is reused instead of running a second one.
 func runAddRepoFetchWithPolicy(ctx context.Context, barePath string) error {
+	requestedAt := time.Now()
+	release, err := acquireRepoPoolFetchLock(ctx, barePath)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if repoPoolLastFetchedAt(barePath).After(requestedAt) {
+		return nil
+	}
 	if _, err := gitutil.RunBare(ctx, barePath, "fetch", "origin", "--prune", "--no-tags"); err != nil {
 		if !isRetryableFetchError(err) {
 			return err
@@ -1288,7 +1613,7 @@ func runAddRepoFetchWithPolicy(ctx context.Context, barePath string) error {
 			return retryErr
 		}
 	}
-	return nil
+	return recordRepoPoolFetch(barePath, time.Now())
 }
 
 func isRetryableFetchError(err error) bool {
@@ -1552,6 +1877,9 @@ func preflightAddRepoPlan(ctx context.Context, root string, workspaceID string,
 		if !ok {
 			return fmt.Errorf("base_ref not found: %s", p.BaseRefUsed)
 		}
+		if p.Reference {
+			continue
+		}
 
 		localRef := "refs/heads/" + p.Branch
 		p.LocalBranchExists, err = gitutil.ShowRefExistsBare(ctx, p.Candidate.BarePath, localRef)
@@ -1615,12 +1943,29 @@ func printAddRepoPlan(out io.Writer, workspaceID string, plan []addRepoPlanItem,
 			connector = "└─ "
 		}
 		body = append(body, fmt.Sprintf("%s%s%s", uiIndent+uiIndent, connectorMuted(connector), p.Candidate.RepoKey))
+		details := make([]string, 0, 2)
 		if strings.TrimSpace(p.FetchDecision) != "" {
-			stem := "│  "
-			if i == len(plan)-1 {
-				stem = "   "
+			details = append(details, "fetch: "+p.FetchDecision)
+		}
+		if len(p.Sparse) > 0 {
+			details = append(details, "sparse: "+strings.Join(p.Sparse, ", "))
+		}
+		if p.Submodules {
+			details = append(details, "submodules: init (recursive)")
+		}
+		if p.LFS {
+			details = append(details, "lfs: pull")
+		}
+		stem := "│  "
+		if i == len(plan)-1 {
+			stem = "   "
+		}
+		for j, d := range details {
+			detailConnector := "├─"
+			if j == len(details)-1 {
+				detailConnector = "└─"
 			}
-			body = append(body, fmt.Sprintf("%s%s%s fetch: %s", uiIndent+uiIndent, connectorMuted(stem), connectorMuted("└─"), p.FetchDecision))
+			body = append(body, fmt.Sprintf("%s%s%s %s", uiIndent+uiIndent, connectorMuted(stem), connectorMuted(detailConnector), d))
 		}
 	}
 	fmt.Fprintln(out)
@@ -1850,13 +2195,21 @@ func addRepoBranchTemplateRepoName(repoKey string) string {
 	return trimmed[lastSlash+1:]
 }
 
-func applyAddRepoPlanAllOrNothing(ctx context.Context, plan []addRepoPlanItem, debugf func(string, ...any)) ([]addRepoAppliedItem, error) {
+func (c *CLI) loadAddRepoCommitHooks(root string, workspaceID string) (workspaceCommitHooks, error) {
+	meta, err := loadWorkspaceMetaFile(filepath.Join(root, "workspaces", workspaceID))
+	if err != nil {
+		return workspaceCommitHooks{}, fmt.Errorf("load %s: %w", workspaceMetaFilename, err)
+	}
+	return c.loadWorkspaceCommitHooks(root, workspaceID, meta.Workspace.SourceURL)
+}
+
+func applyAddRepoPlanAllOrNothing(ctx context.Context, repoPoolPath string, plan []addRepoPlanItem, commitHooks workspaceCommitHooks, debugf func(string, ...any)) ([]addRepoAppliedItem, error) {
 	applied := make([]addRepoAppliedItem, 0, len(plan))
 
 	for _, p := range plan {
 		current := addRepoAppliedItem{Plan: p}
 
-		if !p.LocalBranchExists {
+		if !p.Reference && !p.LocalBranchExists {
 			if p.RemoteBranchExists {
 				if _, err := gitutil.RunBare(ctx, p.Candidate.BarePath, "branch", "--track", p.Branch, "origin/"+p.Branch); err != nil {
 					rollbackAddRepoApplied(ctx, applied, debugf)
@@ -1871,12 +2224,29 @@ func applyAddRepoPlanAllOrNothing(ctx context.Context, plan []addRepoPlanItem, d
 			current.CreatedLocalBranch = true
 		}
 
-		if _, err := gitutil.RunBare(ctx, p.Candidate.BarePath, "worktree", "add", p.WorktreePath, p.Branch); err != nil {
+		var err error
+		if p.Reference {
+			err = addReferenceWorktreeWithSparse(ctx, p.Candidate.BarePath, p.WorktreePath, p.BaseRefUsed, p.Sparse)
+		} else {
+			err = addWorktreeWithSparse(ctx, p.Candidate.BarePath, p.WorktreePath, p.Branch, p.Sparse)
+		}
+		if err != nil {
 			rollbackAddRepoApplied(ctx, append(applied, current), debugf)
 			return nil, fmt.Errorf("create worktree for %s: %w", p.Candidate.RepoKey, err)
 		}
 		current.CreatedWorktree = true
 
+		if err := populateWorktreeContent(ctx, repoPoolPath, p.WorktreePath, worktreeContentOptions{Submodules: p.Submodules, LFS: p.LFS}); err != nil {
+			rollbackAddRepoApplied(ctx, append(applied, current), debugf)
+			return nil, fmt.Errorf("populate worktree for %s: %w", p.Candidate.RepoKey, err)
+		}
+		if !p.Reference {
+			if err := installWorkspaceCommitHooks(ctx, p.WorktreePath, commitHooks); err != nil {
+				rollbackAddRepoApplied(ctx, append(applied, current), debugf)
+				return nil, fmt.Errorf("install commit hooks for %s: %w", p.Candidate.RepoKey, err)
+			}
+		}
+
 		applied = append(applied, current)
 	}
 	return applied, nil
@@ -1917,12 +2287,16 @@ func buildWorkspaceMetaReposRestore(applied []addRepoAppliedItem) []workspaceMet
 	repos := make([]workspaceMetaRepoRestore, 0, len(applied))
 	for _, it := range applied {
 		repos = append(repos, workspaceMetaRepoRestore{
-			RepoUID:   it.Plan.Candidate.RepoUID,
-			RepoKey:   it.Plan.Candidate.RepoKey,
-			RemoteURL: it.Plan.Candidate.RemoteURL,
-			Alias:     it.Plan.Candidate.Alias,
-			Branch:    it.Plan.Branch,
-			BaseRef:   it.Plan.BaseRefUsed,
+			RepoUID:    it.Plan.Candidate.RepoUID,
+			RepoKey:    it.Plan.Candidate.RepoKey,
+			RemoteURL:  it.Plan.Candidate.RemoteURL,
+			Alias:      it.Plan.Candidate.Alias,
+			Branch:     it.Plan.Branch,
+			BaseRef:    it.Plan.BaseRefUsed,
+			Sparse:     it.Plan.Sparse,
+			Submodules: it.Plan.Submodules,
+			LFS:        it.Plan.LFS,
+			Reference:  it.Plan.Reference,
 		})
 	}
 	return repos
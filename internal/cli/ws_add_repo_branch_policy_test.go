@@ -122,3 +122,44 @@ func readWorkspaceMetaBranch(t *testing.T, root string, workspaceID string, repo
 	t.Fatalf("repo_key not found in repos_restore: %s", repoKey)
 	return ""
 }
+
+func TestCLI_WS_AddRepo_JSON_UsesRepoManifestAlias(t *testing.T) {
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+
+	repoSpec := createTestRemoteRepoSpec(t)
+	_, repoKey, _ := seedRepoPoolAndState(t, env, repoSpec)
+
+	manifestPath := filepath.Join(env.Root, ".kra", "repos.yaml")
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, []byte("repos:\n  - spec: "+repoSpec+"\n    alias: custom-alias\n    base_ref: main\n"), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	{
+		var out bytes.Buffer
+		var err bytes.Buffer
+		if code := New(&out, &err).Run([]string{"ws", "create", "--no-prompt", "WS1"}); code != exitOK {
+			t.Fatalf("ws create exit code = %d, want %d (stderr=%q)", code, exitOK, err.String())
+		}
+	}
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	code := New(&out, &err).Run([]string{"ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", repoKey, "--yes"})
+	if code != exitOK {
+		t.Fatalf("ws add-repo json exit code = %d, want %d (stdout=%q stderr=%q)", code, exitOK, out.String(), err.String())
+	}
+	meta, loadErr := loadWorkspaceMetaFile(filepath.Join(env.Root, "workspaces", "WS1"))
+	if loadErr != nil {
+		t.Fatalf("load %s: %v", workspaceMetaFilename, loadErr)
+	}
+	if len(meta.ReposRestore) != 1 || meta.ReposRestore[0].Alias != "custom-alias" || meta.ReposRestore[0].BaseRef != "origin/main" {
+		t.Fatalf("repos_restore = %+v, want manifest alias/base_ref", meta.ReposRestore)
+	}
+	if _, statErr := os.Stat(filepath.Join(env.Root, "workspaces", "WS1", "repos", "custom-alias")); statErr != nil {
+		t.Fatalf("worktree should use manifest alias: %v (stdout=%q)", statErr, out.String())
+	}
+}
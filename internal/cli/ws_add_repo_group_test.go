@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/repomanifest"
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_AddRepo_Group_JSONAndHumanUsePerRepoDefaults(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(stdin string, args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		c.In = strings.NewReader(stdin)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	webSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "web")
+	docsSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "docs")
+	if code, _, stderr := run("", "repo", "add", apiSpec, webSpec, docsSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+
+	manifestPath := repomanifest.Path(env.Root)
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	manifestBody := fmt.Sprintf("repos:\n  - spec: %s\n    alias: api-svc\n    base_ref: main\n    groups: [backend]\n  - spec: %s\n    groups: [backend, frontend]\n  - spec: %s\n    groups: [frontend]\n", apiSpec, webSpec, docsSpec)
+	if err := os.WriteFile(manifestPath, []byte(manifestBody), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	code, out, stderr := run("", "repo", "list")
+	if code != exitOK {
+		t.Fatalf("repo list exit code = %d (stderr=%q)", code, stderr)
+	}
+	if !strings.Contains(out, "example-org/web [backend, frontend]") || !strings.Contains(out, "backend: example-org/api, example-org/web") {
+		t.Fatalf("repo list output missing groups:\n%s", out)
+	}
+	if code, out, _ := run("", "repo", "list", "--groups"); code != exitOK || out != "backend\nfrontend\n" {
+		t.Fatalf("repo list --groups = %d %q", code, out)
+	}
+
+	for _, id := range []string{"WS1", "WS2"} {
+		if code, _, stderr := run("", "ws", "create", "--no-prompt", id); code != exitOK {
+			t.Fatalf("ws create %s exit code = %d (stderr=%q)", id, code, stderr)
+		}
+	}
+
+	code, out, _ = run("", "ws", "add-repo", "--format", "json", "--id", "WS1", "--group", "missing", "--yes")
+	if code != exitUsage || decodeJSONResponse(t, out).Error.Code != "invalid_argument" {
+		t.Fatalf("unknown group: code=%d stdout=%q", code, out)
+	}
+
+	code, out, stderr = run("", "ws", "add-repo", "--format", "json", "--id", "WS1", "--group", "backend", "--repo", "example-org/web", "--yes")
+	if code != exitOK {
+		t.Fatalf("ws add-repo --group exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	if !strings.Contains(out, `"repos":["example-org/web","example-org/api"]`) {
+		t.Fatalf("ws add-repo should add --repo first, then remaining group members: %s", out)
+	}
+	meta, err := loadWorkspaceMetaFile(filepath.Join(env.Root, "workspaces", "WS1"))
+	if err != nil {
+		t.Fatalf("load %s: %v", workspaceMetaFilename, err)
+	}
+	if len(meta.ReposRestore) != 2 {
+		t.Fatalf("repos_restore = %+v, want 2 entries", meta.ReposRestore)
+	}
+	for _, it := range meta.ReposRestore {
+		if it.RepoKey == "example-org/api" && (it.Alias != "api-svc" || it.BaseRef != "origin/main") {
+			t.Fatalf("api repos_restore = %+v, want manifest alias/base_ref", it)
+		}
+	}
+
+	// Human mode: no selector/prompts, one plan for the whole group, Enter confirms.
+	code, out, stderr = run("\n", "ws", "add-repo", "--id", "WS2", "--group", "frontend")
+	if code != exitOK {
+		t.Fatalf("human ws add-repo --group exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	if !strings.Contains(out, "Plan:") || !strings.Contains(out, "example-org/docs") || !strings.Contains(out, "example-org/web") {
+		t.Fatalf("human plan should list all group members:\n%s", out)
+	}
+	for _, alias := range []string{"docs", "web"} {
+		if _, err := os.Stat(filepath.Join(env.Root, "workspaces", "WS2", "repos", alias)); err != nil {
+			t.Fatalf("worktree %s not created: %v", alias, err)
+		}
+	}
+}
+
+func TestPromptAddRepoPoolSelectionFallback_GroupRowSelectsMembers(t *testing.T) {
+	cands := []addRepoPoolCandidate{
+		{RepoKey: "acme/api", Groups: []string{"backend"}},
+		{RepoKey: "acme/docs"},
+		{RepoKey: "acme/web", Groups: []string{"backend"}},
+	}
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	c := New(&out, &errOut)
+	c.In = strings.NewReader("3,4\n")
+
+	selected, err := c.promptAddRepoPoolSelectionFallback(cands)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(addRepoCandidateKeys(selected), ","); got != "acme/web,acme/api" {
+		t.Fatalf("selected = %q, want acme/web,acme/api", got)
+	}
+	if !strings.Contains(errOut.String(), "[4] @backend") {
+		t.Fatalf("group row missing:\n%s", errOut.String())
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_AddRepo_AliasReference_BindsRepoTwice(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	repoSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	if code, _, stderr := run("repo", "add", repoSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--yes"); code != exitOK {
+		t.Fatalf("ws add-repo exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+
+	if code, out, _ := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--yes"); code != exitUsage || !strings.Contains(out, "repo not available in pool") {
+		t.Fatalf("bound repo without --alias: code=%d out=%s", code, out)
+	}
+	if code, _, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--alias", "../x", "--yes"); code != exitUsage || !strings.Contains(stderr, "invalid --alias") {
+		t.Fatalf("invalid --alias: code=%d stderr=%q", code, stderr)
+	}
+	if code, _, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--reference", "--branch", "x", "--yes"); code != exitUsage || !strings.Contains(stderr, "cannot be used together") {
+		t.Fatalf("--reference with --branch: code=%d stderr=%q", code, stderr)
+	}
+	code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--alias", "api-main", "--reference", "--yes")
+	if code != exitOK {
+		t.Fatalf("ws add-repo --reference exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+
+	wsPath := filepath.Join(env.Root, "workspaces", "WS1")
+	refPath := filepath.Join(wsPath, "repos", "api-main")
+	assertReference := func(stage string) {
+		t.Helper()
+		if out, err := exec.Command("git", "-C", refPath, "symbolic-ref", "--quiet", "HEAD").CombinedOutput(); err == nil {
+			t.Fatalf("%s: reference worktree should be detached, HEAD=%s", stage, strings.TrimSpace(string(out)))
+		}
+		meta, err := loadWorkspaceMetaFile(wsPath)
+		if err != nil {
+			t.Fatalf("%s: load %s: %v", stage, workspaceMetaFilename, err)
+		}
+		found := false
+		for _, r := range meta.ReposRestore {
+			if r.Alias == "api-main" {
+				found = true
+				if !r.Reference || r.Branch != "" || r.BaseRef != "origin/main" {
+					t.Fatalf("%s: reference restore entry = %+v", stage, r)
+				}
+			}
+		}
+		if !found || len(meta.ReposRestore) != 2 {
+			t.Fatalf("%s: repos_restore = %+v, want api and api-main", stage, meta.ReposRestore)
+		}
+	}
+	assertReference("add-repo")
+
+	if code, out, _ := run("ws", "status", "--id", "WS1", "--format", "json"); code != exitOK || !strings.Contains(out, `"reference":true`) || strings.Contains(out, `"unknown"`) {
+		t.Fatalf("ws status should report a clean reference repo: code=%d out=%s", code, out)
+	}
+	if code, out, _ := run("ws", "push", "--id", "WS1", "--format", "json"); code != exitOK || !strings.Contains(out, `"reason":"reference repo"`) {
+		t.Fatalf("ws push should skip the reference repo: code=%d out=%s", code, out)
+	}
+	if code, out, _ := run("ws", "branch", "api-main", "--id", "WS1", "--format", "json"); code != exitError || !strings.Contains(out, "reference repo has no branch") {
+		t.Fatalf("ws branch on reference repo: code=%d out=%s", code, out)
+	}
+	if code, out, _ := run("ws", "list", "--tree"); code != exitOK || !strings.Contains(out, "api-main  reference:origin/main") {
+		t.Fatalf("ws list --tree should show the reference repo:\n%s", out)
+	}
+
+	if code, out, _ := run("ws", "remove-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--yes"); code != exitUsage || !strings.Contains(out, "pass its alias") {
+		t.Fatalf("ambiguous remove-repo: code=%d out=%s", code, out)
+	}
+
+	if code, _, stderr := run("ws", "close", "WS1"); code != exitOK {
+		t.Fatalf("ws close exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "reopen", "WS1"); code != exitOK {
+		t.Fatalf("ws reopen exit code = %d (stderr=%q)", code, stderr)
+	}
+	assertReference("reopen")
+
+	if err := os.WriteFile(filepath.Join(refPath, "scratch.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatalf("write scratch: %v", err)
+	}
+	if code, out, _ := run("ws", "remove-repo", "--format", "json", "--id", "WS1", "--repo", "api-main", "--yes"); code != exitError || !strings.Contains(out, "non-clean") {
+		t.Fatalf("dirty reference repo should still be guarded: code=%d out=%s", code, out)
+	}
+	if code, out, stderr := run("ws", "remove-repo", "--format", "json", "--id", "WS1", "--repo", "api-main", "--yes", "--force"); code != exitOK {
+		t.Fatalf("remove-repo by alias exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
+	"github.com/tasuku43/kra/internal/core/repostore"
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_RepoAddFilter_AndWSAddRepoSparse_SurviveCloseReopen(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	repoSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	remoteBare := strings.TrimPrefix(repoSpec, "file://")
+	runGit("", "--git-dir", remoteBare, "config", "uploadpack.allowFilter", "true")
+	work := filepath.Join(t.TempDir(), "work")
+	runGit("", "clone", remoteBare, work)
+	runGit(work, "config", "user.email", "test@example.com")
+	runGit(work, "config", "user.name", "test")
+	for _, rel := range []string{"app/main.txt", "lib/util.txt"} {
+		p := filepath.Join(work, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(rel+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	runGit(work, "add", ".")
+	runGit(work, "commit", "-m", "add dirs")
+	runGit(work, "push", "origin", "main")
+
+	if code, _, stderr := run("repo", "add", "--filter=blob:some", repoSpec); code != exitUsage || !strings.Contains(stderr, "unsupported clone filter") {
+		t.Fatalf("invalid --filter: code=%d stderr=%q", code, stderr)
+	}
+	if code, _, stderr := run("repo", "add", "--filter", "blob:none", repoSpec); code != exitOK {
+		t.Fatalf("repo add --filter exit code = %d (stderr=%q)", code, stderr)
+	}
+	spec, err := repospec.Normalize(repoSpec)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	barePath := repostore.StorePath(env.RepoPoolPath(), spec)
+	filterOut, err := exec.Command("git", "--git-dir", barePath, "config", "--get", "remote.origin.partialclonefilter").Output()
+	if err != nil || strings.TrimSpace(string(filterOut)) != "blob:none" {
+		t.Fatalf("partialclonefilter = %q (err=%v), want blob:none", strings.TrimSpace(string(filterOut)), err)
+	}
+	code, out, _ := run("repo", "list", "--format", "json")
+	if code != exitOK || !strings.Contains(out, `"filter":"blob:none"`) {
+		t.Fatalf("repo list json should report filter: code=%d out=%s", code, out)
+	}
+
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--sparse", "../x", "--yes"); code != exitUsage {
+		t.Fatalf("invalid --sparse: code=%d stderr=%q", code, stderr)
+	}
+	code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--sparse", "app/", "--yes")
+	if code != exitOK {
+		t.Fatalf("ws add-repo --sparse exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	wsPath := filepath.Join(env.Root, "workspaces", "WS1")
+	assertSparseWorktree := func(stage string) {
+		t.Helper()
+		repoPath := filepath.Join(wsPath, "repos", "api")
+		for _, rel := range []string{"README.md", "app/main.txt"} {
+			if _, err := os.Stat(filepath.Join(repoPath, rel)); err != nil {
+				t.Fatalf("%s: %s should be checked out: %v", stage, rel, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(repoPath, "lib")); !os.IsNotExist(err) {
+			t.Fatalf("%s: lib/ should be outside the sparse checkout (err=%v)", stage, err)
+		}
+	}
+	assertSparseWorktree("add-repo")
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		t.Fatalf("load %s: %v", workspaceMetaFilename, err)
+	}
+	if len(meta.ReposRestore) != 1 || !slices.Equal(meta.ReposRestore[0].Sparse, []string{"app"}) {
+		t.Fatalf("repos_restore = %+v, want sparse [app]", meta.ReposRestore)
+	}
+
+	code, out, _ = run("ws", "list", "--tree", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("ws list --tree exit code = %d", code)
+	}
+	var listResp struct {
+		Result struct {
+			Items []struct {
+				ID    string `json:"id"`
+				Repos []struct {
+					Alias  string   `json:"alias"`
+					Sparse []string `json:"sparse"`
+				} `json:"repos"`
+			} `json:"items"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &listResp); err != nil {
+		t.Fatalf("decode ws list: %v (out=%s)", err, out)
+	}
+	if len(listResp.Result.Items) != 1 || len(listResp.Result.Items[0].Repos) != 1 || !slices.Equal(listResp.Result.Items[0].Repos[0].Sparse, []string{"app"}) {
+		t.Fatalf("ws list --tree should report sparse: %s", out)
+	}
+	if code, out, _ := run("ws", "list", "--tree"); code != exitOK || !strings.Contains(out, "sparse:app") {
+		t.Fatalf("ws list --tree human should show sparse:\n%s", out)
+	}
+
+	if code, _, stderr := run("ws", "close", "WS1"); code != exitOK {
+		t.Fatalf("ws close exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "reopen", "WS1"); code != exitOK {
+		t.Fatalf("ws reopen exit code = %d (stderr=%q)", code, stderr)
+	}
+	assertSparseWorktree("reopen")
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/repomanifest"
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_AddRepo_Submodules_FromManifestSurviveReopenAndCountAsRisk(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	// file:// submodule remotes are blocked by default since git 2.38.1.
+	runGit("", "config", "--global", "protocol.file.allow", "always")
+	libSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "lib")
+	appSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "app")
+	work := filepath.Join(t.TempDir(), "app")
+	runGit("", "clone", strings.TrimPrefix(appSpec, "file://"), work)
+	runGit(work, "config", "user.email", "test@example.com")
+	runGit(work, "config", "user.name", "test")
+	runGit(work, "submodule", "add", libSpec, "libs/lib")
+	runGit(work, "commit", "-m", "add lib submodule")
+	runGit(work, "push", "origin", "main")
+
+	if code, _, stderr := run("repo", "add", appSpec, libSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	manifestPath := repomanifest.Path(env.Root)
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, []byte(fmt.Sprintf("repos:\n  - spec: %s\n    submodules: true\n  - spec: %s\n", appSpec, libSpec)), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+
+	wsPath := filepath.Join(env.Root, "workspaces", "WS1")
+	appPath := filepath.Join(wsPath, "repos", "app")
+	if _, err := exec.LookPath("git-lfs"); err != nil {
+		code, out, _ := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/app", "--lfs", "--yes")
+		if code != exitError || !strings.Contains(out, "git-lfs is not available") {
+			t.Fatalf("--lfs without git-lfs: code=%d out=%s", code, out)
+		}
+		if _, err := os.Stat(appPath); !os.IsNotExist(err) {
+			t.Fatalf("failed apply should roll back the worktree (err=%v)", err)
+		}
+	}
+
+	code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/app", "--yes")
+	if code != exitOK {
+		t.Fatalf("ws add-repo exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	subReadme := filepath.Join(appPath, "libs", "lib", "README.md")
+	if _, err := os.Stat(subReadme); err != nil {
+		t.Fatalf("submodule should be initialized: %v", err)
+	}
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		t.Fatalf("load %s: %v", workspaceMetaFilename, err)
+	}
+	if len(meta.ReposRestore) != 1 || !meta.ReposRestore[0].Submodules || meta.ReposRestore[0].LFS {
+		t.Fatalf("repos_restore = %+v, want submodules only", meta.ReposRestore)
+	}
+	if snapshot := inspectGitRepoSnapshot(context.Background(), appPath); snapshot.Status.Dirty || snapshot.Status.Error != nil {
+		t.Fatalf("fresh worktree should not be dirty: %+v", snapshot)
+	}
+
+	if code, _, stderr := run("ws", "close", "WS1"); code != exitOK {
+		t.Fatalf("ws close exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "reopen", "WS1"); code != exitOK {
+		t.Fatalf("ws reopen exit code = %d (stderr=%q)", code, stderr)
+	}
+	if _, err := os.Stat(subReadme); err != nil {
+		t.Fatalf("reopen should re-initialize the submodule: %v", err)
+	}
+
+	// Uncommitted submodule content makes the repo dirty.
+	subPath := filepath.Join(appPath, "libs", "lib")
+	if err := os.WriteFile(filepath.Join(subPath, "scratch.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatalf("write scratch: %v", err)
+	}
+	if snapshot := inspectGitRepoSnapshot(context.Background(), appPath); !snapshot.Status.Dirty {
+		t.Fatalf("untracked submodule content should be dirty: %+v", snapshot)
+	}
+
+	// A submodule commit recorded by the superproject but not pushed anywhere is still at risk.
+	runGit(subPath, "-c", "user.email=test@example.com", "-c", "user.name=test", "add", ".")
+	runGit(subPath, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-m", "local only")
+	runGit(appPath, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-am", "bump lib")
+	snapshot := inspectGitRepoSnapshot(context.Background(), appPath)
+	if !snapshot.Status.Dirty || !strings.Contains(strings.Join(snapshot.Files, "\n"), "S  libs/lib (unpushed submodule commits)") {
+		t.Fatalf("unpushed submodule commit should count as dirty: %+v", snapshot)
+	}
+}
@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/infra/paths"
+)
+
+type wsBranchResult struct {
+	Alias          string
+	Action         string
+	PreviousBranch string
+	Branch         string
+	BaseRef        string
+}
+
+func (c *CLI) runWSBranch(args []string) int {
+	outputFormat := "human"
+	workspaceID := ""
+	useCurrent := false
+	alias := ""
+	switchTo := ""
+	newBranch := ""
+	from := ""
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			c.printWSBranchUsage(c.Out)
+			return exitOK
+		case arg == "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				c.printWSBranchUsage(c.Err)
+				return exitUsage
+			}
+			outputFormat = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case arg == "--id":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--id requires a value")
+				c.printWSBranchUsage(c.Err)
+				return exitUsage
+			}
+			workspaceID = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--id="):
+			workspaceID = strings.TrimSpace(strings.TrimPrefix(arg, "--id="))
+		case arg == "--switch":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--switch requires a value")
+				c.printWSBranchUsage(c.Err)
+				return exitUsage
+			}
+			switchTo = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--switch="):
+			switchTo = strings.TrimSpace(strings.TrimPrefix(arg, "--switch="))
+		case arg == "--new":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--new requires a value")
+				c.printWSBranchUsage(c.Err)
+				return exitUsage
+			}
+			newBranch = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--new="):
+			newBranch = strings.TrimSpace(strings.TrimPrefix(arg, "--new="))
+		case arg == "--from":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--from requires a value")
+				c.printWSBranchUsage(c.Err)
+				return exitUsage
+			}
+			from = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--from="):
+			from = strings.TrimSpace(strings.TrimPrefix(arg, "--from="))
+		case arg == "--current":
+			useCurrent = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(c.Err, "unknown flag for ws branch: %q\n", arg)
+			c.printWSBranchUsage(c.Err)
+			return exitUsage
+		default:
+			if alias != "" {
+				fmt.Fprintf(c.Err, "unexpected args for ws branch: %q\n", arg)
+				c.printWSBranchUsage(c.Err)
+				return exitUsage
+			}
+			alias = arg
+		}
+	}
+	switch outputFormat {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", outputFormat)
+		c.printWSBranchUsage(c.Err)
+		return exitUsage
+	}
+	if alias == "" {
+		fmt.Fprintln(c.Err, "ws branch requires <alias>")
+		c.printWSBranchUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" && useCurrent {
+		fmt.Fprintln(c.Err, "--id and --current cannot be used together")
+		c.printWSBranchUsage(c.Err)
+		return exitUsage
+	}
+	if switchTo != "" && newBranch != "" {
+		fmt.Fprintln(c.Err, "--switch and --new cannot be used together")
+		c.printWSBranchUsage(c.Err)
+		return exitUsage
+	}
+	if from != "" && newBranch == "" {
+		fmt.Fprintln(c.Err, "--from requires --new")
+		c.printWSBranchUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" {
+		if err := validateWorkspaceID(workspaceID); err != nil {
+			fmt.Fprintf(c.Err, "invalid workspace id: %v\n", err)
+			return exitUsage
+		}
+	}
+	jsonMode := outputFormat == "json"
+	fail := func(code int, errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:          false,
+				Action:      "ws.branch",
+				WorkspaceID: workspaceID,
+				Error:       &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("get working dir: %v", err))
+	}
+	root, err := paths.ResolveExistingRoot(wd)
+	if err != nil {
+		return fail(exitError, "not_found", fmt.Sprintf("resolve KRA_ROOT: %v", err))
+	}
+	if err := c.ensureDebugLog(root, "ws-branch"); err != nil {
+		fmt.Fprintf(c.Err, "enable debug logging: %v\n", err)
+	}
+	workspaceID, errCode, err := resolveActiveWorkspaceTarget(root, wd, workspaceID, "ws branch")
+	if err != nil {
+		return fail(exitError, errCode, err.Error())
+	}
+
+	ctx := context.Background()
+	wsPath := filepath.Join(root, "workspaces", workspaceID)
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("load %s: %v", workspaceMetaFilename, err))
+	}
+	restoreIdx := -1
+	for i, r := range meta.ReposRestore {
+		if strings.TrimSpace(r.Alias) == alias {
+			restoreIdx = i
+		}
+	}
+	worktreePath := filepath.Join(wsPath, "repos", alias)
+	if fi, err := os.Stat(worktreePath); err != nil || !fi.IsDir() || restoreIdx < 0 {
+		return fail(exitUsage, "invalid_argument", fmt.Sprintf("repo not bound to workspace %s: %s", workspaceID, alias))
+	}
+	restore := meta.ReposRestore[restoreIdx]
+	if restore.Reference {
+		return fail(exitError, "conflict", fmt.Sprintf("reference repo has no branch: %s (bind it again without --reference)", alias))
+	}
+
+	result := wsBranchResult{Alias: alias, Action: "show", BaseRef: strings.TrimSpace(restore.BaseRef)}
+	if out, err := gitutil.Run(ctx, worktreePath, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		result.PreviousBranch = strings.TrimSpace(out)
+	}
+	result.Branch = result.PreviousBranch
+
+	target := firstNonEmpty(switchTo, newBranch)
+	switch {
+	case target == "":
+	case target == result.PreviousBranch:
+		result.Action = "none"
+	default:
+		if err := gitutil.CheckRefFormat(ctx, "refs/heads/"+target); err != nil {
+			return fail(exitUsage, "invalid_argument", fmt.Sprintf("invalid branch name: %q", target))
+		}
+		snapshot := inspectGitRepoSnapshot(ctx, worktreePath)
+		if snapshot.Status.Error != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("inspect %s: %v", alias, snapshot.Status.Error))
+		}
+		if snapshot.Status.Dirty {
+			return fail(exitError, "conflict", fmt.Sprintf("worktree has uncommitted changes: %s (commit or stash first)", alias))
+		}
+		commonDir, err := worktreeCommonDir(ctx, worktreePath)
+		if err != nil {
+			return fail(exitError, "internal_error", err.Error())
+		}
+		inUse, err := isBranchCheckedOutInBare(ctx, commonDir, target)
+		if err != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("check branch checkout status for %s: %v", alias, err))
+		}
+		if inUse {
+			return fail(exitError, "conflict", fmt.Sprintf("branch is already checked out by another worktree: %s", target))
+		}
+		localExists, err := gitutil.ShowRefExistsBare(ctx, commonDir, "refs/heads/"+target)
+		if err != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("check local branch %s: %v", target, err))
+		}
+		remoteExists, err := gitutil.ShowRefExistsBare(ctx, commonDir, "refs/remotes/origin/"+target)
+		if err != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("check remote branch %s: %v", target, err))
+		}
+
+		var checkoutArgs []string
+		if switchTo != "" {
+			result.Action = "switch"
+			switch {
+			case localExists:
+				checkoutArgs = []string{"switch", target}
+			case remoteExists:
+				checkoutArgs = []string{"switch", "--track", "-c", target, "origin/" + target}
+			default:
+				return fail(exitError, "not_found", fmt.Sprintf("branch not found: %s (use --new to create it)", target))
+			}
+		} else {
+			result.Action = "new"
+			if localExists || remoteExists {
+				return fail(exitError, "conflict", fmt.Sprintf("branch already exists: %s (use --switch)", target))
+			}
+			result.BaseRef = firstNonEmpty(from, result.BaseRef)
+			if !strings.HasPrefix(result.BaseRef, "origin/") {
+				return fail(exitUsage, "invalid_argument", fmt.Sprintf("invalid --from (must be origin/<branch>): %q", result.BaseRef))
+			}
+			ok, err := gitutil.ShowRefExistsBare(ctx, commonDir, "refs/remotes/"+result.BaseRef)
+			if err != nil {
+				return fail(exitError, "internal_error", fmt.Sprintf("check --from %s: %v", result.BaseRef, err))
+			}
+			if !ok {
+				return fail(exitError, "not_found", fmt.Sprintf("base_ref not found: %s", result.BaseRef))
+			}
+			checkoutArgs = []string{"switch", "-c", target, result.BaseRef}
+		}
+		if _, err := gitutil.Run(ctx, worktreePath, checkoutArgs...); err != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("checkout %s in %s: %v", target, alias, err))
+		}
+		result.Branch = target
+		c.debugf("ws branch id=%s alias=%s action=%s from=%s to=%s base_ref=%s", workspaceID, alias, result.Action, result.PreviousBranch, target, result.BaseRef)
+
+		meta.ReposRestore[restoreIdx].Branch = result.Branch
+		meta.ReposRestore[restoreIdx].BaseRef = result.BaseRef
+		if result.Branch != result.PreviousBranch {
+			// The recorded PR belongs to the previous branch.
+			meta.ReposRestore[restoreIdx].PullRequestURL = ""
+		}
+		if err := writeWorkspaceMetaFile(wsPath, meta); err != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("write %s: %v", workspaceMetaFilename, err))
+		}
+	}
+
+	if jsonMode {
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:          true,
+			Action:      "ws.branch",
+			WorkspaceID: workspaceID,
+			Result: map[string]any{
+				"alias":           result.Alias,
+				"action":          result.Action,
+				"previous_branch": result.PreviousBranch,
+				"branch":          result.Branch,
+				"base_ref":        result.BaseRef,
+			},
+		})
+		return exitOK
+	}
+	printWSBranchResult(c.Out, result, writerSupportsColor(c.Out))
+	return exitOK
+}
+
+// worktreeCommonDir returns the absolute git common dir (the pool bare repo) of a workspace worktree.
+func worktreeCommonDir(ctx context.Context, worktreePath string) (string, error) {
+	out, err := gitutil.Run(ctx, worktreePath, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("resolve git common dir: %w", err)
+	}
+	dir := strings.TrimSpace(out)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(worktreePath, dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+func printWSBranchResult(out io.Writer, result wsBranchResult, useColor bool) {
+	var line string
+	switch result.Action {
+	case "switch", "new":
+		line = fmt.Sprintf("%s %s %s → %s", styleSuccess("✔", useColor), result.Alias, result.PreviousBranch, result.Branch)
+		if result.Action == "new" {
+			line += " " + styleMuted("(new from "+result.BaseRef+")", useColor)
+		}
+	case "none":
+		line = fmt.Sprintf("%s %s already on %s", styleMuted("-", useColor), result.Alias, result.Branch)
+	default:
+		line = fmt.Sprintf("%s  branch:%s  base_ref:%s", result.Alias, result.Branch, result.BaseRef)
+	}
+	printResultSection(out, useColor, line)
+}
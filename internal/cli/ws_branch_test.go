@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_Branch_SwitchAndNewUpdateRestoreAndGuardDoubleCheckout(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	gitOut := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+		return strings.TrimSpace(string(out))
+	}
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		gitOut(dir, args...)
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+	restoreOf := func(wsPath string) workspaceMetaRepoRestore {
+		t.Helper()
+		meta, err := loadWorkspaceMetaFile(wsPath)
+		if err != nil || len(meta.ReposRestore) != 1 {
+			t.Fatalf("load meta: %+v (err=%v)", meta.ReposRestore, err)
+		}
+		return meta.ReposRestore[0]
+	}
+	setPullRequestURL := func(wsPath string, url string) {
+		t.Helper()
+		meta, err := loadWorkspaceMetaFile(wsPath)
+		if err != nil {
+			t.Fatalf("load meta: %v", err)
+		}
+		meta.ReposRestore[0].PullRequestURL = url
+		if err := writeWorkspaceMetaFile(wsPath, meta); err != nil {
+			t.Fatalf("write meta: %v", err)
+		}
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	if code, _, stderr := run("repo", "add", apiSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	for _, id := range []string{"WS1", "WS2"} {
+		if code, _, stderr := run("ws", "create", "--no-prompt", id); code != exitOK {
+			t.Fatalf("ws create %s exit code = %d (stderr=%q)", id, code, stderr)
+		}
+		if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", id, "--repo", "example-org/api", "--yes"); code != exitOK {
+			t.Fatalf("ws add-repo %s exit code = %d (stdout=%q stderr=%q)", id, code, out, stderr)
+		}
+	}
+	wsPath := filepath.Join(env.Root, "workspaces", "WS1")
+	apiPath := filepath.Join(wsPath, "repos", "api")
+
+	if code, out, _ := run("ws", "branch", "--format", "json", "--id", "WS1", "api"); code != exitOK || !strings.Contains(out, `"action":"show"`) || !strings.Contains(out, `"branch":"WS1"`) {
+		t.Fatalf("ws branch show: code=%d out=%s", code, out)
+	}
+
+	// The PR recorded for the old branch must not follow the repo to a new branch.
+	setPullRequestURL(wsPath, "https://github.com/example-org/api/pull/1")
+	code, out, stderr := run("ws", "branch", "--id", "WS1", "api", "--new", "feature/login")
+	if code != exitOK || !strings.Contains(out, "WS1 → feature/login") {
+		t.Fatalf("ws branch --new: code=%d out=%q stderr=%q", code, out, stderr)
+	}
+	if got := gitOut(apiPath, "branch", "--show-current"); got != "feature/login" {
+		t.Fatalf("worktree branch = %q, want feature/login", got)
+	}
+	if r := restoreOf(wsPath); r.Branch != "feature/login" || r.BaseRef != "origin/main" || r.PullRequestURL != "" {
+		t.Fatalf("restore after --new = %+v", r)
+	}
+
+	if code, _, stderr := run("ws", "branch", "--id", "WS1", "api", "--switch", "WS2"); code != exitError || !strings.Contains(stderr, "branch is already checked out by another worktree: WS2") {
+		t.Fatalf("ws branch --switch WS2: code=%d stderr=%q", code, stderr)
+	}
+	if code, _, stderr := run("ws", "branch", "--id", "WS1", "api", "--switch", "nope"); code != exitError || !strings.Contains(stderr, "branch not found: nope") {
+		t.Fatalf("ws branch --switch nope: code=%d stderr=%q", code, stderr)
+	}
+	if code, _, stderr := run("ws", "branch", "--id", "WS1", "api", "--new", "WS1"); code != exitError || !strings.Contains(stderr, "branch already exists: WS1") {
+		t.Fatalf("ws branch --new WS1: code=%d stderr=%q", code, stderr)
+	}
+
+	if err := os.WriteFile(filepath.Join(apiPath, "dirty.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatalf("write dirty.txt: %v", err)
+	}
+	if code, _, stderr := run("ws", "branch", "--id", "WS1", "api", "--switch", "WS1"); code != exitError || !strings.Contains(stderr, "uncommitted changes") {
+		t.Fatalf("ws branch dirty: code=%d stderr=%q", code, stderr)
+	}
+	if err := os.Remove(filepath.Join(apiPath, "dirty.txt")); err != nil {
+		t.Fatalf("remove dirty.txt: %v", err)
+	}
+
+	setPullRequestURL(wsPath, "https://github.com/example-org/api/pull/2")
+	if code, out, stderr := run("ws", "branch", "--format", "json", "--id", "WS1", "api", "--switch", "WS1"); code != exitOK || !strings.Contains(out, `"action":"switch"`) {
+		t.Fatalf("ws branch --switch WS1: code=%d out=%s stderr=%q", code, out, stderr)
+	}
+	if r := restoreOf(wsPath); r.Branch != "WS1" || r.PullRequestURL != "" {
+		t.Fatalf("restore after --switch = %+v", r)
+	}
+	if code, _, stderr := run("ws", "branch", "--id", "WS1", "nope"); code != exitUsage || !strings.Contains(stderr, "repo not bound to workspace WS1: nope") {
+		t.Fatalf("ws branch unknown alias: code=%d stderr=%q", code, stderr)
+	}
+}
@@ -0,0 +1,412 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/app/repocmd"
+	"github.com/tasuku43/kra/internal/infra/appports"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+)
+
+// Upstream states of a workspace branch, resolved against the pool bare repo (as of its last fetch).
+const (
+	// branchUpstreamPresent: origin/<branch> exists.
+	branchUpstreamPresent = "present"
+	// branchUpstreamGone: the branch tracked origin/<branch>, which no longer exists.
+	branchUpstreamGone = "gone"
+	// branchUpstreamLocal: the branch was never pushed.
+	branchUpstreamLocal = "local"
+	// branchUpstreamMissing: the local branch no longer exists in the bare repo.
+	branchUpstreamMissing = "missing"
+	// branchUpstreamUnknown: the repo is not in the pool.
+	branchUpstreamUnknown = "unknown"
+)
+
+// workspaceBranchRef is one repos_restore entry of an active or archived workspace.
+type workspaceBranchRef struct {
+	WorkspaceID   string
+	Archived      bool
+	RepoUID       string
+	RepoKey       string
+	Alias         string
+	Branch        string
+	UpstreamState string
+	// DuplicateOf lists other workspaces that record the same repo and branch.
+	DuplicateOf []string
+}
+
+// workspaceBranchDuplicate is one repo/branch recorded by more than one workspace.
+type workspaceBranchDuplicate struct {
+	RepoUID    string
+	RepoKey    string
+	Branch     string
+	Workspaces []string
+}
+
+// scanWorkspaceBranchRefs reads repos_restore of every workspace (active and archived) in root.
+// Active workspaces report the branch currently checked out in the worktree when it is available.
+func scanWorkspaceBranchRefs(ctx context.Context, root string, repoPoolPath string) ([]workspaceBranchRef, error) {
+	refs := make([]workspaceBranchRef, 0, 16)
+	for _, scope := range []string{"workspaces", "archive"} {
+		scopeDir := filepath.Join(root, scope)
+		entries, err := os.ReadDir(scopeDir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			wsPath := filepath.Join(scopeDir, e.Name())
+			meta, err := loadWorkspaceMetaFile(wsPath)
+			if err != nil {
+				continue
+			}
+			for _, r := range meta.ReposRestore {
+				if r.Reference {
+					// Reference bindings never own a branch.
+					continue
+				}
+				ref := workspaceBranchRef{
+					WorkspaceID: e.Name(),
+					Archived:    scope == "archive",
+					RepoUID:     strings.TrimSpace(r.RepoUID),
+					RepoKey:     workspaceBranchRepoKey(r.RepoUID, r.RepoKey),
+					Alias:       strings.TrimSpace(r.Alias),
+					Branch:      strings.TrimSpace(r.Branch),
+				}
+				if !ref.Archived && ref.Alias != "" {
+					ref.Branch = detectBranchForClose(ctx, filepath.Join(wsPath, "repos", ref.Alias), ref.Branch)
+				}
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	barePaths, err := listRepoPoolBarePathsByUID(ctx, repoPoolPath)
+	if err != nil {
+		return nil, err
+	}
+	statesByUID := map[string]map[string]string{}
+	for i := range refs {
+		uid := refs[i].RepoUID
+		states, ok := statesByUID[uid]
+		if !ok {
+			states = loadBareBranchUpstreamStates(ctx, barePaths[uid])
+			statesByUID[uid] = states
+		}
+		switch {
+		case states == nil:
+			refs[i].UpstreamState = branchUpstreamUnknown
+		case states[refs[i].Branch] == "":
+			refs[i].UpstreamState = branchUpstreamMissing
+		default:
+			refs[i].UpstreamState = states[refs[i].Branch]
+		}
+	}
+
+	for _, d := range findWorkspaceBranchDuplicates(refs) {
+		for i := range refs {
+			if refs[i].RepoUID != d.RepoUID || refs[i].Branch != d.Branch {
+				continue
+			}
+			refs[i].DuplicateOf = slices.DeleteFunc(slices.Clone(d.Workspaces), func(id string) bool { return id == refs[i].WorkspaceID })
+		}
+	}
+	slices.SortFunc(refs, func(a, b workspaceBranchRef) int {
+		if a.WorkspaceID != b.WorkspaceID {
+			return strings.Compare(a.WorkspaceID, b.WorkspaceID)
+		}
+		if a.RepoKey != b.RepoKey {
+			return strings.Compare(a.RepoKey, b.RepoKey)
+		}
+		return strings.Compare(a.Alias, b.Alias)
+	})
+	return refs, nil
+}
+
+// workspaceBranchRepoKey returns the repo key in the repo list form (<owner>/<repo>) derived from repo_uid,
+// since repos_restore may carry the key either with or without the host.
+func workspaceBranchRepoKey(repoUID string, repoKey string) string {
+	if _, key, ok := strings.Cut(strings.TrimSpace(repoUID), "/"); ok && strings.Contains(key, "/") {
+		return key
+	}
+	return strings.TrimSpace(repoKey)
+}
+
+// loadBareBranchUpstreamStates maps each local branch of a bare repo to its upstream state (nil when unavailable).
+func loadBareBranchUpstreamStates(ctx context.Context, barePath string) map[string]string {
+	if strings.TrimSpace(barePath) == "" {
+		return nil
+	}
+	out, err := gitutil.RunBare(ctx, barePath, "for-each-ref", "--format=%(refname)%09%(upstream)", "refs/heads", "refs/remotes/origin")
+	if err != nil {
+		return nil
+	}
+	remote := map[string]bool{}
+	upstreams := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		ref, upstream, _ := strings.Cut(strings.TrimSpace(line), "\t")
+		switch {
+		case strings.HasPrefix(ref, "refs/remotes/origin/"):
+			remote[strings.TrimPrefix(ref, "refs/remotes/origin/")] = true
+		case strings.HasPrefix(ref, "refs/heads/"):
+			upstreams[strings.TrimPrefix(ref, "refs/heads/")] = upstream
+		}
+	}
+	states := make(map[string]string, len(upstreams))
+	for branch, upstream := range upstreams {
+		switch {
+		case remote[branch]:
+			states[branch] = branchUpstreamPresent
+		case upstream == "refs/remotes/origin/"+branch:
+			states[branch] = branchUpstreamGone
+		default:
+			states[branch] = branchUpstreamLocal
+		}
+	}
+	return states
+}
+
+// findWorkspaceBranchDuplicates returns repo/branch pairs recorded by more than one workspace.
+func findWorkspaceBranchDuplicates(refs []workspaceBranchRef) []workspaceBranchDuplicate {
+	type key struct{ uid, branch string }
+	byKey := map[key]*workspaceBranchDuplicate{}
+	order := make([]key, 0, len(refs))
+	for _, r := range refs {
+		if r.RepoUID == "" || r.Branch == "" {
+			continue
+		}
+		k := key{r.RepoUID, r.Branch}
+		d, ok := byKey[k]
+		if !ok {
+			d = &workspaceBranchDuplicate{RepoUID: r.RepoUID, RepoKey: r.RepoKey, Branch: r.Branch}
+			byKey[k] = d
+			order = append(order, k)
+		}
+		if !slices.Contains(d.Workspaces, r.WorkspaceID) {
+			d.Workspaces = append(d.Workspaces, r.WorkspaceID)
+		}
+	}
+	out := make([]workspaceBranchDuplicate, 0)
+	for _, k := range order {
+		if d := byKey[k]; len(d.Workspaces) > 1 {
+			slices.Sort(d.Workspaces)
+			out = append(out, *d)
+		}
+	}
+	slices.SortFunc(out, func(a, b workspaceBranchDuplicate) int {
+		if a.RepoKey != b.RepoKey {
+			return strings.Compare(a.RepoKey, b.RepoKey)
+		}
+		return strings.Compare(a.Branch, b.Branch)
+	})
+	return out
+}
+
+func workspaceBranchRefJSON(r workspaceBranchRef) map[string]any {
+	duplicateOf := r.DuplicateOf
+	if duplicateOf == nil {
+		duplicateOf = []string{}
+	}
+	status := "active"
+	if r.Archived {
+		status = "archived"
+	}
+	return map[string]any{
+		"workspace_id":   r.WorkspaceID,
+		"status":         status,
+		"repo_uid":       r.RepoUID,
+		"repo_key":       r.RepoKey,
+		"alias":          r.Alias,
+		"branch":         r.Branch,
+		"upstream_state": r.UpstreamState,
+		"duplicate_of":   duplicateOf,
+	}
+}
+
+func workspaceBranchDuplicatesJSON(dups []workspaceBranchDuplicate) []map[string]any {
+	rows := make([]map[string]any, 0, len(dups))
+	for _, d := range dups {
+		rows = append(rows, map[string]any{
+			"repo_uid":   d.RepoUID,
+			"repo_key":   d.RepoKey,
+			"branch":     d.Branch,
+			"workspaces": d.Workspaces,
+		})
+	}
+	return rows
+}
+
+// renderWorkspaceBranchMarkers returns the trailing markers of a branch line (archived / upstream / duplicate).
+func renderWorkspaceBranchMarkers(r workspaceBranchRef, showArchived bool, useColor bool) string {
+	markers := make([]string, 0, 3)
+	if showArchived && r.Archived {
+		markers = append(markers, styleMuted("[archived]", useColor))
+	}
+	switch r.UpstreamState {
+	case branchUpstreamGone, branchUpstreamMissing:
+		markers = append(markers, styleWarn("["+r.UpstreamState+"]", useColor))
+	case branchUpstreamLocal, branchUpstreamUnknown:
+		markers = append(markers, styleMuted("["+r.UpstreamState+"]", useColor))
+	}
+	if len(r.DuplicateOf) > 0 {
+		markers = append(markers, styleWarn("[duplicate: "+strings.Join(r.DuplicateOf, ", ")+"]", useColor))
+	}
+	if len(markers) == 0 {
+		return ""
+	}
+	return " " + strings.Join(markers, " ")
+}
+
+// renderWorkspaceBranchWarnings lists duplicate branches and branches whose upstream is gone.
+func renderWorkspaceBranchWarnings(refs []workspaceBranchRef, dups []workspaceBranchDuplicate, useColor bool) []string {
+	lines := make([]string, 0, len(dups))
+	for _, d := range dups {
+		lines = append(lines, fmt.Sprintf("%s%s %s: branch %s is used by %s", uiIndent, styleWarn("•", useColor), d.RepoKey, d.Branch, strings.Join(d.Workspaces, ", ")))
+	}
+	for _, r := range refs {
+		if r.UpstreamState != branchUpstreamGone {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s: branch %s no longer exists upstream (%s)", uiIndent, styleWarn("•", useColor), r.RepoKey, r.Branch, r.WorkspaceID))
+	}
+	return lines
+}
+
+func printWorkspaceBranchWarnings(out io.Writer, refs []workspaceBranchRef, dups []workspaceBranchDuplicate, useColor bool) {
+	lines := renderWorkspaceBranchWarnings(refs, dups, useColor)
+	if len(lines) == 0 {
+		return
+	}
+	printSection(out, styleBold(styleWarn("Warnings:", useColor), useColor), lines, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+}
+
+func (c *CLI) runWSBranches(args []string) int {
+	outputFormat := "human"
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			c.printWSBranchesUsage(c.Out)
+			return exitOK
+		case arg == "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				c.printWSBranchesUsage(c.Err)
+				return exitUsage
+			}
+			outputFormat = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(c.Err, "unknown flag for ws branches: %q\n", arg)
+			c.printWSBranchesUsage(c.Err)
+			return exitUsage
+		default:
+			fmt.Fprintf(c.Err, "unexpected args for ws branches: %q\n", strings.Join(args[i:], " "))
+			c.printWSBranchesUsage(c.Err)
+			return exitUsage
+		}
+	}
+	switch outputFormat {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", outputFormat)
+		c.printWSBranchesUsage(c.Err)
+		return exitUsage
+	}
+	jsonMode := outputFormat == "json"
+	fail := func(msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:     false,
+				Action: "ws.branches",
+				Error:  &cliJSONError{Code: "internal_error", Message: msg},
+			})
+			return exitError
+		}
+		fmt.Fprintln(c.Err, msg)
+		return exitError
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(fmt.Sprintf("get working dir: %v", err))
+	}
+	ctx := context.Background()
+	repoUC := repocmd.NewService(appports.NewRepoPort(c.ensureDebugLog, c.touchStateRegistry))
+	session, err := repoUC.Run(ctx, repocmd.Request{CWD: wd, DebugTag: "ws-branches", RequireGit: true})
+	if err != nil {
+		return fail(err.Error())
+	}
+	refs, err := scanWorkspaceBranchRefs(ctx, session.Root, session.RepoPoolPath)
+	if err != nil {
+		return fail(fmt.Sprintf("scan workspace branches: %v", err))
+	}
+	dups := findWorkspaceBranchDuplicates(refs)
+	c.debugf("run ws branches refs=%d duplicates=%d", len(refs), len(dups))
+
+	if jsonMode {
+		items := make([]map[string]any, 0, len(refs))
+		for _, r := range refs {
+			items = append(items, workspaceBranchRefJSON(r))
+		}
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:     true,
+			Action: "ws.branches",
+			Result: map[string]any{
+				"items":      items,
+				"duplicates": workspaceBranchDuplicatesJSON(dups),
+			},
+		})
+		return exitOK
+	}
+	useColor := writerSupportsColor(c.Out)
+	printWSBranches(c.Out, refs, useColor)
+	printWorkspaceBranchWarnings(c.Out, refs, dups, useColor)
+	return exitOK
+}
+
+func printWSBranches(out io.Writer, refs []workspaceBranchRef, useColor bool) {
+	bullet := styleMuted("•", useColor)
+	body := make([]string, 0, len(refs)*2)
+	for i := 0; i < len(refs); {
+		j := i
+		for j < len(refs) && refs[j].WorkspaceID == refs[i].WorkspaceID {
+			j++
+		}
+		heading := fmt.Sprintf("%s%s %s", uiIndent, bullet, refs[i].WorkspaceID)
+		if refs[i].Archived {
+			heading += " " + styleMuted("[archived]", useColor)
+		}
+		body = append(body, heading)
+		details := make([]string, 0, j-i)
+		for _, r := range refs[i:j] {
+			details = append(details, fmt.Sprintf("%s %s%s", r.RepoKey, styleAccent(r.Branch, useColor), renderWorkspaceBranchMarkers(r, false, useColor)))
+		}
+		body = appendRepoTreeLines(body, details, useColor)
+		i = j
+	}
+	if len(body) == 0 {
+		body = append(body, fmt.Sprintf("%s(none)", uiIndent))
+	}
+	printSection(out, styleBold("Branches:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+}
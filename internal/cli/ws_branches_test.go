@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_RepoUsage_AndWSBranches_ReportDuplicatesAndGoneBranches(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	webSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "web")
+	if code, _, stderr := run("repo", "add", apiSpec, webSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	for _, id := range []string{"WS1", "WS2"} {
+		if code, _, stderr := run("ws", "create", "--no-prompt", id); code != exitOK {
+			t.Fatalf("ws create %s exit code = %d (stderr=%q)", id, code, stderr)
+		}
+	}
+	if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--branch", "feature/x", "--yes"); code != exitOK {
+		t.Fatalf("ws add-repo WS1 exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	// Push with upstream tracking, close WS1, then delete the branch upstream.
+	ws1API := filepath.Join(env.Root, "workspaces", "WS1", "repos", "api")
+	runGit(ws1API, "push", "-u", "origin", "feature/x")
+	if code, _, stderr := run("ws", "close", "WS1"); code != exitOK {
+		t.Fatalf("ws close exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS2", "--repo", "example-org/api", "--branch", "feature/x", "--yes"); code != exitOK {
+		t.Fatalf("ws add-repo WS2 exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	runGit("", "--git-dir", strings.TrimPrefix(apiSpec, "file://"), "branch", "-D", "feature/x")
+	if code, _, stderr := run("repo", "fetch"); code != exitOK {
+		t.Fatalf("repo fetch exit code = %d (stderr=%q)", code, stderr)
+	}
+
+	code, out, _ := run("ws", "branches", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("ws branches exit code = %d (out=%s)", code, out)
+	}
+	var branchesResp struct {
+		OK     bool   `json:"ok"`
+		Action string `json:"action"`
+		Result struct {
+			Items []struct {
+				WorkspaceID   string   `json:"workspace_id"`
+				Status        string   `json:"status"`
+				RepoKey       string   `json:"repo_key"`
+				Branch        string   `json:"branch"`
+				UpstreamState string   `json:"upstream_state"`
+				DuplicateOf   []string `json:"duplicate_of"`
+			} `json:"items"`
+			Duplicates []struct {
+				RepoKey    string   `json:"repo_key"`
+				Branch     string   `json:"branch"`
+				Workspaces []string `json:"workspaces"`
+			} `json:"duplicates"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &branchesResp); err != nil {
+		t.Fatalf("decode ws branches: %v (out=%s)", err, out)
+	}
+	if !branchesResp.OK || branchesResp.Action != "ws.branches" || len(branchesResp.Result.Items) != 2 {
+		t.Fatalf("ws branches json = %s", out)
+	}
+	ws1, ws2 := branchesResp.Result.Items[0], branchesResp.Result.Items[1]
+	if ws1.WorkspaceID != "WS1" || ws1.Status != "archived" || ws1.Branch != "feature/x" || ws1.UpstreamState != branchUpstreamGone || !slices.Equal(ws1.DuplicateOf, []string{"WS2"}) {
+		t.Fatalf("WS1 item = %+v", ws1)
+	}
+	if ws2.WorkspaceID != "WS2" || ws2.Status != "active" || !slices.Equal(ws2.DuplicateOf, []string{"WS1"}) {
+		t.Fatalf("WS2 item = %+v", ws2)
+	}
+	if len(branchesResp.Result.Duplicates) != 1 || branchesResp.Result.Duplicates[0].Branch != "feature/x" || !slices.Equal(branchesResp.Result.Duplicates[0].Workspaces, []string{"WS1", "WS2"}) {
+		t.Fatalf("duplicates = %+v", branchesResp.Result.Duplicates)
+	}
+
+	code, out, _ = run("ws", "branches")
+	if code != exitOK {
+		t.Fatalf("ws branches human exit code = %d", code)
+	}
+	for _, want := range []string{"WS1 [archived]", "[gone]", "[duplicate: WS2]", "Warnings:", "branch feature/x is used by WS1, WS2", "branch feature/x no longer exists upstream (WS1)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("ws branches human output missing %q:\n%s", want, out)
+		}
+	}
+
+	code, out, _ = run("repo", "usage")
+	if code != exitOK || !strings.Contains(out, "example-org/web (unused)") || !strings.Contains(out, "WS1 feature/x [archived]") {
+		t.Fatalf("repo usage human: code=%d out=\n%s", code, out)
+	}
+	code, out, _ = run("repo", "usage", "--format", "json", "example-org/api")
+	if code != exitOK {
+		t.Fatalf("repo usage json exit code = %d (out=%s)", code, out)
+	}
+	var usageResp struct {
+		Result struct {
+			Items []struct {
+				RepoKey    string `json:"repo_key"`
+				Workspaces []struct {
+					WorkspaceID string `json:"workspace_id"`
+				} `json:"workspaces"`
+			} `json:"items"`
+			Duplicates []json.RawMessage `json:"duplicates"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &usageResp); err != nil {
+		t.Fatalf("decode repo usage: %v (out=%s)", err, out)
+	}
+	if len(usageResp.Result.Items) != 1 || len(usageResp.Result.Items[0].Workspaces) != 2 || len(usageResp.Result.Duplicates) != 1 {
+		t.Fatalf("repo usage json = %s", out)
+	}
+	if code, out, _ := run("repo", "usage", "--format", "json", "example-org/missing"); code != exitError || !strings.Contains(out, `"code":"not_found"`) {
+		t.Fatalf("repo usage unknown repo: code=%d out=%s", code, out)
+	}
+}
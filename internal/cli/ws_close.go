@@ -488,8 +488,13 @@ func (c *CLI) closeWorkspace(ctx context.Context, root string, workspaceID strin
 		return closeCommitTrace{}, fmt.Errorf("list workspace files for archive commit: %w", err)
 	}
 	trace := closeCommitTrace{CommitEnabled: doCommit}
+	var lc lifecycleCommit
 	if doCommit {
-		preSHA, err := commitClosePreSnapshot(ctx, root, workspaceID)
+		lc, err = c.loadLifecycleCommit(root, workspaceID)
+		if err != nil {
+			return closeCommitTrace{}, fmt.Errorf("resolve lifecycle commit: %w", err)
+		}
+		preSHA, err := commitClosePreSnapshot(ctx, root, workspaceID, lc)
 		if err != nil {
 			return closeCommitTrace{}, fmt.Errorf("commit close pre-snapshot: %w", err)
 		}
@@ -519,7 +524,7 @@ func (c *CLI) closeWorkspace(ctx context.Context, root string, workspaceID strin
 	}
 
 	if doCommit {
-		postSHA, err := commitArchiveChange(ctx, root, workspaceID, expectedFiles)
+		postSHA, err := commitArchiveChange(ctx, root, workspaceID, expectedFiles, lc)
 		if err != nil {
 			return closeCommitTrace{}, fmt.Errorf("commit archive change: %w", err)
 		}
@@ -626,13 +631,25 @@ func buildWorkspaceMetaForClose(ctx context.Context, root string, workspaceID st
 			}
 		}
 
+		prev := existingByAlias[r.Alias]
+		sparse := prev.Sparse
+		if fi, err := os.Stat(worktreePath); err == nil && fi.IsDir() {
+			sparse = detectWorktreeSparse(ctx, worktreePath)
+		}
+
 		entries = append(entries, workspaceMetaRepoRestore{
-			RepoUID:   r.RepoUID,
-			RepoKey:   spec.RepoKey,
-			RemoteURL: remoteURL,
-			Alias:     r.Alias,
-			Branch:    branch,
-			BaseRef:   baseRef,
+			RepoUID:        r.RepoUID,
+			RepoKey:        spec.RepoKey,
+			RemoteURL:      remoteURL,
+			Alias:          r.Alias,
+			Branch:         branch,
+			BaseRef:        baseRef,
+			Sparse:         sparse,
+			Submodules:     prev.Submodules,
+			LFS:            prev.LFS,
+			PullRequestURL: prev.PullRequestURL,
+			Reference:      prev.Reference,
+			Stack:          prev.Stack,
 		})
 	}
 	slices.SortFunc(entries, func(a, b workspaceMetaRepoRestore) int {
@@ -711,6 +728,8 @@ type closeRepoPlanDetail struct {
 	files      []string
 	filesANSI  []string
 	worktreeOK bool
+	// stack is the live state of recorded stacked branches; their risk is folded into state.
+	stack []wsStackBranch
 }
 
 func collectWorkspaceRiskDetails(ctx context.Context, root string, workspaceIDs []string) ([]workspaceRiskDetail, error) {
@@ -811,6 +830,9 @@ func appendCloseRepoPlanBody(body *[]string, item workspaceRiskDetail, useColor
 			styleMuted("behind=", useColor),
 			renderPlanAheadBehindValue(p.behind, useColor),
 		))
+		if len(p.stack) > 0 {
+			*body = append(*body, fmt.Sprintf("%s%s%s %s", uiIndent+uiIndent, prefix, styleMuted("stack:", useColor), renderWSStackSummary(p.stack, useColor)))
+		}
 		if len(p.files) > 0 {
 			*body = append(*body, fmt.Sprintf("%s%s%s", uiIndent+uiIndent, prefix, styleMuted("files:", useColor)))
 			for _, f := range p.files {
@@ -933,6 +955,9 @@ func collectCloseRepoPlanDetails(ctx context.Context, root string, workspaceID s
 			snapshot := inspectGitRepoSnapshot(ctx, worktreePath)
 			status := snapshot.Status
 			d.state = workspacerisk.ClassifyRepoStatus(status)
+			if r.Reference {
+				d.state = workspacerisk.ClassifyReferenceRepoStatus(status)
+			}
 			d.upstream = strings.TrimSpace(status.Upstream)
 			d.ahead = status.AheadCount
 			d.behind = status.BehindCount
@@ -943,6 +968,10 @@ func collectCloseRepoPlanDetails(ctx context.Context, root string, workspaceID s
 			d.unstaged = snapshot.Unstaged
 			d.untracked = snapshot.Untracked
 			d.files = append([]string{}, snapshot.Files...)
+			if len(r.Stack) > 0 && !r.Reference {
+				d.stack = inspectWSStack(ctx, worktreePath, r.BaseRef, r.Stack, d.branch)
+				d.state = stackRiskState(d.state, d.stack)
+			}
 		}
 		details = append(details, d)
 	}
@@ -997,6 +1026,7 @@ func removeWorkspaceWorktrees(ctx context.Context, root string, workspaceID stri
 		}
 
 		if _, err := os.Stat(barePath); err == nil {
+			removeWorkspaceCommitHooksBestEffort(ctx, worktreePath)
 			_, err := gitutil.RunBare(ctx, barePath, "worktree", "remove", "--force", worktreePath)
 			if err != nil {
 				return err
@@ -1144,7 +1174,7 @@ func resetClosePreStaging(ctx context.Context, root, workspacesArg string) {
 	_, _ = gitutil.Run(ctx, root, "reset", "-q", "--", workspacesArg)
 }
 
-func commitClosePreSnapshot(ctx context.Context, root string, workspaceID string) (string, error) {
+func commitClosePreSnapshot(ctx context.Context, root string, workspaceID string, lc lifecycleCommit) (string, error) {
 	workspacesPrefix, err := toGitTopLevelPath(ctx, root, filepath.Join("workspaces", workspaceID))
 	if err != nil {
 		return "", err
@@ -1171,7 +1201,7 @@ func commitClosePreSnapshot(ctx context.Context, root string, workspaceID string
 		}
 	}
 
-	if _, err := gitutil.Run(ctx, root, "commit", "--allow-empty", "--only", "-m", fmt.Sprintf("close-pre: %s", workspaceID), "--", workspacesArg); err != nil {
+	if _, err := gitutil.Run(ctx, root, lc.gitArgs("close-pre", []string{"--allow-empty", "--only"}, workspacesArg)...); err != nil {
 		resetClosePreStaging(ctx, root, workspacesArg)
 		return "", err
 	}
@@ -1184,7 +1214,7 @@ func commitClosePreSnapshot(ctx context.Context, root string, workspaceID string
 	return strings.TrimSpace(sha), nil
 }
 
-func commitArchiveChange(ctx context.Context, root string, workspaceID string, expectedArchiveFiles []string) (string, error) {
+func commitArchiveChange(ctx context.Context, root string, workspaceID string, expectedArchiveFiles []string, lc lifecycleCommit) (string, error) {
 	archivePrefix, err := toGitTopLevelPath(ctx, root, filepath.Join("archive", workspaceID))
 	if err != nil {
 		return "", err
@@ -1225,7 +1255,7 @@ func commitArchiveChange(ctx context.Context, root string, workspaceID string, e
 	}
 	for _, arg := range []string{baselineArg, workStateArg} {
 		if _, err := gitutil.Run(ctx, root, "add", "-A", "--", arg); err != nil {
-			if strings.Contains(err.Error(), "did not match any files") || strings.Contains(err.Error(), "did not match any file") {
+			if gitAddPathSkipped(err) {
 				continue
 			}
 			resetArchiveStaging(ctx, root, resetArgs...)
@@ -1289,16 +1319,17 @@ func commitArchiveChange(ctx context.Context, root string, workspaceID string, e
 		return "", fmt.Errorf("unexpected staged path outside allowlist: %s", p)
 	}
 
-	commitArgs := []string{"commit", "--only", "-m", fmt.Sprintf("archive: %s", workspaceID), "--", archiveArg}
+	commitPaths := []string{archiveArg}
 	if hasWorkspacesStage {
-		commitArgs = append(commitArgs, workspacesArg)
+		commitPaths = append(commitPaths, workspacesArg)
 	}
 	if hasBaselineStage {
-		commitArgs = append(commitArgs, baselineArg)
+		commitPaths = append(commitPaths, baselineArg)
 	}
 	if hasWorkStateStage {
-		commitArgs = append(commitArgs, workStateArg)
+		commitPaths = append(commitPaths, workStateArg)
 	}
+	commitArgs := lc.gitArgs("archive", []string{"--only"}, commitPaths...)
 	if _, err := gitutil.Run(ctx, root, commitArgs...); err != nil {
 		resetArchiveStaging(ctx, root, resetArgs...)
 		return "", err
@@ -669,7 +669,7 @@ func TestCommitArchiveChange_AllowlistsNestedRootRelativePaths(t *testing.T) {
 	sha, err := commitArchiveChange(context.Background(), root, wsID, []string{
 		workspaceMetaFilename,
 		".claude/settings.local.json",
-	})
+	}, lifecycleCommit{workspaceID: wsID})
 	if err != nil {
 		t.Fatalf("commitArchiveChange() error = %v, want nil", err)
 	}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/config"
+	"github.com/tasuku43/kra/internal/core/commitmsg"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+)
+
+// commitHooksDirname is created inside a worktree's own git dir (<bare>/worktrees/<name>/), so it is
+// private to that worktree and disappears with git worktree remove.
+const commitHooksDirname = "kra"
+
+// workspaceCommitHooks is workspace.commit rendered for one workspace.
+type workspaceCommitHooks struct {
+	WorkspaceID string
+	Template    string
+	Prefix      string
+	Trailer     string
+}
+
+func (h workspaceCommitHooks) enabled() bool {
+	return h.Template != "" || h.Prefix != "" || h.Trailer != ""
+}
+
+// renderWorkspaceCommitHooks renders workspace.commit; parts using an empty source_url are dropped.
+func renderWorkspaceCommitHooks(cfg config.WorkspaceCommit, workspaceID string, sourceURL string) (workspaceCommitHooks, error) {
+	vars := commitmsg.Vars{WorkspaceID: workspaceID, SourceURL: sourceURL}
+	out := workspaceCommitHooks{WorkspaceID: workspaceID}
+	for _, part := range []struct {
+		key   string
+		value string
+		dst   *string
+	}{
+		{key: "workspace.commit.template", value: cfg.Template, dst: &out.Template},
+		{key: "workspace.commit.prefix", value: cfg.Prefix, dst: &out.Prefix},
+		{key: "workspace.commit.trailer", value: cfg.Trailer, dst: &out.Trailer},
+	} {
+		rendered, ok, err := commitmsg.Render(part.value, vars)
+		if err != nil {
+			return workspaceCommitHooks{}, fmt.Errorf("%s: %w", part.key, err)
+		}
+		if ok {
+			*part.dst = rendered
+		}
+	}
+	return out, nil
+}
+
+func (c *CLI) loadWorkspaceCommitHooks(root string, workspaceID string, sourceURL string) (workspaceCommitHooks, error) {
+	cfg, err := c.loadMergedConfig(root)
+	if err != nil {
+		return workspaceCommitHooks{}, fmt.Errorf("load config: %w", err)
+	}
+	return renderWorkspaceCommitHooks(cfg.Workspace.Commit, workspaceID, sourceURL)
+}
+
+// installWorkspaceCommitHooks points the worktree at kra-managed hooks and commit template through
+// worktree-specific config (config.worktree). The pool repo's shared config and hooks are not changed;
+// every managed hook forwards to the hook the worktree would have run otherwise.
+func installWorkspaceCommitHooks(ctx context.Context, worktreePath string, hooks workspaceCommitHooks) error {
+	if err := removeWorkspaceCommitHooks(ctx, worktreePath); err != nil {
+		return err
+	}
+	if !hooks.enabled() {
+		return nil
+	}
+	commonDir, err := worktreeCommonDir(ctx, worktreePath)
+	if err != nil {
+		return err
+	}
+	if err := enableWorktreeConfig(ctx, commonDir); err != nil {
+		return err
+	}
+	gitDir, err := gitutil.Run(ctx, worktreePath, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return fmt.Errorf("resolve worktree git dir: %w", err)
+	}
+	dir := filepath.Join(strings.TrimSpace(gitDir), commitHooksDirname)
+
+	chainDir := filepath.Join(commonDir, "hooks")
+	if configured, err := gitutil.Run(ctx, worktreePath, "config", "--path", "--get", "core.hooksPath"); err == nil && strings.TrimSpace(configured) != "" {
+		chainDir = strings.TrimSpace(configured)
+		if !filepath.IsAbs(chainDir) {
+			chainDir = filepath.Join(worktreePath, chainDir)
+		}
+	}
+
+	hooksDir := filepath.Join(dir, "hooks")
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		return fmt.Errorf("create hooks dir: %w", err)
+	}
+	for _, name := range commitmsg.HookNames {
+		script := commitmsg.ForwardHook(hooks.WorkspaceID, name, chainDir)
+		if name == "commit-msg" {
+			script = commitmsg.CommitMsgHook(hooks.WorkspaceID, hooks.Prefix, hooks.Trailer, chainDir)
+		}
+		if err := os.WriteFile(filepath.Join(hooksDir, name), []byte(script), 0o755); err != nil {
+			return fmt.Errorf("write %s hook: %w", name, err)
+		}
+	}
+	if _, err := gitutil.Run(ctx, worktreePath, "config", "--worktree", "core.hooksPath", hooksDir); err != nil {
+		return fmt.Errorf("set core.hooksPath: %w", err)
+	}
+	if hooks.Template != "" {
+		templatePath := filepath.Join(dir, "commit-template")
+		if err := os.WriteFile(templatePath, []byte(hooks.Template+"\n"), 0o644); err != nil {
+			return fmt.Errorf("write commit template: %w", err)
+		}
+		if _, err := gitutil.Run(ctx, worktreePath, "config", "--worktree", "commit.template", templatePath); err != nil {
+			return fmt.Errorf("set commit.template: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeWorkspaceCommitHooks drops the kra-managed entries from the worktree config and its git dir.
+// Values the user set themselves are left alone.
+func removeWorkspaceCommitHooks(ctx context.Context, worktreePath string) error {
+	gitDir, err := gitutil.Run(ctx, worktreePath, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return fmt.Errorf("resolve worktree git dir: %w", err)
+	}
+	dir := filepath.Join(strings.TrimSpace(gitDir), commitHooksDirname)
+	if _, err := os.Stat(filepath.Join(strings.TrimSpace(gitDir), "config.worktree")); err == nil {
+		for _, key := range []string{"core.hooksPath", "commit.template"} {
+			value, err := gitutil.Run(ctx, worktreePath, "config", "--worktree", "--get", key)
+			if err != nil || !strings.HasPrefix(strings.TrimSpace(value), dir+string(filepath.Separator)) {
+				continue
+			}
+			if _, err := gitutil.Run(ctx, worktreePath, "config", "--worktree", "--unset", key); err != nil {
+				return fmt.Errorf("unset %s: %w", key, err)
+			}
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("remove %s: %w", dir, err)
+	}
+	return nil
+}
+
+// enableWorktreeConfig turns on extensions.worktreeConfig for the pool repo. core.bare=true moves to the
+// bare repo's own config.worktree first (as git sparse-checkout does), otherwise linked worktrees
+// would read it from the shared config and stop working.
+func enableWorktreeConfig(ctx context.Context, commonDir string) error {
+	if enabled, err := gitutil.RunBare(ctx, commonDir, "config", "--bool", "--get", "extensions.worktreeConfig"); err == nil && strings.TrimSpace(enabled) == "true" {
+		return nil
+	}
+	bare, err := gitutil.RunBare(ctx, commonDir, "config", "--local", "--bool", "--get", "core.bare")
+	moveBare := err == nil && strings.TrimSpace(bare) == "true"
+	if moveBare {
+		if _, err := gitutil.RunBare(ctx, commonDir, "config", "--file", filepath.Join(commonDir, "config.worktree"), "core.bare", "true"); err != nil {
+			return fmt.Errorf("move core.bare to config.worktree: %w", err)
+		}
+	}
+	if _, err := gitutil.RunBare(ctx, commonDir, "config", "extensions.worktreeConfig", "true"); err != nil {
+		return fmt.Errorf("enable extensions.worktreeConfig: %w", err)
+	}
+	if moveBare {
+		if _, err := gitutil.RunBare(ctx, commonDir, "config", "--local", "--unset", "core.bare"); err != nil {
+			return fmt.Errorf("move core.bare to config.worktree: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeWorkspaceCommitHooksBestEffort is used right before a worktree is removed; a broken worktree
+// must not block close / remove-repo, and its git dir goes away with it anyway.
+func removeWorkspaceCommitHooksBestEffort(ctx context.Context, worktreePath string) {
+	_ = removeWorkspaceCommitHooks(ctx, worktreePath)
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_CommitHooks_PrefixTrailerTemplate_InstallAndRemove(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	gitOut := func(dir string, args ...string) (string, error) {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		return strings.TrimSpace(string(out)), err
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+	commit := func(dir string, name string, message string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(dir, "add", name)
+		runGit(dir, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-m", message)
+		msg, err := gitOut(dir, "log", "-1", "--format=%B")
+		if err != nil {
+			t.Fatalf("git log: %v (%s)", err, msg)
+		}
+		return msg
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	cfgPath := filepath.Join(env.Root, ".kra", "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte(`
+workspace:
+  commit:
+    template: "{{workspace_id}}: "
+    prefix: "[{{workspace_id}}]"
+    trailer: "Refs: {{source_url}}"
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	repoSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	if code, _, stderr := run("repo", "add", repoSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	wsPath := filepath.Join(env.Root, "workspaces", "WS1")
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		t.Fatalf("load %s: %v", workspaceMetaFilename, err)
+	}
+	meta.Workspace.SourceURL = "https://jira.example.com/browse/WS1"
+	if err := writeWorkspaceMetaFile(wsPath, meta); err != nil {
+		t.Fatalf("write %s: %v", workspaceMetaFilename, err)
+	}
+	if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--yes"); code != exitOK {
+		t.Fatalf("ws add-repo exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	repoPath := filepath.Join(wsPath, "repos", "api")
+	barePath, err := worktreeCommonDir(context.Background(), repoPath)
+	if err != nil {
+		t.Fatalf("resolve bare path: %v", err)
+	}
+
+	// The pool repo's own hooks keep running through the managed hooks.
+	sharedLog := filepath.Join(t.TempDir(), "shared-hook.log")
+	sharedHook := "#!/bin/sh\necho ran >>" + sharedLog + "\n"
+	if err := os.WriteFile(filepath.Join(barePath, "hooks", "commit-msg"), []byte(sharedHook), 0o755); err != nil {
+		t.Fatalf("write shared hook: %v", err)
+	}
+
+	msg := commit(repoPath, "a.txt", "fix login")
+	if !strings.HasPrefix(msg, "[WS1] fix login") || !strings.Contains(msg, "Refs: https://jira.example.com/browse/WS1") {
+		t.Fatalf("commit message = %q, want prefix and trailer", msg)
+	}
+	if msg := commit(repoPath, "b.txt", "[WS1] already linked\n\nRefs: https://jira.example.com/browse/WS1"); strings.Count(msg, "[WS1]") != 1 || strings.Count(msg, "Refs:") != 1 {
+		t.Fatalf("commit message should not be linked twice: %q", msg)
+	}
+	if msg := commit(repoPath, "c.txt", "fixup! fix login"); !strings.HasPrefix(msg, "fixup! fix login") {
+		t.Fatalf("fixup subject should be kept for autosquash: %q", msg)
+	}
+	if b, err := os.ReadFile(sharedLog); err != nil || strings.Count(string(b), "ran") != 3 {
+		t.Fatalf("shared commit-msg hook runs = %q (err=%v), want 3", string(b), err)
+	}
+	template, err := gitOut(repoPath, "config", "commit.template")
+	if err != nil {
+		t.Fatalf("commit.template not set: %v (%s)", err, template)
+	}
+	if b, err := os.ReadFile(template); err != nil || string(b) != "WS1:\n" {
+		t.Fatalf("commit template = %q (err=%v)", string(b), err)
+	}
+
+	// Worktree-specific config only: the pool repo keeps its shared settings and stays bare.
+	if out, err := gitOut(barePath, "config", "--local", "--get", "core.hooksPath"); err == nil {
+		t.Fatalf("pool repo core.hooksPath should stay unset, got %q", out)
+	}
+	if out, _ := gitOut(barePath, "rev-parse", "--is-bare-repository"); out != "true" {
+		t.Fatalf("pool repo should stay bare, got %q", out)
+	}
+
+	if code, _, stderr := run("ws", "close", "--force", "--format", "json", "--id", "WS1"); code != exitOK {
+		t.Fatalf("ws close exit code = %d (stderr=%q)", code, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(barePath, "worktrees", "api")); !os.IsNotExist(err) {
+		t.Fatalf("worktree git dir should be gone after close: %v", err)
+	}
+	if code, _, stderr := run("ws", "reopen", "WS1"); code != exitOK {
+		t.Fatalf("ws reopen exit code = %d (stderr=%q)", code, stderr)
+	}
+	if msg := commit(repoPath, "d.txt", "after reopen"); !strings.HasPrefix(msg, "[WS1] after reopen") {
+		t.Fatalf("hooks should be reinstalled on reopen: %q", msg)
+	}
+
+	gitDir, _ := gitOut(repoPath, "rev-parse", "--absolute-git-dir")
+	if err := removeWorkspaceCommitHooks(context.Background(), repoPath); err != nil {
+		t.Fatalf("remove commit hooks: %v", err)
+	}
+	if out, err := gitOut(repoPath, "config", "--get", "core.hooksPath"); err == nil {
+		t.Fatalf("core.hooksPath should be unset after removal, got %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, commitHooksDirname)); !os.IsNotExist(err) {
+		t.Fatalf("managed hooks dir should be removed: %v", err)
+	}
+	if code, out, stderr := run("ws", "remove-repo", "--format", "json", "--id", "WS1", "--repo", "api", "--yes", "--force"); code != exitOK {
+		t.Fatalf("ws remove-repo exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	if _, err := os.Stat(gitDir); !os.IsNotExist(err) {
+		t.Fatalf("worktree git dir should be gone after remove-repo: %v", err)
+	}
+}
@@ -21,6 +21,8 @@ import (
 
 func (c *CLI) runWSCreate(args []string) int {
 	var noPrompt bool
+	var offline bool
+	var noNotes bool
 	var jiraTicketURL string
 	var idFlag string
 	var titleFlag string
@@ -34,6 +36,12 @@ func (c *CLI) runWSCreate(args []string) int {
 		case "--no-prompt":
 			noPrompt = true
 			args = args[1:]
+		case "--offline":
+			offline = true
+			args = args[1:]
+		case "--no-notes":
+			noNotes = true
+			args = args[1:]
 		case "--jira":
 			if len(args) < 2 {
 				fmt.Fprintln(c.Err, "--jira requires a ticket URL")
@@ -110,22 +118,33 @@ func (c *CLI) runWSCreate(args []string) int {
 		c.printWSCreateUsage(c.Err)
 		return exitUsage
 	}
+	jiraWarnings := make([]string, 0)
 	writeRuntimeError := func(code string, message string) int {
 		if outputFormat == "json" {
-			_ = writeCLIJSON(c.Out, cliJSONResponse{
+			resp := cliJSONResponse{
 				OK:     false,
 				Action: "ws.create",
 				Error: &cliJSONError{
 					Code:    code,
 					Message: message,
 				},
-			})
+			}
+			if len(jiraWarnings) > 0 {
+				resp.Warnings = jiraWarnings
+			}
+			_ = writeCLIJSON(c.Out, resp)
 			return exitError
 		}
 		fmt.Fprintln(c.Err, message)
 		return exitError
 	}
 
+	if offline && jiraTicketURL == "" {
+		return writeUsageError("--offline requires --jira")
+	}
+	if noNotes && jiraTicketURL == "" {
+		return writeUsageError("--no-notes requires --jira")
+	}
 	if jiraTicketURL != "" {
 		if idFlag != "" || titleFlag != "" {
 			return writeUsageError("--jira cannot be combined with --id or --title")
@@ -172,19 +191,44 @@ func (c *CLI) runWSCreate(args []string) int {
 		return writeRuntimeError("internal_error", fmt.Sprintf("resolve template: %v", err))
 	}
 
+	notesPath := ""
+	if jiraTicketURL != "" && !noNotes {
+		notesPath, err = resolveTicketNotesPath(cfg)
+		if err != nil {
+			return writeRuntimeError("invalid_argument", fmt.Sprintf("resolve notes path: %v", err))
+		}
+	}
+
 	ctx := context.Background()
 	id := ""
 	title := ""
 	sourceURL := ""
+	notes := ""
 	if jiraTicketURL != "" {
-		svc := wscreate.NewService(appports.NewWSCreateJiraPortWithBaseURL(cfg.Integration.Jira.BaseURL))
+		svc := wscreate.NewService(appports.NewWSCreateJiraPortWithClient(c.newJiraClient(cfg, offline, &jiraWarnings)))
 		in, err := svc.ResolveJiraWorkspaceInput(ctx, jiraTicketURL)
+		if err == nil && notesPath != "" {
+			// Notes are best-effort: a failed detail fetch must not block workspace creation.
+			if notes, err = svc.ResolveTicketNotes(ctx, in.ID); err != nil {
+				jiraWarnings = append(jiraWarnings, fmt.Sprintf("ticket notes skipped: %v", err))
+				notes = ""
+			}
+			err = nil
+		}
+		if outputFormat != "json" {
+			c.printJiraWarnings(jiraWarnings)
+		}
 		if err != nil {
 			return writeRuntimeError("not_found", fmt.Sprintf("resolve jira issue: %v", err))
 		}
 		id = in.ID
 		title = in.Title
 		sourceURL = in.SourceURL
+		templateName = c.resolveJiraWorkspaceTemplateName(cfg, templateNameFlag, config.TemplateRuleSubject{
+			IssueType: in.IssueType,
+			Labels:    in.Labels,
+			Project:   in.ProjectKey,
+		})
 	} else {
 		if strings.TrimSpace(idFlag) != "" {
 			id = strings.TrimSpace(idFlag)
@@ -217,39 +261,58 @@ func (c *CLI) runWSCreate(args []string) int {
 	if err != nil {
 		return writeRuntimeError(classifyWSCreateErrorCode(err), err.Error())
 	}
+	if notes != "" {
+		if err := writeWorkspaceTicketNotes(wsPath, notesPath, notes); err != nil {
+			return writeRuntimeError("internal_error", fmt.Sprintf("write ticket notes: %v", err))
+		}
+	}
 	now := time.Now().Unix()
 	if err := createOrRefreshWorkspaceBaseline(ctx, root, id, now); err != nil {
 		return writeRuntimeError("internal_error", fmt.Sprintf("initialize workspace baseline: %v", err))
 	}
-	createCommitSHA, err := commitCreateWorkspace(ctx, root, id)
+	lc, err := c.loadLifecycleCommit(root, id)
+	if err != nil {
+		return writeRuntimeError("internal_error", fmt.Sprintf("resolve lifecycle commit: %v", err))
+	}
+	createCommitSHA, err := commitCreateWorkspace(ctx, root, id, lc)
 	if err != nil {
 		return writeRuntimeError("internal_error", fmt.Sprintf("commit create change: %v", err))
 	}
 
 	if outputFormat == "json" {
-		_ = writeCLIJSON(c.Out, cliJSONResponse{
+		result := map[string]any{
+			"created":    1,
+			"path":       wsPath,
+			"template":   templateName,
+			"commit_sha": createCommitSHA,
+		}
+		if notes != "" {
+			result["notes_path"] = notesPath
+		}
+		resp := cliJSONResponse{
 			OK:          true,
 			Action:      "ws.create",
 			WorkspaceID: id,
-			Result: map[string]any{
-				"created":    1,
-				"path":       wsPath,
-				"template":   templateName,
-				"commit_sha": createCommitSHA,
-			},
-		})
+			Result:      result,
+		}
+		if len(jiraWarnings) > 0 {
+			resp.Warnings = jiraWarnings
+		}
+		_ = writeCLIJSON(c.Out, resp)
 		c.debugf("ws create completed id=%s path=%s format=json", id, wsPath)
 		return exitOK
 	}
 
 	useColorOut := writerSupportsColor(c.Out)
-	printResultSection(
-		c.Out,
-		useColorOut,
+	resultLines := []string{
 		styleSuccess("Created 1 / 1", useColorOut),
 		fmt.Sprintf("%s %s", styleSuccess("✔", useColorOut), id),
 		styleMuted(fmt.Sprintf("path: %s", wsPath), useColorOut),
-	)
+	}
+	if notes != "" {
+		resultLines = append(resultLines, styleMuted(fmt.Sprintf("notes: %s", notesPath), useColorOut))
+	}
+	printResultSection(c.Out, useColorOut, resultLines...)
 	c.debugf("ws create completed id=%s path=%s commit=%s", id, wsPath, shortCommitSHA(createCommitSHA))
 	return exitOK
 }
@@ -283,6 +346,19 @@ func (c *CLI) resolveWSCreateTemplateName(cfg config.Config, templateNameFlag st
 	return defaultWorkspaceTemplateName, nil
 }
 
+// resolveJiraWorkspaceTemplateName applies workspace.defaults.template_rules to a Jira issue.
+// An explicit --template wins over rules; unmatched issues use the regular template resolution.
+func (c *CLI) resolveJiraWorkspaceTemplateName(cfg config.Config, templateNameFlag string, subject config.TemplateRuleSubject) string {
+	if templateName := strings.TrimSpace(templateNameFlag); templateName != "" {
+		return templateName
+	}
+	if rule, ok := config.MatchTemplateRule(cfg.Workspace.Defaults.TemplateRules, subject); ok {
+		return rule.Template
+	}
+	templateName, _ := c.resolveWSCreateTemplateName(cfg, "")
+	return templateName
+}
+
 func (c *CLI) promptLine(prompt string) (string, error) {
 	inFile, inOK := c.In.(*os.File)
 	errFile, errOK := c.Err.(*os.File)
@@ -322,7 +398,7 @@ func resetCreateStaging(ctx context.Context, root string, args []string) {
 	}
 }
 
-func commitCreateWorkspace(ctx context.Context, root string, workspaceID string) (string, error) {
+func commitCreateWorkspace(ctx context.Context, root string, workspaceID string, lc lifecycleCommit) (string, error) {
 	if err := ensureRootGitWorktree(ctx, root); err != nil {
 		return "", err
 	}
@@ -347,7 +423,7 @@ func commitCreateWorkspace(ctx context.Context, root string, workspaceID string)
 
 	for _, arg := range args {
 		if _, err := gitutil.Run(ctx, root, "add", "-A", "--", arg); err != nil {
-			if strings.Contains(err.Error(), "did not match any files") || strings.Contains(err.Error(), "did not match any file") {
+			if gitAddPathSkipped(err) {
 				continue
 			}
 			resetCreateStaging(ctx, root, args)
@@ -377,13 +453,14 @@ func commitCreateWorkspace(ctx context.Context, root string, workspaceID string)
 		resetCreateStaging(ctx, root, args)
 		return "", fmt.Errorf("unexpected staged path outside allowlist: %s", p)
 	}
-	commitArgs := []string{"commit", "--only", "-m", fmt.Sprintf("create: %s", workspaceID), "--", workspaceArg}
+	commitPaths := []string{workspaceArg}
 	if hasBaselineStage {
-		commitArgs = append(commitArgs, baselineArg)
+		commitPaths = append(commitPaths, baselineArg)
 	}
 	if hasWorkStateStage {
-		commitArgs = append(commitArgs, workStateArg)
+		commitPaths = append(commitPaths, workStateArg)
 	}
+	commitArgs := lc.gitArgs("create", []string{"--only"}, commitPaths...)
 	if _, err := gitutil.Run(ctx, root, commitArgs...); err != nil {
 		resetCreateStaging(ctx, root, args)
 		return "", err
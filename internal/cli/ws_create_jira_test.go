@@ -210,3 +210,128 @@ func TestCLI_WS_Create_Jira_UsesConfigBaseURLWhenEnvMissing(t *testing.T) {
 		t.Fatalf("stdout missing created issue key: %q", out.String())
 	}
 }
+
+func TestCLI_WS_Create_Jira_WritesTicketNotes(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("expand") != "names" {
+			_, _ = w.Write([]byte(`{"key":"PROJ-400","fields":{"summary":"Notes"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"key":"PROJ-400","names":{"customfield_1":"Acceptance Criteria"},"fields":{
+			"summary":"Notes",
+			"labels":["backend"],
+			"assignee":{"displayName":"Dev One"},
+			"description":{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Write the "},{"type":"text","text":"notes","marks":[{"type":"strong"}]}]}]},
+			"customfield_1":{"type":"doc","content":[{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"file exists"}]}]}]}]}
+		}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	rootConfigPath := filepath.Join(env.Root, ".kra", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(rootConfigPath), 0o755); err != nil {
+		t.Fatalf("mkdir root config dir: %v", err)
+	}
+	if err := os.WriteFile(rootConfigPath, []byte("integration:\n  jira:\n    notes:\n      path: docs/TICKET.md\n"), 0o644); err != nil {
+		t.Fatalf("write root config: %v", err)
+	}
+
+	var out bytes.Buffer
+	var errBuf bytes.Buffer
+	code := New(&out, &errBuf).Run([]string{"ws", "create", "--jira", "https://jira.example.com/browse/PROJ-400"})
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stderr=%q)", code, exitOK, errBuf.String())
+	}
+	if !strings.Contains(out.String(), "notes: docs/TICKET.md") {
+		t.Fatalf("stdout missing notes path: %q", out.String())
+	}
+	notes, err := os.ReadFile(filepath.Join(env.Root, "workspaces", "PROJ-400", "docs", "TICKET.md"))
+	if err != nil {
+		t.Fatalf("read notes: %v", err)
+	}
+	for _, want := range []string{
+		"# PROJ-400: Notes\n",
+		"- Assignee: Dev One\n",
+		"- Labels: backend\n",
+		"## Description\n\nWrite the **notes**\n",
+		"## Acceptance Criteria\n\n- file exists\n",
+	} {
+		if !strings.Contains(string(notes), want) {
+			t.Fatalf("notes missing %q:\n%s", want, string(notes))
+		}
+	}
+}
+
+func TestCLI_WS_Create_Jira_NoNotesSkipsTicketNotes(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"key":"PROJ-401","fields":{"summary":"No notes"}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	var out bytes.Buffer
+	var errBuf bytes.Buffer
+	code := New(&out, &errBuf).Run([]string{"ws", "create", "--no-notes", "--jira", "https://jira.example.com/browse/PROJ-401"})
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stderr=%q)", code, exitOK, errBuf.String())
+	}
+	if requests != 1 {
+		t.Fatalf("requests = %d, want 1", requests)
+	}
+	if _, err := os.Stat(filepath.Join(env.Root, "workspaces", "PROJ-401", "notes", "ticket.md")); !os.IsNotExist(err) {
+		t.Fatalf("ticket notes should not exist: %v", err)
+	}
+}
+
+func TestCLI_WS_Create_Jira_TemplateRuleByIssueType(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	bugTemplate := filepath.Join(env.Root, "templates", "bug")
+	if err := os.MkdirAll(bugTemplate, 0o755); err != nil {
+		t.Fatalf("mkdir bug template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(bugTemplate, "REPRO.md"), []byte("steps\n"), 0o644); err != nil {
+		t.Fatalf("write REPRO.md: %v", err)
+	}
+	rootConfigPath := filepath.Join(env.Root, ".kra", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(rootConfigPath), 0o755); err != nil {
+		t.Fatalf("mkdir root config dir: %v", err)
+	}
+	if err := os.WriteFile(rootConfigPath, []byte("workspace:\n  defaults:\n    template_rules:\n      - template: bug\n        issue_type: bug\n"), 0o644); err != nil {
+		t.Fatalf("write root config: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"PROJ-410","fields":{"summary":"Broken","issuetype":{"name":"Bug"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	var out bytes.Buffer
+	var errBuf bytes.Buffer
+	code := New(&out, &errBuf).Run([]string{"ws", "create", "--no-notes", "--format", "json", "--jira", "https://jira.example.com/browse/PROJ-410"})
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stdout=%q)", code, exitOK, out.String())
+	}
+	if !strings.Contains(out.String(), `"template":"bug"`) {
+		t.Fatalf("stdout missing routed template: %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(env.Root, "workspaces", "PROJ-410", "REPRO.md")); err != nil {
+		t.Fatalf("workspace missing bug template file: %v", err)
+	}
+}
@@ -0,0 +1,405 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/infra/paths"
+)
+
+// Per-repo outcomes of ws diff.
+const (
+	wsDiffChanged = "changed"
+	wsDiffClean   = "clean"
+	wsDiffSkipped = "skipped"
+	wsDiffFailed  = "failed"
+)
+
+type wsDiffRepo struct {
+	Alias  string
+	Branch string
+	// From is the ref compared against (base_ref or the upstream); MergeBase is the actual diff origin.
+	From       string
+	MergeBase  string
+	Files      int
+	Insertions int
+	Deletions  int
+	// Untracked counts untracked (not ignored) files; they are not part of the diff.
+	Untracked int
+	// Text is the patch (or --stat output) with paths prefixed by the repo alias.
+	Text   string
+	Result string
+	Reason string
+}
+
+func (c *CLI) runWSDiff(args []string) int {
+	outputFormat := "human"
+	workspaceID := ""
+	useCurrent := false
+	against := "base"
+	statOnly := false
+	output := ""
+	save := false
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			c.printWSDiffUsage(c.Out)
+			return exitOK
+		case arg == "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				c.printWSDiffUsage(c.Err)
+				return exitUsage
+			}
+			outputFormat = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case arg == "--id":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--id requires a value")
+				c.printWSDiffUsage(c.Err)
+				return exitUsage
+			}
+			workspaceID = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--id="):
+			workspaceID = strings.TrimSpace(strings.TrimPrefix(arg, "--id="))
+		case arg == "--current":
+			useCurrent = true
+		case arg == "--against":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--against requires a value")
+				c.printWSDiffUsage(c.Err)
+				return exitUsage
+			}
+			against = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--against="):
+			against = strings.TrimSpace(strings.TrimPrefix(arg, "--against="))
+		case arg == "--stat":
+			statOnly = true
+		case arg == "-o" || arg == "--output":
+			if i+1 >= len(args) {
+				fmt.Fprintf(c.Err, "%s requires a value\n", arg)
+				c.printWSDiffUsage(c.Err)
+				return exitUsage
+			}
+			output = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimSpace(strings.TrimPrefix(arg, "--output="))
+		case arg == "--save":
+			save = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(c.Err, "unknown flag for ws diff: %q\n", arg)
+			c.printWSDiffUsage(c.Err)
+			return exitUsage
+		default:
+			if workspaceID != "" {
+				fmt.Fprintf(c.Err, "unexpected args for ws diff: %q\n", arg)
+				c.printWSDiffUsage(c.Err)
+				return exitUsage
+			}
+			workspaceID = arg
+		}
+	}
+	switch outputFormat {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", outputFormat)
+		c.printWSDiffUsage(c.Err)
+		return exitUsage
+	}
+	switch against {
+	case "base", "upstream":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --against: %q (supported: base, upstream)\n", against)
+		c.printWSDiffUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" && useCurrent {
+		fmt.Fprintln(c.Err, "--id and --current cannot be used together")
+		c.printWSDiffUsage(c.Err)
+		return exitUsage
+	}
+	if output != "" && save {
+		fmt.Fprintln(c.Err, "--output and --save cannot be used together")
+		c.printWSDiffUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" {
+		if err := validateWorkspaceID(workspaceID); err != nil {
+			fmt.Fprintf(c.Err, "invalid workspace id: %v\n", err)
+			return exitUsage
+		}
+	}
+	jsonMode := outputFormat == "json"
+	fail := func(code int, errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:          false,
+				Action:      "ws.diff",
+				WorkspaceID: workspaceID,
+				Error:       &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("get working dir: %v", err))
+	}
+	root, err := paths.ResolveExistingRoot(wd)
+	if err != nil {
+		return fail(exitError, "not_found", fmt.Sprintf("resolve KRA_ROOT: %v", err))
+	}
+	if err := c.ensureDebugLog(root, "ws-diff"); err != nil {
+		fmt.Fprintf(c.Err, "enable debug logging: %v\n", err)
+	}
+	workspaceID, errCode, err := resolveActiveWorkspaceTarget(root, wd, workspaceID, "ws diff")
+	if err != nil {
+		return fail(exitError, errCode, err.Error())
+	}
+
+	ctx := context.Background()
+	wsPath := filepath.Join(root, "workspaces", workspaceID)
+	meta, _ := loadWorkspaceMetaFile(wsPath)
+	bound, err := listWorkspaceReposFromFilesystem(ctx, root, "active", workspaceID, meta)
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("list workspace repos: %v", err))
+	}
+
+	// Files get --binary so the saved patch applies; stdout keeps git's short "Binary files differ".
+	binary := save || (output != "" && output != "-")
+	repos := make([]wsDiffRepo, 0, len(bound))
+	for _, r := range bound {
+		repo := wsDiffRepo{Alias: r.Alias, Branch: r.Branch}
+		if r.MissingAt.Valid {
+			repo.Result, repo.Reason = wsDiffSkipped, "worktree missing"
+		} else {
+			repo = collectWSDiffRepo(ctx, filepath.Join(wsPath, "repos", r.Alias), r.BaseRef, against, statOnly, binary, repo)
+		}
+		c.debugf("ws diff id=%s alias=%s from=%s result=%s reason=%s", workspaceID, repo.Alias, repo.From, repo.Result, repo.Reason)
+		repos = append(repos, repo)
+	}
+	combined := renderWSDiffCombined(repos)
+
+	target := ""
+	switch {
+	case save:
+		ext := ".patch"
+		if statOnly {
+			ext = ".stat"
+		}
+		target = filepath.Join(wsPath, "artifacts", "diffs", time.Now().UTC().Format("20060102-150405")+ext)
+	case output != "" && output != "-":
+		target = output
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(wd, target)
+		}
+	}
+	if target != "" {
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("create diff dir: %v", err))
+		}
+		if err := os.WriteFile(target, []byte(combined), 0o644); err != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("write diff: %v", err))
+		}
+	}
+
+	ok := !slices.ContainsFunc(repos, func(r wsDiffRepo) bool { return r.Result == wsDiffFailed })
+	switch {
+	case jsonMode:
+		resp := cliJSONResponse{
+			OK:          ok,
+			Action:      "ws.diff",
+			WorkspaceID: workspaceID,
+			Result:      wsDiffJSONResult(repos, against, statOnly, target),
+		}
+		if !ok {
+			resp.Error = &cliJSONError{Code: "internal_error", Message: "some repos could not be diffed"}
+		}
+		_ = writeCLIJSON(c.Out, resp)
+	case target != "":
+		printWSDiffWritten(c.Out, repos, target, writerSupportsColor(c.Out))
+	default:
+		_, _ = io.WriteString(c.Out, combined)
+	}
+	if !ok {
+		if !jsonMode {
+			fmt.Fprintln(c.Err, "some repos could not be diffed")
+		}
+		return exitError
+	}
+	return exitOK
+}
+
+// collectWSDiffRepo diffs the worktree (committed and uncommitted tracked changes) against the merge-base
+// of HEAD and base_ref / the upstream branch. Untracked files are not included, only counted.
+func collectWSDiffRepo(ctx context.Context, worktreePath string, baseRef string, against string, statOnly bool, binary bool, repo wsDiffRepo) wsDiffRepo {
+	switch against {
+	case "upstream":
+		out, err := gitutil.Run(ctx, worktreePath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+		if err != nil || strings.TrimSpace(out) == "" {
+			repo.Result, repo.Reason = wsDiffSkipped, "no upstream"
+			return repo
+		}
+		repo.From = strings.TrimSpace(out)
+	default:
+		repo.From = strings.TrimSpace(baseRef)
+		if repo.From == "" {
+			repo.Result, repo.Reason = wsDiffSkipped, "base_ref unknown"
+			return repo
+		}
+	}
+	mergeBase, err := gitutil.Run(ctx, worktreePath, "merge-base", repo.From, "HEAD")
+	if err != nil {
+		repo.Result, repo.Reason = wsDiffFailed, fmt.Sprintf("merge-base with %s: %v", repo.From, err)
+		return repo
+	}
+	repo.MergeBase = strings.TrimSpace(mergeBase)
+
+	numstat, err := gitutil.Run(ctx, worktreePath, "diff", "--no-ext-diff", "--numstat", repo.MergeBase)
+	if err != nil {
+		repo.Result, repo.Reason = wsDiffFailed, err.Error()
+		return repo
+	}
+	repo.Files, repo.Insertions, repo.Deletions = parseDiffNumstat(numstat)
+	if untracked, err := gitutil.Run(ctx, worktreePath, "ls-files", "--others", "--exclude-standard"); err == nil && strings.TrimSpace(untracked) != "" {
+		repo.Untracked = len(strings.Split(strings.TrimSpace(untracked), "\n"))
+	}
+	if repo.Files == 0 {
+		repo.Result = wsDiffClean
+		return repo
+	}
+
+	prefix := repo.Alias + "/"
+	diffArgs := []string{"diff", "--no-color", "--no-ext-diff", "--src-prefix=a/" + prefix, "--dst-prefix=b/" + prefix}
+	if binary {
+		diffArgs = append(diffArgs, "--binary")
+	}
+	if statOnly {
+		diffArgs = []string{"diff", "--no-color", "--no-ext-diff", "--stat"}
+	}
+	text, err := gitutil.Output(ctx, worktreePath, append(diffArgs, repo.MergeBase)...)
+	if err != nil {
+		repo.Result, repo.Reason = wsDiffFailed, err.Error()
+		return repo
+	}
+	repo.Text = text
+	repo.Result = wsDiffChanged
+	return repo
+}
+
+// parseDiffNumstat sums `git diff --numstat` lines ("<added>\t<deleted>\t<path>"; binary files use "-").
+func parseDiffNumstat(out string) (files int, insertions int, deletions int) {
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		files++
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			insertions += n
+		}
+		if n, err := strconv.Atoi(fields[1]); err == nil {
+			deletions += n
+		}
+	}
+	return files, insertions, deletions
+}
+
+// renderWSDiffCombined joins the per-repo diffs under "# repo:" header lines.
+// Patch paths are prefixed with the alias, so the result applies from the workspace repos/ directory.
+func renderWSDiffCombined(repos []wsDiffRepo) string {
+	var b strings.Builder
+	for _, r := range repos {
+		switch r.Result {
+		case wsDiffSkipped, wsDiffFailed:
+			fmt.Fprintf(&b, "# repo: %s  %s: %s\n", r.Alias, r.Result, r.Reason)
+			continue
+		}
+		fmt.Fprintf(&b, "# repo: %s  branch: %s  against: %s (%s)\n", r.Alias, r.Branch, r.From, shortCommitSHA(r.MergeBase))
+		if r.Untracked > 0 {
+			fmt.Fprintf(&b, "# untracked files not included: %d\n", r.Untracked)
+		}
+		if r.Result == wsDiffClean {
+			b.WriteString("# (no changes)\n")
+			continue
+		}
+		b.WriteString(r.Text)
+		if !strings.HasSuffix(r.Text, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func wsDiffJSONResult(repos []wsDiffRepo, against string, statOnly bool, path string) map[string]any {
+	rows := make([]map[string]any, 0, len(repos))
+	files, insertions, deletions := 0, 0, 0
+	for _, r := range repos {
+		files += r.Files
+		insertions += r.Insertions
+		deletions += r.Deletions
+		rows = append(rows, map[string]any{
+			"alias":      r.Alias,
+			"branch":     r.Branch,
+			"from":       r.From,
+			"merge_base": r.MergeBase,
+			"files":      r.Files,
+			"insertions": r.Insertions,
+			"deletions":  r.Deletions,
+			"untracked":  r.Untracked,
+			"result":     r.Result,
+			"reason":     r.Reason,
+		})
+	}
+	result := map[string]any{
+		"against": against,
+		"stat":    statOnly,
+		"totals": map[string]any{
+			"repos":      len(repos),
+			"files":      files,
+			"insertions": insertions,
+			"deletions":  deletions,
+		},
+		"items": rows,
+	}
+	if path != "" {
+		result["path"] = path
+	}
+	return result
+}
+
+func printWSDiffWritten(out io.Writer, repos []wsDiffRepo, path string, useColor bool) {
+	files, insertions, deletions := 0, 0, 0
+	for _, r := range repos {
+		files += r.Files
+		insertions += r.Insertions
+		deletions += r.Deletions
+	}
+	lines := []string{
+		fmt.Sprintf("%s Wrote %s", styleSuccess("✔", useColor), path),
+		fmt.Sprintf("%d repos, %d files, +%d -%d", len(repos), files, insertions, deletions),
+	}
+	for _, r := range repos {
+		if r.Result == wsDiffFailed {
+			lines = append(lines, fmt.Sprintf("%s %s %s", styleError("!", useColor), r.Alias, styleError(r.Reason, useColor)))
+		}
+	}
+	printResultSection(out, useColor, lines...)
+}
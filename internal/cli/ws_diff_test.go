@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_Diff_CombinesReposAndSavesArtifact(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	webSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "web")
+	if code, _, stderr := run("repo", "add", apiSpec, webSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	for _, repo := range []string{"example-org/api", "example-org/web"} {
+		if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", repo, "--yes"); code != exitOK {
+			t.Fatalf("ws add-repo %s exit code = %d (stdout=%q stderr=%q)", repo, code, out, stderr)
+		}
+	}
+	wsPath := filepath.Join(env.Root, "workspaces", "WS1")
+	apiPath := filepath.Join(wsPath, "repos", "api")
+	if err := os.WriteFile(filepath.Join(apiPath, "one.txt"), []byte("one\n"), 0o644); err != nil {
+		t.Fatalf("write one.txt: %v", err)
+	}
+	runGit(apiPath, "add", "one.txt")
+	runGit(apiPath, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-m", "one")
+	// Uncommitted change to a tracked file is part of the diff.
+	if err := os.WriteFile(filepath.Join(apiPath, "README.md"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("write README.md: %v", err)
+	}
+
+	// Untracked files are not diffed, only reported.
+	if err := os.WriteFile(filepath.Join(wsPath, "repos", "web", "scratch.txt"), []byte("scratch\n"), 0o644); err != nil {
+		t.Fatalf("write scratch.txt: %v", err)
+	}
+
+	code, out, stderr := run("ws", "diff", "--id", "WS1")
+	if code != exitOK {
+		t.Fatalf("ws diff exit code = %d (stderr=%q)", code, stderr)
+	}
+	for _, want := range []string{
+		"# repo: api  branch: WS1  against: origin/main (",
+		"diff --git a/api/one.txt b/api/one.txt",
+		"diff --git a/api/README.md b/api/README.md",
+		"# repo: web  branch: WS1  against: origin/main (",
+		"# untracked files not included: 1",
+		"# (no changes)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("ws diff output missing %q:\n%s", want, out)
+		}
+	}
+	// The combined patch applies from the workspace repos/ directory.
+	patchPath := filepath.Join(t.TempDir(), "ws.patch")
+	if err := os.WriteFile(patchPath, []byte(out), 0o644); err != nil {
+		t.Fatalf("write patch: %v", err)
+	}
+	runGit(filepath.Join(wsPath, "repos"), "apply", "--check", "-R", patchPath)
+
+	// Saved patches carry binary changes so they still apply.
+	if err := os.WriteFile(filepath.Join(apiPath, "logo.bin"), []byte{0x00, 0x01, 0x02, 0xff}, 0o644); err != nil {
+		t.Fatalf("write logo.bin: %v", err)
+	}
+	runGit(apiPath, "add", "logo.bin")
+	runGit(apiPath, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-m", "logo")
+
+	code, out, stderr = run("ws", "diff", "--format", "json", "--id", "WS1", "--save")
+	if code != exitOK {
+		t.Fatalf("ws diff --save exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	var resp struct {
+		OK     bool `json:"ok"`
+		Result struct {
+			Path   string `json:"path"`
+			Totals struct {
+				Files      int `json:"files"`
+				Insertions int `json:"insertions"`
+			} `json:"totals"`
+			Items []struct {
+				Alias  string `json:"alias"`
+				Files  int    `json:"files"`
+				Result string `json:"result"`
+			} `json:"items"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("decode ws diff: %v (out=%s)", err, out)
+	}
+	if !resp.OK || resp.Result.Totals.Files != 3 || resp.Result.Items[0].Result != wsDiffChanged || resp.Result.Items[1].Result != wsDiffClean {
+		t.Fatalf("ws diff json = %+v", resp)
+	}
+	if filepath.Dir(resp.Result.Path) != filepath.Join(wsPath, "artifacts", "diffs") || !strings.HasSuffix(resp.Result.Path, ".patch") {
+		t.Fatalf("saved path = %q", resp.Result.Path)
+	}
+	saved, err := os.ReadFile(resp.Result.Path)
+	if err != nil || !strings.Contains(string(saved), "diff --git a/api/one.txt b/api/one.txt") || !strings.Contains(string(saved), "GIT binary patch") {
+		t.Fatalf("saved patch = %q (err=%v)", saved, err)
+	}
+	runGit(filepath.Join(wsPath, "repos"), "apply", "--check", "-R", resp.Result.Path)
+
+	// After push, only the uncommitted change is ahead of the upstream.
+	if code, _, stderr := run("ws", "push", "--id", "WS1"); code != exitOK {
+		t.Fatalf("ws push exit code = %d (stderr=%q)", code, stderr)
+	}
+	code, out, _ = run("ws", "diff", "--format", "json", "--id", "WS1", "--against", "upstream")
+	if code != exitOK || !strings.Contains(out, `"files":1,"from":"origin/WS1"`) {
+		t.Fatalf("ws diff --against upstream: code=%d out=%s", code, out)
+	}
+	if code, _, stderr := run("ws", "diff", "--id", "WS1", "--against", "head"); code != exitUsage || !strings.Contains(stderr, `unsupported --against: "head"`) {
+		t.Fatalf("ws diff --against head: code=%d stderr=%q", code, stderr)
+	}
+}
@@ -33,6 +33,14 @@ type wsImportJiraOpts struct {
 	jql          string
 	board        string
 	spaceKey     string
+	epicKey      string
+	subtasksOf   string
+	noParent     bool
+	mine         bool
+	excludeDone  bool
+	offline      bool
+	noNotes      bool
+	template     string
 	limit        int
 	apply        bool
 	noPrompt     bool
@@ -51,6 +59,7 @@ type wsImportJiraSource struct {
 	Mode   string `json:"mode"`
 	Board  string `json:"board,omitempty"`
 	Sprint string `json:"sprint,omitempty"`
+	Parent string `json:"parent,omitempty"`
 	JQL    string `json:"jql,omitempty"`
 }
 
@@ -71,6 +80,10 @@ type wsImportJiraItem struct {
 	IssueKey    string `json:"issue_key,omitempty"`
 	Title       string `json:"title,omitempty"`
 	WorkspaceID string `json:"workspace_id,omitempty"`
+	Role        string `json:"role,omitempty"`
+	ParentID    string `json:"parent_id,omitempty"`
+	Template    string `json:"template,omitempty"`
+	NotesPath   string `json:"notes_path,omitempty"`
 	Action      string `json:"action"`
 	Reason      string `json:"reason,omitempty"`
 	Message     string `json:"message,omitempty"`
@@ -111,6 +124,12 @@ func (c *CLI) runWSImportJira(args []string) int {
 		return exitUsage
 	}
 	outputJSON := opts.outputFormat == "json"
+	jiraWarnings := make([]string, 0)
+	printedJiraWarnings := 0
+	flushJiraWarnings := func() {
+		c.printJiraWarnings(jiraWarnings[printedJiraWarnings:])
+		printedJiraWarnings = len(jiraWarnings)
+	}
 	writeUsageError := func(message string) int {
 		if outputJSON {
 			_ = writeCLIJSON(c.Out, cliJSONResponse{
@@ -129,16 +148,21 @@ func (c *CLI) runWSImportJira(args []string) int {
 	}
 	writeRuntimeError := func(code string, message string) int {
 		if outputJSON {
-			_ = writeCLIJSON(c.Out, cliJSONResponse{
+			resp := cliJSONResponse{
 				OK:     false,
 				Action: "ws.import.jira",
 				Error: &cliJSONError{
 					Code:    code,
 					Message: message,
 				},
-			})
+			}
+			if len(jiraWarnings) > 0 {
+				resp.Warnings = jiraWarnings
+			}
+			_ = writeCLIJSON(c.Out, resp)
 			return exitError
 		}
+		flushJiraWarnings()
 		fmt.Fprintln(c.Err, message)
 		return exitError
 	}
@@ -155,6 +179,9 @@ func (c *CLI) runWSImportJira(args []string) int {
 			Action: "ws.import.jira",
 			Result: result,
 		}
+		if len(jiraWarnings) > 0 {
+			resp.Warnings = jiraWarnings
+		}
 		if !ok {
 			resp.Error = &cliJSONError{
 				Code:    code,
@@ -189,12 +216,25 @@ func (c *CLI) runWSImportJira(args []string) int {
 	if err != nil {
 		return writeUsageError(err.Error())
 	}
+	notesPath := ""
+	if !opts.noNotes {
+		notesPath, err = resolveTicketNotesPath(cfg)
+		if err != nil {
+			return writeRuntimeError("invalid_argument", fmt.Sprintf("resolve notes path: %v", err))
+		}
+	}
 
 	ctx := context.Background()
-	svc := wsimport.NewService(appports.NewWSImportJiraPortWithBaseURL(cfg.Integration.Jira.BaseURL))
+	svc := wsimport.NewService(appports.NewWSImportJiraPortWithClient(c.newJiraClient(cfg, opts.offline, &jiraWarnings)))
 	jql := ""
 	source := wsImportJiraSource{Type: "jira", Mode: "jql"}
-	if opts.sprintSet {
+	parentKey, parentMode := wsImportJiraParentSelection(opts)
+	if parentMode != "" {
+		jql = buildWSImportJiraChildrenJQL(parentMode, parentKey, opts.mine, opts.excludeDone)
+		source.Mode = parentMode
+		source.Parent = parentKey
+		source.JQL = jql
+	} else if opts.sprintSet {
 		sprintQueryValue := opts.sprintValue
 		sprintDisplayValue := opts.sprintValue
 		if strings.TrimSpace(sprintQueryValue) == "" {
@@ -224,12 +264,38 @@ func (c *CLI) runWSImportJira(args []string) int {
 	}
 	c.debugf("ws import jira: resolved mode=%s jql=%q", source.Mode, jql)
 
-	inputs, err := svc.ResolveWorkspaceInputsByJQL(ctx, jql, opts.limit)
-	if err != nil {
-		return writeRuntimeError("internal_error", fmt.Sprintf("resolve jira issues: %v", err))
+	var inputs []wsimport.WorkspaceInput
+	if parentMode != "" {
+		parent, children, err := svc.ResolveParentAndChildrenInputs(ctx, parentKey, jql, opts.limit)
+		if err != nil {
+			return writeRuntimeError("internal_error", fmt.Sprintf("resolve jira issues: %v", err))
+		}
+		source.Parent = parent.ID
+		if !opts.noParent {
+			inputs = append(inputs, parent)
+		}
+		inputs = append(inputs, children...)
+	} else {
+		inputs, err = svc.ResolveWorkspaceInputsByJQL(ctx, jql, opts.limit)
+		if err != nil {
+			return writeRuntimeError("internal_error", fmt.Sprintf("resolve jira issues: %v", err))
+		}
 	}
 
-	plan, createInputs := buildWSImportJiraPlan(source, opts.limit, root, inputs)
+	resolveTemplate := func(in wsimport.WorkspaceInput) string {
+		return c.resolveJiraWorkspaceTemplateName(cfg, opts.template, config.TemplateRuleSubject{
+			IssueType: in.IssueType,
+			Labels:    in.Labels,
+			Project:   in.ProjectKey,
+		})
+	}
+	plan, createInputs := buildWSImportJiraPlan(source, opts.limit, root, inputs, resolveTemplate)
+	if parentMode != "" {
+		plan.Filters = wsImportJiraChildrenFilters(opts)
+	}
+	if !outputJSON {
+		flushJiraWarnings()
+	}
 
 	shouldApply := false
 	interactivePromptFlow := !opts.noPrompt && !opts.apply
@@ -270,14 +336,39 @@ func (c *CLI) runWSImportJira(args []string) int {
 	if shouldApply {
 		createdCount := 0
 		for _, in := range createInputs {
-			if _, err := c.createWorkspaceAtRoot(root, in.ID, in.Title, in.SourceURL, defaultWorkspaceTemplateName); err != nil {
+			wsPath, err := c.createWorkspaceAtRoot(root, in.ID, in.Title, in.SourceURL, resolveTemplate(in))
+			if err != nil {
 				markWSImportJiraCreateItemAsFailed(&plan, in, classifyWSImportJiraCreateFailureReason(err), err.Error())
 				plan.Summary.Failed++
 				continue
 			}
+			if strings.TrimSpace(in.ParentID) != "" {
+				if err := setWorkspaceMetaParentID(wsPath, in.ParentID, 0); err != nil {
+					// Roll back so a failed item never leaves a child workspace without its parent link.
+					_ = os.RemoveAll(wsPath)
+					markWSImportJiraCreateItemAsFailed(&plan, in, "create_failed", fmt.Sprintf("record parent: %v", err))
+					plan.Summary.Failed++
+					continue
+				}
+			}
+			if notesPath != "" {
+				// Notes are best-effort: the workspace is kept even when the detail fetch fails.
+				notes, err := svc.ResolveTicketNotes(ctx, in.ID)
+				if err == nil {
+					err = writeWorkspaceTicketNotes(wsPath, notesPath, notes)
+				}
+				if err != nil {
+					jiraWarnings = append(jiraWarnings, fmt.Sprintf("%s: ticket notes skipped: %v", in.ID, err))
+				} else {
+					setWSImportJiraItemNotesPath(&plan, in.ID, notesPath)
+				}
+			}
 			createdCount++
 		}
 		plan.Summary.ToCreate = createdCount
+		if !outputJSON {
+			flushJiraWarnings()
+		}
 	}
 
 	if outputJSON {
@@ -351,6 +442,39 @@ func (c *CLI) parseWSImportJiraOpts(args []string) (wsImportJiraOpts, error) {
 			}
 			opts.spaceKey = strings.ToUpper(strings.TrimSpace(rest[1]))
 			rest = rest[2:]
+		case "--epic":
+			if len(rest) < 2 {
+				return wsImportJiraOpts{}, fmt.Errorf("--epic requires a value")
+			}
+			opts.epicKey = strings.ToUpper(strings.TrimSpace(rest[1]))
+			rest = rest[2:]
+		case "--subtasks-of":
+			if len(rest) < 2 {
+				return wsImportJiraOpts{}, fmt.Errorf("--subtasks-of requires a value")
+			}
+			opts.subtasksOf = strings.ToUpper(strings.TrimSpace(rest[1]))
+			rest = rest[2:]
+		case "--no-parent":
+			opts.noParent = true
+			rest = rest[1:]
+		case "--mine":
+			opts.mine = true
+			rest = rest[1:]
+		case "--exclude-done":
+			opts.excludeDone = true
+			rest = rest[1:]
+		case "--no-notes":
+			opts.noNotes = true
+			rest = rest[1:]
+		case "--template":
+			if len(rest) < 2 {
+				return wsImportJiraOpts{}, fmt.Errorf("--template requires a value")
+			}
+			opts.template = strings.TrimSpace(rest[1])
+			if err := validateWorkspaceTemplateName(opts.template); err != nil {
+				return wsImportJiraOpts{}, err
+			}
+			rest = rest[2:]
 		case "--limit":
 			if len(rest) < 2 {
 				return wsImportJiraOpts{}, fmt.Errorf("--limit requires a value")
@@ -367,6 +491,9 @@ func (c *CLI) parseWSImportJiraOpts(args []string) (wsImportJiraOpts, error) {
 		case "--no-prompt":
 			opts.noPrompt = true
 			rest = rest[1:]
+		case "--offline":
+			opts.offline = true
+			rest = rest[1:]
 		case "--json":
 			opts.outputFormat = "json"
 			rest = rest[1:]
@@ -392,6 +519,20 @@ func (c *CLI) parseWSImportJiraOpts(args []string) (wsImportJiraOpts, error) {
 	if opts.sprintSet && opts.jqlSet {
 		return wsImportJiraOpts{}, fmt.Errorf("--sprint and --jql cannot be combined")
 	}
+	if opts.epicKey != "" && opts.subtasksOf != "" {
+		return wsImportJiraOpts{}, fmt.Errorf("--epic and --subtasks-of cannot be combined")
+	}
+	if opts.epicKey != "" || opts.subtasksOf != "" {
+		if opts.sprintSet || opts.jqlSet || opts.spaceKey != "" {
+			return wsImportJiraOpts{}, fmt.Errorf("--epic/--subtasks-of cannot be combined with --sprint, --jql, or --space/--project")
+		}
+		parentKey := firstNonEmpty(opts.epicKey, opts.subtasksOf)
+		if err := validateWSImportJiraIssueKey(parentKey); err != nil {
+			return wsImportJiraOpts{}, err
+		}
+	} else if opts.noParent || opts.mine || opts.excludeDone {
+		return wsImportJiraOpts{}, fmt.Errorf("--no-parent, --mine and --exclude-done are only valid with --epic or --subtasks-of")
+	}
 	if opts.board != "" {
 		return wsImportJiraOpts{}, fmt.Errorf("--board is not supported; use --space/--project with --sprint")
 	}
@@ -427,6 +568,9 @@ func wsImportJiraWantsJSON(args []string) bool {
 
 func applyWSImportJiraConfigDefaults(opts wsImportJiraOpts, cfg config.Config) (wsImportJiraOpts, error) {
 	resolved := opts
+	if _, mode := wsImportJiraParentSelection(resolved); mode != "" {
+		return resolved, nil
+	}
 	if !resolved.sprintSet && !resolved.jqlSet {
 		if strings.TrimSpace(resolved.spaceKey) != "" {
 			resolved.sprintSet = true
@@ -472,7 +616,32 @@ func applyWSImportJiraConfigDefaults(opts wsImportJiraOpts, cfg config.Config) (
 	return resolved, nil
 }
 
-func buildWSImportJiraPlan(source wsImportJiraSource, limit int, root string, inputs []wsimport.WorkspaceInput) (wsImportJiraPlan, []wsimport.WorkspaceInput) {
+func wsImportJiraParentSelection(opts wsImportJiraOpts) (string, string) {
+	switch {
+	case strings.TrimSpace(opts.epicKey) != "":
+		return strings.TrimSpace(opts.epicKey), "epic"
+	case strings.TrimSpace(opts.subtasksOf) != "":
+		return strings.TrimSpace(opts.subtasksOf), "subtasks"
+	default:
+		return "", ""
+	}
+}
+
+func validateWSImportJiraIssueKey(key string) error {
+	key = strings.TrimSpace(key)
+	dash := strings.LastIndex(key, "-")
+	if dash <= 0 || !isDigitsOnly(key[dash+1:]) {
+		return fmt.Errorf("invalid jira issue key: %q", key)
+	}
+	for _, ch := range key[:dash] {
+		if (ch < 'A' || ch > 'Z') && (ch < '0' || ch > '9') && ch != '_' {
+			return fmt.Errorf("invalid jira issue key: %q", key)
+		}
+	}
+	return nil
+}
+
+func buildWSImportJiraPlan(source wsImportJiraSource, limit int, root string, inputs []wsimport.WorkspaceInput, resolveTemplate func(wsimport.WorkspaceInput) string) (wsImportJiraPlan, []wsimport.WorkspaceInput) {
 	plan := wsImportJiraPlan{
 		Source: source,
 		Filters: wsImportJiraFilters{
@@ -496,6 +665,8 @@ func buildWSImportJiraPlan(source wsImportJiraSource, limit int, root string, in
 				IssueKey:    in.ID,
 				Title:       in.Title,
 				WorkspaceID: in.ID,
+				Role:        wsImportJiraItemRole(source, in),
+				ParentID:    in.ParentID,
 				Action:      "fail",
 				Reason:      "invalid_workspace_id",
 				Message:     err.Error(),
@@ -509,6 +680,8 @@ func buildWSImportJiraPlan(source wsImportJiraSource, limit int, root string, in
 				IssueKey:    in.ID,
 				Title:       in.Title,
 				WorkspaceID: in.ID,
+				Role:        wsImportJiraItemRole(source, in),
+				ParentID:    in.ParentID,
 				Action:      "skip",
 				Reason:      "already_active",
 			})
@@ -521,6 +694,8 @@ func buildWSImportJiraPlan(source wsImportJiraSource, limit int, root string, in
 				IssueKey:    in.ID,
 				Title:       in.Title,
 				WorkspaceID: in.ID,
+				Role:        wsImportJiraItemRole(source, in),
+				ParentID:    in.ParentID,
 				Action:      "skip",
 				Reason:      "archived_exists",
 			})
@@ -531,6 +706,9 @@ func buildWSImportJiraPlan(source wsImportJiraSource, limit int, root string, in
 			IssueKey:    in.ID,
 			Title:       in.Title,
 			WorkspaceID: in.ID,
+			Role:        wsImportJiraItemRole(source, in),
+			ParentID:    in.ParentID,
+			Template:    resolveTemplate(in),
 			Action:      "create",
 		})
 		createInputs = append(createInputs, in)
@@ -539,6 +717,46 @@ func buildWSImportJiraPlan(source wsImportJiraSource, limit int, root string, in
 	return plan, createInputs
 }
 
+func wsImportJiraItemRole(source wsImportJiraSource, in wsimport.WorkspaceInput) string {
+	switch {
+	case strings.TrimSpace(source.Parent) == "":
+		return ""
+	case strings.EqualFold(strings.TrimSpace(in.ID), strings.TrimSpace(source.Parent)):
+		return "parent"
+	default:
+		return "child"
+	}
+}
+
+// buildWSImportJiraChildrenJQL resolves children of an epic (issues whose parent is the epic, plus
+// issues linked to it) or sub-tasks of a regular issue. Children of any assignee and status are
+// included unless mine / excludeDone narrow them down.
+func buildWSImportJiraChildrenJQL(mode string, parentKey string, mine bool, excludeDone bool) string {
+	key := strings.ToUpper(strings.TrimSpace(parentKey))
+	clauses := []string{fmt.Sprintf("(parent = %s OR issue in linkedIssues(%s))", key, key)}
+	if mode == "subtasks" {
+		clauses = []string{fmt.Sprintf("parent = %s", key), "issuetype in subTaskIssueTypes()"}
+	}
+	if mine {
+		clauses = append(clauses, "assignee = currentUser()")
+	}
+	if excludeDone {
+		clauses = append(clauses, "statusCategory != Done")
+	}
+	return strings.Join(clauses, " AND ") + " ORDER BY Rank ASC"
+}
+
+func wsImportJiraChildrenFilters(opts wsImportJiraOpts) wsImportJiraFilters {
+	filters := wsImportJiraFilters{Assignee: "any", StatusCategory: "any", Limit: opts.limit}
+	if opts.mine {
+		filters.Assignee = "currentUser()"
+	}
+	if opts.excludeDone {
+		filters.StatusCategory = "not_done"
+	}
+	return filters
+}
+
 func buildWSImportJiraSprintJQL(spaceKey string, sprintValue string) string {
 	key := strings.ToUpper(strings.TrimSpace(spaceKey))
 	if isDigitsOnly(strings.TrimSpace(sprintValue)) {
@@ -703,6 +921,13 @@ func (c *CLI) promptWSImportJiraChoice(title string, options []string) (int, err
 	}
 }
 
+func renderWSImportJiraStatusFilter(statusCategory string) string {
+	if statusCategory == "not_done" {
+		return "statusCategory!=Done"
+	}
+	return "statusCategory=" + statusCategory
+}
+
 func (c *CLI) printWSImportJiraPlanHuman(plan wsImportJiraPlan) {
 	useColor := writerSupportsColor(c.Out)
 	bullet := styleMuted("•", useColor)
@@ -720,6 +945,8 @@ func (c *CLI) printWSImportJiraPlanHuman(plan wsImportJiraPlan) {
 		} else {
 			sourceLine = fmt.Sprintf("%s jira mode=sprint sprint=%s", styleLabel("source:"), plan.Source.Sprint)
 		}
+	} else if strings.TrimSpace(plan.Source.Parent) != "" {
+		sourceLine = fmt.Sprintf("%s jira mode=%s parent=%s", styleLabel("source:"), plan.Source.Mode, plan.Source.Parent)
 	} else {
 		sourceLine = fmt.Sprintf("%s jira mode=jql jql=%s", styleLabel("source:"), plan.Source.JQL)
 	}
@@ -739,7 +966,7 @@ func (c *CLI) printWSImportJiraPlanHuman(plan wsImportJiraPlan) {
 
 	body := []string{
 		fmt.Sprintf("%s%s %s", uiIndent, bullet, sourceLine),
-		fmt.Sprintf("%s%s %s assignee=%s statusCategory!=Done limit=%d", uiIndent, bullet, filtersLabel, plan.Filters.Assignee, plan.Filters.Limit),
+		fmt.Sprintf("%s%s %s assignee=%s %s limit=%d", uiIndent, bullet, filtersLabel, plan.Filters.Assignee, renderWSImportJiraStatusFilter(plan.Filters.StatusCategory), plan.Filters.Limit),
 	}
 	body = append(body, fmt.Sprintf("%s%s %s (%d)", uiIndent, bullet, toCreateLabel, plan.Summary.ToCreate))
 	body = append(body, renderWSImportJiraPlanItems(plan.Items, "create", connectorMuted)...)
@@ -831,6 +1058,9 @@ func renderWSImportJiraPlanItems(items []wsImportJiraItem, action string, connec
 
 func renderWSImportJiraPlanItemLabel(it wsImportJiraItem) string {
 	base := fmt.Sprintf("%s: %s", strings.TrimSpace(it.IssueKey), formatWorkspaceTitle(it.Title))
+	if it.Role == "parent" {
+		base += " [parent]"
+	}
 	switch it.Action {
 	case "skip":
 		reason := strings.TrimSpace(it.Reason)
@@ -845,6 +1075,9 @@ func renderWSImportJiraPlanItemLabel(it wsImportJiraItem) string {
 		}
 		return fmt.Sprintf("%s (%s: %s)", base, strings.TrimSpace(it.Reason), msg)
 	default:
+		if template := strings.TrimSpace(it.Template); template != "" {
+			return fmt.Sprintf("%s (template: %s)", base, template)
+		}
 		return base
 	}
 }
@@ -878,6 +1111,15 @@ func (c *CLI) promptWSImportJiraApplyOnOut() (string, error) {
 	return strings.TrimSpace(line), nil
 }
 
+func setWSImportJiraItemNotesPath(plan *wsImportJiraPlan, issueKey string, notesPath string) {
+	for i := range plan.Items {
+		if plan.Items[i].IssueKey == issueKey && plan.Items[i].Action == "create" {
+			plan.Items[i].NotesPath = notesPath
+			return
+		}
+	}
+}
+
 func markWSImportJiraCreateItemAsFailed(plan *wsImportJiraPlan, in wsimport.WorkspaceInput, reason string, message string) {
 	for i := range plan.Items {
 		if plan.Items[i].IssueKey != in.ID {
@@ -895,6 +1137,8 @@ func markWSImportJiraCreateItemAsFailed(plan *wsImportJiraPlan, in wsimport.Work
 		IssueKey:    in.ID,
 		Title:       in.Title,
 		WorkspaceID: in.ID,
+		Role:        wsImportJiraItemRole(plan.Source, in),
+		ParentID:    in.ParentID,
 		Action:      "fail",
 		Reason:      reason,
 		Message:     message,
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/tasuku43/kra/internal/app/wsimport"
 	"github.com/tasuku43/kra/internal/testutil"
 )
 
@@ -263,6 +264,15 @@ func TestClassifyWSImportJiraCreateFailureReason(t *testing.T) {
 	}
 }
 
+func TestMarkWSImportJiraCreateItemAsFailed_KeepsRole(t *testing.T) {
+	plan := wsImportJiraPlan{Source: wsImportJiraSource{Type: "jira", Mode: "epic", Parent: "PROJ-1"}}
+	markWSImportJiraCreateItemAsFailed(&plan, wsimport.WorkspaceInput{ID: "PROJ-2", ParentID: "PROJ-1"}, "create_failed", "record parent: boom")
+	markWSImportJiraCreateItemAsFailed(&plan, wsimport.WorkspaceInput{ID: "PROJ-1"}, "create_failed", "boom")
+	if len(plan.Items) != 2 || plan.Items[0].Role != "child" || plan.Items[1].Role != "parent" {
+		t.Fatalf("failed items should keep their role: %+v", plan.Items)
+	}
+}
+
 func TestRenderWSImportJiraApplyPrompt_UsesBulletedPlanAlignment(t *testing.T) {
 	got := renderWSImportJiraApplyPrompt(false)
 	want := "  • apply this plan? [Enter=yes / n=no]: "
@@ -572,7 +582,7 @@ func TestCLI_WS_Import_Jira_PromptAccept_PrintsResultSummary(t *testing.T) {
 	c := New(&out, &err)
 	c.In = &in
 
-	code := c.Run([]string{"ws", "import", "jira", "--jql", "assignee=currentUser()"})
+	code := c.Run([]string{"ws", "import", "jira", "--jql", "assignee=currentUser()", "--no-notes"})
 	if code != exitOK {
 		t.Fatalf("exit code = %d, want %d (stdout=%q, stderr=%q)", code, exitOK, out.String(), err.String())
 	}
@@ -957,3 +967,284 @@ func TestCLI_WS_Import_Jira_ProjectAlias_WorksLikeSpace(t *testing.T) {
 		t.Fatalf("stdout missing resolved sprint: %q", out.String())
 	}
 }
+
+func TestCLI_WS_Import_Jira_Epic_NoPromptApply_RecordsParent(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		got := r.URL.Query().Get("jql")
+		switch {
+		case got == "key = PROJ-100":
+			_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-100","fields":{"summary":"Import epic"}}]}`))
+		case got == "(parent = PROJ-100 OR issue in linkedIssues(PROJ-100)) ORDER BY Rank ASC":
+			_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-101","fields":{"summary":"Child one"}},{"key":"PROJ-102","fields":{"summary":"Child two"}}]}`))
+		default:
+			t.Fatalf("unexpected jql: %q", got)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	c := New(&out, &err)
+	code := c.Run([]string{"ws", "import", "jira", "--epic", "proj-100", "--no-prompt", "--apply", "--no-notes"})
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stderr=%q)", code, exitOK, err.String())
+	}
+	if !strings.Contains(out.String(), "create=3 skipped=0 failed=0") {
+		t.Fatalf("stdout missing result summary: %q", out.String())
+	}
+
+	parentMeta, loadErr := loadWorkspaceMetaFile(filepath.Join(env.Root, "workspaces", "PROJ-100"))
+	if loadErr != nil {
+		t.Fatalf("load parent meta: %v", loadErr)
+	}
+	if parentMeta.Workspace.ParentID != "" {
+		t.Fatalf("parent workspace parent_id = %q, want empty", parentMeta.Workspace.ParentID)
+	}
+	for _, id := range []string{"PROJ-101", "PROJ-102"} {
+		meta, loadErr := loadWorkspaceMetaFile(filepath.Join(env.Root, "workspaces", id))
+		if loadErr != nil {
+			t.Fatalf("load child meta %s: %v", id, loadErr)
+		}
+		if meta.Workspace.ParentID != "PROJ-100" {
+			t.Fatalf("%s parent_id = %q, want %q", id, meta.Workspace.ParentID, "PROJ-100")
+		}
+	}
+}
+
+func TestCLI_WS_Import_Jira_SubtasksOf_NoParent_JSONPlan(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.URL.Query().Get("jql")
+		switch {
+		case got == "key = PROJ-7":
+			_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-7","fields":{"summary":"Story"}}]}`))
+		case strings.Contains(got, "issuetype in subTaskIssueTypes()"):
+			_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-8","fields":{"summary":"Sub-task"}}]}`))
+		default:
+			t.Fatalf("unexpected jql: %q", got)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	c := New(&out, &err)
+	code := c.Run([]string{"ws", "import", "jira", "--subtasks-of", "PROJ-7", "--no-parent", "--no-prompt", "--format", "json"})
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stderr=%q)", code, exitOK, err.String())
+	}
+	for _, want := range []string{`"mode":"subtasks"`, `"parent":"PROJ-7"`, `"issue_key":"PROJ-8"`, `"role":"child"`, `"parent_id":"PROJ-7"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("stdout missing %s: %q", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), `"role":"parent"`) {
+		t.Fatalf("--no-parent should not plan the parent workspace: %q", out.String())
+	}
+}
+
+func TestCLI_WS_Import_Jira_Epic_MineExcludeDone_OptInFilters(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.URL.Query().Get("jql")
+		switch got {
+		case "key = PROJ-100":
+			_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-100","fields":{"summary":"Import epic"}}]}`))
+		case "(parent = PROJ-100 OR issue in linkedIssues(PROJ-100)) AND assignee = currentUser() AND statusCategory != Done ORDER BY Rank ASC":
+			_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-101","fields":{"summary":"Child one"}}]}`))
+		default:
+			t.Fatalf("unexpected jql: %q", got)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	c := New(&out, &err)
+	code := c.Run([]string{"ws", "import", "jira", "--epic", "PROJ-100", "--mine", "--exclude-done", "--no-prompt", "--format", "json"})
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stderr=%q)", code, exitOK, err.String())
+	}
+	if !strings.Contains(out.String(), `"filters":{"assignee":"currentUser()","statusCategory":"not_done","limit":30}`) {
+		t.Fatalf("stdout missing opt-in filters: %q", out.String())
+	}
+}
+
+func TestCLI_WS_Import_Jira_EpicRejectsSprintCombination(t *testing.T) {
+	var out bytes.Buffer
+	var err bytes.Buffer
+	c := New(&out, &err)
+
+	code := c.Run([]string{"ws", "import", "jira", "--epic", "PROJ-1", "--jql", "project = PROJ"})
+	if code != exitUsage {
+		t.Fatalf("exit code = %d, want %d", code, exitUsage)
+	}
+	if !strings.Contains(err.String(), "--epic/--subtasks-of cannot be combined") {
+		t.Fatalf("stderr missing combination error: %q", err.String())
+	}
+}
+
+func TestCLI_WS_Import_Jira_JSON_Apply_WritesTicketNotes(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-501","fields":{"summary":"With notes"}},{"key":"PROJ-502","fields":{"summary":"Detail fails"}}]}`))
+		case "/rest/api/3/issue/PROJ-501":
+			_, _ = w.Write([]byte(`{"key":"PROJ-501","fields":{"summary":"With notes","description":{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Body"}]}]}}}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	code := New(&out, &err).Run([]string{"ws", "import", "jira", "--jql", "assignee=currentUser()", "--no-prompt", "--apply", "--format", "json"})
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stdout=%q)", code, exitOK, out.String())
+	}
+	if !strings.Contains(out.String(), `"issue_key":"PROJ-501","title":"With notes","workspace_id":"PROJ-501","template":"default","notes_path":"notes/ticket.md"`) {
+		t.Fatalf("stdout missing notes_path for PROJ-501: %q", out.String())
+	}
+	if !strings.Contains(out.String(), `PROJ-502: ticket notes skipped`) {
+		t.Fatalf("stdout missing notes warning for PROJ-502: %q", out.String())
+	}
+	notes, readErr := os.ReadFile(filepath.Join(env.Root, "workspaces", "PROJ-501", "notes", "ticket.md"))
+	if readErr != nil {
+		t.Fatalf("read notes: %v", readErr)
+	}
+	if !strings.Contains(string(notes), "## Description\n\nBody\n") {
+		t.Fatalf("notes missing description: %q", string(notes))
+	}
+	if _, statErr := os.Stat(filepath.Join(env.Root, "workspaces", "PROJ-502")); statErr != nil {
+		t.Fatalf("workspace should be created even when notes fail: %v", statErr)
+	}
+}
+
+func TestCLI_WS_Import_Jira_TemplateRules_ShownInPlanAndApplied(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+	for _, name := range []string{"bug", "spike"} {
+		dir := filepath.Join(env.Root, "templates", name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir template %s: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, strings.ToUpper(name)+".md"), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write template %s: %v", name, err)
+		}
+	}
+	rootConfigPath := filepath.Join(env.Root, ".kra", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(rootConfigPath), 0o755); err != nil {
+		t.Fatalf("mkdir root config dir: %v", err)
+	}
+	if err := os.WriteFile(rootConfigPath, []byte(`workspace:
+  defaults:
+    template_rules:
+      - template: bug
+        issue_type: Bug
+      - template: spike
+        label: spike
+`), 0o644); err != nil {
+		t.Fatalf("write root config: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"issues":[
+			{"key":"PROJ-601","fields":{"summary":"Crash","issuetype":{"name":"Bug"}}},
+			{"key":"PROJ-602","fields":{"summary":"Research","issuetype":{"name":"Story"},"labels":["Spike"]}},
+			{"key":"PROJ-603","fields":{"summary":"Feature","issuetype":{"name":"Story"}}}
+		]}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	code := New(&out, &err).Run([]string{"ws", "import", "jira", "--jql", "assignee=currentUser()", "--no-prompt"})
+	if code != exitOK {
+		t.Fatalf("plan exit code = %d (stderr=%q)", code, err.String())
+	}
+	for _, want := range []string{
+		"PROJ-601: Crash (template: bug)",
+		"PROJ-602: Research (template: spike)",
+		"PROJ-603: Feature (template: default)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("plan missing %q: %q", want, out.String())
+		}
+	}
+
+	out.Reset()
+	err.Reset()
+	code = New(&out, &err).Run([]string{"ws", "import", "jira", "--jql", "assignee=currentUser()", "--no-prompt", "--apply", "--no-notes"})
+	if code != exitOK {
+		t.Fatalf("apply exit code = %d (stderr=%q)", code, err.String())
+	}
+	for id, file := range map[string]string{"PROJ-601": "BUG.md", "PROJ-602": "SPIKE.md", "PROJ-603": "AGENTS.md"} {
+		if _, statErr := os.Stat(filepath.Join(env.Root, "workspaces", id, file)); statErr != nil {
+			t.Fatalf("%s missing template file %s: %v", id, file, statErr)
+		}
+	}
+}
+
+func TestCLI_WS_Import_Jira_TemplateFlagOverridesRules(t *testing.T) {
+	env := testutil.NewEnv(t)
+	env.EnsureRootLayout(t)
+	rootConfigPath := filepath.Join(env.Root, ".kra", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(rootConfigPath), 0o755); err != nil {
+		t.Fatalf("mkdir root config dir: %v", err)
+	}
+	if err := os.WriteFile(rootConfigPath, []byte("workspace:\n  defaults:\n    template_rules:\n      - template: bug\n        project: PROJ\n"), 0o644); err != nil {
+		t.Fatalf("write root config: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-611","fields":{"summary":"Any"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	var out bytes.Buffer
+	var err bytes.Buffer
+	code := New(&out, &err).Run([]string{"ws", "import", "jira", "--jql", "assignee=currentUser()", "--no-prompt", "--format", "json"})
+	if code != exitOK || !strings.Contains(out.String(), `"template":"bug"`) {
+		t.Fatalf("rule by project not applied: code=%d stdout=%q", code, out.String())
+	}
+	out.Reset()
+	code = New(&out, &err).Run([]string{"ws", "import", "jira", "--jql", "assignee=currentUser()", "--template", "custom", "--no-prompt", "--format", "json"})
+	if code != exitOK || !strings.Contains(out.String(), `"template":"custom"`) {
+		t.Fatalf("--template should override rules: code=%d stdout=%q", code, out.String())
+	}
+}
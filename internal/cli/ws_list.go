@@ -29,6 +29,7 @@ type wsListRow struct {
 	UpdatedAt int64
 	RepoCount int
 	Title     string
+	ParentID  string
 	WorkState workspaceWorkState
 	Repos     []statestore.WorkspaceRepo
 }
@@ -196,9 +197,11 @@ func listRowsFromFilesystem(ctx context.Context, root string, scope string, incl
 		wsPath := filepath.Join(baseDir, id)
 		meta, metaErr := loadWorkspaceMetaFile(wsPath)
 		title := ""
+		parentID := ""
 		updatedAt := int64(0)
 		if metaErr == nil {
 			title = strings.TrimSpace(meta.Workspace.Title)
+			parentID = strings.TrimSpace(meta.Workspace.ParentID)
 			updatedAt = meta.Workspace.UpdatedAt
 		}
 		if updatedAt <= 0 {
@@ -250,6 +253,7 @@ func listRowsFromFilesystem(ctx context.Context, root string, scope string, incl
 			UpdatedAt: updatedAt,
 			RepoCount: repoCount,
 			Title:     title,
+			ParentID:  parentID,
 			WorkState: workState,
 			Repos:     repos,
 		})
@@ -350,11 +354,19 @@ func listWorkspaceReposFromFilesystem(ctx context.Context, root string, scope st
 			branch = strings.TrimSpace(out)
 		}
 		restore := restoreByAlias[alias]
+		if restore.Reference {
+			// Reference bindings are detached; "HEAD" is not a branch.
+			branch = ""
+		}
 		repos = append(repos, statestore.WorkspaceRepo{
-			RepoUID: strings.TrimSpace(restore.RepoUID),
-			Alias:   alias,
-			Branch:  firstNonEmpty(branch, strings.TrimSpace(restore.Branch)),
-			BaseRef: strings.TrimSpace(restore.BaseRef),
+			RepoUID:        strings.TrimSpace(restore.RepoUID),
+			Alias:          alias,
+			Branch:         firstNonEmpty(branch, strings.TrimSpace(restore.Branch)),
+			BaseRef:        strings.TrimSpace(restore.BaseRef),
+			Sparse:         detectWorktreeSparse(ctx, repoPath),
+			PullRequestURL: strings.TrimSpace(restore.PullRequestURL),
+			Reference:      restore.Reference,
+			Stack:          restore.Stack,
 		})
 		seen[alias] = true
 	}
@@ -363,10 +375,14 @@ func listWorkspaceReposFromFilesystem(ctx context.Context, root string, scope st
 			continue
 		}
 		repos = append(repos, statestore.WorkspaceRepo{
-			RepoUID: strings.TrimSpace(restore.RepoUID),
-			Alias:   alias,
-			Branch:  strings.TrimSpace(restore.Branch),
-			BaseRef: strings.TrimSpace(restore.BaseRef),
+			RepoUID:        strings.TrimSpace(restore.RepoUID),
+			Alias:          alias,
+			Branch:         strings.TrimSpace(restore.Branch),
+			BaseRef:        strings.TrimSpace(restore.BaseRef),
+			Sparse:         restore.Sparse,
+			PullRequestURL: strings.TrimSpace(restore.PullRequestURL),
+			Reference:      restore.Reference,
+			Stack:          restore.Stack,
 			MissingAt: sql.NullInt64{
 				Int64: 1,
 				Valid: scope == "active",
@@ -451,16 +467,29 @@ func printWSListJSON(out io.Writer, rows []wsListRow, scope string, tree bool) {
 			"repo_count": row.RepoCount,
 			"title":      row.Title,
 		}
+		if row.ParentID != "" {
+			item["parent_id"] = row.ParentID
+		}
 		if tree {
 			repos := make([]map[string]any, 0, len(row.Repos))
 			for _, r := range row.Repos {
-				repos = append(repos, map[string]any{
+				repo := map[string]any{
 					"repo_uid": r.RepoUID,
 					"alias":    r.Alias,
 					"branch":   r.Branch,
 					"base_ref": r.BaseRef,
 					"missing":  r.MissingAt.Valid,
-				})
+				}
+				if len(r.Sparse) > 0 {
+					repo["sparse"] = r.Sparse
+				}
+				if r.PullRequestURL != "" {
+					repo["pull_request_url"] = r.PullRequestURL
+				}
+				if r.Reference {
+					repo["reference"] = true
+				}
+				repos = append(repos, repo)
 			}
 			item["repos"] = repos
 		}
@@ -489,13 +518,17 @@ func printWSListHuman(out io.Writer, rows []wsListRow, scope string, tree bool,
 	}
 
 	maxCols := listTerminalWidth()
-	for _, row := range rows {
-		body = append(body, renderWSListSummaryRow(row, maxCols, useColor))
-
-		if !tree {
-			continue
+	if !tree {
+		for _, row := range rows {
+			body = append(body, renderWSListSummaryRow(row, maxCols, useColor))
+		}
+	} else {
+		for _, entry := range groupWSListRowsByParent(rows) {
+			// Child workspaces are nested one indent level per depth under their parent.
+			indent := strings.Repeat(uiIndent, entry.depth)
+			body = append(body, indent+renderWSListSummaryRow(entry.row, maxCols-len(indent), useColor))
+			body = append(body, indentWSListLines(indent, renderWSListTreeLines(entry.row.Repos, maxCols-len(indent), useColor))...)
 		}
-		body = append(body, renderWSListTreeLines(row.Repos, maxCols, useColor)...)
 	}
 	printSection(out, renderWorkspacesTitle(scope, useColor), body, sectionRenderOptions{
 		blankAfterHeading: true,
@@ -503,6 +536,60 @@ func printWSListHuman(out io.Writer, rows []wsListRow, scope string, tree bool,
 	})
 }
 
+type wsListTreeEntry struct {
+	row   wsListRow
+	depth int
+}
+
+// groupWSListRowsByParent keeps the row order but moves each child workspace right after
+// its parent when the parent is listed in the same scope.
+func groupWSListRowsByParent(rows []wsListRow) []wsListTreeEntry {
+	present := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		present[row.ID] = true
+	}
+	isNested := func(row wsListRow) bool {
+		return row.ParentID != "" && row.ParentID != row.ID && present[row.ParentID]
+	}
+	children := map[string][]wsListRow{}
+	for _, row := range rows {
+		if isNested(row) {
+			children[row.ParentID] = append(children[row.ParentID], row)
+		}
+	}
+	out := make([]wsListTreeEntry, 0, len(rows))
+	visited := make(map[string]bool, len(rows))
+	var visit func(row wsListRow, depth int)
+	visit = func(row wsListRow, depth int) {
+		if visited[row.ID] {
+			return
+		}
+		visited[row.ID] = true
+		out = append(out, wsListTreeEntry{row: row, depth: depth})
+		for _, child := range children[row.ID] {
+			visit(child, depth+1)
+		}
+	}
+	for _, row := range rows {
+		if !isNested(row) {
+			visit(row, 0)
+		}
+	}
+	// Parent cycles have no root; keep them visible at top level.
+	for _, row := range rows {
+		visit(row, 0)
+	}
+	return out
+}
+
+func indentWSListLines(indent string, lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		out = append(out, indent+line)
+	}
+	return out
+}
+
 func renderWSListSummaryRow(row wsListRow, maxCols int, useColor bool) string {
 	idPlain := strings.TrimSpace(row.ID)
 	if idPlain == "" {
@@ -545,6 +632,15 @@ func renderWSListTreeLines(repos []statestore.WorkspaceRepo, maxCols int, useCol
 			state = "missing"
 		}
 		line := fmt.Sprintf("%s- %s  branch:%s  state:%s", repoIndent, repo.Alias, repo.Branch, state)
+		if repo.Reference {
+			line = fmt.Sprintf("%s- %s  reference:%s  state:%s", repoIndent, repo.Alias, repo.BaseRef, state)
+		}
+		if len(repo.Sparse) > 0 {
+			line += "  sparse:" + strings.Join(repo.Sparse, ",")
+		}
+		if repo.PullRequestURL != "" {
+			line += "  pr:" + repo.PullRequestURL
+		}
 		line = truncateDisplay(line, maxCols)
 		if useColor {
 			line = styleMuted(line, useColor)
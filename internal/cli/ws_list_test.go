@@ -516,3 +516,32 @@ func TestCountWorkspaceReposFromFilesystem_DeduplicatesMetaAndDirs(t *testing.T)
 		t.Fatalf("repo count = %d, want 3", got)
 	}
 }
+
+func TestGroupWSListRowsByParent_NestsChildrenUnderParent(t *testing.T) {
+	rows := []wsListRow{
+		{ID: "PROJ-101", ParentID: "PROJ-100"},
+		{ID: "OTHER-1"},
+		{ID: "PROJ-100"},
+		{ID: "PROJ-102", ParentID: "PROJ-100"},
+		{ID: "ORPHAN-1", ParentID: "MISSING-1"},
+	}
+	got := groupWSListRowsByParent(rows)
+	want := []struct {
+		id    string
+		depth int
+	}{
+		{"OTHER-1", 0},
+		{"PROJ-100", 0},
+		{"PROJ-101", 1},
+		{"PROJ-102", 1},
+		{"ORPHAN-1", 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %d, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].row.ID != w.id || got[i].depth != w.depth {
+			t.Fatalf("entry[%d] = %s/%d, want %s/%d", i, got[i].row.ID, got[i].depth, w.id, w.depth)
+		}
+	}
+}
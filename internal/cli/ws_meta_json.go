@@ -22,6 +22,7 @@ type workspaceMetaWorkspace struct {
 	ID        string `json:"id"`
 	Title     string `json:"title"`
 	SourceURL string `json:"source_url"`
+	ParentID  string `json:"parent_id,omitempty"`
 	Status    string `json:"status"`
 	CreatedAt int64  `json:"created_at"`
 	UpdatedAt int64  `json:"updated_at"`
@@ -34,6 +35,17 @@ type workspaceMetaRepoRestore struct {
 	Alias     string `json:"alias"`
 	Branch    string `json:"branch"`
 	BaseRef   string `json:"base_ref"`
+	// Sparse lists cone-mode sparse-checkout directories (empty = full checkout).
+	Sparse []string `json:"sparse,omitempty"`
+	// Submodules / LFS record that the worktree was populated with submodules / LFS objects.
+	Submodules bool `json:"submodules,omitempty"`
+	LFS        bool `json:"lfs,omitempty"`
+	// PullRequestURL is the PR/MR opened for the branch by ws pr create.
+	PullRequestURL string `json:"pull_request_url,omitempty"`
+	// Reference marks a read-only binding checked out detached at base_ref (branch is empty).
+	Reference bool `json:"reference,omitempty"`
+	// Stack lists stacked branches bottom-up: stack[0] is based on base_ref, stack[i] on stack[i-1].
+	Stack []string `json:"stack,omitempty"`
 }
 
 type workspaceMetaProtection struct {
@@ -160,6 +172,18 @@ func upsertWorkspaceMetaReposRestore(wsPath string, repos []workspaceMetaRepoRes
 	return writeWorkspaceMetaFile(wsPath, meta)
 }
 
+func setWorkspaceMetaParentID(wsPath string, parentID string, now int64) error {
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		return err
+	}
+	meta.Workspace.ParentID = strings.TrimSpace(parentID)
+	if now > 0 {
+		meta.Workspace.UpdatedAt = now
+	}
+	return writeWorkspaceMetaFile(wsPath, meta)
+}
+
 func workspaceMetaPurgeGuardEnabled(meta workspaceMetaFile) bool {
 	if meta.Protection.PurgeGuard.Enabled == nil {
 		return true
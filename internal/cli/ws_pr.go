@@ -0,0 +1,417 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/forge"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/infra/paths"
+)
+
+// Per-repo outcomes of ws pr create.
+const (
+	wsPRCreated  = "created"
+	wsPRExisting = "existing"
+	wsPRSkipped  = "skipped"
+	wsPRRefused  = "refused"
+	wsPRFailed   = "failed"
+)
+
+type wsPRItem struct {
+	Alias  string
+	Branch string
+	Base   string
+	// Repo is the forge repository path (<owner>/<repo>).
+	Repo   string
+	Forge  string
+	Number int
+	URL    string
+	Result string
+	Reason string
+	// LinkError is set when cross-linking sibling PRs into this PR's body failed.
+	LinkError string
+}
+
+func (c *CLI) runWSPR(args []string) int {
+	if len(args) == 0 {
+		c.printWSPRUsage(c.Err)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "-h", "--help", "help":
+		c.printWSPRUsage(c.Out)
+		return exitOK
+	case "create":
+		return c.runWSPRCreate(args[1:])
+	default:
+		fmt.Fprintf(c.Err, "unknown command: %q\n", strings.Join(append([]string{"ws", "pr"}, args[0]), " "))
+		c.printWSPRUsage(c.Err)
+		return exitUsage
+	}
+}
+
+func (c *CLI) runWSPRCreate(args []string) int {
+	outputFormat := "human"
+	workspaceID := ""
+	useCurrent := false
+	draft := false
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			c.printWSPRCreateUsage(c.Out)
+			return exitOK
+		case arg == "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				c.printWSPRCreateUsage(c.Err)
+				return exitUsage
+			}
+			outputFormat = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case arg == "--id":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--id requires a value")
+				c.printWSPRCreateUsage(c.Err)
+				return exitUsage
+			}
+			workspaceID = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--id="):
+			workspaceID = strings.TrimSpace(strings.TrimPrefix(arg, "--id="))
+		case arg == "--current":
+			useCurrent = true
+		case arg == "--draft":
+			draft = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(c.Err, "unknown flag for ws pr create: %q\n", arg)
+			c.printWSPRCreateUsage(c.Err)
+			return exitUsage
+		default:
+			if workspaceID != "" {
+				fmt.Fprintf(c.Err, "unexpected args for ws pr create: %q\n", arg)
+				c.printWSPRCreateUsage(c.Err)
+				return exitUsage
+			}
+			workspaceID = arg
+		}
+	}
+	switch outputFormat {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", outputFormat)
+		c.printWSPRCreateUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" && useCurrent {
+		fmt.Fprintln(c.Err, "--id and --current cannot be used together")
+		c.printWSPRCreateUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" {
+		if err := validateWorkspaceID(workspaceID); err != nil {
+			fmt.Fprintf(c.Err, "invalid workspace id: %v\n", err)
+			return exitUsage
+		}
+	}
+	jsonMode := outputFormat == "json"
+	fail := func(code int, errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:          false,
+				Action:      "ws.pr.create",
+				WorkspaceID: workspaceID,
+				Error:       &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("get working dir: %v", err))
+	}
+	root, err := paths.ResolveExistingRoot(wd)
+	if err != nil {
+		return fail(exitError, "not_found", fmt.Sprintf("resolve KRA_ROOT: %v", err))
+	}
+	if err := c.ensureDebugLog(root, "ws-pr-create"); err != nil {
+		fmt.Fprintf(c.Err, "enable debug logging: %v\n", err)
+	}
+	workspaceID, errCode, err := resolveActiveWorkspaceTarget(root, wd, workspaceID, "ws pr create")
+	if err != nil {
+		return fail(exitError, errCode, err.Error())
+	}
+
+	ctx := context.Background()
+	wsPath := filepath.Join(root, "workspaces", workspaceID)
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("load %s: %v", workspaceMetaFilename, err))
+	}
+	bound, err := listWorkspaceReposFromFilesystem(ctx, root, "active", workspaceID, meta)
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("list workspace repos: %v", err))
+	}
+
+	title := buildWSPRTitle(meta.Workspace)
+	body := buildWSPRBody(meta.Workspace)
+	items := make([]wsPRItem, 0, len(bound))
+	for _, r := range bound {
+		item := wsPRItem{Alias: r.Alias, Branch: r.Branch, Base: strings.TrimPrefix(r.BaseRef, "origin/"), URL: r.PullRequestURL}
+		switch {
+		case item.URL != "":
+			item.Result = wsPRExisting
+		case r.MissingAt.Valid:
+			item.Result, item.Reason = wsPRSkipped, "worktree missing"
+		case r.Reference:
+			item.Result, item.Reason = wsPRSkipped, "reference repo"
+		default:
+			item = planWSPRItem(ctx, filepath.Join(wsPath, "repos", r.Alias), r.RepoUID, r.BaseRef, item)
+		}
+		if item.Result == "" {
+			item = createWSPRItem(ctx, item, title, body, draft)
+		}
+		c.debugf("ws pr create id=%s alias=%s branch=%s result=%s reason=%s url=%s", workspaceID, item.Alias, item.Branch, item.Result, item.Reason, item.URL)
+		items = append(items, item)
+	}
+
+	if slices.ContainsFunc(items, func(it wsPRItem) bool { return it.Result == wsPRCreated }) {
+		for i := range meta.ReposRestore {
+			for _, it := range items {
+				if it.Result == wsPRCreated && it.Alias == meta.ReposRestore[i].Alias {
+					meta.ReposRestore[i].PullRequestURL = it.URL
+				}
+			}
+		}
+		if err := writeWorkspaceMetaFile(wsPath, meta); err != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("write %s: %v", workspaceMetaFilename, err))
+		}
+		linkWSPRSiblings(ctx, items, body)
+	}
+
+	ok := !slices.ContainsFunc(items, func(it wsPRItem) bool {
+		return it.Result == wsPRRefused || it.Result == wsPRFailed || it.LinkError != ""
+	})
+	if jsonMode {
+		resp := cliJSONResponse{
+			OK:          ok,
+			Action:      "ws.pr.create",
+			WorkspaceID: workspaceID,
+			Result:      wsPRJSONResult(items, draft),
+		}
+		if !ok {
+			resp.Error = &cliJSONError{Code: "conflict", Message: "some pull requests were not created"}
+		}
+		_ = writeCLIJSON(c.Out, resp)
+	} else {
+		printWSPRCreateResult(c.Out, items, writerSupportsColor(c.Out))
+	}
+	if !ok {
+		return exitError
+	}
+	return exitOK
+}
+
+// planWSPRItem checks that the worktree branch has commits beyond base_ref and is pushed to origin.
+// It leaves Result empty when a PR should be opened.
+func planWSPRItem(ctx context.Context, worktreePath string, repoUID string, baseRef string, item wsPRItem) wsPRItem {
+	branchOut, err := gitutil.Run(ctx, worktreePath, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		item.Result, item.Reason = wsPRSkipped, "detached HEAD"
+		return item
+	}
+	item.Branch = strings.TrimSpace(branchOut)
+	if item.Base == "" {
+		item.Result, item.Reason = wsPRSkipped, "base_ref unknown"
+		return item
+	}
+
+	out, err := gitutil.Run(ctx, worktreePath, "rev-list", "--count", baseRef+"..HEAD")
+	if err != nil {
+		item.Result, item.Reason = wsPRFailed, fmt.Sprintf("compare with %s: %v", baseRef, err)
+		return item
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(out)); err != nil || n == 0 {
+		item.Result, item.Reason = wsPRSkipped, fmt.Sprintf("no commits beyond %s", baseRef)
+		return item
+	}
+
+	remoteRef := "refs/remotes/origin/" + item.Branch
+	if _, err := gitutil.Run(ctx, worktreePath, "rev-parse", "--verify", "--quiet", remoteRef); err != nil {
+		item.Result, item.Reason = wsPRRefused, fmt.Sprintf("origin/%s does not exist (run kra ws push)", item.Branch)
+		return item
+	}
+	out, err = gitutil.Run(ctx, worktreePath, "rev-list", "--count", remoteRef+"..HEAD")
+	if err != nil {
+		item.Result, item.Reason = wsPRFailed, fmt.Sprintf("compare with origin/%s: %v", item.Branch, err)
+		return item
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(out)); err != nil || n > 0 {
+		item.Result, item.Reason = wsPRRefused, fmt.Sprintf("unpushed commits on %s (run kra ws push)", item.Branch)
+		return item
+	}
+
+	host, _, _ := strings.Cut(strings.TrimSpace(repoUID), "/")
+	item.Repo = workspaceBranchRepoKey(repoUID, "")
+	if host == "" || item.Repo == "" {
+		item.Result, item.Reason = wsPRFailed, "repo_uid unknown"
+		return item
+	}
+	if item.Forge, err = forge.NameForHost(host); err != nil {
+		item.Result, item.Reason = wsPRSkipped, err.Error()
+		return item
+	}
+	return item
+}
+
+func createWSPRItem(ctx context.Context, item wsPRItem, title string, body string, draft bool) wsPRItem {
+	f, err := forge.New(item.Forge)
+	if err != nil {
+		item.Result, item.Reason = wsPRFailed, err.Error()
+		return item
+	}
+	pr, err := f.CreatePullRequest(ctx, forge.PullRequestInput{
+		Repo:  item.Repo,
+		Head:  item.Branch,
+		Base:  item.Base,
+		Title: title,
+		Body:  body,
+		Draft: draft,
+	})
+	if err != nil {
+		item.Result, item.Reason = wsPRFailed, err.Error()
+		return item
+	}
+	item.Number, item.URL, item.Result = pr.Number, pr.URL, wsPRCreated
+	return item
+}
+
+// linkWSPRSiblings appends the other PRs of the workspace to the body of each PR created in this run.
+// PRs recorded by an earlier run are listed but their bodies are left untouched.
+func linkWSPRSiblings(ctx context.Context, items []wsPRItem, body string) {
+	linked := slices.DeleteFunc(slices.Clone(items), func(it wsPRItem) bool { return it.URL == "" })
+	if len(linked) < 2 {
+		return
+	}
+	for i := range items {
+		if items[i].Result != wsPRCreated {
+			continue
+		}
+		lines := []string{body, "", "Related PRs:"}
+		for _, sib := range linked {
+			if sib.Alias != items[i].Alias {
+				lines = append(lines, fmt.Sprintf("- %s: %s", sib.Alias, sib.URL))
+			}
+		}
+		f, err := forge.New(items[i].Forge)
+		if err == nil {
+			err = f.UpdatePullRequestBody(ctx, items[i].Repo, items[i].Number, strings.Join(lines, "\n"))
+		}
+		if err != nil {
+			items[i].LinkError = err.Error()
+		}
+	}
+}
+
+func buildWSPRTitle(ws workspaceMetaWorkspace) string {
+	title := strings.TrimSpace(ws.Title)
+	if title == "" {
+		return ws.ID
+	}
+	return ws.ID + ": " + title
+}
+
+func buildWSPRBody(ws workspaceMetaWorkspace) string {
+	lines := []string{}
+	if title := strings.TrimSpace(ws.Title); title != "" {
+		lines = append(lines, title, "")
+	}
+	if src := strings.TrimSpace(ws.SourceURL); src != "" {
+		lines = append(lines, "Source: "+src)
+	}
+	lines = append(lines, "Workspace: "+ws.ID)
+	return strings.Join(lines, "\n")
+}
+
+func wsPRJSONResult(items []wsPRItem, draft bool) map[string]any {
+	rows := make([]map[string]any, 0, len(items))
+	created := 0
+	for _, it := range items {
+		if it.Result == wsPRCreated {
+			created++
+		}
+		row := map[string]any{
+			"alias":  it.Alias,
+			"branch": it.Branch,
+			"base":   it.Base,
+			"forge":  it.Forge,
+			"url":    it.URL,
+			"result": it.Result,
+			"reason": it.Reason,
+		}
+		if it.LinkError != "" {
+			row["link_error"] = it.LinkError
+		}
+		rows = append(rows, row)
+	}
+	return map[string]any{
+		"draft":   draft,
+		"created": created,
+		"total":   len(items),
+		"items":   rows,
+	}
+}
+
+func printWSPRCreateResult(out io.Writer, items []wsPRItem, useColor bool) {
+	created := 0
+	for _, it := range items {
+		if it.Result == wsPRCreated {
+			created++
+		}
+	}
+	lines := []string{fmt.Sprintf("Created %d / %d", created, len(items))}
+	for _, it := range items {
+		var prefix string
+		switch it.Result {
+		case wsPRCreated:
+			prefix = styleSuccess("✔", useColor)
+		case wsPRRefused, wsPRFailed:
+			prefix = styleError("!", useColor)
+		default:
+			prefix = styleMuted("-", useColor)
+		}
+		line := fmt.Sprintf("%s %s", prefix, it.Alias)
+		switch {
+		case it.URL != "":
+			line += " " + it.URL
+			if it.Result == wsPRExisting {
+				line += " " + styleMuted("(existing)", useColor)
+			}
+		case it.Reason != "" && (it.Result == wsPRRefused || it.Result == wsPRFailed):
+			line += " " + styleError(it.Reason, useColor)
+		case it.Reason != "":
+			line += " " + styleMuted(it.Reason, useColor)
+		}
+		if it.LinkError != "" {
+			line += " " + styleError("cross-link failed: "+it.LinkError, useColor)
+		}
+		lines = append(lines, line)
+	}
+	if len(items) == 0 {
+		lines = append(lines, styleMuted("(none)", useColor))
+	}
+	printResultSection(out, useColor, lines...)
+}
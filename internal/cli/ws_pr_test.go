@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_PR_Create_OpensCrossLinkedPullRequestsAndRecordsURLs(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+	commit := func(dir string, name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(dir, "add", name)
+		runGit(dir, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-m", name)
+	}
+
+	var mu sync.Mutex
+	created := map[string]map[string]any{}
+	patched := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/pulls"):
+			repo := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/repos/"), "/pulls")
+			created[repo] = payload
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"number":%d,"html_url":"https://github.com/%s/pull/%d"}`, len(created), repo, len(created))
+		case r.Method == http.MethodPatch:
+			patched[r.URL.Path], _ = payload["body"].(string)
+			_, _ = fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_GITHUB_BASE_URL", server.URL)
+	t.Setenv("KRA_GITHUB_TOKEN", "ghp-test")
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	// The forge is picked by matching the repo host against the configured API host.
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parse server url: %v", err)
+	}
+	apiHost := serverURL.Hostname()
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, apiHost, "example-org", "api")
+	webSpec := prepareRemoteRepoSpecWithName(t, runGit, apiHost, "example-org", "web")
+	docsSpec := prepareRemoteRepoSpecWithName(t, runGit, apiHost, "example-org", "docs")
+	zetaSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "zeta")
+	if code, _, stderr := run("repo", "add", apiSpec, webSpec, docsSpec, zetaSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "--title", "Fix login", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	for _, repo := range []string{"example-org/api", "example-org/web", "example-org/docs", "example-org/zeta"} {
+		if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", repo, "--yes"); code != exitOK {
+			t.Fatalf("ws add-repo %s exit code = %d (stdout=%q stderr=%q)", repo, code, out, stderr)
+		}
+	}
+	wsPath := filepath.Join(env.Root, "workspaces", "WS1")
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		t.Fatalf("load meta: %v", err)
+	}
+	meta.Workspace.SourceURL = "https://jira.example.com/browse/WS1"
+	if err := writeWorkspaceMetaFile(wsPath, meta); err != nil {
+		t.Fatalf("write meta: %v", err)
+	}
+	commit(filepath.Join(wsPath, "repos", "api"), "api.txt")
+	commit(filepath.Join(wsPath, "repos", "web"), "web.txt")
+	commit(filepath.Join(wsPath, "repos", "zeta"), "zeta.txt")
+
+	type prResp struct {
+		OK     bool `json:"ok"`
+		Result struct {
+			Created int `json:"created"`
+			Items   []struct {
+				Alias  string `json:"alias"`
+				URL    string `json:"url"`
+				Result string `json:"result"`
+				Reason string `json:"reason"`
+			} `json:"items"`
+		} `json:"result"`
+	}
+	prCreate := func(wantCode int) prResp {
+		t.Helper()
+		code, out, stderr := run("ws", "pr", "create", "--format", "json", "--id", "WS1", "--draft")
+		if code != wantCode {
+			t.Fatalf("ws pr create exit code = %d, want %d (stdout=%q stderr=%q)", code, wantCode, out, stderr)
+		}
+		var resp prResp
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			t.Fatalf("decode ws pr create: %v (out=%s)", err, out)
+		}
+		return resp
+	}
+
+	resp := prCreate(exitError)
+	if resp.OK || resp.Result.Created != 0 || !strings.Contains(resp.Result.Items[0].Reason, "kra ws push") {
+		t.Fatalf("unpushed pr create = %+v", resp)
+	}
+
+	if code, _, stderr := run("ws", "push", "--id", "WS1"); code != exitOK {
+		t.Fatalf("ws push exit code = %d (stderr=%q)", code, stderr)
+	}
+	resp = prCreate(exitOK)
+	if resp.Result.Created != 2 || len(resp.Result.Items) != 4 {
+		t.Fatalf("pr create = %+v", resp.Result)
+	}
+	if it := resp.Result.Items[3]; it.Alias != "zeta" || it.Result != wsPRSkipped || !strings.Contains(it.Reason, `no forge configured for host "github.com"`) {
+		t.Fatalf("zeta item (host without a configured API) = %+v", it)
+	}
+	if it := resp.Result.Items[1]; it.Alias != "docs" || it.Result != wsPRSkipped || it.Reason != "no commits beyond origin/main" {
+		t.Fatalf("docs item = %+v", it)
+	}
+	api := created["example-org/api"]
+	if api["head"] != "WS1" || api["base"] != "main" || api["title"] != "WS1: Fix login" || api["draft"] != true {
+		t.Fatalf("api create payload = %+v", api)
+	}
+	if body, _ := api["body"].(string); !strings.Contains(body, "Source: https://jira.example.com/browse/WS1") {
+		t.Fatalf("api body = %q", body)
+	}
+	apiURL, webURL := resp.Result.Items[0].URL, resp.Result.Items[2].URL
+	if got := patched["/repos/example-org/api/pulls/"+strings.TrimPrefix(apiURL, "https://github.com/example-org/api/pull/")]; !strings.Contains(got, "- web: "+webURL) || strings.Contains(got, "- api:") {
+		t.Fatalf("api cross-link body = %q", got)
+	}
+	if got := patched["/repos/example-org/web/pulls/"+strings.TrimPrefix(webURL, "https://github.com/example-org/web/pull/")]; !strings.Contains(got, "- api: "+apiURL) {
+		t.Fatalf("web cross-link body = %q", got)
+	}
+
+	code, out, _ := run("ws", "list", "--tree")
+	if code != exitOK || !strings.Contains(out, "pr:"+apiURL) || !strings.Contains(out, "pr:"+webURL) {
+		t.Fatalf("ws list --tree: code=%d out=%s", code, out)
+	}
+
+	// Recorded PRs are not opened again.
+	resp = prCreate(exitOK)
+	if resp.Result.Created != 0 || resp.Result.Items[0].Result != wsPRExisting || resp.Result.Items[0].URL != apiURL {
+		t.Fatalf("second pr create = %+v", resp.Result)
+	}
+	if len(created) != 2 {
+		t.Fatalf("created %d PRs, want 2", len(created))
+	}
+}
@@ -424,8 +424,13 @@ func (c *CLI) purgeWorkspace(ctx context.Context, root string, workspaceID strin
 		return purgeCommitTrace{}, fmt.Errorf("workspace cannot be purged unless archived (run: kra ws close %s)", workspaceID)
 	}
 	trace := purgeCommitTrace{CommitEnabled: doCommit}
+	var lc lifecycleCommit
 	if doCommit {
-		preSHA, err := commitPurgePreSnapshot(ctx, root, workspaceID)
+		lc, err = c.loadLifecycleCommit(root, workspaceID)
+		if err != nil {
+			return purgeCommitTrace{}, fmt.Errorf("resolve lifecycle commit: %w", err)
+		}
+		preSHA, err := commitPurgePreSnapshot(ctx, root, workspaceID, lc)
 		if err != nil {
 			return purgeCommitTrace{}, fmt.Errorf("commit purge pre-snapshot: %w", err)
 		}
@@ -453,7 +458,7 @@ func (c *CLI) purgeWorkspace(ctx context.Context, root string, workspaceID strin
 	}
 
 	if doCommit {
-		postSHA, err := commitPurgeChange(ctx, root, workspaceID)
+		postSHA, err := commitPurgeChange(ctx, root, workspaceID, lc)
 		if err != nil {
 			return purgeCommitTrace{}, fmt.Errorf("commit purge change: %w", err)
 		}
@@ -519,7 +524,7 @@ func (c *CLI) printWSPurgeFlowResult(done []string, total int, useColor bool, tr
 	})
 }
 
-func commitPurgeChange(ctx context.Context, root string, workspaceID string) (string, error) {
+func commitPurgeChange(ctx context.Context, root string, workspaceID string, lc lifecycleCommit) (string, error) {
 	archivePrefix, err := toGitTopLevelPath(ctx, root, filepath.Join("archive", workspaceID))
 	if err != nil {
 		return "", err
@@ -562,7 +567,7 @@ func commitPurgeChange(ctx context.Context, root string, workspaceID string) (st
 	}
 	for _, arg := range []string{baselineArg, workStateArg} {
 		if _, err := gitutil.Run(ctx, root, "add", "-A", "--", arg); err != nil {
-			if strings.Contains(err.Error(), "did not match any files") || strings.Contains(err.Error(), "did not match any file") {
+			if gitAddPathSkipped(err) {
 				continue
 			}
 			resetStaging()
@@ -601,16 +606,17 @@ func commitPurgeChange(ctx context.Context, root string, workspaceID string) (st
 		return "", fmt.Errorf("unexpected staged path outside allowlist: %s", p)
 	}
 
-	commitArgs := []string{"commit", "--allow-empty", "--only", "-m", fmt.Sprintf("purge: %s", workspaceID), "--", archiveArg}
+	commitPaths := []string{archiveArg}
 	if hasWorkspacesStage {
-		commitArgs = append(commitArgs, workspacesArg)
+		commitPaths = append(commitPaths, workspacesArg)
 	}
 	if hasBaselineStage {
-		commitArgs = append(commitArgs, baselineArg)
+		commitPaths = append(commitPaths, baselineArg)
 	}
 	if hasWorkStateStage {
-		commitArgs = append(commitArgs, workStateArg)
+		commitPaths = append(commitPaths, workStateArg)
 	}
+	commitArgs := lc.gitArgs("purge", []string{"--allow-empty", "--only"}, commitPaths...)
 	if _, err := gitutil.Run(ctx, root, commitArgs...); err != nil {
 		resetStaging()
 		return "", err
@@ -624,7 +630,7 @@ func commitPurgeChange(ctx context.Context, root string, workspaceID string) (st
 	return strings.TrimSpace(sha), nil
 }
 
-func commitPurgePreSnapshot(ctx context.Context, root string, workspaceID string) (string, error) {
+func commitPurgePreSnapshot(ctx context.Context, root string, workspaceID string, lc lifecycleCommit) (string, error) {
 	archivePrefix, err := toGitTopLevelPath(ctx, root, filepath.Join("archive", workspaceID))
 	if err != nil {
 		return "", err
@@ -648,7 +654,7 @@ func commitPurgePreSnapshot(ctx context.Context, root string, workspaceID string
 		_, _ = gitutil.Run(ctx, root, "reset", "-q", "--", archiveArg)
 		return "", fmt.Errorf("unexpected staged path outside allowlist: %s", p)
 	}
-	if _, err := gitutil.Run(ctx, root, "commit", "--allow-empty", "--only", "-m", fmt.Sprintf("purge-pre: %s", workspaceID), "--", archiveArg); err != nil {
+	if _, err := gitutil.Run(ctx, root, lc.gitArgs("purge-pre", []string{"--allow-empty", "--only"}, archiveArg)...); err != nil {
 		_, _ = gitutil.Run(ctx, root, "reset", "-q", "--", archiveArg)
 		return "", err
 	}
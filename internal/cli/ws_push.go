@@ -0,0 +1,386 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/infra/paths"
+	"github.com/tasuku43/kra/internal/infra/statestore"
+)
+
+// Per-repo outcomes of ws push.
+const (
+	wsPushPushed   = "pushed"
+	wsPushPlanned  = "planned"
+	wsPushUpToDate = "up_to_date"
+	wsPushSkipped  = "skipped"
+	wsPushRefused  = "refused"
+	wsPushFailed   = "failed"
+)
+
+type wsPushItem struct {
+	Alias  string
+	Branch string
+	// Ahead/Behind are counted against origin/<branch> (zero when the remote branch does not exist).
+	Ahead        int
+	Behind       int
+	RemoteExists bool
+	SetUpstream  bool
+	Force        bool
+	Result       string
+	Reason       string
+}
+
+func (c *CLI) runWSPush(args []string) int {
+	outputFormat := "human"
+	workspaceID := ""
+	useCurrent := false
+	forceWithLease := false
+	dryRun := false
+	aliases := make([]string, 0, 2)
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			c.printWSPushUsage(c.Out)
+			return exitOK
+		case arg == "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				c.printWSPushUsage(c.Err)
+				return exitUsage
+			}
+			outputFormat = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case arg == "--id":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--id requires a value")
+				c.printWSPushUsage(c.Err)
+				return exitUsage
+			}
+			workspaceID = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--id="):
+			workspaceID = strings.TrimSpace(strings.TrimPrefix(arg, "--id="))
+		case arg == "--current":
+			useCurrent = true
+		case arg == "--repo":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--repo requires a value")
+				c.printWSPushUsage(c.Err)
+				return exitUsage
+			}
+			aliases = append(aliases, strings.TrimSpace(args[i+1]))
+			i++
+		case strings.HasPrefix(arg, "--repo="):
+			aliases = append(aliases, strings.TrimSpace(strings.TrimPrefix(arg, "--repo=")))
+		case arg == "--force-with-lease":
+			forceWithLease = true
+		case arg == "--dry-run":
+			dryRun = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(c.Err, "unknown flag for ws push: %q\n", arg)
+			c.printWSPushUsage(c.Err)
+			return exitUsage
+		default:
+			if workspaceID != "" {
+				fmt.Fprintf(c.Err, "unexpected args for ws push: %q\n", arg)
+				c.printWSPushUsage(c.Err)
+				return exitUsage
+			}
+			workspaceID = arg
+		}
+	}
+	switch outputFormat {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", outputFormat)
+		c.printWSPushUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" && useCurrent {
+		fmt.Fprintln(c.Err, "--id and --current cannot be used together")
+		c.printWSPushUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" {
+		if err := validateWorkspaceID(workspaceID); err != nil {
+			fmt.Fprintf(c.Err, "invalid workspace id: %v\n", err)
+			return exitUsage
+		}
+	}
+	jsonMode := outputFormat == "json"
+	fail := func(code int, errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:          false,
+				Action:      "ws.push",
+				WorkspaceID: workspaceID,
+				Error:       &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("get working dir: %v", err))
+	}
+	root, err := paths.ResolveExistingRoot(wd)
+	if err != nil {
+		return fail(exitError, "not_found", fmt.Sprintf("resolve KRA_ROOT: %v", err))
+	}
+	if err := c.ensureDebugLog(root, "ws-push"); err != nil {
+		fmt.Fprintf(c.Err, "enable debug logging: %v\n", err)
+	}
+	workspaceID, errCode, err := resolveActiveWorkspaceTarget(root, wd, workspaceID, "ws push")
+	if err != nil {
+		return fail(exitError, errCode, err.Error())
+	}
+
+	ctx := context.Background()
+	wsPath := filepath.Join(root, "workspaces", workspaceID)
+	meta, _ := loadWorkspaceMetaFile(wsPath)
+	bound, err := listWorkspaceReposFromFilesystem(ctx, root, "active", workspaceID, meta)
+	if err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("list workspace repos: %v", err))
+	}
+	for _, alias := range aliases {
+		if !slices.ContainsFunc(bound, func(r statestore.WorkspaceRepo) bool { return r.Alias == alias }) {
+			return fail(exitUsage, "invalid_argument", fmt.Sprintf("repo not bound to workspace %s: %s", workspaceID, alias))
+		}
+	}
+
+	items := make([]wsPushItem, 0, len(bound))
+	for _, r := range bound {
+		if len(aliases) > 0 && !slices.Contains(aliases, r.Alias) {
+			continue
+		}
+		item := wsPushItem{Alias: r.Alias, Branch: r.Branch}
+		if r.MissingAt.Valid {
+			item.Result, item.Reason = wsPushSkipped, "worktree missing"
+			items = append(items, item)
+			continue
+		}
+		if r.Reference {
+			item.Result, item.Reason = wsPushSkipped, "reference repo"
+			items = append(items, item)
+			continue
+		}
+		item = planWSPushItem(ctx, filepath.Join(wsPath, "repos", r.Alias), item, forceWithLease)
+		if item.Result == wsPushPlanned && !dryRun {
+			item = runWSPushItem(ctx, filepath.Join(wsPath, "repos", r.Alias), item)
+		}
+		c.debugf("ws push id=%s alias=%s branch=%s result=%s reason=%s", workspaceID, item.Alias, item.Branch, item.Result, item.Reason)
+		items = append(items, item)
+	}
+
+	ok := !slices.ContainsFunc(items, func(it wsPushItem) bool {
+		return it.Result == wsPushRefused || it.Result == wsPushFailed
+	})
+	if jsonMode {
+		resp := cliJSONResponse{
+			OK:          ok,
+			Action:      "ws.push",
+			WorkspaceID: workspaceID,
+			Result:      wsPushJSONResult(items, dryRun),
+		}
+		if !ok {
+			resp.Error = &cliJSONError{Code: "conflict", Message: "some repos were not pushed"}
+		}
+		_ = writeCLIJSON(c.Out, resp)
+	} else {
+		printWSPushResult(c.Out, items, dryRun, writerSupportsColor(c.Out))
+	}
+	if !ok {
+		return exitError
+	}
+	return exitOK
+}
+
+// planWSPushItem decides what pushing the worktree branch to origin/<branch> would do.
+// Only branches with commits not on any remote ref are pushed when origin/<branch> does not exist yet.
+func planWSPushItem(ctx context.Context, worktreePath string, item wsPushItem, forceWithLease bool) wsPushItem {
+	branchOut, err := gitutil.Run(ctx, worktreePath, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		item.Result, item.Reason = wsPushSkipped, "detached HEAD"
+		return item
+	}
+	item.Branch = strings.TrimSpace(branchOut)
+	remoteRef := "refs/remotes/origin/" + item.Branch
+
+	upstream, _ := gitutil.Run(ctx, worktreePath, "rev-parse", "--symbolic-full-name", "@{upstream}")
+	item.SetUpstream = strings.TrimSpace(upstream) != remoteRef
+
+	if _, err := gitutil.Run(ctx, worktreePath, "rev-parse", "--verify", "--quiet", remoteRef); err == nil {
+		item.RemoteExists = true
+		out, err := gitutil.Run(ctx, worktreePath, "rev-list", "--left-right", "--count", "HEAD..."+remoteRef)
+		if err != nil {
+			item.Result, item.Reason = wsPushFailed, fmt.Sprintf("compare with origin/%s: %v", item.Branch, err)
+			return item
+		}
+		if item.Ahead, item.Behind, err = parseRevListLeftRightCount(out); err != nil {
+			item.Result, item.Reason = wsPushFailed, err.Error()
+			return item
+		}
+	} else {
+		out, err := gitutil.Run(ctx, worktreePath, "rev-list", "--count", "HEAD", "--not", "--remotes")
+		if err != nil {
+			item.Result, item.Reason = wsPushFailed, fmt.Sprintf("count unpushed commits: %v", err)
+			return item
+		}
+		if item.Ahead, err = strconv.Atoi(strings.TrimSpace(out)); err != nil {
+			item.Result, item.Reason = wsPushFailed, fmt.Sprintf("count unpushed commits: %v", err)
+			return item
+		}
+	}
+
+	switch {
+	case item.Ahead == 0 && item.RemoteExists && item.SetUpstream:
+		item.Result = wsPushPlanned
+	case item.Ahead == 0:
+		item.Result = wsPushUpToDate
+		if !item.RemoteExists {
+			item.Result, item.Reason = wsPushSkipped, "no commits to push"
+		}
+		item.SetUpstream = false
+	case item.Behind > 0 && !forceWithLease:
+		item.Result, item.Reason = wsPushRefused, fmt.Sprintf("diverged from origin/%s (use --force-with-lease)", item.Branch)
+	case item.Behind > 0:
+		item.Force = true
+		item.Result = wsPushPlanned
+	default:
+		item.Result = wsPushPlanned
+	}
+	return item
+}
+
+func runWSPushItem(ctx context.Context, worktreePath string, item wsPushItem) wsPushItem {
+	if item.Ahead == 0 {
+		// Remote already has the commits; only record the tracking config.
+		if _, err := gitutil.Run(ctx, worktreePath, "branch", "--set-upstream-to=origin/"+item.Branch); err != nil {
+			item.Result, item.Reason = wsPushFailed, fmt.Sprintf("set upstream: %v", err)
+			return item
+		}
+		item.Result, item.Reason = wsPushPushed, ""
+		return item
+	}
+	args := []string{"push"}
+	if item.SetUpstream {
+		args = append(args, "--set-upstream")
+	}
+	if item.Force {
+		args = append(args, "--force-with-lease")
+	}
+	args = append(args, "origin", item.Branch)
+	if _, err := gitutil.Run(ctx, worktreePath, args...); err != nil {
+		item.Result, item.Reason = wsPushFailed, err.Error()
+		return item
+	}
+	item.Result, item.Reason = wsPushPushed, ""
+	return item
+}
+
+func wsPushItemAction(item wsPushItem) string {
+	switch {
+	case item.Result != wsPushPlanned && item.Result != wsPushPushed:
+		return "none"
+	case item.Force:
+		return "force_push"
+	case item.Ahead == 0:
+		return "set_upstream"
+	case item.SetUpstream:
+		return "push_set_upstream"
+	default:
+		return "push"
+	}
+}
+
+func wsPushJSONResult(items []wsPushItem, dryRun bool) map[string]any {
+	rows := make([]map[string]any, 0, len(items))
+	pushed := 0
+	for _, it := range items {
+		if it.Result == wsPushPushed {
+			pushed++
+		}
+		rows = append(rows, map[string]any{
+			"alias":         it.Alias,
+			"branch":        it.Branch,
+			"ahead":         it.Ahead,
+			"behind":        it.Behind,
+			"remote_exists": it.RemoteExists,
+			"action":        wsPushItemAction(it),
+			"result":        it.Result,
+			"reason":        it.Reason,
+		})
+	}
+	return map[string]any{
+		"dry_run": dryRun,
+		"pushed":  pushed,
+		"total":   len(items),
+		"items":   rows,
+	}
+}
+
+func printWSPushResult(out io.Writer, items []wsPushItem, dryRun bool, useColor bool) {
+	pushed := 0
+	for _, it := range items {
+		if it.Result == wsPushPushed {
+			pushed++
+		}
+	}
+	summary := fmt.Sprintf("Pushed %d / %d", pushed, len(items))
+	if dryRun {
+		summary = fmt.Sprintf("Dry run: %d repos", len(items))
+	}
+	lines := []string{summary}
+	for _, it := range items {
+		detail := it.Branch
+		switch wsPushItemAction(it) {
+		case "force_push":
+			detail += " (force-with-lease)"
+		case "set_upstream":
+			detail += " (set upstream)"
+		case "push_set_upstream":
+			detail += fmt.Sprintf(" (%d commits, set upstream)", it.Ahead)
+		case "push":
+			detail += fmt.Sprintf(" (%d commits)", it.Ahead)
+		}
+		var prefix string
+		switch it.Result {
+		case wsPushPushed:
+			prefix = styleSuccess("✔", useColor)
+		case wsPushPlanned:
+			prefix = styleAccent("→", useColor)
+		case wsPushRefused, wsPushFailed:
+			prefix = styleError("!", useColor)
+		default:
+			prefix = styleMuted("-", useColor)
+		}
+		line := fmt.Sprintf("%s %s %s", prefix, it.Alias, detail)
+		switch {
+		case it.Result == wsPushUpToDate:
+			line += " " + styleMuted("up to date", useColor)
+		case it.Reason != "" && (it.Result == wsPushRefused || it.Result == wsPushFailed):
+			line += " " + styleError(it.Reason, useColor)
+		case it.Reason != "" && it.Result == wsPushSkipped:
+			line += " " + styleMuted(it.Reason, useColor)
+		}
+		lines = append(lines, line)
+	}
+	if len(items) == 0 {
+		lines = append(lines, styleMuted("(none)", useColor))
+	}
+	printResultSection(out, useColor, lines...)
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_Push_SetsUpstreamAndRefusesDivergedUnlessForced(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	gitOut := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+		return strings.TrimSpace(string(out))
+	}
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		gitOut(dir, args...)
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+	commit := func(dir string, name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(dir, "add", name)
+		runGit(dir, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-m", name)
+	}
+	type pushResp struct {
+		OK     bool `json:"ok"`
+		Result struct {
+			Pushed int `json:"pushed"`
+			Items  []struct {
+				Alias  string `json:"alias"`
+				Action string `json:"action"`
+				Result string `json:"result"`
+				Reason string `json:"reason"`
+			} `json:"items"`
+		} `json:"result"`
+	}
+	push := func(wantCode int, args ...string) pushResp {
+		t.Helper()
+		code, out, stderr := run(append([]string{"ws", "push", "--format", "json", "--id", "WS1"}, args...)...)
+		if code != wantCode {
+			t.Fatalf("ws push %v exit code = %d, want %d (stdout=%q stderr=%q)", args, code, wantCode, out, stderr)
+		}
+		var resp pushResp
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			t.Fatalf("decode ws push: %v (out=%s)", err, out)
+		}
+		return resp
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	webSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "web")
+	apiRemote := strings.TrimPrefix(apiSpec, "file://")
+	if code, _, stderr := run("repo", "add", apiSpec, webSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	for _, repo := range []string{"example-org/api", "example-org/web"} {
+		if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", repo, "--yes"); code != exitOK {
+			t.Fatalf("ws add-repo %s exit code = %d (stdout=%q stderr=%q)", repo, code, out, stderr)
+		}
+	}
+	apiPath := filepath.Join(env.Root, "workspaces", "WS1", "repos", "api")
+	commit(apiPath, "one.txt")
+
+	if code, _, stderr := run("ws", "push", "--id", "WS1", "--repo", "nope"); code != exitUsage || !strings.Contains(stderr, "repo not bound to workspace WS1: nope") {
+		t.Fatalf("ws push unknown alias: code=%d stderr=%q", code, stderr)
+	}
+
+	resp := push(exitOK, "--dry-run")
+	if len(resp.Result.Items) != 2 || resp.Result.Items[0].Result != wsPushPlanned || resp.Result.Items[0].Action != "push_set_upstream" || resp.Result.Items[1].Result != wsPushSkipped {
+		t.Fatalf("dry-run = %+v", resp.Result)
+	}
+	if out, _ := exec.Command("git", "--git-dir", apiRemote, "rev-parse", "--verify", "--quiet", "refs/heads/WS1").Output(); len(out) != 0 {
+		t.Fatalf("dry-run must not push")
+	}
+
+	resp = push(exitOK)
+	if resp.Result.Pushed != 1 || resp.Result.Items[0].Result != wsPushPushed {
+		t.Fatalf("push = %+v", resp.Result)
+	}
+	if got := gitOut("", "--git-dir", apiRemote, "rev-parse", "refs/heads/WS1"); got != gitOut(apiPath, "rev-parse", "HEAD") {
+		t.Fatalf("remote WS1 = %s, want local HEAD", got)
+	}
+	if got := gitOut(apiPath, "rev-parse", "--abbrev-ref", "@{upstream}"); got != "origin/WS1" {
+		t.Fatalf("upstream = %q, want origin/WS1", got)
+	}
+	if code, out, _ := run("ws", "status", "--format", "json", "--id", "WS1"); code != exitOK || !strings.Contains(out, `"alias":"api"`) || strings.Contains(out, `"state":"unpushed"`) {
+		t.Fatalf("ws status after push: code=%d out=%s", code, out)
+	}
+	if resp := push(exitOK, "--repo", "api"); len(resp.Result.Items) != 1 || resp.Result.Items[0].Result != wsPushUpToDate {
+		t.Fatalf("second push = %+v", resp.Result)
+	}
+
+	// Diverge: someone else pushes to origin/WS1 while a local commit is made.
+	other := filepath.Join(t.TempDir(), "other")
+	runGit("", "clone", "--branch", "WS1", apiRemote, other)
+	commit(other, "theirs.txt")
+	runGit(other, "push", "origin", "WS1")
+	commit(apiPath, "mine.txt")
+	if code, _, stderr := run("repo", "fetch"); code != exitOK {
+		t.Fatalf("repo fetch exit code = %d (stderr=%q)", code, stderr)
+	}
+	resp = push(exitError, "--repo", "api")
+	if resp.OK || resp.Result.Items[0].Result != wsPushRefused || !strings.Contains(resp.Result.Items[0].Reason, "--force-with-lease") {
+		t.Fatalf("diverged push = %+v", resp)
+	}
+	resp = push(exitOK, "--repo", "api", "--force-with-lease")
+	if resp.Result.Items[0].Result != wsPushPushed || resp.Result.Items[0].Action != "force_push" {
+		t.Fatalf("forced push = %+v", resp.Result)
+	}
+	if got := gitOut("", "--git-dir", apiRemote, "rev-parse", "refs/heads/WS1"); got != gitOut(apiPath, "rev-parse", "HEAD") {
+		t.Fatalf("remote WS1 after force = %s, want local HEAD", got)
+	}
+}
@@ -22,6 +22,7 @@ type removeRepoCandidate struct {
 	Alias        string
 	SelectorID   string
 	WorktreePath string
+	Reference    bool
 }
 
 type removeRepoPlanDetail struct {
@@ -313,10 +314,18 @@ func (c *CLI) runWSRemoveRepoJSON(root string, workspaceID string, wd string, ca
 			return exitError
 		}
 	}
-	byRepoKey := make(map[string]removeRepoCandidate, len(candidates))
+	// --repo accepts a repo key or an alias; aliases disambiguate repos bound more than once.
+	byRepoKey := make(map[string]removeRepoCandidate, len(candidates)*2)
+	ambiguous := map[string]bool{}
 	for _, cand := range candidates {
+		if _, dup := byRepoKey[cand.RepoKey]; dup {
+			ambiguous[cand.RepoKey] = true
+		}
 		byRepoKey[cand.RepoKey] = cand
 	}
+	for _, cand := range candidates {
+		byRepoKey[cand.Alias] = cand
+	}
 	selected := make([]removeRepoCandidate, 0, len(repoKeys))
 	seen := map[string]bool{}
 	for _, raw := range repoKeys {
@@ -325,6 +334,18 @@ func (c *CLI) runWSRemoveRepoJSON(root string, workspaceID string, wd string, ca
 			continue
 		}
 		seen[repoKey] = true
+		if ambiguous[repoKey] {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:          false,
+				Action:      "remove-repo",
+				WorkspaceID: workspaceID,
+				Error: &cliJSONError{
+					Code:    "invalid_argument",
+					Message: fmt.Sprintf("repo is bound more than once, pass its alias: %s", repoKey),
+				},
+			})
+			return exitUsage
+		}
 		cand, ok := byRepoKey[repoKey]
 		if !ok {
 			_ = writeCLIJSON(c.Out, cliJSONResponse{
@@ -338,6 +359,9 @@ func (c *CLI) runWSRemoveRepoJSON(root string, workspaceID string, wd string, ca
 			})
 			return exitUsage
 		}
+		if slices.ContainsFunc(selected, func(s removeRepoCandidate) bool { return s.Alias == cand.Alias }) {
+			continue
+		}
 		selected = append(selected, cand)
 	}
 
@@ -445,6 +469,7 @@ func listRemoveRepoCandidates(ctx context.Context, root string, workspaceID stri
 			RepoKey:      repoKey,
 			Alias:        alias,
 			WorktreePath: filepath.Join(root, "workspaces", workspaceID, "repos", alias),
+			Reference:    restore.Reference,
 		})
 	}
 	slices.SortFunc(out, func(a, b removeRepoCandidate) int {
@@ -523,6 +548,9 @@ func collectRemoveRepoPlanDetails(ctx context.Context, selected []removeRepoCand
 		snapshot := inspectGitRepoSnapshot(ctx, it.WorktreePath)
 		status := snapshot.Status
 		d.state = workspacerisk.ClassifyRepoStatus(status)
+		if it.Reference {
+			d.state = workspacerisk.ClassifyReferenceRepoStatus(status)
+		}
 		d.upstream = strings.TrimSpace(status.Upstream)
 		d.ahead = status.AheadCount
 		d.behind = status.BehindCount
@@ -556,6 +584,7 @@ func applyRemoveRepoPlan(ctx context.Context, root string, workspaceID string, s
 			barePath, bareErr := resolveBarePathFromWorktreeGitdir(it.WorktreePath)
 			if bareErr == nil {
 				if _, statErr := os.Stat(barePath); statErr == nil {
+					removeWorkspaceCommitHooksBestEffort(ctx, it.WorktreePath)
 					if _, err := gitutil.RunBare(ctx, barePath, "worktree", "remove", "--force", it.WorktreePath); err != nil {
 						return err
 					}
@@ -263,8 +263,14 @@ func (c *CLI) reopenWorkspace(ctx context.Context, root string, repoPoolPath str
 	}
 
 	trace := reopenCommitTrace{CommitEnabled: doCommit}
+	var lc lifecycleCommit
 	if doCommit {
-		preSHA, err := commitReopenPreSnapshot(ctx, root, workspaceID)
+		var err error
+		lc, err = c.loadLifecycleCommit(root, workspaceID)
+		if err != nil {
+			return reopenCommitTrace{}, fmt.Errorf("resolve lifecycle commit: %w", err)
+		}
+		preSHA, err := commitReopenPreSnapshot(ctx, root, workspaceID, lc)
 		if err != nil {
 			return reopenCommitTrace{}, fmt.Errorf("commit reopen pre-snapshot: %w", err)
 		}
@@ -280,7 +286,12 @@ func (c *CLI) reopenWorkspace(ctx context.Context, root string, repoPoolPath str
 		return reopenCommitTrace{}, fmt.Errorf("load %s: %w", workspaceMetaFilename, err)
 	}
 
-	if err := recreateWorkspaceWorktreesFromMeta(ctx, root, repoPoolPath, workspaceID, meta.ReposRestore); err != nil {
+	commitHooks, err := c.loadWorkspaceCommitHooks(root, workspaceID, meta.Workspace.SourceURL)
+	if err != nil {
+		_ = os.Rename(wsPath, archivePath)
+		return reopenCommitTrace{}, fmt.Errorf("resolve commit hooks: %w", err)
+	}
+	if err := recreateWorkspaceWorktreesFromMeta(ctx, root, repoPoolPath, workspaceID, meta.ReposRestore, commitHooks); err != nil {
 		_ = os.Rename(wsPath, archivePath)
 		return reopenCommitTrace{}, fmt.Errorf("recreate worktrees: %w", err)
 	}
@@ -295,7 +306,7 @@ func (c *CLI) reopenWorkspace(ctx context.Context, root string, repoPoolPath str
 	}
 
 	if doCommit {
-		postSHA, err := commitReopenChange(ctx, root, workspaceID)
+		postSHA, err := commitReopenChange(ctx, root, workspaceID, lc)
 		if err != nil {
 			return reopenCommitTrace{}, fmt.Errorf("commit reopen change: %w", err)
 		}
@@ -361,7 +372,7 @@ func (c *CLI) printWSReopenFlowResult(done []string, total int, useColor bool, t
 	})
 }
 
-func recreateWorkspaceWorktreesFromMeta(ctx context.Context, root string, repoPoolPath string, workspaceID string, repos []workspaceMetaRepoRestore) error {
+func recreateWorkspaceWorktreesFromMeta(ctx context.Context, root string, repoPoolPath string, workspaceID string, repos []workspaceMetaRepoRestore, commitHooks workspaceCommitHooks) error {
 	reposDir := filepath.Join(root, "workspaces", workspaceID, "repos")
 	if err := os.MkdirAll(reposDir, 0o755); err != nil {
 		return err
@@ -376,7 +387,7 @@ func recreateWorkspaceWorktreesFromMeta(ctx context.Context, root string, repoPo
 			return fmt.Errorf("invalid repos_restore entry: duplicate alias %q", r.Alias)
 		}
 		aliasSeen[r.Alias] = true
-		if strings.TrimSpace(r.RepoUID) == "" || strings.TrimSpace(r.RemoteURL) == "" || strings.TrimSpace(r.Branch) == "" {
+		if strings.TrimSpace(r.RepoUID) == "" || strings.TrimSpace(r.RemoteURL) == "" || (strings.TrimSpace(r.Branch) == "" && !r.Reference) {
 			return fmt.Errorf("invalid repos_restore entry for alias %q", r.Alias)
 		}
 		worktreePath := filepath.Join(reposDir, r.Alias)
@@ -392,7 +403,9 @@ func recreateWorkspaceWorktreesFromMeta(ctx context.Context, root string, repoPo
 		}
 		barePath := repostore.StorePath(repoPoolPath, spec)
 
-		defaultBaseRef, err := gitutil.EnsureBareRepoFetched(ctx, r.RemoteURL, barePath, baseBranchFromBaseRef(r.BaseRef))
+		defaultBaseRef, err := ensureRepoPoolFetched(ctx, barePath, func() (string, error) {
+			return gitutil.EnsureBareRepoFetched(ctx, r.RemoteURL, barePath, baseBranchFromBaseRef(r.BaseRef))
+		})
 		if err != nil {
 			return err
 		}
@@ -405,6 +418,16 @@ func recreateWorkspaceWorktreesFromMeta(ctx context.Context, root string, repoPo
 			return fmt.Errorf("invalid base_ref (must be origin/<branch>): %q", baseRefUsed)
 		}
 
+		if r.Reference {
+			if err := addReferenceWorktreeWithSparse(ctx, barePath, worktreePath, baseRefUsed, r.Sparse); err != nil {
+				return err
+			}
+			if err := populateWorktreeContent(ctx, repoPoolPath, worktreePath, worktreeContentOptions{Submodules: r.Submodules, LFS: r.LFS}); err != nil {
+				return fmt.Errorf("%s: %w", r.Alias, err)
+			}
+			continue
+		}
+
 		remoteBranchRef := "refs/remotes/origin/" + r.Branch
 		remoteExists, err := gitutil.ShowRefExistsBare(ctx, barePath, remoteBranchRef)
 		if err != nil {
@@ -429,13 +452,19 @@ func recreateWorkspaceWorktreesFromMeta(ctx context.Context, root string, repoPo
 			}
 		}
 
-		if _, err := gitutil.RunBare(ctx, barePath, "worktree", "add", worktreePath, r.Branch); err != nil {
+		if err := addWorktreeWithSparse(ctx, barePath, worktreePath, r.Branch, r.Sparse); err != nil {
 			msg := err.Error()
 			if strings.Contains(msg, "already checked out") || strings.Contains(msg, "already used by worktree") {
 				return fmt.Errorf("branch is already checked out by another worktree: %s", r.Branch)
 			}
 			return err
 		}
+		if err := populateWorktreeContent(ctx, repoPoolPath, worktreePath, worktreeContentOptions{Submodules: r.Submodules, LFS: r.LFS}); err != nil {
+			return fmt.Errorf("%s: %w", r.Alias, err)
+		}
+		if err := installWorkspaceCommitHooks(ctx, worktreePath, commitHooks); err != nil {
+			return fmt.Errorf("%s: install commit hooks: %w", r.Alias, err)
+		}
 	}
 
 	return nil
@@ -453,7 +482,7 @@ func baseBranchFromBaseRef(baseRef string) string {
 	return b
 }
 
-func commitReopenChange(ctx context.Context, root string, workspaceID string) (string, error) {
+func commitReopenChange(ctx context.Context, root string, workspaceID string, lc lifecycleCommit) (string, error) {
 	workspacesPrefix, err := toGitTopLevelPath(ctx, root, filepath.Join("workspaces", workspaceID))
 	if err != nil {
 		return "", err
@@ -496,7 +525,7 @@ func commitReopenChange(ctx context.Context, root string, workspaceID string) (s
 	}
 	for _, arg := range []string{baselineArg, workStateArg} {
 		if _, err := gitutil.Run(ctx, root, "add", "-A", "--", arg); err != nil {
-			if strings.Contains(err.Error(), "did not match any files") || strings.Contains(err.Error(), "did not match any file") {
+			if gitAddPathSkipped(err) {
 				continue
 			}
 			resetStaging()
@@ -530,13 +559,14 @@ func commitReopenChange(ctx context.Context, root string, workspaceID string) (s
 		return "", fmt.Errorf("unexpected staged path outside allowlist: %s", p)
 	}
 
-	commitArgs := []string{"commit", "--only", "-m", fmt.Sprintf("reopen: %s", workspaceID), "--", workspacesArg, archiveArg}
+	commitPaths := []string{workspacesArg, archiveArg}
 	if hasBaselineStage {
-		commitArgs = append(commitArgs, baselineArg)
+		commitPaths = append(commitPaths, baselineArg)
 	}
 	if hasWorkStateStage {
-		commitArgs = append(commitArgs, workStateArg)
+		commitPaths = append(commitPaths, workStateArg)
 	}
+	commitArgs := lc.gitArgs("reopen", []string{"--only"}, commitPaths...)
 	if _, err := gitutil.Run(ctx, root, commitArgs...); err != nil {
 		resetStaging()
 		return "", err
@@ -550,7 +580,7 @@ func commitReopenChange(ctx context.Context, root string, workspaceID string) (s
 	return strings.TrimSpace(sha), nil
 }
 
-func commitReopenPreSnapshot(ctx context.Context, root string, workspaceID string) (string, error) {
+func commitReopenPreSnapshot(ctx context.Context, root string, workspaceID string, lc lifecycleCommit) (string, error) {
 	archivePrefix, err := toGitTopLevelPath(ctx, root, filepath.Join("archive", workspaceID))
 	if err != nil {
 		return "", err
@@ -574,7 +604,7 @@ func commitReopenPreSnapshot(ctx context.Context, root string, workspaceID strin
 		_, _ = gitutil.Run(ctx, root, "reset", "-q", "--", archiveArg)
 		return "", fmt.Errorf("unexpected staged path outside allowlist: %s", p)
 	}
-	if _, err := gitutil.Run(ctx, root, "commit", "--allow-empty", "--only", "-m", fmt.Sprintf("reopen-pre: %s", workspaceID), "--", archiveArg); err != nil {
+	if _, err := gitutil.Run(ctx, root, lc.gitArgs("reopen-pre", []string{"--allow-empty", "--only"}, archiveArg)...); err != nil {
 		_, _ = gitutil.Run(ctx, root, "reset", "-q", "--", archiveArg)
 		return "", err
 	}
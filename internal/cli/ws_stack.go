@@ -0,0 +1,681 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/core/workspacerisk"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/infra/paths"
+)
+
+// Per-repo outcomes of ws stack rebase.
+const (
+	wsStackRebased  = "rebased"
+	wsStackUpToDate = "up_to_date"
+	wsStackSkipped  = "skipped"
+	wsStackFailed   = "failed"
+)
+
+// wsStackBranch is the live state of one stacked branch relative to its parent.
+type wsStackBranch struct {
+	Branch string
+	// Parent is base_ref for the bottom branch, otherwise the branch below.
+	Parent string
+	// Ahead counts commits of the branch not in its parent.
+	Ahead int
+	// NeedsRestack is set when the parent tip is not an ancestor of the branch.
+	NeedsRestack bool
+	Current      bool
+	State        workspacerisk.RepoState
+}
+
+type wsStackRebaseItem struct {
+	Alias   string
+	Stack   []string
+	Rebased []string
+	Result  string
+	Reason  string
+}
+
+type wsStackOptions struct {
+	OutputFormat string
+	WorkspaceID  string
+	UseCurrent   bool
+	Args         []string
+}
+
+// wsStackTarget is the resolved active workspace a ws stack subcommand operates on.
+type wsStackTarget struct {
+	Root        string
+	WorkspaceID string
+	WsPath      string
+	Meta        workspaceMetaFile
+}
+
+func (c *CLI) runWSStack(args []string) int {
+	if len(args) == 0 {
+		c.printWSStackUsage(c.Err)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "-h", "--help", "help":
+		c.printWSStackUsage(c.Out)
+		return exitOK
+	case "list":
+		return c.runWSStackList(args[1:])
+	case "push":
+		return c.runWSStackPush(args[1:])
+	case "rebase":
+		return c.runWSStackRebase(args[1:])
+	default:
+		fmt.Fprintf(c.Err, "unknown command: %q\n", strings.Join(append([]string{"ws", "stack"}, args[0]), " "))
+		c.printWSStackUsage(c.Err)
+		return exitUsage
+	}
+}
+
+// parseWSStackArgs parses the flags shared by ws stack subcommands; positional args are returned in order.
+// ok=false means the caller should return code.
+func (c *CLI) parseWSStackArgs(command string, args []string, usage func(io.Writer)) (opts wsStackOptions, code int, ok bool) {
+	opts.OutputFormat = "human"
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			usage(c.Out)
+			return opts, exitOK, false
+		case arg == "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				usage(c.Err)
+				return opts, exitUsage, false
+			}
+			opts.OutputFormat = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			opts.OutputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case arg == "--id":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--id requires a value")
+				usage(c.Err)
+				return opts, exitUsage, false
+			}
+			opts.WorkspaceID = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--id="):
+			opts.WorkspaceID = strings.TrimSpace(strings.TrimPrefix(arg, "--id="))
+		case arg == "--current":
+			opts.UseCurrent = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(c.Err, "unknown flag for %s: %q\n", command, arg)
+			usage(c.Err)
+			return opts, exitUsage, false
+		default:
+			opts.Args = append(opts.Args, arg)
+		}
+	}
+	switch opts.OutputFormat {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", opts.OutputFormat)
+		usage(c.Err)
+		return opts, exitUsage, false
+	}
+	if opts.WorkspaceID != "" && opts.UseCurrent {
+		fmt.Fprintln(c.Err, "--id and --current cannot be used together")
+		usage(c.Err)
+		return opts, exitUsage, false
+	}
+	if opts.WorkspaceID != "" {
+		if err := validateWorkspaceID(opts.WorkspaceID); err != nil {
+			fmt.Fprintf(c.Err, "invalid workspace id: %v\n", err)
+			return opts, exitUsage, false
+		}
+	}
+	return opts, exitOK, true
+}
+
+// resolveWSStackTarget resolves the active workspace and loads its meta; errCode is set on failure.
+func (c *CLI) resolveWSStackTarget(command string, workspaceID string) (wsStackTarget, string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return wsStackTarget{}, "internal_error", fmt.Errorf("get working dir: %w", err)
+	}
+	root, err := paths.ResolveExistingRoot(wd)
+	if err != nil {
+		return wsStackTarget{}, "not_found", fmt.Errorf("resolve KRA_ROOT: %w", err)
+	}
+	if err := c.ensureDebugLog(root, strings.ReplaceAll(command, " ", "-")); err != nil {
+		fmt.Fprintf(c.Err, "enable debug logging: %v\n", err)
+	}
+	workspaceID, errCode, err := resolveActiveWorkspaceTarget(root, wd, workspaceID, command)
+	if err != nil {
+		return wsStackTarget{}, errCode, err
+	}
+	wsPath := filepath.Join(root, "workspaces", workspaceID)
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		return wsStackTarget{}, "internal_error", fmt.Errorf("load %s: %w", workspaceMetaFilename, err)
+	}
+	return wsStackTarget{Root: root, WorkspaceID: workspaceID, WsPath: wsPath, Meta: meta}, "", nil
+}
+
+func (c *CLI) wsStackFail(jsonMode bool, action string, workspaceID string) func(code int, errCode string, msg string) int {
+	return func(code int, errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:          false,
+				Action:      action,
+				WorkspaceID: workspaceID,
+				Error:       &cliJSONError{Code: errCode, Message: msg},
+			})
+			return code
+		}
+		fmt.Fprintln(c.Err, msg)
+		return code
+	}
+}
+
+func (c *CLI) runWSStackList(args []string) int {
+	opts, code, ok := c.parseWSStackArgs("ws stack list", args, c.printWSStackListUsage)
+	if !ok {
+		return code
+	}
+	if len(opts.Args) > 1 {
+		fmt.Fprintf(c.Err, "unexpected args for ws stack list: %q\n", strings.Join(opts.Args[1:], " "))
+		c.printWSStackListUsage(c.Err)
+		return exitUsage
+	}
+	jsonMode := opts.OutputFormat == "json"
+	fail := c.wsStackFail(jsonMode, "ws.stack.list", opts.WorkspaceID)
+	target, errCode, err := c.resolveWSStackTarget("ws stack list", opts.WorkspaceID)
+	if err != nil {
+		return fail(exitError, errCode, err.Error())
+	}
+	fail = c.wsStackFail(jsonMode, "ws.stack.list", target.WorkspaceID)
+	alias := ""
+	if len(opts.Args) == 1 {
+		alias = opts.Args[0]
+		if !slices.ContainsFunc(target.Meta.ReposRestore, func(r workspaceMetaRepoRestore) bool { return r.Alias == alias }) {
+			return fail(exitUsage, "invalid_argument", fmt.Sprintf("repo not bound to workspace %s: %s", target.WorkspaceID, alias))
+		}
+	}
+
+	ctx := context.Background()
+	type stackRow struct {
+		Alias    string
+		BaseRef  string
+		Branches []wsStackBranch
+	}
+	rows := make([]stackRow, 0, len(target.Meta.ReposRestore))
+	for _, r := range target.Meta.ReposRestore {
+		if len(r.Stack) == 0 || (alias != "" && r.Alias != alias) {
+			continue
+		}
+		worktreePath := filepath.Join(target.WsPath, "repos", r.Alias)
+		current := ""
+		if out, err := gitutil.Run(ctx, worktreePath, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+			current = strings.TrimSpace(out)
+		}
+		rows = append(rows, stackRow{Alias: r.Alias, BaseRef: r.BaseRef, Branches: inspectWSStack(ctx, worktreePath, r.BaseRef, r.Stack, current)})
+	}
+
+	if jsonMode {
+		items := make([]map[string]any, 0, len(rows))
+		for _, row := range rows {
+			items = append(items, map[string]any{
+				"alias":    row.Alias,
+				"base_ref": row.BaseRef,
+				"branches": wsStackBranchesJSON(row.Branches),
+			})
+		}
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:          true,
+			Action:      "ws.stack.list",
+			WorkspaceID: target.WorkspaceID,
+			Result:      map[string]any{"items": items},
+		})
+		return exitOK
+	}
+	useColor := writerSupportsColor(c.Out)
+	bullet := styleMuted("•", useColor)
+	body := make([]string, 0, len(rows)*4)
+	for _, row := range rows {
+		body = append(body, fmt.Sprintf("%s%s %s %s", uiIndent, bullet, row.Alias, styleMuted("(base: "+row.BaseRef+")", useColor)))
+		for i, b := range row.Branches {
+			connector := "├─ "
+			if i == len(row.Branches)-1 {
+				connector = "└─ "
+			}
+			body = append(body, fmt.Sprintf("%s%s%s", uiIndent+uiIndent, styleMuted(connector, useColor), renderWSStackBranchLine(b, useColor)))
+		}
+	}
+	if len(body) == 0 {
+		body = append(body, fmt.Sprintf("%s%s %s", uiIndent, styleMuted("-", useColor), styleMuted("no stacks (start one with kra ws stack push <alias> <branch>)", useColor)))
+	}
+	printSection(c.Out, styleBold("Stacks:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+	return exitOK
+}
+
+func (c *CLI) runWSStackPush(args []string) int {
+	opts, code, ok := c.parseWSStackArgs("ws stack push", args, c.printWSStackPushUsage)
+	if !ok {
+		return code
+	}
+	if len(opts.Args) != 2 {
+		if len(opts.Args) > 2 {
+			fmt.Fprintf(c.Err, "unexpected args for ws stack push: %q\n", strings.Join(opts.Args[2:], " "))
+		} else {
+			fmt.Fprintln(c.Err, "ws stack push requires <alias> and <branch>")
+		}
+		c.printWSStackPushUsage(c.Err)
+		return exitUsage
+	}
+	alias, branch := opts.Args[0], opts.Args[1]
+	jsonMode := opts.OutputFormat == "json"
+	fail := c.wsStackFail(jsonMode, "ws.stack.push", opts.WorkspaceID)
+	target, errCode, err := c.resolveWSStackTarget("ws stack push", opts.WorkspaceID)
+	if err != nil {
+		return fail(exitError, errCode, err.Error())
+	}
+	fail = c.wsStackFail(jsonMode, "ws.stack.push", target.WorkspaceID)
+
+	ctx := context.Background()
+	restoreIdx := slices.IndexFunc(target.Meta.ReposRestore, func(r workspaceMetaRepoRestore) bool { return r.Alias == alias })
+	worktreePath := filepath.Join(target.WsPath, "repos", alias)
+	if fi, err := os.Stat(worktreePath); err != nil || !fi.IsDir() || restoreIdx < 0 {
+		return fail(exitUsage, "invalid_argument", fmt.Sprintf("repo not bound to workspace %s: %s", target.WorkspaceID, alias))
+	}
+	restore := target.Meta.ReposRestore[restoreIdx]
+	if restore.Reference {
+		return fail(exitError, "conflict", fmt.Sprintf("reference repo has no branch: %s", alias))
+	}
+	if err := gitutil.CheckRefFormat(ctx, "refs/heads/"+branch); err != nil {
+		return fail(exitUsage, "invalid_argument", fmt.Sprintf("invalid branch name: %q", branch))
+	}
+	out, err := gitutil.Run(ctx, worktreePath, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return fail(exitError, "conflict", fmt.Sprintf("worktree HEAD is detached: %s", alias))
+	}
+	current := strings.TrimSpace(out)
+	stack := slices.Clone(restore.Stack)
+	if len(stack) == 0 {
+		stack = []string{current}
+	}
+	parent := stack[len(stack)-1]
+	if current != parent {
+		return fail(exitError, "conflict", fmt.Sprintf("%s is on %s, not the stack top %s (kra ws branch %s --switch %s)", alias, current, parent, alias, parent))
+	}
+	if slices.Contains(stack, branch) {
+		return fail(exitError, "conflict", fmt.Sprintf("branch is already in the stack: %s", branch))
+	}
+	snapshot := inspectGitRepoSnapshot(ctx, worktreePath)
+	if snapshot.Status.Error != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("inspect %s: %v", alias, snapshot.Status.Error))
+	}
+	if snapshot.Status.Dirty {
+		return fail(exitError, "conflict", fmt.Sprintf("worktree has uncommitted changes: %s (commit or stash first)", alias))
+	}
+	commonDir, err := worktreeCommonDir(ctx, worktreePath)
+	if err != nil {
+		return fail(exitError, "internal_error", err.Error())
+	}
+	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/origin/" + branch} {
+		exists, err := gitutil.ShowRefExistsBare(ctx, commonDir, ref)
+		if err != nil {
+			return fail(exitError, "internal_error", fmt.Sprintf("check branch %s: %v", branch, err))
+		}
+		if exists {
+			return fail(exitError, "conflict", fmt.Sprintf("branch already exists: %s", branch))
+		}
+	}
+	if _, err := gitutil.Run(ctx, worktreePath, "switch", "-c", branch); err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("create %s in %s: %v", branch, alias, err))
+	}
+	stack = append(stack, branch)
+	c.debugf("ws stack push id=%s alias=%s parent=%s branch=%s stack=%s", target.WorkspaceID, alias, parent, branch, strings.Join(stack, ","))
+
+	target.Meta.ReposRestore[restoreIdx].Branch = branch
+	target.Meta.ReposRestore[restoreIdx].Stack = stack
+	// The recorded PR belongs to the parent branch; ws pr create records one for the new top.
+	target.Meta.ReposRestore[restoreIdx].PullRequestURL = ""
+	if err := writeWorkspaceMetaFile(target.WsPath, target.Meta); err != nil {
+		return fail(exitError, "internal_error", fmt.Sprintf("write %s: %v", workspaceMetaFilename, err))
+	}
+
+	if jsonMode {
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:          true,
+			Action:      "ws.stack.push",
+			WorkspaceID: target.WorkspaceID,
+			Result: map[string]any{
+				"alias":  alias,
+				"branch": branch,
+				"parent": parent,
+				"stack":  stack,
+			},
+		})
+		return exitOK
+	}
+	useColor := writerSupportsColor(c.Out)
+	printResultSection(c.Out, useColor, fmt.Sprintf("%s %s %s → %s %s", styleSuccess("✔", useColor), alias, parent, branch, styleMuted("(stack: "+strings.Join(stack, " → ")+")", useColor)))
+	return exitOK
+}
+
+func (c *CLI) runWSStackRebase(args []string) int {
+	opts, code, ok := c.parseWSStackArgs("ws stack rebase", args, c.printWSStackRebaseUsage)
+	if !ok {
+		return code
+	}
+	if len(opts.Args) > 1 {
+		fmt.Fprintf(c.Err, "unexpected args for ws stack rebase: %q\n", strings.Join(opts.Args[1:], " "))
+		c.printWSStackRebaseUsage(c.Err)
+		return exitUsage
+	}
+	jsonMode := opts.OutputFormat == "json"
+	fail := c.wsStackFail(jsonMode, "ws.stack.rebase", opts.WorkspaceID)
+	target, errCode, err := c.resolveWSStackTarget("ws stack rebase", opts.WorkspaceID)
+	if err != nil {
+		return fail(exitError, errCode, err.Error())
+	}
+	fail = c.wsStackFail(jsonMode, "ws.stack.rebase", target.WorkspaceID)
+	alias := ""
+	if len(opts.Args) == 1 {
+		alias = opts.Args[0]
+		idx := slices.IndexFunc(target.Meta.ReposRestore, func(r workspaceMetaRepoRestore) bool { return r.Alias == alias })
+		if idx < 0 {
+			return fail(exitUsage, "invalid_argument", fmt.Sprintf("repo not bound to workspace %s: %s", target.WorkspaceID, alias))
+		}
+		if len(target.Meta.ReposRestore[idx].Stack) == 0 {
+			return fail(exitUsage, "invalid_argument", fmt.Sprintf("repo has no stack: %s", alias))
+		}
+	}
+
+	ctx := context.Background()
+	items := make([]wsStackRebaseItem, 0, len(target.Meta.ReposRestore))
+	for _, r := range target.Meta.ReposRestore {
+		if len(r.Stack) == 0 || (alias != "" && r.Alias != alias) {
+			continue
+		}
+		item := wsStackRebaseItem{Alias: r.Alias, Stack: r.Stack}
+		worktreePath := filepath.Join(target.WsPath, "repos", r.Alias)
+		if fi, err := os.Stat(worktreePath); err != nil || !fi.IsDir() {
+			item.Result, item.Reason = wsStackSkipped, "worktree missing"
+		} else {
+			item = rebaseWSStack(ctx, worktreePath, r.BaseRef, item)
+		}
+		c.debugf("ws stack rebase id=%s alias=%s result=%s reason=%s rebased=%s", target.WorkspaceID, item.Alias, item.Result, item.Reason, strings.Join(item.Rebased, ","))
+		items = append(items, item)
+	}
+
+	ok = !slices.ContainsFunc(items, func(it wsStackRebaseItem) bool { return it.Result == wsStackFailed })
+	if jsonMode {
+		rows := make([]map[string]any, 0, len(items))
+		rebased := 0
+		for _, it := range items {
+			if it.Result == wsStackRebased {
+				rebased++
+			}
+			rows = append(rows, map[string]any{
+				"alias":   it.Alias,
+				"stack":   it.Stack,
+				"rebased": nonNilStrings(it.Rebased),
+				"result":  it.Result,
+				"reason":  it.Reason,
+			})
+		}
+		resp := cliJSONResponse{
+			OK:          ok,
+			Action:      "ws.stack.rebase",
+			WorkspaceID: target.WorkspaceID,
+			Result: map[string]any{
+				"rebased": rebased,
+				"total":   len(items),
+				"items":   rows,
+			},
+		}
+		if !ok {
+			resp.Error = &cliJSONError{Code: "conflict", Message: "some stacks were not rebased"}
+		}
+		_ = writeCLIJSON(c.Out, resp)
+	} else {
+		printWSStackRebaseResult(c.Out, items, writerSupportsColor(c.Out))
+	}
+	if !ok {
+		return exitError
+	}
+	return exitOK
+}
+
+// rebaseWSStack replays every stacked branch onto its (possibly moved) parent, bottom-up.
+// A failed rebase is aborted and every branch is reset to its previous tip, so a repo is restacked all-or-nothing.
+func rebaseWSStack(ctx context.Context, worktreePath string, baseRef string, item wsStackRebaseItem) wsStackRebaseItem {
+	if strings.TrimSpace(baseRef) == "" {
+		item.Result, item.Reason = wsStackSkipped, "base_ref unknown"
+		return item
+	}
+	snapshot := inspectGitRepoSnapshot(ctx, worktreePath)
+	if snapshot.Status.Error != nil {
+		item.Result, item.Reason = wsStackFailed, fmt.Sprintf("inspect worktree: %v", snapshot.Status.Error)
+		return item
+	}
+	if snapshot.Status.Dirty {
+		item.Result, item.Reason = wsStackFailed, "worktree has uncommitted changes (commit or stash first)"
+		return item
+	}
+	out, err := gitutil.Run(ctx, worktreePath, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		item.Result, item.Reason = wsStackFailed, "worktree HEAD is detached"
+		return item
+	}
+	original := strings.TrimSpace(out)
+	commonDir, err := worktreeCommonDir(ctx, worktreePath)
+	if err != nil {
+		item.Result, item.Reason = wsStackFailed, err.Error()
+		return item
+	}
+
+	oldTips := make([]string, len(item.Stack))
+	for i, branch := range item.Stack {
+		tip, err := gitutil.Run(ctx, worktreePath, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+		if err != nil {
+			item.Result, item.Reason = wsStackFailed, fmt.Sprintf("stack branch not found: %s", branch)
+			return item
+		}
+		oldTips[i] = strings.TrimSpace(tip)
+		if branch == original {
+			continue
+		}
+		inUse, err := isBranchCheckedOutInBare(ctx, commonDir, branch)
+		if err != nil {
+			item.Result, item.Reason = wsStackFailed, fmt.Sprintf("check branch checkout status: %v", err)
+			return item
+		}
+		if inUse {
+			item.Result, item.Reason = wsStackFailed, fmt.Sprintf("branch is checked out by another worktree: %s", branch)
+			return item
+		}
+	}
+
+	restore := func() {
+		for i, branch := range item.Stack {
+			_, _ = gitutil.Run(ctx, worktreePath, "update-ref", "refs/heads/"+branch, oldTips[i])
+		}
+		_, _ = gitutil.Run(ctx, worktreePath, "switch", "--force", original)
+	}
+	for i, branch := range item.Stack {
+		parent := baseRef
+		if i > 0 {
+			parent = item.Stack[i-1]
+		}
+		if _, err := gitutil.Run(ctx, worktreePath, "merge-base", "--is-ancestor", parent, branch); err == nil {
+			continue
+		}
+		rebaseArgs := []string{"rebase", parent, branch}
+		if i > 0 {
+			// Replay only the commits above the parent's previous tip.
+			rebaseArgs = []string{"rebase", "--onto", parent, oldTips[i-1], branch}
+		}
+		if _, err := gitutil.Run(ctx, worktreePath, rebaseArgs...); err != nil {
+			_, _ = gitutil.Run(ctx, worktreePath, "rebase", "--abort")
+			restore()
+			item.Rebased = nil
+			item.Result, item.Reason = wsStackFailed, fmt.Sprintf("rebase %s onto %s failed (stack restored): %v", branch, parent, err)
+			return item
+		}
+		item.Rebased = append(item.Rebased, branch)
+	}
+	if _, err := gitutil.Run(ctx, worktreePath, "switch", original); err != nil {
+		item.Result, item.Reason = wsStackFailed, fmt.Sprintf("switch back to %s: %v", original, err)
+		return item
+	}
+	if len(item.Rebased) == 0 {
+		item.Result = wsStackUpToDate
+		return item
+	}
+	item.Result = wsStackRebased
+	return item
+}
+
+// inspectWSStack reports each stacked branch against its parent; current is the checked-out branch.
+func inspectWSStack(ctx context.Context, worktreePath string, baseRef string, stack []string, current string) []wsStackBranch {
+	out := make([]wsStackBranch, 0, len(stack))
+	for i, branch := range stack {
+		b := wsStackBranch{Branch: branch, Parent: baseRef, Current: branch == current}
+		if i > 0 {
+			b.Parent = stack[i-1]
+		}
+		b.State = stackBranchSyncState(ctx, worktreePath, branch)
+		if b.State != workspacerisk.RepoStateUnknown && strings.TrimSpace(b.Parent) != "" {
+			if n, err := gitutil.Run(ctx, worktreePath, "rev-list", "--count", b.Parent+".."+branch); err == nil {
+				b.Ahead, _ = strconv.Atoi(strings.TrimSpace(n))
+			}
+			if _, err := gitutil.Run(ctx, worktreePath, "merge-base", "--is-ancestor", b.Parent, branch); err != nil {
+				b.NeedsRestack = true
+			}
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// stackBranchSyncState classifies a branch against origin/<branch>; without a remote branch,
+// commits not on any remote ref count as unpushed.
+func stackBranchSyncState(ctx context.Context, worktreePath string, branch string) workspacerisk.RepoState {
+	status := workspacerisk.RepoStatus{}
+	localRef := "refs/heads/" + branch
+	if _, err := gitutil.Run(ctx, worktreePath, "rev-parse", "--verify", "--quiet", localRef); err != nil {
+		status.Error = fmt.Errorf("stack branch not found: %s", branch)
+		return workspacerisk.ClassifyStackBranchStatus(status)
+	}
+	remoteRef := "refs/remotes/origin/" + branch
+	if _, err := gitutil.Run(ctx, worktreePath, "rev-parse", "--verify", "--quiet", remoteRef); err == nil {
+		status.Upstream = "origin/" + branch
+		out, err := gitutil.Run(ctx, worktreePath, "rev-list", "--left-right", "--count", localRef+"..."+remoteRef)
+		if err != nil {
+			status.Error = err
+		} else if fields := strings.Fields(out); len(fields) == 2 {
+			status.AheadCount, _ = strconv.Atoi(fields[0])
+			status.BehindCount, _ = strconv.Atoi(fields[1])
+		}
+		return workspacerisk.ClassifyStackBranchStatus(status)
+	}
+	out, err := gitutil.Run(ctx, worktreePath, "rev-list", "--count", localRef, "--not", "--remotes")
+	if err != nil {
+		status.Error = err
+	} else {
+		status.AheadCount, _ = strconv.Atoi(strings.TrimSpace(out))
+	}
+	return workspacerisk.ClassifyStackBranchStatus(status)
+}
+
+// stackRiskState folds the states of stacked branches that are not checked out into the repo state,
+// so close/purge gates see unpushed work on every branch of the stack.
+func stackRiskState(state workspacerisk.RepoState, branches []wsStackBranch) workspacerisk.RepoState {
+	states := []workspacerisk.RepoState{state}
+	for _, b := range branches {
+		if !b.Current {
+			states = append(states, b.State)
+		}
+	}
+	return workspacerisk.MostSevereRepoState(states...)
+}
+
+func wsStackBranchesJSON(branches []wsStackBranch) []map[string]any {
+	out := make([]map[string]any, 0, len(branches))
+	for _, b := range branches {
+		out = append(out, map[string]any{
+			"branch":        b.Branch,
+			"parent":        b.Parent,
+			"ahead":         b.Ahead,
+			"needs_restack": b.NeedsRestack,
+			"current":       b.Current,
+			"state":         string(b.State),
+		})
+	}
+	return out
+}
+
+func renderWSStackBranchLine(b wsStackBranch, useColor bool) string {
+	name := b.Branch
+	if b.Current {
+		name = styleAccent("*"+b.Branch, useColor)
+	}
+	line := fmt.Sprintf("%s %s %s", name, styleMuted(fmt.Sprintf("+%d", b.Ahead), useColor), renderRepoRiskState(b.State, useColor))
+	if b.NeedsRestack {
+		line += " " + styleWarn("needs restack onto "+b.Parent, useColor)
+	}
+	return line
+}
+
+// renderWSStackSummary is the one-line stack rendering used by ws status and ws close plans.
+func renderWSStackSummary(branches []wsStackBranch, useColor bool) string {
+	names := make([]string, 0, len(branches))
+	restack := make([]string, 0, len(branches))
+	for _, b := range branches {
+		name := b.Branch
+		if b.Current {
+			name = "*" + name
+		}
+		names = append(names, name+" "+renderRepoRiskState(b.State, useColor))
+		if b.NeedsRestack {
+			restack = append(restack, b.Branch)
+		}
+	}
+	line := strings.Join(names, " → ")
+	if len(restack) > 0 {
+		line += " " + styleWarn("(needs restack: "+strings.Join(restack, ", ")+")", useColor)
+	}
+	return line
+}
+
+func printWSStackRebaseResult(out io.Writer, items []wsStackRebaseItem, useColor bool) {
+	lines := make([]string, 0, len(items))
+	for _, it := range items {
+		switch it.Result {
+		case wsStackRebased:
+			lines = append(lines, fmt.Sprintf("%s %s rebased %s", styleSuccess("✔", useColor), it.Alias, strings.Join(it.Rebased, ", ")))
+		case wsStackFailed:
+			lines = append(lines, fmt.Sprintf("%s %s %s", styleError("!", useColor), it.Alias, it.Reason))
+		case wsStackUpToDate:
+			lines = append(lines, fmt.Sprintf("%s %s up to date", styleMuted("-", useColor), it.Alias))
+		default:
+			lines = append(lines, fmt.Sprintf("%s %s skipped (%s)", styleMuted("-", useColor), it.Alias, it.Reason))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, fmt.Sprintf("%s no stacks", styleMuted("-", useColor)))
+	}
+	printResultSection(out, useColor, lines...)
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_Stack_PushListRebase_AndCloseRisk(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	gitOut := func(dir string, args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+	commitFile := func(dir string, name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(dir, "add", name)
+		runGit(dir, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-m", "add "+name)
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	repoSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	if code, _, stderr := run("repo", "add", repoSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", "example-org/api", "--yes"); code != exitOK {
+		t.Fatalf("ws add-repo exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	wsPath := filepath.Join(env.Root, "workspaces", "WS1")
+	repoPath := filepath.Join(wsPath, "repos", "api")
+	commitFile(repoPath, "a.txt")
+
+	if code, _, stderr := run("ws", "stack", "push", "--id", "WS1", "api"); code != exitUsage || !strings.Contains(stderr, "requires <alias> and <branch>") {
+		t.Fatalf("stack push without branch: code=%d stderr=%q", code, stderr)
+	}
+	// A PR recorded for the bottom branch must not be attributed to the pushed branch.
+	seed, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		t.Fatalf("load %s: %v", workspaceMetaFilename, err)
+	}
+	seed.ReposRestore[0].PullRequestURL = "https://github.com/example-org/api/pull/1"
+	if err := writeWorkspaceMetaFile(wsPath, seed); err != nil {
+		t.Fatalf("write %s: %v", workspaceMetaFilename, err)
+	}
+	code, out, stderr := run("ws", "stack", "push", "--id", "WS1", "api", "WS1-part2", "--format", "json")
+	if code != exitOK || !strings.Contains(out, `"stack":["WS1","WS1-part2"]`) {
+		t.Fatalf("ws stack push: code=%d out=%s stderr=%q", code, out, stderr)
+	}
+	if got := gitOut(repoPath, "branch", "--show-current"); got != "WS1-part2" {
+		t.Fatalf("checked-out branch = %q, want WS1-part2", got)
+	}
+	meta, err := loadWorkspaceMetaFile(wsPath)
+	if err != nil {
+		t.Fatalf("load %s: %v", workspaceMetaFilename, err)
+	}
+	if len(meta.ReposRestore) != 1 || meta.ReposRestore[0].Branch != "WS1-part2" || !slices.Equal(meta.ReposRestore[0].Stack, []string{"WS1", "WS1-part2"}) || meta.ReposRestore[0].PullRequestURL != "" {
+		t.Fatalf("repos_restore = %+v, want branch WS1-part2, stack [WS1 WS1-part2] and no PR URL", meta.ReposRestore)
+	}
+	commitFile(repoPath, "b.txt")
+
+	// Push both, then add a commit to the bottom branch only: the checked-out top is clean,
+	// but the bottom branch of the stack holds unpushed work.
+	runGit(repoPath, "push", "origin", "WS1")
+	runGit(repoPath, "push", "-u", "origin", "WS1-part2")
+	runGit(repoPath, "switch", "WS1")
+	commitFile(repoPath, "c.txt")
+	runGit(repoPath, "switch", "WS1-part2")
+	code, out, _ = run("ws", "close", "--id", "WS1", "--format", "json", "--dry-run")
+	if code == exitOK || !strings.Contains(out, `"workspace":"unpushed"`) {
+		t.Fatalf("ws close should see the unpushed bottom branch: code=%d out=%s", code, out)
+	}
+	if code, out, _ := run("ws", "status", "--id", "WS1", "--format", "json"); code != exitOK || !strings.Contains(out, `"stack":[`) || !strings.Contains(out, `"state":"unpushed"`) {
+		t.Fatalf("ws status should report the stack: code=%d out=%s", code, out)
+	}
+
+	// Move base_ref: push a new commit to origin/main, then fetch.
+	remoteBare := strings.TrimPrefix(repoSpec, "file://")
+	work := filepath.Join(t.TempDir(), "work")
+	runGit("", "clone", remoteBare, work)
+	commitFile(work, "main.txt")
+	runGit(work, "push", "origin", "main")
+	runGit(repoPath, "fetch", "origin")
+
+	type stackListResp struct {
+		Result struct {
+			Items []struct {
+				Alias    string `json:"alias"`
+				Branches []struct {
+					Branch       string `json:"branch"`
+					Parent       string `json:"parent"`
+					Ahead        int    `json:"ahead"`
+					NeedsRestack bool   `json:"needs_restack"`
+					Current      bool   `json:"current"`
+				} `json:"branches"`
+			} `json:"items"`
+		} `json:"result"`
+	}
+	listStack := func() stackListResp {
+		t.Helper()
+		code, out, stderr := run("ws", "stack", "list", "--id", "WS1", "--format", "json")
+		if code != exitOK {
+			t.Fatalf("ws stack list: code=%d out=%s stderr=%q", code, out, stderr)
+		}
+		var resp stackListResp
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			t.Fatalf("decode ws stack list: %v (out=%s)", err, out)
+		}
+		if len(resp.Result.Items) != 1 || len(resp.Result.Items[0].Branches) != 2 {
+			t.Fatalf("ws stack list items = %s", out)
+		}
+		return resp
+	}
+	before := listStack().Result.Items[0].Branches
+	if !before[0].NeedsRestack || !before[1].NeedsRestack || before[0].Parent != "origin/main" || before[1].Parent != "WS1" || !before[1].Current || before[1].Ahead != 1 {
+		t.Fatalf("stack before rebase = %+v", before)
+	}
+
+	code, out, stderr = run("ws", "stack", "rebase", "--id", "WS1", "--format", "json")
+	if code != exitOK || !strings.Contains(out, `"rebased":["WS1","WS1-part2"]`) {
+		t.Fatalf("ws stack rebase: code=%d out=%s stderr=%q", code, out, stderr)
+	}
+	after := listStack().Result.Items[0].Branches
+	if after[0].NeedsRestack || after[1].NeedsRestack || after[0].Ahead != 2 || after[1].Ahead != 1 {
+		t.Fatalf("stack after rebase = %+v", after)
+	}
+	if got := gitOut(repoPath, "branch", "--show-current"); got != "WS1-part2" {
+		t.Fatalf("checked-out branch after rebase = %q, want WS1-part2", got)
+	}
+	for _, name := range []string{"a.txt", "b.txt", "c.txt", "main.txt"} {
+		if _, err := os.Stat(filepath.Join(repoPath, name)); err != nil {
+			t.Fatalf("%s should exist on the restacked top: %v", name, err)
+		}
+	}
+	if code, out, _ := run("ws", "stack", "rebase", "--id", "WS1", "--format", "json"); code != exitOK || !strings.Contains(out, `"result":"up_to_date"`) {
+		t.Fatalf("second rebase should be up to date: code=%d out=%s", code, out)
+	}
+}
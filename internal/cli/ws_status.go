@@ -0,0 +1,511 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tasuku43/kra/internal/core/workspacerisk"
+	"github.com/tasuku43/kra/internal/infra/gitutil"
+	"github.com/tasuku43/kra/internal/infra/paths"
+)
+
+// wsStatusConcurrency bounds concurrent repo inspections of one workspace.
+const wsStatusConcurrency = 8
+
+type wsStatusRepo struct {
+	Alias    string
+	RepoUID  string
+	RepoKey  string
+	Branch   string
+	Upstream string
+	Ahead    int
+	Behind   int
+	BaseRef  string
+	// BaseAhead/BaseBehind are commits of HEAD not in base_ref / of base_ref not in HEAD.
+	BaseAhead  int
+	BaseBehind int
+	BaseErr    error
+	Staged     []string
+	Unstaged   []string
+	Untracked  []string
+	Stashes    int
+	State      workspacerisk.RepoState
+	Missing    bool
+	// Reference marks a read-only binding detached at base_ref.
+	Reference bool
+	// StackDef is repos_restore[].stack; Stack is its live state (nil without a stack).
+	StackDef []string
+	Stack    []wsStackBranch
+	Err      error
+}
+
+type wsStatusSummary struct {
+	Risk      workspacerisk.WorkspaceRisk
+	Repos     int
+	States    map[workspacerisk.RepoState]int
+	Staged    int
+	Unstaged  int
+	Untracked int
+	Stashes   int
+}
+
+func (c *CLI) runWSStatus(args []string) int {
+	outputFormat := "human"
+	workspaceID := ""
+	useCurrent := false
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "help":
+			c.printWSStatusUsage(c.Out)
+			return exitOK
+		case arg == "--format":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--format requires a value")
+				c.printWSStatusUsage(c.Err)
+				return exitUsage
+			}
+			outputFormat = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimSpace(strings.TrimPrefix(arg, "--format="))
+		case arg == "--id":
+			if i+1 >= len(args) {
+				fmt.Fprintln(c.Err, "--id requires a value")
+				c.printWSStatusUsage(c.Err)
+				return exitUsage
+			}
+			workspaceID = strings.TrimSpace(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--id="):
+			workspaceID = strings.TrimSpace(strings.TrimPrefix(arg, "--id="))
+		case arg == "--current":
+			useCurrent = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(c.Err, "unknown flag for ws status: %q\n", arg)
+			c.printWSStatusUsage(c.Err)
+			return exitUsage
+		default:
+			if workspaceID != "" {
+				fmt.Fprintf(c.Err, "unexpected args for ws status: %q\n", arg)
+				c.printWSStatusUsage(c.Err)
+				return exitUsage
+			}
+			workspaceID = arg
+		}
+	}
+	switch outputFormat {
+	case "human", "json":
+	default:
+		fmt.Fprintf(c.Err, "unsupported --format: %q (supported: human, json)\n", outputFormat)
+		c.printWSStatusUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" && useCurrent {
+		fmt.Fprintln(c.Err, "--id and --current cannot be used together")
+		c.printWSStatusUsage(c.Err)
+		return exitUsage
+	}
+	if workspaceID != "" {
+		if err := validateWorkspaceID(workspaceID); err != nil {
+			fmt.Fprintf(c.Err, "invalid workspace id: %v\n", err)
+			return exitUsage
+		}
+	}
+	jsonMode := outputFormat == "json"
+	fail := func(errCode string, msg string) int {
+		if jsonMode {
+			_ = writeCLIJSON(c.Out, cliJSONResponse{
+				OK:          false,
+				Action:      "ws.status",
+				WorkspaceID: workspaceID,
+				Error:       &cliJSONError{Code: errCode, Message: msg},
+			})
+			return exitError
+		}
+		fmt.Fprintln(c.Err, msg)
+		return exitError
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail("internal_error", fmt.Sprintf("get working dir: %v", err))
+	}
+	root, err := paths.ResolveExistingRoot(wd)
+	if err != nil {
+		return fail("not_found", fmt.Sprintf("resolve KRA_ROOT: %v", err))
+	}
+	if err := c.ensureDebugLog(root, "ws-status"); err != nil {
+		fmt.Fprintf(c.Err, "enable debug logging: %v\n", err)
+	}
+	workspaceID, errCode, err := resolveActiveWorkspaceTarget(root, wd, workspaceID, "ws status")
+	if err != nil {
+		return fail(errCode, err.Error())
+	}
+
+	ctx := context.Background()
+	repos, err := collectWSStatusRepos(ctx, root, workspaceID)
+	if err != nil {
+		return fail("internal_error", fmt.Sprintf("inspect workspace repos: %v", err))
+	}
+	summary := summarizeWSStatusRepos(repos)
+	c.debugf("run ws status id=%s repos=%d risk=%s", workspaceID, len(repos), summary.Risk)
+
+	if jsonMode {
+		_ = writeCLIJSON(c.Out, cliJSONResponse{
+			OK:          true,
+			Action:      "ws.status",
+			WorkspaceID: workspaceID,
+			Result:      wsStatusJSONResult(summary, repos),
+		})
+		return exitOK
+	}
+	printWSStatus(c.Out, workspaceID, summary, repos, writerSupportsColor(c.Out))
+	return exitOK
+}
+
+// resolveActiveWorkspaceTarget returns workspaceID, or the active workspace containing wd when it is empty.
+// On failure it also returns the JSON error code.
+func resolveActiveWorkspaceTarget(root string, wd string, workspaceID string, command string) (string, string, error) {
+	if workspaceID == "" {
+		resolved, ok := detectWorkspaceFromCWD(root, wd)
+		if !ok || resolved.Status != "active" {
+			return "", "invalid_argument", fmt.Errorf("%s requires --id <id> or current path under workspaces/<id>/...", command)
+		}
+		workspaceID = resolved.ID
+	}
+	if fi, err := os.Stat(filepath.Join(root, "workspaces", workspaceID)); err != nil || !fi.IsDir() {
+		return workspaceID, "not_found", fmt.Errorf("workspace not found: %s", workspaceID)
+	}
+	return workspaceID, "", nil
+}
+
+// collectWSStatusRepos inspects every repo bound to an active workspace concurrently; results keep alias order.
+func collectWSStatusRepos(ctx context.Context, root string, workspaceID string) ([]wsStatusRepo, error) {
+	wsPath := filepath.Join(root, "workspaces", workspaceID)
+	meta, _ := loadWorkspaceMetaFile(wsPath)
+	bound, err := listWorkspaceReposFromFilesystem(ctx, root, "active", workspaceID, meta)
+	if err != nil {
+		return nil, err
+	}
+	keyByAlias := make(map[string]string, len(meta.ReposRestore))
+	for _, r := range meta.ReposRestore {
+		keyByAlias[strings.TrimSpace(r.Alias)] = workspaceBranchRepoKey(r.RepoUID, r.RepoKey)
+	}
+
+	out := make([]wsStatusRepo, len(bound))
+	sem := make(chan struct{}, wsStatusConcurrency)
+	var wg sync.WaitGroup
+	for i, r := range bound {
+		out[i] = wsStatusRepo{
+			Alias:     r.Alias,
+			RepoUID:   r.RepoUID,
+			RepoKey:   keyByAlias[r.Alias],
+			Branch:    r.Branch,
+			BaseRef:   r.BaseRef,
+			State:     workspacerisk.RepoStateUnknown,
+			Missing:   r.MissingAt.Valid,
+			Reference: r.Reference,
+			StackDef:  r.Stack,
+		}
+		if out[i].Missing {
+			continue
+		}
+		wg.Add(1)
+		go func(item *wsStatusRepo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			inspectWSStatusRepo(ctx, filepath.Join(wsPath, "repos", item.Alias), item)
+		}(&out[i])
+	}
+	wg.Wait()
+	return out, nil
+}
+
+func inspectWSStatusRepo(ctx context.Context, worktreePath string, item *wsStatusRepo) {
+	snapshot := inspectGitRepoSnapshot(ctx, worktreePath)
+	item.State = workspacerisk.ClassifyRepoStatus(snapshot.Status)
+	if item.Reference {
+		item.State = workspacerisk.ClassifyReferenceRepoStatus(snapshot.Status)
+	}
+	if snapshot.Status.Error != nil {
+		item.Err = snapshot.Status.Error
+		return
+	}
+	if snapshot.Branch != "" {
+		item.Branch = snapshot.Branch
+	} else if snapshot.Status.Detached {
+		item.Branch = ""
+	}
+	item.Upstream = snapshot.Status.Upstream
+	item.Ahead = snapshot.Status.AheadCount
+	item.Behind = snapshot.Status.BehindCount
+	item.Staged, item.Unstaged, item.Untracked = splitGitSnapshotFiles(snapshot.Files)
+
+	if item.BaseRef != "" {
+		out, err := gitutil.Run(ctx, worktreePath, "rev-list", "--left-right", "--count", "HEAD..."+item.BaseRef)
+		if err == nil {
+			ahead, behind, parseErr := parseRevListLeftRightCount(out)
+			item.BaseAhead, item.BaseBehind, item.BaseErr = ahead, behind, parseErr
+		} else {
+			item.BaseErr = err
+		}
+	}
+	item.Stashes = countBranchStashes(ctx, worktreePath, item.Branch)
+	if len(item.StackDef) > 0 && !item.Reference {
+		item.Stack = inspectWSStack(ctx, worktreePath, item.BaseRef, item.StackDef, item.Branch)
+		item.State = stackRiskState(item.State, item.Stack)
+	}
+}
+
+// splitGitSnapshotFiles splits gitRepoSnapshot.Files ("XY path") into staged, unstaged and untracked paths.
+// A file with both index and worktree changes appears in staged and unstaged.
+func splitGitSnapshotFiles(files []string) (staged []string, unstaged []string, untracked []string) {
+	staged, unstaged, untracked = []string{}, []string{}, []string{}
+	for _, f := range files {
+		if len(f) < 4 {
+			continue
+		}
+		x, y, path := f[0], f[1], f[3:]
+		switch {
+		case x == '?' && y == '?':
+			untracked = append(untracked, path)
+		case x == 'S':
+			// Submodule with unpushed commits (see inspectGitRepoSnapshot).
+			unstaged = append(unstaged, path)
+		default:
+			if x != ' ' {
+				staged = append(staged, path)
+			}
+			if y != ' ' {
+				unstaged = append(unstaged, path)
+			}
+		}
+	}
+	return staged, unstaged, untracked
+}
+
+func parseRevListLeftRightCount(out string) (int, int, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list count output: %q", strings.TrimSpace(out))
+	}
+	left, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	right, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return left, right, nil
+}
+
+// countBranchStashes counts stash entries created on branch.
+// refs/stash is shared by every worktree of the bare repo, so entries are matched by their "On <branch>:" subject.
+func countBranchStashes(ctx context.Context, worktreePath string, branch string) int {
+	if branch == "" {
+		return 0
+	}
+	out, err := gitutil.Run(ctx, worktreePath, "stash", "list", "--format=%gs")
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "WIP on "+branch+":") || strings.HasPrefix(line, "On "+branch+":") {
+			n++
+		}
+	}
+	return n
+}
+
+func summarizeWSStatusRepos(repos []wsStatusRepo) wsStatusSummary {
+	summary := wsStatusSummary{Repos: len(repos), States: map[workspacerisk.RepoState]int{}}
+	states := make([]workspacerisk.RepoState, 0, len(repos))
+	for _, r := range repos {
+		states = append(states, r.State)
+		summary.States[r.State]++
+		summary.Staged += len(r.Staged)
+		summary.Unstaged += len(r.Unstaged)
+		summary.Untracked += len(r.Untracked)
+		summary.Stashes += r.Stashes
+	}
+	summary.Risk = workspacerisk.Aggregate(states)
+	return summary
+}
+
+func wsStatusJSONResult(summary wsStatusSummary, repos []wsStatusRepo) map[string]any {
+	rows := make([]map[string]any, 0, len(repos))
+	for _, r := range repos {
+		row := map[string]any{
+			"alias":       r.Alias,
+			"repo_uid":    r.RepoUID,
+			"repo_key":    r.RepoKey,
+			"branch":      r.Branch,
+			"upstream":    r.Upstream,
+			"ahead":       r.Ahead,
+			"behind":      r.Behind,
+			"base_ref":    r.BaseRef,
+			"base_ahead":  r.BaseAhead,
+			"base_behind": r.BaseBehind,
+			"staged":      nonNilStrings(r.Staged),
+			"unstaged":    nonNilStrings(r.Unstaged),
+			"untracked":   nonNilStrings(r.Untracked),
+			"stash_count": r.Stashes,
+			"state":       string(r.State),
+			"missing":     r.Missing,
+		}
+		if r.Reference {
+			row["reference"] = true
+		}
+		if len(r.Stack) > 0 {
+			row["stack"] = wsStackBranchesJSON(r.Stack)
+		}
+		if r.Err != nil {
+			row["error"] = r.Err.Error()
+		}
+		if r.BaseErr != nil {
+			row["base_error"] = r.BaseErr.Error()
+		}
+		rows = append(rows, row)
+	}
+	states := map[string]int{}
+	for state, n := range summary.States {
+		states[string(state)] = n
+	}
+	return map[string]any{
+		"summary": map[string]any{
+			"risk":      string(summary.Risk),
+			"repos":     summary.Repos,
+			"states":    states,
+			"staged":    summary.Staged,
+			"unstaged":  summary.Unstaged,
+			"untracked": summary.Untracked,
+			"stashes":   summary.Stashes,
+		},
+		"repos": rows,
+	}
+}
+
+func nonNilStrings(v []string) []string {
+	if v == nil {
+		return []string{}
+	}
+	return v
+}
+
+// renderWSStatusSummaryLine renders the one-line workspace summary, e.g.
+// "WS1 risk:dirty repos:2 (dirty:1 clean:1) files: +1 ~2 ?1 stash:0".
+func renderWSStatusSummaryLine(workspaceID string, summary wsStatusSummary, useColor bool) string {
+	stateParts := make([]string, 0, 5)
+	for _, state := range []workspacerisk.RepoState{
+		workspacerisk.RepoStateDirty,
+		workspacerisk.RepoStateDiverged,
+		workspacerisk.RepoStateUnpushed,
+		workspacerisk.RepoStateUnknown,
+		workspacerisk.RepoStateClean,
+	} {
+		if n := summary.States[state]; n > 0 {
+			stateParts = append(stateParts, fmt.Sprintf("%s:%d", state, n))
+		}
+	}
+	line := fmt.Sprintf("%s %s:%s %s:%d", workspaceID,
+		styleMuted("risk", useColor), renderDashboardWorkspaceRisk(summary.Risk, useColor),
+		styleMuted("repos", useColor), summary.Repos)
+	if len(stateParts) > 0 {
+		line += " " + styleMuted("("+strings.Join(stateParts, " ")+")", useColor)
+	}
+	line += fmt.Sprintf(" %s: +%d ~%d ?%d %s:%d",
+		styleMuted("files", useColor), summary.Staged, summary.Unstaged, summary.Untracked,
+		styleMuted("stash", useColor), summary.Stashes)
+	return line
+}
+
+func printWSStatus(out io.Writer, workspaceID string, summary wsStatusSummary, repos []wsStatusRepo, useColor bool) {
+	bullet := styleMuted("•", useColor)
+	printSection(out, styleBold("Workspace:", useColor), []string{
+		fmt.Sprintf("%s%s %s", uiIndent, bullet, renderWSStatusSummaryLine(workspaceID, summary, useColor)),
+	}, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     true,
+	})
+
+	body := make([]string, 0, len(repos)*4)
+	for _, r := range repos {
+		heading := fmt.Sprintf("%s%s %s", uiIndent, bullet, r.Alias)
+		if r.RepoKey != "" && r.RepoKey != r.Alias {
+			heading += " " + styleMuted("("+r.RepoKey+")", useColor)
+		}
+		branch := r.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		if r.Reference {
+			branch = "(reference)"
+		}
+		heading += " " + styleAccent(branch, useColor) + " " + renderRepoRiskState(r.State, useColor)
+		body = append(body, heading)
+
+		details := make([]string, 0, 6)
+		switch {
+		case r.Missing:
+			details = append(details, styleError("worktree missing", useColor))
+		case r.Err != nil:
+			details = append(details, styleError(fmt.Sprintf("error: %v", r.Err), useColor))
+		default:
+			upstream := styleMuted("(none)", useColor)
+			if r.Upstream != "" {
+				upstream = fmt.Sprintf("%s %s", r.Upstream, formatAheadBehind(r.Ahead, r.Behind, useColor))
+			}
+			details = append(details, fmt.Sprintf("%s %s", styleAccent("upstream:", useColor), upstream))
+			if r.BaseRef != "" {
+				base := r.BaseRef
+				if r.BaseErr != nil {
+					base += " " + styleWarn("(unresolved)", useColor)
+				} else {
+					base += " " + formatAheadBehind(r.BaseAhead, r.BaseBehind, useColor)
+				}
+				details = append(details, fmt.Sprintf("%s %s", styleAccent("base:", useColor), base))
+			}
+			if len(r.Stack) > 0 {
+				details = append(details, fmt.Sprintf("%s %s", styleAccent("stack:", useColor), renderWSStackSummary(r.Stack, useColor)))
+			}
+			for _, group := range []struct {
+				label string
+				files []string
+			}{
+				{"staged:", r.Staged},
+				{"unstaged:", r.Unstaged},
+				{"untracked:", r.Untracked},
+			} {
+				if len(group.files) > 0 {
+					details = append(details, fmt.Sprintf("%s %s", styleAccent(group.label, useColor), strings.Join(group.files, ", ")))
+				}
+			}
+			if r.Stashes > 0 {
+				details = append(details, fmt.Sprintf("%s %d", styleAccent("stash:", useColor), r.Stashes))
+			}
+		}
+		body = appendRepoTreeLines(body, details, useColor)
+	}
+	if len(body) == 0 {
+		body = append(body, fmt.Sprintf("%s(none)", uiIndent))
+	}
+	printSection(out, styleBold("Repos:", useColor), body, sectionRenderOptions{
+		blankAfterHeading: false,
+		trailingBlank:     false,
+	})
+}
+
+func formatAheadBehind(ahead int, behind int, useColor bool) string {
+	return styleMuted(fmt.Sprintf("(ahead %d, behind %d)", ahead, behind), useColor)
+}
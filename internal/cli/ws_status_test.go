@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/kra/internal/testutil"
+)
+
+func TestCLI_WS_Status_ReportsPerRepoGitState(t *testing.T) {
+	testutil.RequireCommand(t, "git")
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if dir != "" {
+			cmd.Dir = dir
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	run := func(args ...string) (int, string, string) {
+		t.Helper()
+		var out bytes.Buffer
+		var err bytes.Buffer
+		c := New(&out, &err)
+		code := c.Run(args)
+		return code, out.String(), err.String()
+	}
+	writeFile := func(path string, body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	env := testutil.NewEnv(t)
+	initAndConfigureRootRepo(t, env.Root)
+	apiSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "api")
+	webSpec := prepareRemoteRepoSpecWithName(t, runGit, "github.com", "example-org", "web")
+	if code, _, stderr := run("repo", "add", apiSpec, webSpec); code != exitOK {
+		t.Fatalf("repo add exit code = %d (stderr=%q)", code, stderr)
+	}
+	if code, _, stderr := run("ws", "create", "--no-prompt", "WS1"); code != exitOK {
+		t.Fatalf("ws create exit code = %d (stderr=%q)", code, stderr)
+	}
+	for _, repo := range []string{"example-org/api", "example-org/web"} {
+		if code, out, stderr := run("ws", "add-repo", "--format", "json", "--id", "WS1", "--repo", repo, "--yes"); code != exitOK {
+			t.Fatalf("ws add-repo %s exit code = %d (stdout=%q stderr=%q)", repo, code, out, stderr)
+		}
+	}
+
+	apiPath := filepath.Join(env.Root, "workspaces", "WS1", "repos", "api")
+	git := func(args ...string) {
+		t.Helper()
+		runGit(apiPath, append([]string{"-c", "user.email=test@example.com", "-c", "user.name=test"}, args...)...)
+	}
+	writeFile(filepath.Join(apiPath, "committed.txt"), "c\n")
+	git("add", "committed.txt")
+	git("commit", "-m", "local commit")
+	writeFile(filepath.Join(apiPath, "stashed.txt"), "s\n")
+	git("add", "stashed.txt")
+	git("stash")
+	writeFile(filepath.Join(apiPath, "staged.txt"), "a\n")
+	git("add", "staged.txt")
+	writeFile(filepath.Join(apiPath, "README.md"), "changed\n")
+	writeFile(filepath.Join(apiPath, "new.txt"), "n\n")
+
+	code, out, stderr := run("ws", "status", "--format", "json", "--id", "WS1")
+	if code != exitOK {
+		t.Fatalf("ws status exit code = %d (stdout=%q stderr=%q)", code, out, stderr)
+	}
+	var resp struct {
+		OK          bool   `json:"ok"`
+		Action      string `json:"action"`
+		WorkspaceID string `json:"workspace_id"`
+		Result      struct {
+			Summary struct {
+				Risk    string         `json:"risk"`
+				Repos   int            `json:"repos"`
+				States  map[string]int `json:"states"`
+				Stashes int            `json:"stashes"`
+			} `json:"summary"`
+			Repos []struct {
+				Alias      string   `json:"alias"`
+				RepoKey    string   `json:"repo_key"`
+				Branch     string   `json:"branch"`
+				BaseRef    string   `json:"base_ref"`
+				BaseAhead  int      `json:"base_ahead"`
+				BaseBehind int      `json:"base_behind"`
+				Staged     []string `json:"staged"`
+				Unstaged   []string `json:"unstaged"`
+				Untracked  []string `json:"untracked"`
+				StashCount int      `json:"stash_count"`
+				State      string   `json:"state"`
+			} `json:"repos"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("decode ws status: %v (out=%s)", err, out)
+	}
+	if !resp.OK || resp.Action != "ws.status" || resp.WorkspaceID != "WS1" || len(resp.Result.Repos) != 2 {
+		t.Fatalf("ws status json = %s", out)
+	}
+	if resp.Result.Summary.Risk != "dirty" || resp.Result.Summary.Repos != 2 || resp.Result.Summary.States["dirty"] != 1 || resp.Result.Summary.Stashes != 1 {
+		t.Fatalf("summary = %+v", resp.Result.Summary)
+	}
+	api, web := resp.Result.Repos[0], resp.Result.Repos[1]
+	if api.Alias != "api" || api.RepoKey != "example-org/api" || api.Branch != "WS1" || api.BaseRef != "origin/main" || api.State != "dirty" {
+		t.Fatalf("api repo = %+v", api)
+	}
+	if api.BaseAhead != 1 || api.BaseBehind != 0 || api.StashCount != 1 {
+		t.Fatalf("api base/stash = %+v", api)
+	}
+	if !slices.Equal(api.Staged, []string{"staged.txt"}) || !slices.Equal(api.Unstaged, []string{"README.md"}) || !slices.Equal(api.Untracked, []string{"new.txt"}) {
+		t.Fatalf("api files = staged:%v unstaged:%v untracked:%v", api.Staged, api.Unstaged, api.Untracked)
+	}
+	if web.Alias != "web" || web.State == "dirty" || len(web.Staged)+len(web.Unstaged)+len(web.Untracked) != 0 || web.StashCount != 0 {
+		t.Fatalf("web repo = %+v", web)
+	}
+
+	code, out, _ = run("ws", "status", "WS1")
+	if code != exitOK {
+		t.Fatalf("ws status human exit code = %d", code)
+	}
+	for _, want := range []string{"WS1 risk:dirty repos:2", "files: +1 ~1 ?1 stash:1", "api (example-org/api) WS1 [dirty]", "base: origin/main (ahead 1, behind 0)", "staged: staged.txt", "untracked: new.txt", "stash: 1"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("ws status human output missing %q:\n%s", want, out)
+		}
+	}
+
+	if code, out, _ := run("ws", "status", "--format", "json", "--id", "MISSING"); code != exitError || !strings.Contains(out, `"code":"not_found"`) {
+		t.Fatalf("ws status unknown workspace: code=%d out=%s", code, out)
+	}
+}
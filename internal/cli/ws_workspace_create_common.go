@@ -7,6 +7,9 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/tasuku43/kra/internal/config"
+	"github.com/tasuku43/kra/internal/core/ticketnotes"
 )
 
 func (c *CLI) createWorkspaceAtRoot(root string, id string, title string, sourceURL string, templateName string) (string, error) {
@@ -66,3 +69,20 @@ func (c *CLI) createWorkspaceAtRoot(root string, id string, title string, source
 	}
 	return wsPath, nil
 }
+
+func resolveTicketNotesPath(cfg config.Config) (string, error) {
+	return ticketnotes.ResolvePath(cfg.Integration.Jira.Notes.Path)
+}
+
+// writeWorkspaceTicketNotes writes Jira ticket notes into the workspace.
+// relPath is expected to be resolved by resolveTicketNotesPath.
+func writeWorkspaceTicketNotes(wsPath string, relPath string, content string) error {
+	target := filepath.Join(wsPath, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("create notes dir: %w", err)
+	}
+	if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", relPath, err)
+	}
+	return nil
+}
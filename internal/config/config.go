@@ -6,7 +6,10 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/tasuku43/kra/internal/core/commitmsg"
+	"github.com/tasuku43/kra/internal/core/ticketnotes"
 	"gopkg.in/yaml.v3"
 )
 
@@ -15,6 +18,14 @@ const (
 	JiraTypeJQL    = "jql"
 )
 
+const (
+	LifecycleSignGPG = "gpg"
+	LifecycleSignSSH = "ssh"
+)
+
+// LifecycleCommitPlaceholders are the placeholders of workspace.lifecycle_commit templates.
+var LifecycleCommitPlaceholders = []string{"action", "id", "title", "source_url"}
+
 type Config struct {
 	Workspace   WorkspaceConfig   `yaml:"workspace"`
 	Integration IntegrationConfig `yaml:"integration"`
@@ -23,16 +34,59 @@ type Config struct {
 type WorkspaceConfig struct {
 	Defaults WorkspaceDefaults `yaml:"defaults"`
 	Branch   WorkspaceBranch   `yaml:"branch"`
+	Commit   WorkspaceCommit   `yaml:"commit"`
+	// LifecycleCommit shapes the commits kra makes in the KRA_ROOT repo (create/close/reopen/purge).
+	LifecycleCommit LifecycleCommit `yaml:"lifecycle_commit"`
 }
 
 type WorkspaceDefaults struct {
-	Template string `yaml:"template"`
+	Template      string         `yaml:"template"`
+	TemplateRules []TemplateRule `yaml:"template_rules"`
+}
+
+// TemplateRule routes Jira-backed workspaces to a template.
+// Every non-empty matcher must match; the first matching rule wins.
+type TemplateRule struct {
+	Template  string `yaml:"template"`
+	IssueType string `yaml:"issue_type"`
+	Label     string `yaml:"label"`
+	Project   string `yaml:"project"`
+}
+
+// TemplateRuleSubject is the issue data matched against template rules.
+type TemplateRuleSubject struct {
+	IssueType string
+	Labels    []string
+	Project   string
 }
 
 type WorkspaceBranch struct {
 	Template string `yaml:"template"`
 }
 
+// WorkspaceCommit links commits in workspace worktrees to the workspace ticket.
+// Values are templates with {{workspace_id}} / {{source_url}} placeholders.
+type WorkspaceCommit struct {
+	Template string `yaml:"template"`
+	Prefix   string `yaml:"prefix"`
+	Trailer  string `yaml:"trailer"`
+}
+
+// LifecycleCommit is the message, trailers, signing, and committer of root lifecycle commits.
+// Message and trailers are templates with {{action}} / {{id}} / {{title}} / {{source_url}} placeholders.
+type LifecycleCommit struct {
+	Message    string             `yaml:"message"`
+	Trailers   []string           `yaml:"trailers"`
+	Sign       string             `yaml:"sign"`
+	SigningKey string             `yaml:"signing_key"`
+	Committer  LifecycleCommitter `yaml:"committer"`
+}
+
+type LifecycleCommitter struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email"`
+}
+
 type IntegrationConfig struct {
 	Jira JiraConfig `yaml:"jira"`
 }
@@ -40,6 +94,8 @@ type IntegrationConfig struct {
 type JiraConfig struct {
 	BaseURL  string       `yaml:"base_url"`
 	Defaults JiraDefaults `yaml:"defaults"`
+	Cache    JiraCache    `yaml:"cache"`
+	Notes    JiraNotes    `yaml:"notes"`
 }
 
 type JiraDefaults struct {
@@ -48,6 +104,18 @@ type JiraDefaults struct {
 	Type    string `yaml:"type"`
 }
 
+// JiraCache holds offline cache TTLs as Go duration strings (e.g. "15m", "24h").
+type JiraCache struct {
+	IssuesTTL  string `yaml:"issues_ttl"`
+	BoardsTTL  string `yaml:"boards_ttl"`
+	SprintsTTL string `yaml:"sprints_ttl"`
+}
+
+// JiraNotes controls the ticket notes file written into Jira-backed workspaces.
+type JiraNotes struct {
+	Path string `yaml:"path"`
+}
+
 func LoadFile(path string) (Config, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -73,15 +141,85 @@ func LoadFile(path string) (Config, error) {
 
 func (c *Config) Normalize() {
 	c.Workspace.Defaults.Template = strings.TrimSpace(c.Workspace.Defaults.Template)
+	for i := range c.Workspace.Defaults.TemplateRules {
+		r := &c.Workspace.Defaults.TemplateRules[i]
+		r.Template = strings.TrimSpace(r.Template)
+		r.IssueType = strings.TrimSpace(r.IssueType)
+		r.Label = strings.TrimSpace(r.Label)
+		r.Project = strings.ToUpper(strings.TrimSpace(r.Project))
+	}
 	c.Workspace.Branch.Template = strings.TrimSpace(c.Workspace.Branch.Template)
+	c.Workspace.Commit.Template = strings.TrimSpace(c.Workspace.Commit.Template)
+	c.Workspace.Commit.Prefix = strings.TrimSpace(c.Workspace.Commit.Prefix)
+	c.Workspace.Commit.Trailer = strings.TrimSpace(c.Workspace.Commit.Trailer)
+	lc := &c.Workspace.LifecycleCommit
+	lc.Message = strings.TrimSpace(lc.Message)
+	trailers := make([]string, 0, len(lc.Trailers))
+	for _, t := range lc.Trailers {
+		if t = strings.TrimSpace(t); t != "" {
+			trailers = append(trailers, t)
+		}
+	}
+	lc.Trailers = nil
+	if len(trailers) > 0 {
+		lc.Trailers = trailers
+	}
+	lc.Sign = strings.ToLower(strings.TrimSpace(lc.Sign))
+	lc.SigningKey = strings.TrimSpace(lc.SigningKey)
+	lc.Committer.Name = strings.TrimSpace(lc.Committer.Name)
+	lc.Committer.Email = strings.TrimSpace(lc.Committer.Email)
 	c.Integration.Jira.BaseURL = strings.TrimSpace(c.Integration.Jira.BaseURL)
 	c.Integration.Jira.Defaults.Space = strings.ToUpper(strings.TrimSpace(c.Integration.Jira.Defaults.Space))
 	c.Integration.Jira.Defaults.Project = strings.ToUpper(strings.TrimSpace(c.Integration.Jira.Defaults.Project))
 	c.Integration.Jira.Defaults.Type = strings.ToLower(strings.TrimSpace(c.Integration.Jira.Defaults.Type))
+	c.Integration.Jira.Cache.IssuesTTL = strings.TrimSpace(c.Integration.Jira.Cache.IssuesTTL)
+	c.Integration.Jira.Cache.BoardsTTL = strings.TrimSpace(c.Integration.Jira.Cache.BoardsTTL)
+	c.Integration.Jira.Cache.SprintsTTL = strings.TrimSpace(c.Integration.Jira.Cache.SprintsTTL)
+	c.Integration.Jira.Notes.Path = strings.TrimSpace(c.Integration.Jira.Notes.Path)
 }
 
 func (c Config) Validate() error {
 	issues := make([]string, 0, 3)
+	for i, r := range c.Workspace.Defaults.TemplateRules {
+		key := fmt.Sprintf("workspace.defaults.template_rules[%d]", i)
+		if r.Template == "" {
+			issues = append(issues, key+".template is required")
+		}
+		if r.IssueType == "" && r.Label == "" && r.Project == "" {
+			issues = append(issues, key+" must set at least one of: issue_type, label, project")
+		}
+	}
+	if err := commitmsg.Validate(c.Workspace.Commit.Template); err != nil {
+		issues = append(issues, fmt.Sprintf("workspace.commit.template: %v", err))
+	}
+	if err := commitmsg.Validate(c.Workspace.Commit.Prefix); err != nil {
+		issues = append(issues, fmt.Sprintf("workspace.commit.prefix: %v", err))
+	} else if strings.Contains(c.Workspace.Commit.Prefix, "\n") {
+		issues = append(issues, "workspace.commit.prefix must be a single line")
+	}
+	if c.Workspace.Commit.Trailer != "" {
+		if err := commitmsg.ValidateTrailer(c.Workspace.Commit.Trailer); err != nil {
+			issues = append(issues, fmt.Sprintf("workspace.commit.trailer: %v", err))
+		}
+	}
+	lc := c.Workspace.LifecycleCommit
+	if err := commitmsg.ValidateFields(lc.Message, LifecycleCommitPlaceholders...); err != nil {
+		issues = append(issues, fmt.Sprintf("workspace.lifecycle_commit.message: %v", err))
+	}
+	for i, t := range lc.Trailers {
+		if err := commitmsg.ValidateTrailerFields(t, LifecycleCommitPlaceholders...); err != nil {
+			issues = append(issues, fmt.Sprintf("workspace.lifecycle_commit.trailers[%d]: %v", i, err))
+		}
+	}
+	if lc.Sign != "" && lc.Sign != LifecycleSignGPG && lc.Sign != LifecycleSignSSH {
+		issues = append(issues, "workspace.lifecycle_commit.sign must be one of: gpg, ssh")
+	}
+	if lc.SigningKey != "" && lc.Sign == "" {
+		issues = append(issues, "workspace.lifecycle_commit.signing_key requires workspace.lifecycle_commit.sign")
+	}
+	if (lc.Committer.Name == "") != (lc.Committer.Email == "") {
+		issues = append(issues, "workspace.lifecycle_commit.committer requires both name and email")
+	}
 	if c.Integration.Jira.BaseURL != "" {
 		u, err := url.Parse(c.Integration.Jira.BaseURL)
 		if err != nil || u.Scheme == "" || u.Host == "" {
@@ -96,6 +234,26 @@ func (c Config) Validate() error {
 	if c.Integration.Jira.Defaults.Space != "" && c.Integration.Jira.Defaults.Project != "" {
 		issues = append(issues, "integration.jira.defaults.space and integration.jira.defaults.project cannot be combined")
 	}
+	for _, ttl := range []struct {
+		key   string
+		value string
+	}{
+		{key: "integration.jira.cache.issues_ttl", value: c.Integration.Jira.Cache.IssuesTTL},
+		{key: "integration.jira.cache.boards_ttl", value: c.Integration.Jira.Cache.BoardsTTL},
+		{key: "integration.jira.cache.sprints_ttl", value: c.Integration.Jira.Cache.SprintsTTL},
+	} {
+		if ttl.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(ttl.value); err != nil || d <= 0 {
+			issues = append(issues, fmt.Sprintf("%s must be a positive duration (e.g. 15m, 24h)", ttl.key))
+		}
+	}
+	if p := c.Integration.Jira.Notes.Path; p != "" {
+		if _, err := ticketnotes.ResolvePath(p); err != nil {
+			issues = append(issues, "integration.jira.notes.path must be a relative path inside the workspace (not repos/, .git/, .kra.meta.json)")
+		}
+	}
 	if len(issues) == 0 {
 		return nil
 	}
@@ -110,9 +268,37 @@ func Merge(global Config, root Config) Config {
 	if root.Workspace.Defaults.Template != "" {
 		out.Workspace.Defaults.Template = root.Workspace.Defaults.Template
 	}
+	if len(root.Workspace.Defaults.TemplateRules) > 0 {
+		// Rules are an ordered list: root rules replace global rules as a whole.
+		out.Workspace.Defaults.TemplateRules = root.Workspace.Defaults.TemplateRules
+	}
 	if root.Workspace.Branch.Template != "" {
 		out.Workspace.Branch.Template = root.Workspace.Branch.Template
 	}
+	if root.Workspace.Commit.Template != "" {
+		out.Workspace.Commit.Template = root.Workspace.Commit.Template
+	}
+	if root.Workspace.Commit.Prefix != "" {
+		out.Workspace.Commit.Prefix = root.Workspace.Commit.Prefix
+	}
+	if root.Workspace.Commit.Trailer != "" {
+		out.Workspace.Commit.Trailer = root.Workspace.Commit.Trailer
+	}
+	if root.Workspace.LifecycleCommit.Message != "" {
+		out.Workspace.LifecycleCommit.Message = root.Workspace.LifecycleCommit.Message
+	}
+	if len(root.Workspace.LifecycleCommit.Trailers) > 0 {
+		// Trailers are a list: root trailers replace global trailers as a whole.
+		out.Workspace.LifecycleCommit.Trailers = root.Workspace.LifecycleCommit.Trailers
+	}
+	if root.Workspace.LifecycleCommit.Sign != "" {
+		out.Workspace.LifecycleCommit.Sign = root.Workspace.LifecycleCommit.Sign
+		out.Workspace.LifecycleCommit.SigningKey = root.Workspace.LifecycleCommit.SigningKey
+	}
+	if root.Workspace.LifecycleCommit.Committer.Name != "" {
+		// Name and email are validated as a pair, so they are merged as a pair.
+		out.Workspace.LifecycleCommit.Committer = root.Workspace.LifecycleCommit.Committer
+	}
 	if root.Integration.Jira.BaseURL != "" {
 		out.Integration.Jira.BaseURL = root.Integration.Jira.BaseURL
 	}
@@ -125,6 +311,45 @@ func Merge(global Config, root Config) Config {
 	if root.Integration.Jira.Defaults.Type != "" {
 		out.Integration.Jira.Defaults.Type = root.Integration.Jira.Defaults.Type
 	}
+	if root.Integration.Jira.Cache.IssuesTTL != "" {
+		out.Integration.Jira.Cache.IssuesTTL = root.Integration.Jira.Cache.IssuesTTL
+	}
+	if root.Integration.Jira.Cache.BoardsTTL != "" {
+		out.Integration.Jira.Cache.BoardsTTL = root.Integration.Jira.Cache.BoardsTTL
+	}
+	if root.Integration.Jira.Cache.SprintsTTL != "" {
+		out.Integration.Jira.Cache.SprintsTTL = root.Integration.Jira.Cache.SprintsTTL
+	}
+	if root.Integration.Jira.Notes.Path != "" {
+		out.Integration.Jira.Notes.Path = root.Integration.Jira.Notes.Path
+	}
 	out.Normalize()
 	return out
 }
+
+// MatchTemplateRule returns the first rule matching subject.
+// Issue type and label comparisons are case-insensitive.
+func MatchTemplateRule(rules []TemplateRule, subject TemplateRuleSubject) (TemplateRule, bool) {
+	for _, r := range rules {
+		if r.IssueType != "" && !strings.EqualFold(r.IssueType, strings.TrimSpace(subject.IssueType)) {
+			continue
+		}
+		if r.Project != "" && !strings.EqualFold(r.Project, strings.TrimSpace(subject.Project)) {
+			continue
+		}
+		if r.Label != "" && !containsFold(subject.Labels, r.Label) {
+			continue
+		}
+		return r, true
+	}
+	return TemplateRule{}, false
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), want) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -12,7 +13,7 @@ func TestLoadFile_MissingIsEmpty(t *testing.T) {
 	if err != nil {
 		t.Fatalf("LoadFile() error = %v", err)
 	}
-	if cfg != (Config{}) {
+	if !reflect.DeepEqual(cfg, Config{}) {
 		t.Fatalf("LoadFile() = %+v, want zero", cfg)
 	}
 }
@@ -165,3 +166,180 @@ func TestMerge_RootOverridesGlobal(t *testing.T) {
 		t.Fatalf("integration.jira.defaults.type = %q, want %q", got.Integration.Jira.Defaults.Type, JiraTypeJQL)
 	}
 }
+
+func TestLoadFile_InvalidJiraCacheTTLFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+integration:
+  jira:
+    cache:
+      issues_ttl: soon
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	_, err := LoadFile(path)
+	if err == nil {
+		t.Fatalf("LoadFile() error = nil, want non-nil")
+	}
+	if !strings.Contains(err.Error(), "integration.jira.cache.issues_ttl") {
+		t.Fatalf("error = %q, want cache ttl hint", err)
+	}
+}
+
+func TestLoadFile_JiraNotesPathMustStayInsideWorkspace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+integration:
+  jira:
+    notes:
+      path: ../outside.md
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	_, err := LoadFile(path)
+	if err == nil {
+		t.Fatalf("LoadFile() error = nil, want non-nil")
+	}
+	if !strings.Contains(err.Error(), "integration.jira.notes.path") {
+		t.Fatalf("error = %q, want notes path hint", err)
+	}
+}
+
+func TestLoadFile_TemplateRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+workspace:
+  defaults:
+    template_rules:
+      - template: " bug "
+        issue_type: Bug
+      - template: spike
+        label: spike
+        project: " ops "
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	rules := cfg.Workspace.Defaults.TemplateRules
+	if len(rules) != 2 || rules[0].Template != "bug" || rules[1].Project != "OPS" {
+		t.Fatalf("rules = %+v", rules)
+	}
+
+	for _, tt := range []struct {
+		subject TemplateRuleSubject
+		want    string
+	}{
+		{subject: TemplateRuleSubject{IssueType: "bug", Project: "APP"}, want: "bug"},
+		{subject: TemplateRuleSubject{IssueType: "Story", Labels: []string{"Spike"}, Project: "OPS"}, want: "spike"},
+		{subject: TemplateRuleSubject{IssueType: "Story", Labels: []string{"spike"}, Project: "APP"}, want: ""},
+	} {
+		got, ok := MatchTemplateRule(rules, tt.subject)
+		if got.Template != tt.want || ok != (tt.want != "") {
+			t.Fatalf("MatchTemplateRule(%+v) = %q, %v; want %q", tt.subject, got.Template, ok, tt.want)
+		}
+	}
+}
+
+func TestLoadFile_TemplateRuleWithoutMatcherFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+workspace:
+  defaults:
+    template_rules:
+      - template: bug
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	_, err := LoadFile(path)
+	if err == nil || !strings.Contains(err.Error(), "workspace.defaults.template_rules[0]") {
+		t.Fatalf("LoadFile() error = %v, want template_rules hint", err)
+	}
+}
+
+func TestLoadFile_WorkspaceCommitValidation(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{name: "unknown placeholder", yaml: "prefix: \"[{{ticket}}]\"", want: "workspace.commit.prefix"},
+		{name: "trailer without key", yaml: "trailer: \"{{source_url}}\"", want: "workspace.commit.trailer"},
+		{name: "trailer key placeholder", yaml: "trailer: \"{{workspace_id}}: x\"", want: "workspace.commit.trailer"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte("workspace:\n  commit:\n    "+tt.yaml+"\n"), 0o644); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			_, err := LoadFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadFile() error = %v, want %s hint", err, tt.want)
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+workspace:
+  commit:
+    prefix: " [{{workspace_id}}] "
+    trailer: "Refs: {{ source_url }}"
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if cfg.Workspace.Commit.Prefix != "[{{workspace_id}}]" || cfg.Workspace.Commit.Trailer != "Refs: {{ source_url }}" {
+		t.Fatalf("workspace.commit = %+v", cfg.Workspace.Commit)
+	}
+}
+
+func TestLoadFile_LifecycleCommitValidation(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{name: "unknown placeholder", yaml: "message: \"{{action}} {{ticket}}\"", want: "workspace.lifecycle_commit.message"},
+		{name: "bad trailer", yaml: "trailers: [\"just text\"]", want: "workspace.lifecycle_commit.trailers[0]"},
+		{name: "unknown sign", yaml: "sign: x509", want: "workspace.lifecycle_commit.sign"},
+		{name: "key without sign", yaml: "signing_key: ABC", want: "workspace.lifecycle_commit.signing_key"},
+		{name: "committer without email", yaml: "committer: {name: kra}", want: "workspace.lifecycle_commit.committer"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte("workspace:\n  lifecycle_commit:\n    "+tt.yaml+"\n"), 0o644); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			_, err := LoadFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadFile() error = %v, want %s hint", err, tt.want)
+			}
+		})
+	}
+
+	global := Config{Workspace: WorkspaceConfig{LifecycleCommit: LifecycleCommit{
+		Message:   "{{action}}: {{id}} {{title}}",
+		Trailers:  []string{"Kra-Action: {{action}}"},
+		Committer: LifecycleCommitter{Name: "global", Email: "global@example.com"},
+	}}}
+	root := Config{Workspace: WorkspaceConfig{LifecycleCommit: LifecycleCommit{
+		Sign:       " SSH ",
+		SigningKey: "~/.ssh/kra.pub",
+		Trailers:   []string{"Kra-Workspace: {{id}}", " "},
+	}}}
+	got := Merge(global, root).Workspace.LifecycleCommit
+	if got.Message != "{{action}}: {{id}} {{title}}" || got.Sign != LifecycleSignSSH || got.SigningKey != "~/.ssh/kra.pub" ||
+		!reflect.DeepEqual(got.Trailers, []string{"Kra-Workspace: {{id}}"}) || got.Committer.Name != "global" {
+		t.Fatalf("merged lifecycle_commit = %+v", got)
+	}
+}
@@ -0,0 +1,174 @@
+package commitmsg
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Vars is the per-workspace render context of commit templates.
+type Vars struct {
+	WorkspaceID string
+	SourceURL   string
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+var trailerPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*:\s*\S`)
+
+// Validate reports unsupported placeholders (allowed: workspace_id, source_url).
+func Validate(template string) error {
+	return ValidateFields(template, "workspace_id", "source_url")
+}
+
+// ValidateFields reports placeholders outside allowed.
+func ValidateFields(template string, allowed ...string) error {
+	for _, m := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		if !slices.Contains(allowed, m[1]) {
+			return fmt.Errorf("unsupported placeholder %q (allowed: %s)", m[1], strings.Join(allowed, ", "))
+		}
+	}
+	rest := placeholderPattern.ReplaceAllString(template, "")
+	if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return fmt.Errorf("unresolved placeholder syntax")
+	}
+	return nil
+}
+
+// RenderFields substitutes placeholders from values; unknown or empty values render as "".
+func RenderFields(template string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(s string) string {
+		return values[placeholderPattern.FindStringSubmatch(s)[1]]
+	})
+}
+
+// ValidateTrailer checks that a trailer template renders to a `Key: value` line.
+func ValidateTrailer(template string) error {
+	if err := Validate(template); err != nil {
+		return err
+	}
+	return validateTrailerShape(template)
+}
+
+// ValidateTrailerFields is ValidateTrailer with a custom placeholder set.
+func ValidateTrailerFields(template string, allowed ...string) error {
+	if err := ValidateFields(template, allowed...); err != nil {
+		return err
+	}
+	return validateTrailerShape(template)
+}
+
+func validateTrailerShape(template string) error {
+	if strings.Contains(template, "\n") || !trailerPattern.MatchString(placeholderPattern.ReplaceAllString(template, "x")) {
+		return fmt.Errorf("trailer must be a single `Key: value` line")
+	}
+	key := strings.SplitN(template, ":", 2)[0]
+	if placeholderPattern.MatchString(key) {
+		return fmt.Errorf("trailer key must not use placeholders")
+	}
+	return nil
+}
+
+// TrailerHasValue reports whether a rendered trailer still has a value (placeholders may render empty).
+func TrailerHasValue(trailer string) bool {
+	parts := strings.SplitN(trailer, ":", 2)
+	return len(parts) == 2 && strings.TrimSpace(parts[1]) != ""
+}
+
+// Render substitutes placeholders. ok is false when the template uses a placeholder whose value is
+// empty (e.g. source_url of a workspace created without a ticket), so callers can drop that part.
+func Render(template string, v Vars) (rendered string, ok bool, err error) {
+	template = strings.TrimSpace(template)
+	if template == "" {
+		return "", false, nil
+	}
+	if err := Validate(template); err != nil {
+		return "", false, err
+	}
+	values := map[string]string{
+		"workspace_id": strings.TrimSpace(v.WorkspaceID),
+		"source_url":   strings.TrimSpace(v.SourceURL),
+	}
+	ok = true
+	rendered = placeholderPattern.ReplaceAllStringFunc(template, func(s string) string {
+		value := values[placeholderPattern.FindStringSubmatch(s)[1]]
+		if value == "" {
+			ok = false
+		}
+		return value
+	})
+	if !ok {
+		return "", false, nil
+	}
+	return rendered, true, nil
+}
+
+// HookNames are the client-side hooks that a per-worktree hooks directory must forward to the
+// repo's own hooks, since core.hooksPath replaces the hooks directory as a whole.
+var HookNames = []string{
+	"applypatch-msg",
+	"pre-applypatch",
+	"post-applypatch",
+	"pre-commit",
+	"pre-merge-commit",
+	"prepare-commit-msg",
+	"commit-msg",
+	"post-commit",
+	"pre-rebase",
+	"post-checkout",
+	"post-merge",
+	"pre-push",
+	"post-rewrite",
+	"pre-auto-gc",
+}
+
+// ForwardHook returns a hook script that runs the same hook from chainDir when it is executable.
+func ForwardHook(workspaceID string, name string, chainDir string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Managed by kra (workspace %s). Runs the repo's own %s hook, if any.\n", workspaceID, name)
+	fmt.Fprintf(&b, "hook=%s\n", shellQuote(chainDir+"/"+name))
+	b.WriteString("if [ -x \"$hook\" ]; then exec \"$hook\" \"$@\"; fi\n")
+	b.WriteString("exit 0\n")
+	return b.String()
+}
+
+// CommitMsgHook returns the commit-msg hook script.
+//   - prefix is prepended to the subject unless already present (fixup!/squash!/amend!/Merge subjects
+//     are left alone so autosquash and merges keep working)
+//   - trailer is appended via git interpret-trailers unless the same trailer exists
+//   - empty messages are left untouched so git still aborts them
+func CommitMsgHook(workspaceID string, prefix string, trailer string, chainDir string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Managed by kra (workspace %s). Links commits to the workspace ticket.\n", workspaceID)
+	fmt.Fprintf(&b, "KRA_COMMIT_PREFIX=%s\n", shellQuote(prefix))
+	fmt.Fprintf(&b, "trailer=%s\n", shellQuote(trailer))
+	b.WriteString(`msg="$1"
+if git stripspace --strip-comments <"$msg" | grep -q '[^[:space:]]'; then
+	if [ -n "$KRA_COMMIT_PREFIX" ]; then
+		export KRA_COMMIT_PREFIX
+		awk '
+			!done && $0 !~ /^#/ && $0 !~ /^[[:space:]]*$/ {
+				done = 1
+				p = ENVIRON["KRA_COMMIT_PREFIX"]
+				if (index($0, p) != 1 && $0 !~ /^(fixup|squash|amend)! / && $0 !~ /^Merge /) $0 = p " " $0
+			}
+			{ print }
+		' "$msg" >"$msg.kra" && mv "$msg.kra" "$msg" || exit 1
+	fi
+	if [ -n "$trailer" ]; then
+		git interpret-trailers --in-place --if-exists addIfDifferent --trailer "$trailer" "$msg" || exit 1
+	fi
+fi
+`)
+	fmt.Fprintf(&b, "hook=%s\n", shellQuote(chainDir+"/commit-msg"))
+	b.WriteString("if [ -x \"$hook\" ]; then exec \"$hook\" \"$@\"; fi\n")
+	b.WriteString("exit 0\n")
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
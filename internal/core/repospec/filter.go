@@ -0,0 +1,19 @@
+package repospec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var cloneFilterPattern = regexp.MustCompile(`^(blob:none|blob:limit=[0-9]+[kmgKMG]?|tree:[0-9]+)$`)
+
+// ValidateCloneFilter checks a partial clone filter accepted by the repo pool
+// (git clone --filter). An empty filter means a full clone.
+func ValidateCloneFilter(filter string) error {
+	filter = strings.TrimSpace(filter)
+	if filter == "" || cloneFilterPattern.MatchString(filter) {
+		return nil
+	}
+	return fmt.Errorf("unsupported clone filter: %q (supported: blob:none, blob:limit=<n>[k|m|g], tree:<depth>)", filter)
+}
@@ -4,6 +4,12 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
+)
+
+var (
+	nestedHostsMu sync.RWMutex
+	nestedHosts   map[string]bool
 )
 
 type Spec struct {
@@ -31,6 +37,14 @@ func Normalize(input string) (Spec, error) {
 		}
 		host = trimmed[at+1 : colon]
 		path = trimmed[colon+1:]
+	case strings.HasPrefix(trimmed, "ssh://"):
+		// ssh:// URLs may carry a port (e.g. Bitbucket Server); the port is not part of identity.
+		u, err := url.Parse(trimmed)
+		if err != nil {
+			return Spec{}, fmt.Errorf("invalid ssh repo spec: %q", input)
+		}
+		host = u.Hostname()
+		path = strings.TrimPrefix(u.Path, "/")
 	case strings.HasPrefix(trimmed, "https://"):
 		u, err := url.Parse(trimmed)
 		if err != nil {
@@ -38,6 +52,10 @@ func Normalize(input string) (Spec, error) {
 		}
 		host = u.Hostname()
 		path = strings.TrimPrefix(u.Path, "/")
+		// Bitbucket Server serves https clones under /scm/<project>/<repo>; the ssh form has no scm/.
+		if parts := strings.Split(strings.Trim(path, "/"), "/"); len(parts) == 3 && parts[0] == "scm" && !NestedNamespaceHost(host) {
+			path = strings.Join(parts[1:], "/")
+		}
 	case strings.HasPrefix(trimmed, "file://"):
 		u, err := url.Parse(trimmed)
 		if err != nil {
@@ -55,12 +73,15 @@ func Normalize(input string) (Spec, error) {
 		return Spec{}, fmt.Errorf("repo spec must be ssh, https, or file: %q", input)
 	}
 
+	if host == "" {
+		return Spec{}, fmt.Errorf("host is required in repo spec: %q", input)
+	}
 	owner, repo, err := splitOwnerRepo(path)
 	if err != nil {
 		return Spec{}, err
 	}
-	if host == "" {
-		return Spec{}, fmt.Errorf("host is required in repo spec: %q", input)
+	if err := ValidateIdentity(host, owner, repo); err != nil {
+		return Spec{}, fmt.Errorf("%w: %q", err, input)
 	}
 
 	return Spec{
@@ -78,14 +99,82 @@ func splitOwnerRepo(path string) (string, string, error) {
 	}
 
 	parts := strings.Split(trimmed, "/")
-	if len(parts) != 2 {
+	if len(parts) < 2 {
 		return "", "", fmt.Errorf("repo path must be <owner>/<repo>")
 	}
+	owner := strings.Join(parts[:len(parts)-1], "/")
+	repo := strings.TrimSuffix(parts[len(parts)-1], ".git")
+	return owner, repo, nil
+}
 
-	owner := parts[0]
-	repo := strings.TrimSuffix(parts[1], ".git")
-	if owner == "" || repo == "" {
-		return "", "", fmt.Errorf("owner/repo cannot be empty")
+// ValidateIdentity checks host/owner/repo before they are used as a repo pool path. Every segment must
+// be a plain name (no empty, "." or ".." segments), and owner may only be nested (group/subgroup) on
+// hosts that have nested namespaces, see NestedNamespaceHost.
+func ValidateIdentity(host string, owner string, repo string) error {
+	if err := validateSegment(host); err != nil {
+		return fmt.Errorf("invalid host: %w", err)
 	}
-	return owner, repo, nil
+	if strings.Contains(host, "/") {
+		return fmt.Errorf("invalid host: %q", host)
+	}
+	if err := validateSegment(repo); err != nil {
+		return fmt.Errorf("invalid repo: %w", err)
+	}
+	if strings.Contains(repo, "/") {
+		return fmt.Errorf("invalid repo: %q", repo)
+	}
+	segments := strings.Split(owner, "/")
+	for _, seg := range segments {
+		if err := validateSegment(seg); err != nil {
+			return fmt.Errorf("invalid owner: %w", err)
+		}
+	}
+	if len(segments) > 1 && !NestedNamespaceHost(host) {
+		return fmt.Errorf("repo path must be <owner>/<repo> on %s", host)
+	}
+	return nil
+}
+
+// SetNestedNamespaceHosts replaces the extra hosts that allow nested owners, on top of the "gitlab"-labelled
+// ones. The caller derives them from its configuration (e.g. a self-hosted GitLab base URL).
+func SetNestedNamespaceHosts(hosts []string) {
+	set := map[string]bool{}
+	for _, h := range hosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			set[h] = true
+		}
+	}
+	nestedHostsMu.Lock()
+	nestedHosts = set
+	nestedHostsMu.Unlock()
+}
+
+// NestedNamespaceHost reports whether host allows nested owners (GitLab groups/subgroups): gitlab.com,
+// any host with a "gitlab" label (e.g. gitlab.example.com), and hosts set by SetNestedNamespaceHosts.
+func NestedNamespaceHost(host string) bool {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "gitlab" {
+			return true
+		}
+	}
+	nestedHostsMu.RLock()
+	defer nestedHostsMu.RUnlock()
+	return nestedHosts[host]
+}
+
+func validateSegment(seg string) error {
+	switch strings.TrimSpace(seg) {
+	case "":
+		return fmt.Errorf("empty path segment")
+	case ".", "..":
+		return fmt.Errorf("path segment %q is not allowed", seg)
+	}
+	if seg != strings.TrimSpace(seg) || strings.ContainsAny(seg, "\\\x00") {
+		return fmt.Errorf("path segment %q is not allowed", seg)
+	}
+	return nil
 }
@@ -0,0 +1,71 @@
+package repospec
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		want    Spec
+		wantErr bool
+	}{
+		{name: "ssh", input: "git@github.com:example-org/api.git", want: Spec{Host: "github.com", Owner: "example-org", Repo: "api", RepoKey: "github.com/example-org/api"}},
+		{name: "ssh url with port", input: "ssh://git@bb.example.com:7999/proj/api.git", want: Spec{Host: "bb.example.com", Owner: "proj", Repo: "api", RepoKey: "bb.example.com/proj/api"}},
+		{name: "https", input: "https://github.com/example-org/api", want: Spec{Host: "github.com", Owner: "example-org", Repo: "api", RepoKey: "github.com/example-org/api"}},
+		{name: "file", input: "file:///tmp/remotes/github.com/example-org/api.git", want: Spec{Host: "github.com", Owner: "example-org", Repo: "api", RepoKey: "github.com/example-org/api"}},
+		{name: "bitbucket server scm prefix", input: "https://bb.example.com/scm/PROJ/repo.git", want: Spec{Host: "bb.example.com", Owner: "PROJ", Repo: "repo", RepoKey: "bb.example.com/PROJ/repo"}},
+		{name: "gitlab subgroup", input: "https://gitlab.com/group/sub/api.git", want: Spec{Host: "gitlab.com", Owner: "group/sub", Repo: "api", RepoKey: "gitlab.com/group/sub/api"}},
+		{name: "self-hosted gitlab subgroup", input: "git@gitlab.example.com:group/sub/api.git", want: Spec{Host: "gitlab.example.com", Owner: "group/sub", Repo: "api", RepoKey: "gitlab.example.com/group/sub/api"}},
+		{name: "gitlab group named scm", input: "https://gitlab.com/scm/sub/api.git", want: Spec{Host: "gitlab.com", Owner: "scm/sub", Repo: "api", RepoKey: "gitlab.com/scm/sub/api"}},
+		{name: "empty", input: "  ", wantErr: true},
+		{name: "unsupported scheme", input: "http://github.com/example-org/api", wantErr: true},
+		{name: "missing owner", input: "https://github.com/api", wantErr: true},
+		{name: "dot dot escapes pool", input: "git@h:../../../tmp/evil.git", wantErr: true},
+		{name: "dot segment", input: "https://github.com/./api", wantErr: true},
+		{name: "empty segment", input: "git@github.com:example-org//api.git", wantErr: true},
+		{name: "dot dot file host", input: "file:///tmp/../example-org/api.git", wantErr: true},
+		{name: "nested path on github", input: "https://github.com/o/r/tree/main", wantErr: true},
+		{name: "nested path on bitbucket", input: "https://bb.example.com/scm/PROJ/repo/extra", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Normalize(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Normalize(%q) = %+v, want error", tc.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize(%q) error: %v", tc.input, err)
+			}
+			if got != tc.want {
+				t.Fatalf("Normalize(%q) = %+v, want %+v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNestedNamespaceHost(t *testing.T) {
+	SetNestedNamespaceHosts([]string{" Code.Example.com "})
+	t.Cleanup(func() { SetNestedNamespaceHosts(nil) })
+
+	cases := map[string]bool{
+		"gitlab.com":         true,
+		"gitlab.example.com": true,
+		"code.example.com":   true,
+		"github.com":         false,
+		"mygitlab.com":       false,
+		"":                   false,
+	}
+	for host, want := range cases {
+		if got := NestedNamespaceHost(host); got != want {
+			t.Fatalf("NestedNamespaceHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+
+	spec, err := Normalize("git@code.example.com:group/sub/api.git")
+	if err != nil || spec.Owner != "group/sub" {
+		t.Fatalf("Normalize on configured host = %+v, %v", spec, err)
+	}
+}
@@ -0,0 +1,107 @@
+package ticketnotes
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DefaultPath is the workspace-relative notes file written for Jira-backed workspaces.
+const DefaultPath = "notes/ticket.md"
+
+type Link struct {
+	Relation string
+	Key      string
+	Summary  string
+	Status   string
+}
+
+// Ticket is the issue context rendered into the notes file.
+// Description and AcceptanceCriteria are Markdown.
+type Ticket struct {
+	Key                string
+	Summary            string
+	URL                string
+	IssueType          string
+	Status             string
+	Assignee           string
+	Labels             []string
+	Components         []string
+	Description        string
+	AcceptanceCriteria string
+	Links              []Link
+}
+
+// ResolvePath returns the cleaned workspace-relative notes path (DefaultPath when raw is empty).
+// The path must stay inside the workspace and must not target kra-managed entries.
+func ResolvePath(raw string) (string, error) {
+	p := strings.TrimSpace(raw)
+	if p == "" {
+		return DefaultPath, nil
+	}
+	p = strings.ReplaceAll(p, "\\", "/")
+	if path.IsAbs(p) {
+		return "", fmt.Errorf("notes path must be relative to the workspace: %q", raw)
+	}
+	p = path.Clean(p)
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("notes path must stay inside the workspace: %q", raw)
+	}
+	top := strings.SplitN(p, "/", 2)[0]
+	switch top {
+	case "repos", ".git", ".kra.meta.json":
+		return "", fmt.Errorf("notes path must not use reserved path %q: %q", top, raw)
+	}
+	return p, nil
+}
+
+func Render(t Ticket) string {
+	var b strings.Builder
+	heading := strings.TrimSpace(t.Key)
+	if summary := strings.TrimSpace(t.Summary); summary != "" {
+		heading += ": " + summary
+	}
+	fmt.Fprintf(&b, "# %s\n", heading)
+
+	meta := make([]string, 0, 6)
+	addMeta := func(label string, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			meta = append(meta, fmt.Sprintf("- %s: %s", label, value))
+		}
+	}
+	addMeta("URL", t.URL)
+	addMeta("Type", t.IssueType)
+	addMeta("Status", t.Status)
+	addMeta("Assignee", t.Assignee)
+	addMeta("Labels", strings.Join(t.Labels, ", "))
+	addMeta("Components", strings.Join(t.Components, ", "))
+	if len(meta) > 0 {
+		b.WriteString("\n" + strings.Join(meta, "\n") + "\n")
+	}
+
+	writeSection := func(title string, body string) {
+		if body = strings.TrimSpace(body); body != "" {
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", title, body)
+		}
+	}
+	writeSection("Description", t.Description)
+	writeSection("Acceptance Criteria", t.AcceptanceCriteria)
+
+	links := make([]string, 0, len(t.Links))
+	for _, l := range t.Links {
+		line := "- "
+		if rel := strings.TrimSpace(l.Relation); rel != "" {
+			line += rel + " "
+		}
+		line += strings.TrimSpace(l.Key)
+		if summary := strings.TrimSpace(l.Summary); summary != "" {
+			line += ": " + summary
+		}
+		if status := strings.TrimSpace(l.Status); status != "" {
+			line += " (" + status + ")"
+		}
+		links = append(links, line)
+	}
+	writeSection("Linked Issues", strings.Join(links, "\n"))
+	return b.String()
+}
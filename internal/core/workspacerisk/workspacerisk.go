@@ -53,6 +53,50 @@ func ClassifyRepoStatus(status RepoStatus) RepoState {
 	return RepoStateClean
 }
 
+// ClassifyReferenceRepoStatus classifies a reference (detached, read-only) repo binding.
+// It has no branch to push, so only local modifications count as risk.
+func ClassifyReferenceRepoStatus(status RepoStatus) RepoState {
+	if status.Error != nil {
+		return RepoStateUnknown
+	}
+	if status.Dirty {
+		return RepoStateDirty
+	}
+	return RepoStateClean
+}
+
+// ClassifyStackBranchStatus classifies a stacked branch that is not checked out (Dirty/Detached do not apply).
+// Upstream is empty when origin/<branch> does not exist; AheadCount then counts commits not on any remote.
+func ClassifyStackBranchStatus(status RepoStatus) RepoState {
+	if status.Error != nil {
+		return RepoStateUnknown
+	}
+	if status.AheadCount > 0 && status.BehindCount > 0 {
+		return RepoStateDiverged
+	}
+	if status.AheadCount > 0 {
+		return RepoStateUnpushed
+	}
+	return RepoStateClean
+}
+
+// MostSevereRepoState returns the riskiest state using the Aggregate order
+// (unknown > dirty > diverged > unpushed > clean).
+func MostSevereRepoState(states ...RepoState) RepoState {
+	switch Aggregate(states) {
+	case WorkspaceRiskUnknown:
+		return RepoStateUnknown
+	case WorkspaceRiskDirty:
+		return RepoStateDirty
+	case WorkspaceRiskDiverged:
+		return RepoStateDiverged
+	case WorkspaceRiskUnpushed:
+		return RepoStateUnpushed
+	default:
+		return RepoStateClean
+	}
+}
+
 func Aggregate(repos []RepoState) WorkspaceRisk {
 	hasDirty := false
 	hasUnknown := false
@@ -0,0 +1,117 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PullRequestInput describes a pull request (merge request) to open.
+type PullRequestInput struct {
+	// Repo is the repository path on the forge ("<owner>/<repo>"; owner may be a nested namespace).
+	Repo  string
+	Head  string
+	Base  string
+	Title string
+	Body  string
+	Draft bool
+}
+
+// PullRequest is a pull request (merge request) opened on a forge.
+type PullRequest struct {
+	// Number is the PR number (GitHub) or the MR iid (GitLab).
+	Number int
+	URL    string
+}
+
+// Forge opens pull requests on a hosting service.
+type Forge interface {
+	Name() string
+	CreatePullRequest(ctx context.Context, in PullRequestInput) (PullRequest, error)
+	// UpdatePullRequestBody replaces the description of an existing pull request.
+	UpdatePullRequestBody(ctx context.Context, repo string, number int, body string) error
+}
+
+type Factory func() Forge
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{
+		"github": func() Forge { return NewGitHubForge(nil) },
+		"gitlab": func() Forge { return NewGitLabForge(nil) },
+	}
+)
+
+func Register(name string, factory Factory) error {
+	normalized := normalizeName(name)
+	if normalized == "" {
+		return fmt.Errorf("forge name is required")
+	}
+	if factory == nil {
+		return fmt.Errorf("forge factory is required")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[normalized]; exists {
+		return fmt.Errorf("forge already registered: %q", normalized)
+	}
+	registry[normalized] = factory
+	return nil
+}
+
+func Supported() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func New(name string) (Forge, error) {
+	normalized := normalizeName(name)
+	registryMu.RLock()
+	factory, ok := registry[normalized]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported forge: %q (supported: %s)", normalized, strings.Join(Supported(), ", "))
+	}
+	return factory(), nil
+}
+
+// repoHosts resolves, per built-in forge, the repo host served by its configured API base URL.
+var repoHosts = []struct {
+	name string
+	host func() (string, error)
+}{
+	{name: "github", host: gitHubRepoHost},
+	{name: "gitlab", host: gitLabRepoHost},
+}
+
+// NameForHost picks the forge whose configured API serves repos on host (github.com / gitlab.com by
+// default, or the host of KRA_GITHUB_BASE_URL / KRA_GITLAB_BASE_URL), so requests never go to the API
+// of another host. Hosts no forge is configured for are not supported.
+func NameForHost(host string) (string, error) {
+	h := strings.ToLower(strings.TrimSpace(host))
+	configured := make([]string, 0, len(repoHosts))
+	for _, rh := range repoHosts {
+		apiHost, err := rh.host()
+		if err != nil {
+			configured = append(configured, fmt.Sprintf("%s: %v", rh.name, err))
+			continue
+		}
+		if h != "" && apiHost == h {
+			return rh.name, nil
+		}
+		configured = append(configured, fmt.Sprintf("%s: %s", rh.name, apiHost))
+	}
+	return "", fmt.Errorf("no forge configured for host %q (%s; set %s or %s)", host, strings.Join(configured, ", "), envGitHubBaseURL, envGitLabBaseURL)
+}
@@ -0,0 +1,59 @@
+package forge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNameForHost(t *testing.T) {
+	t.Setenv(envGitHubBaseURL, "")
+	t.Setenv(envGitLabBaseURL, "")
+	cases := map[string]string{
+		"github.com": "github",
+		"GitLab.com": "gitlab",
+	}
+	for host, want := range cases {
+		got, err := NameForHost(host)
+		if err != nil || got != want {
+			t.Fatalf("NameForHost(%q) = %q, %v; want %q", host, got, err, want)
+		}
+	}
+	for _, host := range []string{"bitbucket.org", "github.example.com", "gitlab.example.com", ""} {
+		if _, err := NameForHost(host); err == nil {
+			t.Fatalf("NameForHost(%q) should fail without a matching base URL", host)
+		}
+	}
+}
+
+func TestNameForHost_MatchesConfiguredBaseURL(t *testing.T) {
+	t.Setenv(envGitHubBaseURL, "https://ghe.example.com/api/v3")
+	t.Setenv(envGitLabBaseURL, "https://code.example.com/")
+	cases := map[string]string{
+		"ghe.example.com":  "github",
+		"code.example.com": "gitlab",
+	}
+	for host, want := range cases {
+		got, err := NameForHost(host)
+		if err != nil || got != want {
+			t.Fatalf("NameForHost(%q) = %q, %v; want %q", host, got, err, want)
+		}
+	}
+	// Repos on the public hosts must not be sent to the enterprise APIs.
+	_, err := NameForHost("github.com")
+	if err == nil || !strings.Contains(err.Error(), `no forge configured for host "github.com"`) || !strings.Contains(err.Error(), "github: ghe.example.com") {
+		t.Fatalf("NameForHost(github.com) err = %v", err)
+	}
+
+	t.Setenv(envGitHubBaseURL, "https://api.ghe.example.com")
+	if got, err := NameForHost("ghe.example.com"); err != nil || got != "github" {
+		t.Fatalf("NameForHost with api. base URL = %q, %v; want github", got, err)
+	}
+
+	t.Setenv(envGitHubBaseURL, "not a url")
+	if got, err := NameForHost("code.example.com"); err != nil || got != "gitlab" {
+		t.Fatalf("invalid github base URL should not block gitlab: %q, %v", got, err)
+	}
+	if _, err := NameForHost("ghe.example.com"); err == nil || !strings.Contains(err.Error(), "invalid "+envGitHubBaseURL) {
+		t.Fatalf("invalid github base URL should be reported: %v", err)
+	}
+}
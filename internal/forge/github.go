@@ -0,0 +1,115 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/httpapi"
+)
+
+const (
+	envGitHubBaseURL     = "KRA_GITHUB_BASE_URL"
+	envGitHubToken       = "KRA_GITHUB_TOKEN"
+	defaultGitHubBaseURL = "https://api.github.com"
+)
+
+// GitHubForge opens pull requests via the GitHub REST API.
+// KRA_GITHUB_BASE_URL points at the API root (GitHub Enterprise: https://<host>/api/v3).
+type GitHubForge struct {
+	httpClient *http.Client
+}
+
+type gitHubEnvConfig struct {
+	baseURL string
+	token   string
+}
+
+func NewGitHubForge(httpClient *http.Client) *GitHubForge {
+	return &GitHubForge{httpClient: httpapi.NewDefaultClient(httpClient)}
+}
+
+func (f *GitHubForge) Name() string {
+	return "github"
+}
+
+func (f *GitHubForge) CreatePullRequest(ctx context.Context, in PullRequestInput) (PullRequest, error) {
+	cfg, err := loadGitHubEnvConfig()
+	if err != nil {
+		return PullRequest{}, err
+	}
+	payload := map[string]any{
+		"title": in.Title,
+		"head":  in.Head,
+		"base":  in.Base,
+		"body":  in.Body,
+		"draft": in.Draft,
+	}
+	var created struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/pulls", cfg.baseURL, strings.Trim(in.Repo, "/"))
+	if err := sendJSON(ctx, f.httpClient, "github", http.MethodPost, endpoint, cfg.authorize, payload, &created); err != nil {
+		return PullRequest{}, fmt.Errorf("create github pull request for %s: %w", in.Repo, err)
+	}
+	return PullRequest{Number: created.Number, URL: created.HTMLURL}, nil
+}
+
+func (f *GitHubForge) UpdatePullRequestBody(ctx context.Context, repo string, number int, body string) error {
+	cfg, err := loadGitHubEnvConfig()
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/pulls/%d", cfg.baseURL, strings.Trim(repo, "/"), number)
+	if err := sendJSON(ctx, f.httpClient, "github", http.MethodPatch, endpoint, cfg.authorize, map[string]any{"body": body}, nil); err != nil {
+		return fmt.Errorf("update github pull request %s#%d: %w", repo, number, err)
+	}
+	return nil
+}
+
+func (cfg gitHubEnvConfig) authorize(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+cfg.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+}
+
+func loadGitHubEnvConfig() (gitHubEnvConfig, error) {
+	token := strings.TrimSpace(os.Getenv(envGitHubToken))
+	if token == "" {
+		return gitHubEnvConfig{}, fmt.Errorf("missing github env vars: %s", envGitHubToken)
+	}
+	baseURL, err := loadGitHubBaseURL()
+	if err != nil {
+		return gitHubEnvConfig{}, err
+	}
+	return gitHubEnvConfig{baseURL: baseURL, token: token}, nil
+}
+
+func loadGitHubBaseURL() (string, error) {
+	baseURLRaw := strings.TrimSpace(os.Getenv(envGitHubBaseURL))
+	if baseURLRaw == "" {
+		baseURLRaw = defaultGitHubBaseURL
+	}
+	return httpapi.ParseBaseURL(envGitHubBaseURL, baseURLRaw)
+}
+
+// gitHubRepoHost returns the host of the repos served by the configured API: the API host without its
+// "api." label (api.github.com -> github.com), or the host itself for GitHub Enterprise (<host>/api/v3).
+func gitHubRepoHost() (string, error) {
+	baseURL, err := loadGitHubBaseURL()
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	host := strings.ToLower(u.Hostname())
+	if rest, ok := strings.CutPrefix(host, "api."); ok && strings.Contains(rest, ".") {
+		return rest, nil
+	}
+	return host, nil
+}
@@ -0,0 +1,78 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitHubForge_CreateAndUpdatePullRequest(t *testing.T) {
+	var patched string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer ghp-test" {
+			t.Fatalf("Authorization = %q", got)
+		}
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/api/pulls":
+			if payload["head"] != "WS1" || payload["base"] != "main" || payload["title"] != "WS1: Fix" || payload["draft"] != true {
+				t.Fatalf("unexpected create payload: %+v", payload)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"number":12,"html_url":"https://github.com/acme/api/pull/12"}`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/api/pulls/12":
+			patched, _ = payload["body"].(string)
+			_, _ = fmt.Fprint(w, `{}`)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(envGitHubBaseURL, server.URL)
+	t.Setenv(envGitHubToken, "ghp-test")
+
+	f := NewGitHubForge(server.Client())
+	pr, err := f.CreatePullRequest(context.Background(), PullRequestInput{Repo: "acme/api", Head: "WS1", Base: "main", Title: "WS1: Fix", Body: "body", Draft: true})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error: %v", err)
+	}
+	if pr.Number != 12 || pr.URL != "https://github.com/acme/api/pull/12" {
+		t.Fatalf("pr = %+v", pr)
+	}
+	if err := f.UpdatePullRequestBody(context.Background(), "acme/api", 12, "new body"); err != nil {
+		t.Fatalf("UpdatePullRequestBody() error: %v", err)
+	}
+	if patched != "new body" {
+		t.Fatalf("patched body = %q", patched)
+	}
+}
+
+func TestGitHubForge_CreatePullRequest_ReportsAPIMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = fmt.Fprint(w, `{"message":"Validation Failed"}`)
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(envGitHubBaseURL, server.URL)
+	t.Setenv(envGitHubToken, "ghp-test")
+
+	_, err := NewGitHubForge(server.Client()).CreatePullRequest(context.Background(), PullRequestInput{Repo: "acme/api", Head: "WS1", Base: "main"})
+	if err == nil || !strings.Contains(err.Error(), "status=422: Validation Failed") {
+		t.Fatalf("error = %v, want validation message", err)
+	}
+}
+
+func TestGitHubForge_RequiresToken(t *testing.T) {
+	t.Setenv(envGitHubToken, "")
+	_, err := NewGitHubForge(nil).CreatePullRequest(context.Background(), PullRequestInput{Repo: "acme/api"})
+	if err == nil || !strings.Contains(err.Error(), envGitHubToken) {
+		t.Fatalf("error = %v, want missing token", err)
+	}
+}
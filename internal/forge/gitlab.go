@@ -0,0 +1,115 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/httpapi"
+)
+
+const (
+	envGitLabBaseURL     = "KRA_GITLAB_BASE_URL"
+	envGitLabToken       = "KRA_GITLAB_TOKEN"
+	defaultGitLabBaseURL = "https://gitlab.com"
+)
+
+// GitLabForge opens merge requests via the GitLab REST API v4.
+type GitLabForge struct {
+	httpClient *http.Client
+}
+
+type gitLabEnvConfig struct {
+	baseURL string
+	token   string
+}
+
+func NewGitLabForge(httpClient *http.Client) *GitLabForge {
+	return &GitLabForge{httpClient: httpapi.NewDefaultClient(httpClient)}
+}
+
+func (f *GitLabForge) Name() string {
+	return "gitlab"
+}
+
+// CreatePullRequest opens a merge request. Draft MRs are created with the "Draft: " title prefix.
+func (f *GitLabForge) CreatePullRequest(ctx context.Context, in PullRequestInput) (PullRequest, error) {
+	cfg, err := loadGitLabEnvConfig()
+	if err != nil {
+		return PullRequest{}, err
+	}
+	title := in.Title
+	if in.Draft && !strings.HasPrefix(title, "Draft:") {
+		title = "Draft: " + title
+	}
+	payload := map[string]any{
+		"source_branch": in.Head,
+		"target_branch": in.Base,
+		"title":         title,
+		"description":   in.Body,
+	}
+	var created struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	if err := sendJSON(ctx, f.httpClient, "gitlab", http.MethodPost, cfg.mergeRequestsURL(in.Repo), cfg.authorize, payload, &created); err != nil {
+		return PullRequest{}, fmt.Errorf("create gitlab merge request for %s: %w", in.Repo, err)
+	}
+	return PullRequest{Number: created.IID, URL: created.WebURL}, nil
+}
+
+func (f *GitLabForge) UpdatePullRequestBody(ctx context.Context, repo string, number int, body string) error {
+	cfg, err := loadGitLabEnvConfig()
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/%d", cfg.mergeRequestsURL(repo), number)
+	if err := sendJSON(ctx, f.httpClient, "gitlab", http.MethodPut, endpoint, cfg.authorize, map[string]any{"description": body}, nil); err != nil {
+		return fmt.Errorf("update gitlab merge request %s!%d: %w", repo, number, err)
+	}
+	return nil
+}
+
+func (cfg gitLabEnvConfig) mergeRequestsURL(repo string) string {
+	return fmt.Sprintf("%s/api/v4/projects/%s/merge_requests", cfg.baseURL, url.PathEscape(strings.Trim(repo, "/")))
+}
+
+func (cfg gitLabEnvConfig) authorize(req *http.Request) {
+	req.Header.Set("PRIVATE-TOKEN", cfg.token)
+}
+
+func loadGitLabEnvConfig() (gitLabEnvConfig, error) {
+	token := strings.TrimSpace(os.Getenv(envGitLabToken))
+	if token == "" {
+		return gitLabEnvConfig{}, fmt.Errorf("missing gitlab env vars: %s", envGitLabToken)
+	}
+	baseURL, err := loadGitLabBaseURL()
+	if err != nil {
+		return gitLabEnvConfig{}, err
+	}
+	return gitLabEnvConfig{baseURL: baseURL, token: token}, nil
+}
+
+func loadGitLabBaseURL() (string, error) {
+	baseURLRaw := strings.TrimSpace(os.Getenv(envGitLabBaseURL))
+	if baseURLRaw == "" {
+		baseURLRaw = defaultGitLabBaseURL
+	}
+	return httpapi.ParseBaseURL(envGitLabBaseURL, baseURLRaw)
+}
+
+// gitLabRepoHost returns the host of the repos served by the configured API, which is the API host itself.
+func gitLabRepoHost() (string, error) {
+	baseURL, err := loadGitLabBaseURL()
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(u.Hostname()), nil
+}
@@ -0,0 +1,54 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitLabForge_CreateDraftMergeRequestAndUpdateDescription(t *testing.T) {
+	var updated string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "glpat-test" {
+			t.Fatalf("PRIVATE-TOKEN = %q", got)
+		}
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/api/v4/projects/acme%2Fplatform%2Fapi/merge_requests":
+			if payload["source_branch"] != "WS1" || payload["target_branch"] != "main" || payload["title"] != "Draft: WS1" {
+				t.Fatalf("unexpected create payload: %+v", payload)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"iid":3,"web_url":"https://gitlab.example.com/acme/platform/api/-/merge_requests/3"}`)
+		case r.Method == http.MethodPut && r.URL.EscapedPath() == "/api/v4/projects/acme%2Fplatform%2Fapi/merge_requests/3":
+			updated, _ = payload["description"].(string)
+			_, _ = fmt.Fprint(w, `{}`)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.EscapedPath())
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(envGitLabBaseURL, server.URL)
+	t.Setenv(envGitLabToken, "glpat-test")
+
+	f := NewGitLabForge(server.Client())
+	mr, err := f.CreatePullRequest(context.Background(), PullRequestInput{Repo: "acme/platform/api", Head: "WS1", Base: "main", Title: "WS1", Draft: true})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error: %v", err)
+	}
+	if mr.Number != 3 || mr.URL != "https://gitlab.example.com/acme/platform/api/-/merge_requests/3" {
+		t.Fatalf("mr = %+v", mr)
+	}
+	if err := f.UpdatePullRequestBody(context.Background(), "acme/platform/api", 3, "linked"); err != nil {
+		t.Fatalf("UpdatePullRequestBody() error: %v", err)
+	}
+	if updated != "linked" {
+		t.Fatalf("updated description = %q", updated)
+	}
+}
@@ -0,0 +1,70 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// sendJSON sends payload as a JSON body and decodes a 2xx JSON response into out (when non-nil).
+// Error responses include the forge message when the body carries one.
+func sendJSON(ctx context.Context, client *http.Client, forge string, method string, endpoint string, authorize func(*http.Request), payload any, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s request: %w", forge, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build %s request: %w", forge, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if authorize != nil {
+		authorize(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", forge, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		// continue
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%s authentication failed: status=%d", forge, resp.StatusCode)
+	default:
+		if msg := readAPIErrorMessage(resp.Body); msg != "" {
+			return fmt.Errorf("%s request failed: status=%d: %s", forge, resp.StatusCode, msg)
+		}
+		return fmt.Errorf("%s request failed: status=%d", forge, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", forge, err)
+	}
+	return nil
+}
+
+// readAPIErrorMessage extracts "message" (GitHub, GitLab) from an error body.
+func readAPIErrorMessage(r io.Reader) string {
+	var payload struct {
+		Message json.RawMessage `json:"message"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r, 64<<10)).Decode(&payload); err != nil || len(payload.Message) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(payload.Message, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	// GitLab may return message as an array or object.
+	return strings.TrimSpace(string(payload.Message))
+}
@@ -39,6 +39,29 @@ func Run(ctx context.Context, dir string, args ...string) (string, error) {
 	return s, nil
 }
 
+// Output runs git and returns stdout verbatim (not trimmed, stderr excluded), for
+// output that must be preserved byte-for-byte such as patches.
+func Output(ctx context.Context, dir string, args ...string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("git args are required")
+	}
+	if err := EnsureGitInPath(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return string(out), fmt.Errorf("git %s failed: %w (output=%s)", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
 func RunBare(ctx context.Context, gitDir string, args ...string) (string, error) {
 	if strings.TrimSpace(gitDir) == "" {
 		return "", fmt.Errorf("git dir is required")
@@ -127,6 +150,13 @@ func DefaultBranchFromRemote(ctx context.Context, remoteURL string) (string, err
 }
 
 func EnsureBareRepoFetched(ctx context.Context, remoteURL string, barePath string, fallbackDefaultBranch string) (defaultBaseRef string, err error) {
+	return EnsureBareRepoFetchedWithFilter(ctx, remoteURL, barePath, fallbackDefaultBranch, "")
+}
+
+// EnsureBareRepoFetchedWithFilter is EnsureBareRepoFetched with an optional partial clone filter
+// (e.g. "blob:none"). For an existing bare repo the filter is recorded in remote.origin so later
+// fetches stay partial; objects already present are kept.
+func EnsureBareRepoFetchedWithFilter(ctx context.Context, remoteURL string, barePath string, fallbackDefaultBranch string, filter string) (defaultBaseRef string, err error) {
 	remoteURL = strings.TrimSpace(remoteURL)
 	if remoteURL == "" {
 		return "", fmt.Errorf("remote url is required")
@@ -140,7 +170,18 @@ func EnsureBareRepoFetched(ctx context.Context, remoteURL string, barePath strin
 		if !os.IsNotExist(statErr) {
 			return "", fmt.Errorf("stat bare repo: %w", statErr)
 		}
-		if _, err := Run(ctx, "", "clone", "--bare", remoteURL, barePath); err != nil {
+		cloneArgs := []string{"clone", "--bare"}
+		if filter = strings.TrimSpace(filter); filter != "" {
+			cloneArgs = append(cloneArgs, "--filter="+filter)
+		}
+		if _, err := Run(ctx, "", append(cloneArgs, remoteURL, barePath)...); err != nil {
+			return "", err
+		}
+	} else if filter = strings.TrimSpace(filter); filter != "" {
+		if _, err := RunBare(ctx, barePath, "config", "remote.origin.promisor", "true"); err != nil {
+			return "", err
+		}
+		if _, err := RunBare(ctx, barePath, "config", "remote.origin.partialclonefilter", filter); err != nil {
 			return "", err
 		}
 	}
@@ -0,0 +1,29 @@
+// Package httpapi holds the HTTP helpers shared by the REST API clients (forge, repodiscovery).
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DefaultTimeout = 30 * time.Second
+
+// NewDefaultClient returns client, or a client with DefaultTimeout when it is nil.
+func NewDefaultClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Timeout: DefaultTimeout}
+}
+
+// ParseBaseURL validates an API base URL taken from envName and returns it without a trailing slash.
+func ParseBaseURL(envName string, raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid %s: %q", envName, raw)
+	}
+	return strings.TrimRight(u.String(), "/"), nil
+}
@@ -0,0 +1,28 @@
+package httpapi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseBaseURL(t *testing.T) {
+	got, err := ParseBaseURL("KRA_X_BASE_URL", " https://ghe.example.com/api/v3/ ")
+	if err != nil || got != "https://ghe.example.com/api/v3" {
+		t.Fatalf("ParseBaseURL = %q, %v", got, err)
+	}
+	for _, raw := range []string{"", "ghe.example.com", "https://", "://x"} {
+		if _, err := ParseBaseURL("KRA_X_BASE_URL", raw); err == nil {
+			t.Fatalf("ParseBaseURL(%q) should fail", raw)
+		}
+	}
+}
+
+func TestNewDefaultClient(t *testing.T) {
+	custom := &http.Client{}
+	if NewDefaultClient(custom) != custom {
+		t.Fatalf("custom client should be kept")
+	}
+	if c := NewDefaultClient(nil); c.Timeout != DefaultTimeout {
+		t.Fatalf("default client timeout = %v, want %v", c.Timeout, DefaultTimeout)
+	}
+}
@@ -0,0 +1,33 @@
+package appports
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tasuku43/kra/internal/core/ticketnotes"
+	"github.com/tasuku43/kra/internal/infra/jira"
+)
+
+func fetchJiraTicket(ctx context.Context, client *jira.Client, issueKey string) (ticketnotes.Ticket, error) {
+	d, err := client.FetchIssueDetail(ctx, issueKey)
+	if err != nil {
+		return ticketnotes.Ticket{}, fmt.Errorf("fetch jira issue detail: %w", err)
+	}
+	links := make([]ticketnotes.Link, 0, len(d.Links))
+	for _, l := range d.Links {
+		links = append(links, ticketnotes.Link{Relation: l.Relation, Key: l.Key, Summary: l.Summary, Status: l.Status})
+	}
+	return ticketnotes.Ticket{
+		Key:                d.Key,
+		Summary:            d.Summary,
+		URL:                d.TicketURL,
+		IssueType:          d.IssueType,
+		Status:             d.Status,
+		Assignee:           d.Assignee,
+		Labels:             d.Labels,
+		Components:         d.Components,
+		Description:        d.Description,
+		AcceptanceCriteria: d.AcceptanceCriteria,
+		Links:              links,
+	}, nil
+}
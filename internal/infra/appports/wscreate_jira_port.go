@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/tasuku43/kra/internal/app/wscreate"
+	"github.com/tasuku43/kra/internal/core/ticketnotes"
 	"github.com/tasuku43/kra/internal/infra/jira"
 )
 
@@ -20,10 +21,24 @@ func NewWSCreateJiraPortWithBaseURL(baseURL string) *WSCreateJiraPort {
 	return &WSCreateJiraPort{client: jira.NewClientWithBaseURL(baseURL)}
 }
 
+func NewWSCreateJiraPortWithClient(client *jira.Client) *WSCreateJiraPort {
+	return &WSCreateJiraPort{client: client}
+}
+
 func (p *WSCreateJiraPort) FetchIssueByTicketURL(ctx context.Context, ticketURL string) (wscreate.JiraIssue, error) {
-	key, summary, err := p.client.FetchIssueByTicketURL(ctx, ticketURL)
+	issue, err := p.client.FetchIssueByTicketURL(ctx, ticketURL)
 	if err != nil {
 		return wscreate.JiraIssue{}, fmt.Errorf("fetch jira issue: %w", err)
 	}
-	return wscreate.JiraIssue{Key: key, Summary: summary}, nil
+	return wscreate.JiraIssue{
+		Key:        issue.Key,
+		Summary:    issue.Summary,
+		IssueType:  issue.IssueType,
+		Labels:     issue.Labels,
+		ProjectKey: issue.ProjectKey,
+	}, nil
+}
+
+func (p *WSCreateJiraPort) FetchTicket(ctx context.Context, issueKey string) (ticketnotes.Ticket, error) {
+	return fetchJiraTicket(ctx, p.client, issueKey)
 }
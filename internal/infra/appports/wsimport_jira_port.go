@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/tasuku43/kra/internal/app/wsimport"
+	"github.com/tasuku43/kra/internal/core/ticketnotes"
 	"github.com/tasuku43/kra/internal/infra/jira"
 )
 
@@ -20,6 +21,10 @@ func NewWSImportJiraPortWithBaseURL(baseURL string) *WSImportJiraPort {
 	return &WSImportJiraPort{client: jira.NewClientWithBaseURL(baseURL)}
 }
 
+func NewWSImportJiraPortWithClient(client *jira.Client) *WSImportJiraPort {
+	return &WSImportJiraPort{client: client}
+}
+
 func (p *WSImportJiraPort) SearchIssuesByJQL(ctx context.Context, jql string, maxResults int) ([]wsimport.JiraIssue, error) {
 	issues, err := p.client.SearchIssuesByJQL(ctx, jql, maxResults)
 	if err != nil {
@@ -28,9 +33,12 @@ func (p *WSImportJiraPort) SearchIssuesByJQL(ctx context.Context, jql string, ma
 	out := make([]wsimport.JiraIssue, 0, len(issues))
 	for _, it := range issues {
 		out = append(out, wsimport.JiraIssue{
-			Key:       it.Key,
-			Summary:   it.Summary,
-			TicketURL: it.TicketURL,
+			Key:        it.Key,
+			Summary:    it.Summary,
+			TicketURL:  it.TicketURL,
+			IssueType:  it.IssueType,
+			Labels:     it.Labels,
+			ProjectKey: it.ProjectKey,
 		})
 	}
 	return out, nil
@@ -114,3 +122,7 @@ func (p *WSImportJiraPort) ListProjectOpenSprints(ctx context.Context, projectKe
 	}
 	return out, nil
 }
+
+func (p *WSImportJiraPort) FetchTicket(ctx context.Context, issueKey string) (ticketnotes.Ticket, error) {
+	return fetchJiraTicket(ctx, p.client, issueKey)
+}
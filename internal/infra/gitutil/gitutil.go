@@ -10,6 +10,10 @@ func Run(ctx context.Context, dir string, args ...string) (string, error) {
 	return base.Run(ctx, dir, args...)
 }
 
+func Output(ctx context.Context, dir string, args ...string) (string, error) {
+	return base.Output(ctx, dir, args...)
+}
+
 func RunBare(ctx context.Context, barePath string, args ...string) (string, error) {
 	return base.RunBare(ctx, barePath, args...)
 }
@@ -22,6 +26,10 @@ func EnsureBareRepoFetched(ctx context.Context, repoSpecInput string, barePath s
 	return base.EnsureBareRepoFetched(ctx, repoSpecInput, barePath, defaultBranch)
 }
 
+func EnsureBareRepoFetchedWithFilter(ctx context.Context, repoSpecInput string, barePath string, defaultBranch string, filter string) (string, error) {
+	return base.EnsureBareRepoFetchedWithFilter(ctx, repoSpecInput, barePath, defaultBranch, filter)
+}
+
 func DefaultBranchFromRemote(ctx context.Context, repoSpecInput string) (string, error) {
 	return base.DefaultBranchFromRemote(ctx, repoSpecInput)
 }
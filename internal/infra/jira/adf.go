@@ -0,0 +1,279 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type adfNode struct {
+	Type    string         `json:"type"`
+	Text    string         `json:"text"`
+	Content []adfNode      `json:"content"`
+	Marks   []adfMark      `json:"marks"`
+	Attrs   map[string]any `json:"attrs"`
+}
+
+type adfMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs"`
+}
+
+// ADFToMarkdown converts an Atlassian Document Format value into Markdown.
+// Plain string values (legacy wiki text) are returned as-is; null/empty yields "".
+func ADFToMarkdown(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return ""
+		}
+		return strings.TrimSpace(s)
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	out := renderADFBlock(doc)
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func renderADFBlocks(nodes []adfNode, sep string) string {
+	parts := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if s := renderADFBlock(n); strings.TrimSpace(s) != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func renderADFBlock(n adfNode) string {
+	switch n.Type {
+	case "doc":
+		return renderADFBlocks(n.Content, "\n\n")
+	case "paragraph":
+		return renderADFInline(n.Content)
+	case "heading":
+		level := adfAttrInt(n.Attrs, "level")
+		if level < 1 || level > 6 {
+			level = 1
+		}
+		return strings.Repeat("#", level) + " " + renderADFInline(n.Content)
+	case "bulletList":
+		return renderADFList(n.Content, func(int) string { return "- " })
+	case "orderedList":
+		start := adfAttrInt(n.Attrs, "order")
+		if start < 1 {
+			start = 1
+		}
+		return renderADFList(n.Content, func(i int) string { return strconv.Itoa(start+i) + ". " })
+	case "taskList":
+		items := make([]string, 0, len(n.Content))
+		for _, item := range n.Content {
+			box := "[ ]"
+			if adfAttrString(item.Attrs, "state") == "DONE" {
+				box = "[x]"
+			}
+			items = append(items, prefixADFLines("- "+box+" ", renderADFInline(item.Content)))
+		}
+		return strings.Join(items, "\n")
+	case "codeBlock":
+		lang := adfAttrString(n.Attrs, "language")
+		return "```" + lang + "\n" + adfPlainText(n.Content) + "\n```"
+	case "blockquote", "panel":
+		return quoteADFLines(renderADFBlocks(n.Content, "\n\n"))
+	case "rule":
+		return "---"
+	case "table":
+		return renderADFTable(n)
+	case "expand", "nestedExpand":
+		body := renderADFBlocks(n.Content, "\n\n")
+		if title := adfAttrString(n.Attrs, "title"); title != "" {
+			return "**" + title + "**\n\n" + body
+		}
+		return body
+	case "mediaSingle", "mediaGroup", "media":
+		return ""
+	default:
+		if len(n.Content) > 0 {
+			if adfIsInline(n.Content[0]) {
+				return renderADFInline(n.Content)
+			}
+			return renderADFBlocks(n.Content, "\n\n")
+		}
+		return renderADFInline([]adfNode{n})
+	}
+}
+
+func renderADFList(items []adfNode, marker func(i int) string) string {
+	out := make([]string, 0, len(items))
+	for i, item := range items {
+		out = append(out, prefixADFLines(marker(i), renderADFBlocks(item.Content, "\n")))
+	}
+	return strings.Join(out, "\n")
+}
+
+// prefixADFLines puts marker before the first line and aligns the following lines under it.
+func prefixADFLines(marker string, body string) string {
+	lines := strings.Split(body, "\n")
+	pad := strings.Repeat(" ", len(marker))
+	for i := range lines {
+		if i == 0 {
+			lines[i] = marker + lines[i]
+			continue
+		}
+		if lines[i] != "" {
+			lines[i] = pad + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func quoteADFLines(body string) string {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+			continue
+		}
+		lines[i] = "> " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderADFTable(n adfNode) string {
+	rows := make([]string, 0, len(n.Content)+1)
+	for i, row := range n.Content {
+		cells := make([]string, 0, len(row.Content))
+		for _, cell := range row.Content {
+			text := strings.ReplaceAll(renderADFBlocks(cell.Content, " "), "\n", " ")
+			cells = append(cells, strings.ReplaceAll(text, "|", "\\|"))
+		}
+		rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			sep := make([]string, len(cells))
+			for j := range sep {
+				sep[j] = "---"
+			}
+			rows = append(rows, "| "+strings.Join(sep, " | ")+" |")
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+func renderADFInline(nodes []adfNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(applyADFMarks(n.Text, n.Marks))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			b.WriteString(adfAttrString(n.Attrs, "text"))
+		case "emoji":
+			if text := adfAttrString(n.Attrs, "text"); text != "" {
+				b.WriteString(text)
+			} else {
+				b.WriteString(adfAttrString(n.Attrs, "shortName"))
+			}
+		case "inlineCard", "blockCard":
+			if u := adfAttrString(n.Attrs, "url"); u != "" {
+				b.WriteString("<" + u + ">")
+			}
+		case "status":
+			b.WriteString("`" + adfAttrString(n.Attrs, "text") + "`")
+		case "date":
+			if ms, err := strconv.ParseInt(adfAttrString(n.Attrs, "timestamp"), 10, 64); err == nil {
+				b.WriteString(time.UnixMilli(ms).UTC().Format("2006-01-02"))
+			}
+		default:
+			b.WriteString(renderADFInline(n.Content))
+		}
+	}
+	return b.String()
+}
+
+func applyADFMarks(text string, marks []adfMark) string {
+	if text == "" {
+		return ""
+	}
+	link := ""
+	for _, m := range marks {
+		if m.Type == "code" {
+			text = "`" + text + "`"
+		}
+	}
+	for _, m := range marks {
+		switch m.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "_" + text + "_"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			link = adfAttrString(m.Attrs, "href")
+		}
+	}
+	if link != "" {
+		text = "[" + text + "](" + link + ")"
+	}
+	return text
+}
+
+func adfPlainText(nodes []adfNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		if n.Type == "hardBreak" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(n.Text)
+		b.WriteString(adfPlainText(n.Content))
+	}
+	return b.String()
+}
+
+func adfIsInline(n adfNode) bool {
+	switch n.Type {
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "status", "date":
+		return true
+	default:
+		return false
+	}
+}
+
+func adfAttrString(attrs map[string]any, key string) string {
+	v, ok := attrs[key]
+	if !ok || v == nil {
+		return ""
+	}
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return strings.TrimSpace(fmt.Sprint(t))
+	}
+}
+
+func adfAttrInt(attrs map[string]any, key string) int {
+	n, err := strconv.Atoi(adfAttrString(attrs, key))
+	if err != nil {
+		return 0
+	}
+	return n
+}
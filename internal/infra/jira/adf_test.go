@@ -0,0 +1,103 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestADFToMarkdown(t *testing.T) {
+	doc := `{"type":"doc","version":1,"content":[
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Goal"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Use "},
+			{"type":"text","text":"kra","marks":[{"type":"code"}]},
+			{"type":"text","text":" with "},
+			{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"hardBreak"},
+			{"type":"mention","attrs":{"text":"@dev"}}
+		]},
+		{"type":"orderedList","content":[
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"first","marks":[{"type":"em"}]}]},
+				{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"nested"}]}]}]}
+			]},
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"second"}]}]}
+		]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"fmt.Println(1)"}]},
+		{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"quoted"}]}]},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]},{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}]},
+			{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"1|2"}]}]},{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"3"}]}]}]}
+		]},
+		{"type":"rule"}
+	]}`
+	want := "## Goal\n\n" +
+		"Use `kra` with [docs](https://example.com)\n@dev\n\n" +
+		"1. _first_\n   - nested\n2. second\n\n" +
+		"```go\nfmt.Println(1)\n```\n\n" +
+		"> quoted\n\n" +
+		"| a | b |\n| --- | --- |\n| 1\\|2 | 3 |\n\n" +
+		"---"
+	if got := ADFToMarkdown(json.RawMessage(doc)); got != want {
+		t.Fatalf("ADFToMarkdown() =\n%s\nwant:\n%s", got, want)
+	}
+	if got := ADFToMarkdown(json.RawMessage(`"plain *wiki* text"`)); got != "plain *wiki* text" {
+		t.Fatalf("ADFToMarkdown(string) = %q", got)
+	}
+	if got := ADFToMarkdown(json.RawMessage(`null`)); got != "" {
+		t.Fatalf("ADFToMarkdown(null) = %q", got)
+	}
+}
+
+func TestClient_FetchIssueDetail_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-9" || r.URL.Query().Get("expand") != "names" {
+			t.Fatalf("unexpected request: %s", r.URL.String())
+		}
+		_, _ = w.Write([]byte(`{"key":"PROJ-9","names":{"customfield_2":"Story Points","customfield_3":"Acceptance criteria"},"fields":{
+			"summary":"Detail",
+			"issuetype":{"name":"Story"},
+			"status":{"name":"In Progress"},
+			"assignee":{"displayName":"Dev One"},
+			"labels":["api","cli"],
+			"components":[{"name":"core"}],
+			"description":"legacy text",
+			"customfield_3":{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"works offline"}]}]},
+			"issuelinks":[
+				{"type":{"inward":"is blocked by","outward":"blocks"},"outwardIssue":{"key":"PROJ-10","fields":{"summary":"Downstream","status":{"name":"To Do"}}}},
+				{"type":{"inward":"is blocked by","outward":"blocks"},"inwardIssue":{"key":"PROJ-8","fields":{"summary":"Upstream"}}}
+			]
+		}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	d, err := NewClient().FetchIssueDetail(context.Background(), "proj-9")
+	if err != nil {
+		t.Fatalf("FetchIssueDetail() error: %v", err)
+	}
+	if d.Key != "PROJ-9" || d.Summary != "Detail" || d.IssueType != "Story" || d.Status != "In Progress" || d.Assignee != "Dev One" {
+		t.Fatalf("detail = %#v", d)
+	}
+	if d.TicketURL != server.URL+"/browse/PROJ-9" {
+		t.Fatalf("ticket url = %q", d.TicketURL)
+	}
+	if len(d.Labels) != 2 || len(d.Components) != 1 || d.Components[0] != "core" {
+		t.Fatalf("labels/components = %#v / %#v", d.Labels, d.Components)
+	}
+	if d.Description != "legacy text" || d.AcceptanceCriteria != "works offline" {
+		t.Fatalf("description/ac = %q / %q", d.Description, d.AcceptanceCriteria)
+	}
+	want := []IssueLink{
+		{Relation: "blocks", Key: "PROJ-10", Summary: "Downstream", Status: "To Do"},
+		{Relation: "is blocked by", Key: "PROJ-8", Summary: "Upstream"},
+	}
+	if len(d.Links) != len(want) || d.Links[0] != want[0] || d.Links[1] != want[1] {
+		t.Fatalf("links = %#v", d.Links)
+	}
+}
@@ -0,0 +1,250 @@
+package jira
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type CacheKind string
+
+const (
+	CacheKindIssues  CacheKind = "issues"
+	CacheKindBoards  CacheKind = "boards"
+	CacheKindSprints CacheKind = "sprints"
+)
+
+const (
+	DefaultIssuesCacheTTL  = 15 * time.Minute
+	DefaultBoardsCacheTTL  = 24 * time.Hour
+	DefaultSprintsCacheTTL = time.Hour
+)
+
+// CacheKinds lists cache kinds in display order.
+var CacheKinds = []CacheKind{CacheKindIssues, CacheKindBoards, CacheKindSprints}
+
+type CacheTTLs struct {
+	Issues  time.Duration
+	Boards  time.Duration
+	Sprints time.Duration
+}
+
+// Cache stores Jira API results as JSON files under <dir>/<kind>/.
+// Entries past their TTL are stale: they are still served in offline mode or when
+// the network is unreachable, and are removed by Prune.
+type Cache struct {
+	dir  string
+	ttls CacheTTLs
+	now  func() time.Time
+}
+
+type CacheEntryInfo struct {
+	FetchedAt time.Time
+	Age       time.Duration
+	TTL       time.Duration
+	Stale     bool
+}
+
+type CacheKindStats struct {
+	Kind    CacheKind
+	TTL     time.Duration
+	Entries int
+	Stale   int
+	Bytes   int64
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+type CachePruneResult struct {
+	Removed int
+	Kept    int
+}
+
+type cacheEntryFile struct {
+	Key       string          `json:"key"`
+	FetchedAt int64           `json:"fetched_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+func NewCache(dir string, ttls CacheTTLs) *Cache {
+	if ttls.Issues <= 0 {
+		ttls.Issues = DefaultIssuesCacheTTL
+	}
+	if ttls.Boards <= 0 {
+		ttls.Boards = DefaultBoardsCacheTTL
+	}
+	if ttls.Sprints <= 0 {
+		ttls.Sprints = DefaultSprintsCacheTTL
+	}
+	return &Cache{dir: strings.TrimSpace(dir), ttls: ttls, now: time.Now}
+}
+
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+func (c *Cache) TTL(kind CacheKind) time.Duration {
+	switch kind {
+	case CacheKindBoards:
+		return c.ttls.Boards
+	case CacheKindSprints:
+		return c.ttls.Sprints
+	default:
+		return c.ttls.Issues
+	}
+}
+
+// Get decodes the cached value for key into out. ok=false means no entry exists.
+func (c *Cache) Get(kind CacheKind, key string, out any) (CacheEntryInfo, bool, error) {
+	b, err := os.ReadFile(c.entryPath(kind, key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return CacheEntryInfo{}, false, nil
+		}
+		return CacheEntryInfo{}, false, fmt.Errorf("read jira cache entry: %w", err)
+	}
+	var entry cacheEntryFile
+	if err := json.Unmarshal(b, &entry); err != nil || entry.Key != key {
+		// Corrupt or colliding entries are treated as a miss.
+		return CacheEntryInfo{}, false, nil
+	}
+	if err := json.Unmarshal(entry.Value, out); err != nil {
+		return CacheEntryInfo{}, false, nil
+	}
+	return c.entryInfo(kind, entry.FetchedAt), true, nil
+}
+
+func (c *Cache) Put(kind CacheKind, key string, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal jira cache value: %w", err)
+	}
+	b, err := json.Marshal(cacheEntryFile{Key: key, FetchedAt: c.now().Unix(), Value: raw})
+	if err != nil {
+		return fmt.Errorf("marshal jira cache entry: %w", err)
+	}
+	kindDir := filepath.Join(c.dir, string(kind))
+	if err := os.MkdirAll(kindDir, 0o755); err != nil {
+		return fmt.Errorf("create jira cache dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(kindDir, ".entry-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create jira cache temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write jira cache temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close jira cache temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.entryPath(kind, key)); err != nil {
+		return fmt.Errorf("replace jira cache entry: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) Stats() ([]CacheKindStats, error) {
+	out := make([]CacheKindStats, 0, len(CacheKinds))
+	for _, kind := range CacheKinds {
+		stats := CacheKindStats{Kind: kind, TTL: c.TTL(kind)}
+		err := c.walkEntries(kind, func(path string, size int64, entry cacheEntryFile, valid bool) error {
+			stats.Entries++
+			stats.Bytes += size
+			if !valid {
+				stats.Stale++
+				return nil
+			}
+			info := c.entryInfo(kind, entry.FetchedAt)
+			if info.Stale {
+				stats.Stale++
+			}
+			if stats.Oldest.IsZero() || info.FetchedAt.Before(stats.Oldest) {
+				stats.Oldest = info.FetchedAt
+			}
+			if info.FetchedAt.After(stats.Newest) {
+				stats.Newest = info.FetchedAt
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, stats)
+	}
+	return out, nil
+}
+
+// Prune removes stale and unreadable entries. all=true removes every entry.
+func (c *Cache) Prune(all bool) (CachePruneResult, error) {
+	result := CachePruneResult{}
+	for _, kind := range CacheKinds {
+		err := c.walkEntries(kind, func(path string, _ int64, entry cacheEntryFile, valid bool) error {
+			if !all && valid && !c.entryInfo(kind, entry.FetchedAt).Stale {
+				result.Kept++
+				return nil
+			}
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("remove jira cache entry: %w", err)
+			}
+			result.Removed++
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (c *Cache) walkEntries(kind CacheKind, fn func(path string, size int64, entry cacheEntryFile, valid bool) error) error {
+	kindDir := filepath.Join(c.dir, string(kind))
+	entries, err := os.ReadDir(kindDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read jira cache dir: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(kindDir, e.Name())
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read jira cache entry: %w", err)
+		}
+		var entry cacheEntryFile
+		valid := json.Unmarshal(b, &entry) == nil && entry.FetchedAt > 0
+		if err := fn(path, int64(len(b)), entry, valid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache) entryInfo(kind CacheKind, fetchedAtUnix int64) CacheEntryInfo {
+	fetchedAt := time.Unix(fetchedAtUnix, 0)
+	age := c.now().Sub(fetchedAt)
+	if age < 0 {
+		age = 0
+	}
+	ttl := c.TTL(kind)
+	return CacheEntryInfo{FetchedAt: fetchedAt, Age: age, TTL: ttl, Stale: age > ttl}
+}
+
+func (c *Cache) entryPath(kind CacheKind, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, string(kind), hex.EncodeToString(sum[:16])+".json")
+}
@@ -0,0 +1,148 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_PutGet_StaleAndPrune(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cache := NewCache(t.TempDir(), CacheTTLs{Issues: time.Minute})
+	cache.now = func() time.Time { return now }
+
+	if err := cache.Put(CacheKindIssues, "issue/A-1", Issue{Key: "A-1", Summary: "old"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	now = now.Add(30 * time.Second)
+	if err := cache.Put(CacheKindIssues, "issue/A-2", Issue{Key: "A-2", Summary: "new"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+
+	var got Issue
+	info, ok, err := cache.Get(CacheKindIssues, "issue/A-1", &got)
+	if err != nil || !ok {
+		t.Fatalf("Get() ok=%v err=%v", ok, err)
+	}
+	if got.Summary != "old" || info.Stale {
+		t.Fatalf("Get() = %#v stale=%v", got, info.Stale)
+	}
+	if _, ok, _ := cache.Get(CacheKindIssues, "issue/missing", &got); ok {
+		t.Fatalf("Get(missing) ok = true")
+	}
+
+	now = now.Add(45 * time.Second)
+	info, _, _ = cache.Get(CacheKindIssues, "issue/A-1", &got)
+	if !info.Stale || info.Age != 75*time.Second {
+		t.Fatalf("info = %#v, want stale with age 75s", info)
+	}
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error: %v", err)
+	}
+	if stats[0].Kind != CacheKindIssues || stats[0].Entries != 2 || stats[0].Stale != 1 {
+		t.Fatalf("issues stats = %#v", stats[0])
+	}
+	if stats[1].Kind != CacheKindBoards || stats[1].TTL != DefaultBoardsCacheTTL {
+		t.Fatalf("boards stats = %#v", stats[1])
+	}
+
+	pruned, err := cache.Prune(false)
+	if err != nil {
+		t.Fatalf("Prune(false) error: %v", err)
+	}
+	if pruned.Removed != 1 || pruned.Kept != 1 {
+		t.Fatalf("Prune(false) = %#v", pruned)
+	}
+	pruned, err = cache.Prune(true)
+	if err != nil {
+		t.Fatalf("Prune(true) error: %v", err)
+	}
+	if pruned.Removed != 1 || pruned.Kept != 0 {
+		t.Fatalf("Prune(true) = %#v", pruned)
+	}
+}
+
+func TestClient_SearchIssuesByJQL_ServesCacheWhenUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"issues":[{"key":"PROJ-1","fields":{"summary":"First"}}]}`))
+	}))
+
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	var warnings []string
+	client := NewClientWithOptions("", ClientOptions{
+		Cache: NewCache(t.TempDir(), CacheTTLs{}),
+		Warnf: func(format string, args ...any) { warnings = append(warnings, fmt.Sprintf(format, args...)) },
+	})
+	if _, err := client.SearchIssuesByJQL(context.Background(), "project = PROJ", 10); err != nil {
+		t.Fatalf("SearchIssuesByJQL() error: %v", err)
+	}
+	server.Close()
+
+	issues, err := client.SearchIssuesByJQL(context.Background(), "project = PROJ", 10)
+	if err != nil {
+		t.Fatalf("SearchIssuesByJQL() after close error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Key != "PROJ-1" {
+		t.Fatalf("issues = %#v", issues)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "jira unreachable") || !strings.Contains(warnings[0], "fresh cached issues") {
+		t.Fatalf("warnings = %#v", warnings)
+	}
+
+	if _, err := client.SearchIssuesByJQL(context.Background(), "project = OTHER", 10); err == nil {
+		t.Fatalf("expected error for uncached query while unreachable")
+	}
+}
+
+func TestClient_Offline_UsesCacheOnly(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"id":7,"name":"Sprint 7","state":"active"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("KRA_JIRA_BASE_URL", server.URL)
+	t.Setenv("KRA_JIRA_EMAIL", "dev@example.com")
+	t.Setenv("KRA_JIRA_API_TOKEN", "token-123")
+
+	now := time.Unix(1_700_000_000, 0)
+	cache := NewCache(t.TempDir(), CacheTTLs{Sprints: time.Minute})
+	cache.now = func() time.Time { return now }
+	if _, err := NewClientWithOptions("", ClientOptions{Cache: cache}).GetSprint(context.Background(), 7); err != nil {
+		t.Fatalf("GetSprint() error: %v", err)
+	}
+
+	var warnings []string
+	offline := NewClientWithOptions("", ClientOptions{
+		Cache:   cache,
+		Offline: true,
+		Warnf:   func(format string, args ...any) { warnings = append(warnings, fmt.Sprintf(format, args...)) },
+	})
+	now = now.Add(2 * time.Minute)
+	sprint, err := offline.GetSprint(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("offline GetSprint() error: %v", err)
+	}
+	if sprint.ID != 7 || sprint.Name != "Sprint 7" {
+		t.Fatalf("sprint = %#v", sprint)
+	}
+	if requests != 1 {
+		t.Fatalf("requests = %d, want 1", requests)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "stale cached sprints") {
+		t.Fatalf("warnings = %#v", warnings)
+	}
+	if _, err := offline.GetSprint(context.Background(), 8); err == nil || !strings.Contains(err.Error(), "no cached sprints") {
+		t.Fatalf("offline GetSprint(8) err = %v", err)
+	}
+}
@@ -27,12 +27,57 @@ var legacySprintKVPattern = regexp.MustCompile(`([a-zA-Z]+)=([^,\]]+)`)
 type Client struct {
 	httpClient        *http.Client
 	baseURLFromConfig string
+	cache             *Cache
+	offline           bool
+	warnf             func(format string, args ...any)
+}
+
+// ClientOptions configures the optional offline cache.
+// Warnf receives stale-while-error and offline warnings.
+type ClientOptions struct {
+	Cache   *Cache
+	Offline bool
+	Warnf   func(format string, args ...any)
 }
 
 type Issue struct {
-	Key       string
-	Summary   string
-	TicketURL string
+	Key        string
+	Summary    string
+	TicketURL  string
+	IssueType  string
+	Labels     []string
+	ProjectKey string
+}
+
+// issueListFields are the fields needed to build Issue (including template routing inputs).
+const issueListFields = "summary,issuetype,labels,project"
+
+type issueFieldsPayload struct {
+	Summary   string `json:"summary"`
+	IssueType struct {
+		Name string `json:"name"`
+	} `json:"issuetype"`
+	Labels  []string `json:"labels"`
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+func newIssueFromPayload(baseURL string, key string, f issueFieldsPayload) Issue {
+	project := strings.ToUpper(strings.TrimSpace(f.Project.Key))
+	if project == "" {
+		if i := strings.LastIndex(key, "-"); i > 0 {
+			project = key[:i]
+		}
+	}
+	return Issue{
+		Key:        key,
+		Summary:    strings.TrimSpace(f.Summary),
+		TicketURL:  strings.TrimRight(baseURL, "/") + "/browse/" + url.PathEscape(key),
+		IssueType:  strings.TrimSpace(f.IssueType.Name),
+		Labels:     f.Labels,
+		ProjectKey: project,
+	}
 }
 
 type Board struct {
@@ -54,33 +99,40 @@ func NewClient() *Client {
 }
 
 func NewClientWithBaseURL(baseURL string) *Client {
+	return NewClientWithOptions(baseURL, ClientOptions{})
+}
+
+func NewClientWithOptions(baseURL string, opts ClientOptions) *Client {
 	return &Client{
 		httpClient:        &http.Client{Timeout: 10 * time.Second},
 		baseURLFromConfig: strings.TrimSpace(baseURL),
+		cache:             opts.Cache,
+		offline:           opts.Offline,
+		warnf:             opts.Warnf,
 	}
 }
 
-func (c *Client) FetchIssueByTicketURL(ctx context.Context, ticketURL string) (key string, summary string, err error) {
+func (c *Client) fetchIssueByTicketURL(ctx context.Context, ticketURL string) (Issue, error) {
 	cfg, err := loadEnvConfig(c.baseURLFromConfig)
 	if err != nil {
-		return "", "", err
+		return Issue{}, err
 	}
 	issueKey, err := parseTicketURL(ticketURL)
 	if err != nil {
-		return "", "", err
+		return Issue{}, err
 	}
 
-	endpoint := strings.TrimRight(cfg.baseURL.String(), "/") + "/rest/api/3/issue/" + url.PathEscape(issueKey) + "?fields=summary"
+	endpoint := strings.TrimRight(cfg.baseURL.String(), "/") + "/rest/api/3/issue/" + url.PathEscape(issueKey) + "?fields=" + issueListFields
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
-		return "", "", fmt.Errorf("build jira request: %w", err)
+		return Issue{}, fmt.Errorf("build jira request: %w", err)
 	}
 	req.Header.Set("Authorization", "Basic "+basicAuth(cfg.email, cfg.apiToken))
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", "", fmt.Errorf("jira request failed: %w", err)
+		return Issue{}, fmt.Errorf("jira request failed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -88,27 +140,25 @@ func (c *Client) FetchIssueByTicketURL(ctx context.Context, ticketURL string) (k
 	case http.StatusOK:
 		// continue
 	case http.StatusUnauthorized, http.StatusForbidden:
-		return "", "", fmt.Errorf("jira authentication failed: status=%d", resp.StatusCode)
+		return Issue{}, fmt.Errorf("jira authentication failed: status=%d", resp.StatusCode)
 	case http.StatusNotFound:
-		return "", "", fmt.Errorf("jira issue not found: %s", issueKey)
+		return Issue{}, fmt.Errorf("jira issue not found: %s", issueKey)
 	default:
-		return "", "", fmt.Errorf("jira request failed: status=%d", resp.StatusCode)
+		return Issue{}, fmt.Errorf("jira request failed: status=%d", resp.StatusCode)
 	}
 
 	var payload struct {
-		Key    string `json:"key"`
-		Fields struct {
-			Summary string `json:"summary"`
-		} `json:"fields"`
+		Key    string             `json:"key"`
+		Fields issueFieldsPayload `json:"fields"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return "", "", fmt.Errorf("decode jira response: %w", err)
+		return Issue{}, fmt.Errorf("decode jira response: %w", err)
 	}
 	resolvedKey := strings.TrimSpace(payload.Key)
 	if resolvedKey == "" {
 		resolvedKey = issueKey
 	}
-	return strings.ToUpper(resolvedKey), strings.TrimSpace(payload.Fields.Summary), nil
+	return newIssueFromPayload(cfg.baseURL.String(), strings.ToUpper(resolvedKey), payload.Fields), nil
 }
 
 type envConfig struct {
@@ -164,7 +214,7 @@ func basicAuth(email string, token string) string {
 	return base64.StdEncoding.EncodeToString([]byte(email + ":" + token))
 }
 
-func (c *Client) SearchIssuesByJQL(ctx context.Context, jql string, maxResults int) ([]Issue, error) {
+func (c *Client) searchIssuesByJQL(ctx context.Context, jql string, maxResults int) ([]Issue, error) {
 	cfg, err := loadEnvConfig(c.baseURLFromConfig)
 	if err != nil {
 		return nil, err
@@ -179,7 +229,7 @@ func (c *Client) SearchIssuesByJQL(ctx context.Context, jql string, maxResults i
 	q := url.Values{}
 	q.Set("jql", jql)
 	q.Set("maxResults", fmt.Sprintf("%d", maxResults))
-	q.Set("fields", "summary")
+	q.Set("fields", issueListFields)
 	endpoint := strings.TrimRight(cfg.baseURL.String(), "/") + "/rest/api/3/search/jql?" + q.Encode()
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -205,10 +255,8 @@ func (c *Client) SearchIssuesByJQL(ctx context.Context, jql string, maxResults i
 
 	var payload struct {
 		Issues []struct {
-			Key    string `json:"key"`
-			Fields struct {
-				Summary string `json:"summary"`
-			} `json:"fields"`
+			Key    string             `json:"key"`
+			Fields issueFieldsPayload `json:"fields"`
 		} `json:"issues"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
@@ -221,21 +269,22 @@ func (c *Client) SearchIssuesByJQL(ctx context.Context, jql string, maxResults i
 		if key == "" {
 			continue
 		}
-		issues = append(issues, Issue{
-			Key:       key,
-			Summary:   strings.TrimSpace(it.Fields.Summary),
-			TicketURL: strings.TrimRight(cfg.baseURL.String(), "/") + "/browse/" + url.PathEscape(key),
-		})
+		issues = append(issues, newIssueFromPayload(cfg.baseURL.String(), key, it.Fields))
 	}
 	return issues, nil
 }
 
 func (c *Client) ListScrumBoards(ctx context.Context) ([]Board, error) {
-	return c.listScrumBoards(ctx, "")
+	return withCache(c, CacheKindBoards, "boards?type=scrum", func() ([]Board, error) {
+		return c.listScrumBoards(ctx, "")
+	})
 }
 
 func (c *Client) ListScrumBoardsByProject(ctx context.Context, projectKey string) ([]Board, error) {
-	return c.listScrumBoards(ctx, strings.ToUpper(strings.TrimSpace(projectKey)))
+	key := strings.ToUpper(strings.TrimSpace(projectKey))
+	return withCache(c, CacheKindBoards, "boards?type=scrum&project="+key, func() ([]Board, error) {
+		return c.listScrumBoards(ctx, key)
+	})
 }
 
 func (c *Client) listScrumBoards(ctx context.Context, projectKey string) ([]Board, error) {
@@ -307,7 +356,7 @@ func (c *Client) listScrumBoards(ctx context.Context, projectKey string) ([]Boar
 	return boards, nil
 }
 
-func (c *Client) ListBoardSprintsActiveFuture(ctx context.Context, boardID int) ([]Sprint, error) {
+func (c *Client) listBoardSprintsActiveFuture(ctx context.Context, boardID int) ([]Sprint, error) {
 	cfg, err := loadEnvConfig(c.baseURLFromConfig)
 	if err != nil {
 		return nil, err
@@ -373,7 +422,7 @@ func (c *Client) ListBoardSprintsActiveFuture(ctx context.Context, boardID int)
 	return sprints, nil
 }
 
-func (c *Client) GetSprint(ctx context.Context, sprintID int) (Sprint, error) {
+func (c *Client) getSprint(ctx context.Context, sprintID int) (Sprint, error) {
 	cfg, err := loadEnvConfig(c.baseURLFromConfig)
 	if err != nil {
 		return Sprint{}, err
@@ -421,7 +470,7 @@ func (c *Client) GetSprint(ctx context.Context, sprintID int) (Sprint, error) {
 	}, nil
 }
 
-func (c *Client) ListProjectOpenSprints(ctx context.Context, projectKey string, maxResults int) ([]Sprint, error) {
+func (c *Client) listProjectOpenSprints(ctx context.Context, projectKey string, maxResults int) ([]Sprint, error) {
 	cfg, err := loadEnvConfig(c.baseURLFromConfig)
 	if err != nil {
 		return nil, err
@@ -685,7 +734,7 @@ func parseLegacySprintString(v string) (Sprint, bool) {
 	return sprint, true
 }
 
-func (c *Client) ListBoardProjectKeys(ctx context.Context, boardID int) ([]string, error) {
+func (c *Client) listBoardProjectKeys(ctx context.Context, boardID int) ([]string, error) {
 	cfg, err := loadEnvConfig(c.baseURLFromConfig)
 	if err != nil {
 		return nil, err
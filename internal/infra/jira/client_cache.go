@@ -0,0 +1,147 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+func (c *Client) FetchIssueByTicketURL(ctx context.Context, ticketURL string) (Issue, error) {
+	issueKey, err := parseTicketURL(ticketURL)
+	if err != nil {
+		return Issue{}, err
+	}
+	return withCache(c, CacheKindIssues, "issue/"+issueKey, func() (Issue, error) {
+		return c.fetchIssueByTicketURL(ctx, ticketURL)
+	})
+}
+
+func (c *Client) SearchIssuesByJQL(ctx context.Context, jql string, maxResults int) ([]Issue, error) {
+	return withCache(c, CacheKindIssues, fmt.Sprintf("search?max=%d&jql=%s", maxResults, strings.TrimSpace(jql)), func() ([]Issue, error) {
+		return c.searchIssuesByJQL(ctx, jql, maxResults)
+	})
+}
+
+func (c *Client) ListBoardSprintsActiveFuture(ctx context.Context, boardID int) ([]Sprint, error) {
+	return withCache(c, CacheKindSprints, fmt.Sprintf("board/%d/sprints?state=active,future", boardID), func() ([]Sprint, error) {
+		return c.listBoardSprintsActiveFuture(ctx, boardID)
+	})
+}
+
+func (c *Client) GetSprint(ctx context.Context, sprintID int) (Sprint, error) {
+	return withCache(c, CacheKindSprints, fmt.Sprintf("sprint/%d", sprintID), func() (Sprint, error) {
+		return c.getSprint(ctx, sprintID)
+	})
+}
+
+func (c *Client) ListProjectOpenSprints(ctx context.Context, projectKey string, maxResults int) ([]Sprint, error) {
+	key := strings.ToUpper(strings.TrimSpace(projectKey))
+	return withCache(c, CacheKindSprints, fmt.Sprintf("project/%s/sprints?max=%d", key, maxResults), func() ([]Sprint, error) {
+		return c.listProjectOpenSprints(ctx, projectKey, maxResults)
+	})
+}
+
+func (c *Client) ListBoardProjectKeys(ctx context.Context, boardID int) ([]string, error) {
+	return withCache(c, CacheKindBoards, fmt.Sprintf("board/%d/projects", boardID), func() ([]string, error) {
+		return c.listBoardProjectKeys(ctx, boardID)
+	})
+}
+
+// withCache runs fetch and records the result. When the client is offline, the cached
+// value is returned without any request. When the request fails because Jira is
+// unreachable, a cached value is served instead (stale-while-error) with a warning.
+func withCache[T any](c *Client, kind CacheKind, key string, fetch func() (T, error)) (T, error) {
+	var zero T
+	if c.cache == nil {
+		if c.offline {
+			return zero, fmt.Errorf("jira offline mode requires the jira cache")
+		}
+		return fetch()
+	}
+	scopedKey := c.cacheScope() + " " + key
+
+	if c.offline {
+		var cached T
+		info, ok, err := c.cache.Get(kind, scopedKey, &cached)
+		if err != nil {
+			return zero, err
+		}
+		if !ok {
+			return zero, fmt.Errorf("jira offline: no cached %s for %s", kind, key)
+		}
+		if info.Stale {
+			c.warn("jira offline: using stale cached %s fetched %s ago (ttl=%s)", kind, FormatCacheAge(info.Age), info.TTL)
+		}
+		return cached, nil
+	}
+
+	v, fetchErr := fetch()
+	if fetchErr == nil {
+		if err := c.cache.Put(kind, scopedKey, v); err != nil {
+			c.warn("jira cache write skipped: %v", err)
+		}
+		return v, nil
+	}
+	if !isJiraUnreachableError(fetchErr) {
+		return zero, fetchErr
+	}
+	var cached T
+	info, ok, err := c.cache.Get(kind, scopedKey, &cached)
+	if err != nil || !ok {
+		return zero, fetchErr
+	}
+	staleLabel := "fresh"
+	if info.Stale {
+		staleLabel = "stale"
+	}
+	c.warn("jira unreachable (%v); using %s cached %s fetched %s ago (ttl=%s)", fetchErr, staleLabel, kind, FormatCacheAge(info.Age), info.TTL)
+	return cached, nil
+}
+
+func (c *Client) warn(format string, args ...any) {
+	if c.warnf == nil {
+		return
+	}
+	c.warnf(format, args...)
+}
+
+// cacheScope separates entries of different Jira sites without requiring credentials,
+// so offline mode works with only the base URL configured.
+func (c *Client) cacheScope() string {
+	raw := strings.TrimSpace(c.baseURLFromConfig)
+	if envBaseURL := strings.TrimSpace(os.Getenv(envJiraBaseURL)); envBaseURL != "" {
+		raw = envBaseURL
+	}
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return strings.ToLower(u.Host) + strings.TrimRight(u.Path, "/")
+	}
+	return "default"
+}
+
+func isJiraUnreachableError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// FormatCacheAge renders the age of a cache entry in whole seconds (e.g. "1h2m3s").
+func FormatCacheAge(age time.Duration) string {
+	if age < 0 {
+		age = 0
+	}
+	return age.Truncate(time.Second).String()
+}
+
+func (c *Client) FetchIssueDetail(ctx context.Context, issueKey string) (IssueDetail, error) {
+	key := strings.ToUpper(strings.TrimSpace(issueKey))
+	return withCache(c, CacheKindIssues, "issue-detail/"+key, func() (IssueDetail, error) {
+		return c.fetchIssueDetail(ctx, key)
+	})
+}
@@ -0,0 +1,180 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const acceptanceCriteriaFieldName = "acceptance criteria"
+
+type IssueLink struct {
+	Relation string `json:"relation"`
+	Key      string `json:"key"`
+	Summary  string `json:"summary,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+// IssueDetail is the ticket context used for workspace notes.
+// Description and AcceptanceCriteria are converted from ADF to Markdown.
+type IssueDetail struct {
+	Key                string      `json:"key"`
+	Summary            string      `json:"summary"`
+	TicketURL          string      `json:"ticket_url"`
+	IssueType          string      `json:"issue_type,omitempty"`
+	Status             string      `json:"status,omitempty"`
+	Assignee           string      `json:"assignee,omitempty"`
+	Labels             []string    `json:"labels,omitempty"`
+	Components         []string    `json:"components,omitempty"`
+	Description        string      `json:"description,omitempty"`
+	AcceptanceCriteria string      `json:"acceptance_criteria,omitempty"`
+	Links              []IssueLink `json:"links,omitempty"`
+}
+
+type issueLinkedRef struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+func (c *Client) fetchIssueDetail(ctx context.Context, issueKey string) (IssueDetail, error) {
+	cfg, err := loadEnvConfig(c.baseURLFromConfig)
+	if err != nil {
+		return IssueDetail{}, err
+	}
+	issueKey = strings.ToUpper(strings.TrimSpace(issueKey))
+	if issueKey == "" {
+		return IssueDetail{}, fmt.Errorf("jira issue key is required")
+	}
+
+	// All fields are requested with expand=names so the acceptance criteria custom field
+	// can be located by its display name (its field id differs per Jira site).
+	base := strings.TrimRight(cfg.baseURL.String(), "/")
+	endpoint := base + "/rest/api/3/issue/" + url.PathEscape(issueKey) + "?expand=names"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return IssueDetail{}, fmt.Errorf("build jira request: %w", err)
+	}
+	req.Header.Set("Authorization", "Basic "+basicAuth(cfg.email, cfg.apiToken))
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return IssueDetail{}, fmt.Errorf("jira request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// continue
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return IssueDetail{}, fmt.Errorf("jira authentication failed: status=%d", resp.StatusCode)
+	case http.StatusNotFound:
+		return IssueDetail{}, fmt.Errorf("jira issue not found: %s", issueKey)
+	default:
+		return IssueDetail{}, fmt.Errorf("jira request failed: status=%d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Key    string                     `json:"key"`
+		Names  map[string]string          `json:"names"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return IssueDetail{}, fmt.Errorf("decode jira response: %w", err)
+	}
+	return parseIssueDetail(base, issueKey, payload.Key, payload.Names, payload.Fields), nil
+}
+
+func parseIssueDetail(baseURL string, requestedKey string, key string, names map[string]string, fields map[string]json.RawMessage) IssueDetail {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if key == "" {
+		key = requestedKey
+	}
+	out := IssueDetail{
+		Key:         key,
+		TicketURL:   baseURL + "/browse/" + key,
+		Description: ADFToMarkdown(fields["description"]),
+	}
+	decode := func(name string, v any) {
+		if raw, ok := fields[name]; ok && len(raw) > 0 {
+			_ = json.Unmarshal(raw, v)
+		}
+	}
+
+	var summary string
+	decode("summary", &summary)
+	out.Summary = strings.TrimSpace(summary)
+
+	var issueType, status struct {
+		Name string `json:"name"`
+	}
+	decode("issuetype", &issueType)
+	decode("status", &status)
+	out.IssueType = strings.TrimSpace(issueType.Name)
+	out.Status = strings.TrimSpace(status.Name)
+	var assignee struct {
+		DisplayName string `json:"displayName"`
+	}
+	decode("assignee", &assignee)
+	out.Assignee = strings.TrimSpace(assignee.DisplayName)
+
+	decode("labels", &out.Labels)
+	var components []struct {
+		Name string `json:"name"`
+	}
+	decode("components", &components)
+	for _, comp := range components {
+		if name := strings.TrimSpace(comp.Name); name != "" {
+			out.Components = append(out.Components, name)
+		}
+	}
+
+	fieldIDs := make([]string, 0, len(names))
+	for id := range names {
+		fieldIDs = append(fieldIDs, id)
+	}
+	sort.Strings(fieldIDs)
+	for _, id := range fieldIDs {
+		if strings.EqualFold(strings.TrimSpace(names[id]), acceptanceCriteriaFieldName) {
+			out.AcceptanceCriteria = ADFToMarkdown(fields[id])
+			if out.AcceptanceCriteria != "" {
+				break
+			}
+		}
+	}
+
+	var links []struct {
+		Type struct {
+			Inward  string `json:"inward"`
+			Outward string `json:"outward"`
+		} `json:"type"`
+		InwardIssue  *issueLinkedRef `json:"inwardIssue"`
+		OutwardIssue *issueLinkedRef `json:"outwardIssue"`
+	}
+	decode("issuelinks", &links)
+	for _, l := range links {
+		relation, ref := l.Type.Outward, l.OutwardIssue
+		if ref == nil {
+			relation, ref = l.Type.Inward, l.InwardIssue
+		}
+		if ref == nil || strings.TrimSpace(ref.Key) == "" {
+			continue
+		}
+		out.Links = append(out.Links, IssueLink{
+			Relation: strings.TrimSpace(relation),
+			Key:      strings.TrimSpace(ref.Key),
+			Summary:  strings.TrimSpace(ref.Fields.Summary),
+			Status:   strings.TrimSpace(ref.Fields.Status.Name),
+		})
+	}
+	return out
+}
@@ -46,6 +46,15 @@ func ConfigPath() (string, error) {
 	return filepath.Join(home, "config.yaml"), nil
 }
 
+// JiraCacheDir returns the offline Jira cache directory.
+func JiraCacheDir() (string, error) {
+	home, err := KraHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "cache", "jira"), nil
+}
+
 // RootConfigPath returns the root-local config path.
 func RootConfigPath(root string) string {
 	return filepath.Join(root, ".kra", "config.yaml")
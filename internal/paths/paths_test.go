@@ -67,6 +67,21 @@ func TestConfigPath_UsesKraHomeDefault(t *testing.T) {
 	}
 }
 
+func TestJiraCacheDir_UsesKraHomeDefault(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KRA_HOME", "")
+
+	got, err := JiraCacheDir()
+	if err != nil {
+		t.Fatalf("JiraCacheDir() err = %v", err)
+	}
+	want := filepath.Join(home, ".kra", "cache", "jira")
+	if got != want {
+		t.Fatalf("JiraCacheDir() = %q, want %q", got, want)
+	}
+}
+
 func TestPaths_UsesKraHomeOverride(t *testing.T) {
 	kraHome := filepath.Join(t.TempDir(), ".kra")
 	t.Setenv("KRA_HOME", kraHome)
@@ -0,0 +1,288 @@
+package repodiscovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/httpapi"
+)
+
+const (
+	envBitbucketBaseURL     = "KRA_BITBUCKET_BASE_URL"
+	envBitbucketUsername    = "KRA_BITBUCKET_USERNAME"
+	envBitbucketAppPassword = "KRA_BITBUCKET_APP_PASSWORD"
+	envBitbucketToken       = "KRA_BITBUCKET_TOKEN"
+	defaultBitbucketBaseURL = "https://api.bitbucket.org"
+
+	envBitbucketServerURL   = "KRA_BITBUCKET_SERVER_URL"
+	envBitbucketServerToken = "KRA_BITBUCKET_SERVER_TOKEN"
+
+	bitbucketPageLen = 100
+)
+
+type bitbucketCloneLink struct {
+	Name string `json:"name"`
+	Href string `json:"href"`
+}
+
+// pickBitbucketCloneURL prefers the ssh clone link and falls back to http(s).
+func pickBitbucketCloneURL(links []bitbucketCloneLink) string {
+	byName := map[string]string{}
+	for _, l := range links {
+		byName[strings.ToLower(strings.TrimSpace(l.Name))] = strings.TrimSpace(l.Href)
+	}
+	for _, name := range []string{"ssh", "https", "http"} {
+		if href := byName[name]; href != "" {
+			return href
+		}
+	}
+	return ""
+}
+
+// BitbucketCloudProvider discovers repositories of a Bitbucket Cloud workspace via the REST API 2.0.
+type BitbucketCloudProvider struct {
+	httpClient *http.Client
+}
+
+type bitbucketCloudEnvConfig struct {
+	baseURL     string
+	username    string
+	appPassword string
+	token       string
+}
+
+func NewBitbucketCloudProvider(httpClient *http.Client) *BitbucketCloudProvider {
+	return &BitbucketCloudProvider{httpClient: httpapi.NewDefaultClient(httpClient)}
+}
+
+func (p *BitbucketCloudProvider) Name() string {
+	return "bitbucket"
+}
+
+func (p *BitbucketCloudProvider) CheckAuth(ctx context.Context) error {
+	cfg, err := loadBitbucketCloudEnvConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.token != "" {
+		// Workspace/repository access tokens cannot read /user; they are verified by the listing call.
+		return nil
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	if _, err := getJSON(ctx, p.httpClient, "bitbucket", cfg.baseURL+"/2.0/user", cfg.authorize, &user); err != nil {
+		return fmt.Errorf("bitbucket authentication required (set %s and %s, or %s): %w", envBitbucketUsername, envBitbucketAppPassword, envBitbucketToken, err)
+	}
+	return nil
+}
+
+// ListOrgRepos lists repositories in the workspace (org is the workspace slug).
+func (p *BitbucketCloudProvider) ListOrgRepos(ctx context.Context, org string) ([]Repo, error) {
+	org = strings.TrimSpace(org)
+	if org == "" {
+		return nil, fmt.Errorf("org is required")
+	}
+	cfg, err := loadBitbucketCloudEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	collector := newRepoCollector()
+	next := fmt.Sprintf("%s/2.0/repositories/%s?pagelen=%d", cfg.baseURL, url.PathEscape(org), bitbucketPageLen)
+	for next != "" {
+		var page struct {
+			Values []struct {
+				FullName string `json:"full_name"`
+				Language string `json:"language"`
+				// Bitbucket Cloud has no push timestamp; updated_on is the closest signal.
+				UpdatedOn  string `json:"updated_on"`
+				MainBranch *struct {
+					Name string `json:"name"`
+				} `json:"mainbranch"`
+				Parent *struct {
+					FullName string `json:"full_name"`
+				} `json:"parent"`
+				Links struct {
+					Clone []bitbucketCloneLink `json:"clone"`
+				} `json:"links"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		if _, err := getJSON(ctx, p.httpClient, "bitbucket", next, cfg.authorize, &page); err != nil {
+			if errors.Is(err, errHTTPNotFound) {
+				return nil, fmt.Errorf("bitbucket workspace not found: %s", org)
+			}
+			return nil, fmt.Errorf("list bitbucket repos for workspace %s: %w", org, err)
+		}
+		for _, v := range page.Values {
+			remoteURL := pickBitbucketCloneURL(v.Links.Clone)
+			if remoteURL == "" && strings.Contains(v.FullName, "/") {
+				remoteURL = fmt.Sprintf("git@bitbucket.org:%s.git", strings.TrimSpace(v.FullName))
+			}
+			repo := Repo{
+				RemoteURL: remoteURL,
+				Fork:      v.Parent != nil,
+				Language:  strings.TrimSpace(v.Language),
+				PushedAt:  parseAPITime(v.UpdatedOn),
+			}
+			if v.MainBranch != nil {
+				repo.DefaultBranch = strings.TrimSpace(v.MainBranch.Name)
+			}
+			if err := collector.add(repo); err != nil {
+				return nil, err
+			}
+		}
+		next = strings.TrimSpace(page.Next)
+		if next != "" && !sameOrigin(cfg.baseURL, next) {
+			return nil, fmt.Errorf("list bitbucket repos for workspace %s: next page URL %q does not match %s", org, next, cfg.baseURL)
+		}
+	}
+	return collector.repos(), nil
+}
+
+func (cfg bitbucketCloudEnvConfig) authorize(req *http.Request) {
+	if cfg.token != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.token)
+		return
+	}
+	req.SetBasicAuth(cfg.username, cfg.appPassword)
+}
+
+func loadBitbucketCloudEnvConfig() (bitbucketCloudEnvConfig, error) {
+	cfg := bitbucketCloudEnvConfig{
+		username:    strings.TrimSpace(os.Getenv(envBitbucketUsername)),
+		appPassword: strings.TrimSpace(os.Getenv(envBitbucketAppPassword)),
+		token:       strings.TrimSpace(os.Getenv(envBitbucketToken)),
+	}
+	if cfg.token == "" {
+		missing := make([]string, 0, 2)
+		if cfg.username == "" {
+			missing = append(missing, envBitbucketUsername)
+		}
+		if cfg.appPassword == "" {
+			missing = append(missing, envBitbucketAppPassword)
+		}
+		if len(missing) > 0 {
+			return bitbucketCloudEnvConfig{}, fmt.Errorf("missing bitbucket env vars: %s (or set %s)", strings.Join(missing, ", "), envBitbucketToken)
+		}
+	}
+	baseURLRaw := strings.TrimSpace(os.Getenv(envBitbucketBaseURL))
+	if baseURLRaw == "" {
+		baseURLRaw = defaultBitbucketBaseURL
+	}
+	baseURL, err := httpapi.ParseBaseURL(envBitbucketBaseURL, baseURLRaw)
+	if err != nil {
+		return bitbucketCloudEnvConfig{}, err
+	}
+	cfg.baseURL = baseURL
+	return cfg, nil
+}
+
+// BitbucketServerProvider discovers repositories of a Bitbucket Server/Data Center project via the REST API 1.0.
+type BitbucketServerProvider struct {
+	httpClient *http.Client
+}
+
+type bitbucketServerEnvConfig struct {
+	baseURL string
+	token   string
+}
+
+func NewBitbucketServerProvider(httpClient *http.Client) *BitbucketServerProvider {
+	return &BitbucketServerProvider{httpClient: httpapi.NewDefaultClient(httpClient)}
+}
+
+func (p *BitbucketServerProvider) Name() string {
+	return "bitbucket-server"
+}
+
+// CheckAuth validates local configuration only; Bitbucket Server has no cheap whoami endpoint,
+// so credential errors surface from the listing call.
+func (p *BitbucketServerProvider) CheckAuth(context.Context) error {
+	_, err := loadBitbucketServerEnvConfig()
+	return err
+}
+
+// ListOrgRepos lists repositories in the project (org is the project key, or ~user for personal repos).
+func (p *BitbucketServerProvider) ListOrgRepos(ctx context.Context, org string) ([]Repo, error) {
+	org = strings.TrimSpace(org)
+	if org == "" {
+		return nil, fmt.Errorf("org is required")
+	}
+	cfg, err := loadBitbucketServerEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	collector := newRepoCollector()
+	start := 0
+	for {
+		endpoint := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos?limit=%d&start=%d", cfg.baseURL, url.PathEscape(org), bitbucketPageLen, start)
+		var page struct {
+			Values []struct {
+				Slug     string `json:"slug"`
+				Archived bool   `json:"archived"`
+				Origin   *struct {
+					Slug string `json:"slug"`
+				} `json:"origin"`
+				Links struct {
+					Clone []bitbucketCloneLink `json:"clone"`
+				} `json:"links"`
+			} `json:"values"`
+			IsLastPage    bool `json:"isLastPage"`
+			NextPageStart *int `json:"nextPageStart"`
+		}
+		if _, err := getJSON(ctx, p.httpClient, "bitbucket-server", endpoint, cfg.authorize, &page); err != nil {
+			if errors.Is(err, errHTTPNotFound) {
+				return nil, fmt.Errorf("bitbucket-server project not found: %s", org)
+			}
+			return nil, fmt.Errorf("list bitbucket-server repos for project %s: %w", org, err)
+		}
+		for _, v := range page.Values {
+			if err := collector.add(Repo{
+				RemoteURL: pickBitbucketCloneURL(v.Links.Clone),
+				Archived:  v.Archived,
+				Fork:      v.Origin != nil,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		if page.IsLastPage || page.NextPageStart == nil || *page.NextPageStart <= start {
+			break
+		}
+		start = *page.NextPageStart
+	}
+	return collector.repos(), nil
+}
+
+func (cfg bitbucketServerEnvConfig) authorize(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+cfg.token)
+}
+
+func loadBitbucketServerEnvConfig() (bitbucketServerEnvConfig, error) {
+	baseURLRaw := strings.TrimSpace(os.Getenv(envBitbucketServerURL))
+	token := strings.TrimSpace(os.Getenv(envBitbucketServerToken))
+	missing := make([]string, 0, 2)
+	if baseURLRaw == "" {
+		missing = append(missing, envBitbucketServerURL)
+	}
+	if token == "" {
+		missing = append(missing, envBitbucketServerToken)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return bitbucketServerEnvConfig{}, fmt.Errorf("missing bitbucket-server env vars: %s", strings.Join(missing, ", "))
+	}
+	baseURL, err := httpapi.ParseBaseURL(envBitbucketServerURL, baseURLRaw)
+	if err != nil {
+		return bitbucketServerEnvConfig{}, err
+	}
+	return bitbucketServerEnvConfig{baseURL: baseURL, token: token}, nil
+}
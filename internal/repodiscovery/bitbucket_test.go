@@ -0,0 +1,130 @@
+package repodiscovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBitbucketCloudProvider_ListOrgRepos_FollowsNext(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "dev" || pass != "app-pass" {
+			t.Fatalf("basic auth = %q/%q (ok=%t)", user, pass, ok)
+		}
+		if r.URL.Path != "/2.0/repositories/acme" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = fmt.Fprint(w, `{"values":[{"full_name":"acme/api","links":{"clone":[{"name":"https","href":"https://dev@bitbucket.org/acme/api.git"}]}}]}`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"values":[{"full_name":"acme/web","links":{"clone":[{"name":"https","href":"https://dev@bitbucket.org/acme/web.git"},{"name":"ssh","href":"git@bitbucket.org:acme/web.git"}]}}],"next":%q}`, server.URL+"/2.0/repositories/acme?pagelen=100&page=2")
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(envBitbucketBaseURL, server.URL)
+	t.Setenv(envBitbucketUsername, "dev")
+	t.Setenv(envBitbucketAppPassword, "app-pass")
+	t.Setenv(envBitbucketToken, "")
+
+	repos, err := NewBitbucketCloudProvider(server.Client()).ListOrgRepos(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("repos len = %d, want 2: %+v", len(repos), repos)
+	}
+	if repos[0].RepoUID != "bitbucket.org/acme/api" || repos[0].RemoteURL != "https://dev@bitbucket.org/acme/api.git" {
+		t.Fatalf("repos[0] = %+v", repos[0])
+	}
+	if repos[1].RepoKey != "acme/web" || repos[1].RemoteURL != "git@bitbucket.org:acme/web.git" {
+		t.Fatalf("repos[1] = %+v (ssh clone link should be preferred)", repos[1])
+	}
+}
+
+func TestBitbucketCloudProvider_ListOrgRepos_RejectsForeignNext(t *testing.T) {
+	foreignHits := 0
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		foreignHits++
+		_, _ = fmt.Fprint(w, `{"values":[]}`)
+	}))
+	t.Cleanup(foreign.Close)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"values":[],"next":%q}`, foreign.URL+"/2.0/repositories/acme?page=2")
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(envBitbucketBaseURL, server.URL)
+	t.Setenv(envBitbucketUsername, "dev")
+	t.Setenv(envBitbucketAppPassword, "app-pass")
+	t.Setenv(envBitbucketToken, "")
+
+	_, err := NewBitbucketCloudProvider(server.Client()).ListOrgRepos(context.Background(), "acme")
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("ListOrgRepos() error = %v, want next URL mismatch", err)
+	}
+	if foreignHits != 0 {
+		t.Fatalf("foreign next URL was requested %d times", foreignHits)
+	}
+}
+
+func TestBitbucketCloudProvider_CheckAuthRequiresCredentials(t *testing.T) {
+	t.Setenv(envBitbucketUsername, "")
+	t.Setenv(envBitbucketAppPassword, "")
+	t.Setenv(envBitbucketToken, "")
+	err := NewBitbucketCloudProvider(nil).CheckAuth(context.Background())
+	if err == nil || !strings.Contains(err.Error(), envBitbucketAppPassword) {
+		t.Fatalf("CheckAuth() error = %v, want missing env vars", err)
+	}
+}
+
+func TestBitbucketServerProvider_ListOrgRepos_Paginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer server-token" {
+			t.Fatalf("Authorization = %q", got)
+		}
+		if r.URL.Path != "/rest/api/1.0/projects/PLAT/repos" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("start") {
+		case "0":
+			_, _ = fmt.Fprint(w, `{"values":[{"slug":"core","links":{"clone":[{"name":"http","href":"https://bitbucket.example.com/scm/plat/core.git"},{"name":"ssh","href":"ssh://git@bitbucket.example.com:7999/plat/core.git"}]}}],"isLastPage":false,"nextPageStart":1}`)
+		case "1":
+			_, _ = fmt.Fprint(w, `{"values":[{"slug":"billing","links":{"clone":[{"name":"ssh","href":"ssh://git@bitbucket.example.com:7999/plat/billing.git"}]}}],"isLastPage":true}`)
+		default:
+			t.Fatalf("unexpected start: %q", r.URL.Query().Get("start"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(envBitbucketServerURL, server.URL)
+	t.Setenv(envBitbucketServerToken, "server-token")
+
+	repos, err := NewBitbucketServerProvider(server.Client()).ListOrgRepos(context.Background(), "PLAT")
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("repos len = %d, want 2: %+v", len(repos), repos)
+	}
+	if repos[0].RepoUID != "bitbucket.example.com/plat/billing" || repos[0].RemoteURL != "ssh://git@bitbucket.example.com:7999/plat/billing.git" {
+		t.Fatalf("repos[0] = %+v", repos[0])
+	}
+	if repos[1].RepoKey != "plat/core" {
+		t.Fatalf("repos[1] = %+v", repos[1])
+	}
+}
+
+func TestBitbucketServerProvider_ProjectNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+	t.Setenv(envBitbucketServerURL, server.URL)
+	t.Setenv(envBitbucketServerToken, "server-token")
+
+	_, err := NewBitbucketServerProvider(server.Client()).ListOrgRepos(context.Background(), "NOPE")
+	if err == nil || !strings.Contains(err.Error(), "project not found: NOPE") {
+		t.Fatalf("ListOrgRepos() error = %v, want project not found", err)
+	}
+}
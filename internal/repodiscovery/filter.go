@@ -0,0 +1,75 @@
+package repodiscovery
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Filter narrows discovered repos. Zero-value fields do not filter.
+type Filter struct {
+	// Topics requires every listed topic (case-insensitive).
+	Topics          []string
+	Language        string
+	ExcludeArchived bool
+	ExcludeForks    bool
+	// Match is a glob matched against repo_key (owner/repo) or the repo name.
+	Match string
+}
+
+func (f Filter) Validate() error {
+	if strings.TrimSpace(f.Match) == "" {
+		return nil
+	}
+	if _, err := path.Match(f.Match, ""); err != nil {
+		return fmt.Errorf("invalid --match glob %q: %w", f.Match, err)
+	}
+	return nil
+}
+
+func (f Filter) Matches(r Repo) bool {
+	if f.ExcludeArchived && r.Archived {
+		return false
+	}
+	if f.ExcludeForks && r.Fork {
+		return false
+	}
+	if lang := strings.TrimSpace(f.Language); lang != "" && !strings.EqualFold(lang, strings.TrimSpace(r.Language)) {
+		return false
+	}
+	for _, want := range f.Topics {
+		want = strings.TrimSpace(want)
+		if want == "" {
+			continue
+		}
+		found := false
+		for _, topic := range r.Topics {
+			if strings.EqualFold(want, strings.TrimSpace(topic)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if pattern := strings.TrimSpace(f.Match); pattern != "" {
+		name := r.RepoKey[strings.LastIndex(r.RepoKey, "/")+1:]
+		keyMatched, _ := path.Match(pattern, r.RepoKey)
+		nameMatched, _ := path.Match(pattern, name)
+		if !keyMatched && !nameMatched {
+			return false
+		}
+	}
+	return true
+}
+
+func FilterRepos(repos []Repo, f Filter) []Repo {
+	out := make([]Repo, 0, len(repos))
+	for _, r := range repos {
+		if f.Matches(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package repodiscovery
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterRepos(t *testing.T) {
+	repos := []Repo{
+		{RepoKey: "acme/svc-api", Language: "Go", Topics: []string{"backend", "platform"}},
+		{RepoKey: "acme/svc-web", Language: "TypeScript", Topics: []string{"frontend"}},
+		{RepoKey: "acme/legacy", Language: "Go", Archived: true, Topics: []string{"backend"}},
+		{RepoKey: "acme/svc-fork", Language: "go", Fork: true, Topics: []string{"Backend"}},
+	}
+	keys := func(rs []Repo) string {
+		out := make([]string, 0, len(rs))
+		for _, r := range rs {
+			out = append(out, r.RepoKey)
+		}
+		return strings.Join(out, ",")
+	}
+
+	cases := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{name: "no filter", filter: Filter{}, want: "acme/svc-api,acme/svc-web,acme/legacy,acme/svc-fork"},
+		{name: "language is case-insensitive", filter: Filter{Language: "GO"}, want: "acme/svc-api,acme/legacy,acme/svc-fork"},
+		{name: "topics require all", filter: Filter{Topics: []string{"backend", "platform"}}, want: "acme/svc-api"},
+		{name: "exclude archived and forks", filter: Filter{Topics: []string{"backend"}, ExcludeArchived: true, ExcludeForks: true}, want: "acme/svc-api"},
+		{name: "match by repo name", filter: Filter{Match: "svc-*"}, want: "acme/svc-api,acme/svc-web,acme/svc-fork"},
+		{name: "match by repo key", filter: Filter{Match: "acme/*-web"}, want: "acme/svc-web"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := keys(FilterRepos(repos, tc.filter)); got != tc.want {
+				t.Fatalf("FilterRepos() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilter_ValidateRejectsBadGlob(t *testing.T) {
+	if err := (Filter{Match: "svc-["}).Validate(); err == nil {
+		t.Fatalf("Validate() error = nil, want error")
+	}
+}
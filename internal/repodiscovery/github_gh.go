@@ -4,12 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
-	"slices"
 	"strings"
-
-	"github.com/tasuku43/kra/internal/core/repospec"
 )
 
+// ghRepoListJQ emits one TSV row per repo:
+// full_name, ssh_url, clone_url, archived, fork, topics (comma separated), language, default_branch, pushed_at.
+const ghRepoListJQ = `.[] | [.full_name, .ssh_url, .clone_url, (.archived | tostring), (.fork | tostring), ((.topics // []) | join(",")), (.language // ""), (.default_branch // ""), (.pushed_at // "")] | @tsv`
+
 type ghRunner func(ctx context.Context, args ...string) (string, error)
 
 type GitHubGHProvider struct {
@@ -40,19 +41,19 @@ func (p *GitHubGHProvider) ListOrgRepos(ctx context.Context, org string) ([]Repo
 		return nil, fmt.Errorf("org is required")
 	}
 
-	out, err := p.run(ctx, "api", "--paginate", fmt.Sprintf("/orgs/%s/repos?per_page=100&type=all", org), "--jq", ".[] | [.full_name,.ssh_url,.clone_url] | @tsv")
+	out, err := p.run(ctx, "api", "--paginate", fmt.Sprintf("/orgs/%s/repos?per_page=100&type=all", org), "--jq", ghRepoListJQ)
 	if err != nil {
 		if !isGitHubNotFound(err) {
 			return nil, fmt.Errorf("list github repos for org %s: %w", org, err)
 		}
 		// Compat fallback: allow personal account handles in --org.
-		out, err = p.run(ctx, "api", "--paginate", fmt.Sprintf("/users/%s/repos?per_page=100&type=owner", org), "--jq", ".[] | [.full_name,.ssh_url,.clone_url] | @tsv")
+		out, err = p.run(ctx, "api", "--paginate", fmt.Sprintf("/users/%s/repos?per_page=100&type=owner", org), "--jq", ghRepoListJQ)
 		if err != nil {
 			return nil, fmt.Errorf("list github repos for owner %s: %w", org, err)
 		}
 	}
 
-	byRepoUID := map[string]Repo{}
+	collector := newRepoCollector()
 	for _, line := range strings.Split(out, "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
@@ -82,28 +83,37 @@ func (p *GitHubGHProvider) ListOrgRepos(ctx context.Context, org string) ([]Repo
 		if remoteURL == "" {
 			remoteURL = fmt.Sprintf("git@github.com:%s.git", fullName)
 		}
-
-		spec, err := repospec.Normalize(remoteURL)
-		if err != nil {
-			return nil, fmt.Errorf("normalize discovered repo %q: %w", remoteURL, err)
+		repo := Repo{RemoteURL: remoteURL}
+		field := func(i int) string {
+			if len(parts) > i {
+				return strings.TrimSpace(parts[i])
+			}
+			return ""
 		}
-		repoUID := fmt.Sprintf("%s/%s/%s", spec.Host, spec.Owner, spec.Repo)
-		repoKey := fmt.Sprintf("%s/%s", spec.Owner, spec.Repo)
-		byRepoUID[repoUID] = Repo{
-			RepoUID:   repoUID,
-			RepoKey:   repoKey,
-			RemoteURL: remoteURL,
+		repo.Archived = field(3) == "true"
+		repo.Fork = field(4) == "true"
+		repo.Topics = splitTopics(field(5))
+		repo.Language = field(6)
+		repo.DefaultBranch = field(7)
+		repo.PushedAt = parseAPITime(field(8))
+		if err := collector.add(repo); err != nil {
+			return nil, err
 		}
 	}
+	return collector.repos(), nil
+}
 
-	repos := make([]Repo, 0, len(byRepoUID))
-	for _, r := range byRepoUID {
-		repos = append(repos, r)
+func splitTopics(raw string) []string {
+	out := make([]string, 0, 4)
+	for _, t := range strings.Split(raw, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		return nil
 	}
-	slices.SortFunc(repos, func(a, b Repo) int {
-		return strings.Compare(a.RepoKey, b.RepoKey)
-	})
-	return repos, nil
+	return out
 }
 
 func isGitHubNotFound(err error) bool {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewProvider_DefaultsToGitHub(t *testing.T) {
@@ -19,7 +20,7 @@ func TestNewProvider_DefaultsToGitHub(t *testing.T) {
 }
 
 func TestNewProvider_Unsupported(t *testing.T) {
-	_, err := NewProvider("gitlab")
+	_, err := NewProvider("unknown-provider")
 	if err == nil {
 		t.Fatalf("NewProvider() error = nil, want error")
 	}
@@ -94,3 +95,34 @@ func TestGitHubGHProvider_ListOrgRepos_FallbackToUserEndpointOn404(t *testing.T)
 		t.Fatalf("RepoKey = %q", repos[0].RepoKey)
 	}
 }
+
+func TestGitHubGHProvider_ListOrgRepos_ParsesMetadata(t *testing.T) {
+	p := NewGitHubGHProvider(func(ctx context.Context, args ...string) (string, error) {
+		return strings.Join([]string{
+			"example-org/api\tgit@github.com:example-org/api.git\thttps://github.com/example-org/api.git\tfalse\ttrue\tbackend,go\tGo\tmain\t2026-01-02T03:04:05Z",
+			"example-org/old\tgit@github.com:example-org/old.git\t\ttrue\tfalse\t\t\tmaster\t",
+		}, "\n"), nil
+	})
+
+	repos, err := p.ListOrgRepos(context.Background(), "example-org")
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("len(repos) = %d, want 2", len(repos))
+	}
+	api := repos[0]
+	if api.Archived || !api.Fork || api.Language != "Go" || api.DefaultBranch != "main" {
+		t.Fatalf("api metadata = %+v", api)
+	}
+	if strings.Join(api.Topics, ",") != "backend,go" {
+		t.Fatalf("api topics = %v", api.Topics)
+	}
+	if got := api.PushedAt.UTC().Format(time.RFC3339); got != "2026-01-02T03:04:05Z" {
+		t.Fatalf("api pushed_at = %s", got)
+	}
+	old := repos[1]
+	if !old.Archived || old.Fork || len(old.Topics) != 0 || !old.PushedAt.IsZero() {
+		t.Fatalf("old metadata = %+v", old)
+	}
+}
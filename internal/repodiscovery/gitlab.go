@@ -0,0 +1,162 @@
+package repodiscovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/httpapi"
+)
+
+const (
+	envGitLabBaseURL     = "KRA_GITLAB_BASE_URL"
+	envGitLabToken       = "KRA_GITLAB_TOKEN"
+	defaultGitLabBaseURL = "https://gitlab.com"
+	gitLabPerPage        = 100
+)
+
+// GitLabProvider discovers projects of a GitLab group (including subgroups) via the REST API v4.
+type GitLabProvider struct {
+	httpClient *http.Client
+}
+
+type gitLabEnvConfig struct {
+	baseURL string
+	token   string
+}
+
+type gitLabProject struct {
+	PathWithNamespace string          `json:"path_with_namespace"`
+	SSHURLToRepo      string          `json:"ssh_url_to_repo"`
+	HTTPURLToRepo     string          `json:"http_url_to_repo"`
+	Archived          bool            `json:"archived"`
+	ForkedFromProject json.RawMessage `json:"forked_from_project"`
+	Topics            []string        `json:"topics"`
+	TagList           []string        `json:"tag_list"`
+	DefaultBranch     string          `json:"default_branch"`
+	LastActivityAt    string          `json:"last_activity_at"`
+}
+
+func NewGitLabProvider(httpClient *http.Client) *GitLabProvider {
+	return &GitLabProvider{httpClient: httpapi.NewDefaultClient(httpClient)}
+}
+
+func (p *GitLabProvider) Name() string {
+	return "gitlab"
+}
+
+func (p *GitLabProvider) CheckAuth(ctx context.Context) error {
+	cfg, err := loadGitLabEnvConfig()
+	if err != nil {
+		return err
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	if _, err := getJSON(ctx, p.httpClient, "gitlab", cfg.baseURL+"/api/v4/user", cfg.authorize, &user); err != nil {
+		if errors.Is(err, errHTTPNotFound) {
+			return fmt.Errorf("gitlab api not found at %s", cfg.baseURL)
+		}
+		return fmt.Errorf("gitlab authentication required (set %s): %w", envGitLabToken, err)
+	}
+	return nil
+}
+
+// ListOrgRepos lists projects in the group (org is the full group path, e.g. "group/subgroup").
+// Projects of nested subgroups are included. Falls back to user-owned projects when no group matches.
+func (p *GitLabProvider) ListOrgRepos(ctx context.Context, org string) ([]Repo, error) {
+	org = strings.Trim(strings.TrimSpace(org), "/")
+	if org == "" {
+		return nil, fmt.Errorf("org is required")
+	}
+	cfg, err := loadGitLabEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := p.listProjects(ctx, cfg, fmt.Sprintf("%s/api/v4/groups/%s/projects?include_subgroups=true&per_page=%d", cfg.baseURL, url.PathEscape(org), gitLabPerPage))
+	if err != nil {
+		if !errors.Is(err, errHTTPNotFound) {
+			return nil, fmt.Errorf("list gitlab projects for group %s: %w", org, err)
+		}
+		// Compat fallback: allow personal namespaces in --org.
+		projects, err = p.listProjects(ctx, cfg, fmt.Sprintf("%s/api/v4/users/%s/projects?per_page=%d", cfg.baseURL, url.PathEscape(org), gitLabPerPage))
+		if err != nil {
+			if errors.Is(err, errHTTPNotFound) {
+				return nil, fmt.Errorf("gitlab group or user not found: %s", org)
+			}
+			return nil, fmt.Errorf("list gitlab projects for user %s: %w", org, err)
+		}
+	}
+
+	collector := newRepoCollector()
+	for _, project := range projects {
+		remoteURL := strings.TrimSpace(project.SSHURLToRepo)
+		if remoteURL == "" {
+			remoteURL = strings.TrimSpace(project.HTTPURLToRepo)
+		}
+		topics := project.Topics
+		if len(topics) == 0 {
+			// tag_list is the pre-14.0 name of topics.
+			topics = project.TagList
+		}
+		forked := strings.TrimSpace(string(project.ForkedFromProject))
+		// The projects list API does not expose languages; Language stays empty.
+		if err := collector.add(Repo{
+			RemoteURL:     remoteURL,
+			Archived:      project.Archived,
+			Fork:          forked != "" && forked != "null",
+			Topics:        topics,
+			DefaultBranch: strings.TrimSpace(project.DefaultBranch),
+			PushedAt:      parseAPITime(project.LastActivityAt),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return collector.repos(), nil
+}
+
+// listProjects follows X-Next-Page pagination until the last page.
+func (p *GitLabProvider) listProjects(ctx context.Context, cfg gitLabEnvConfig, endpoint string) ([]gitLabProject, error) {
+	out := make([]gitLabProject, 0, gitLabPerPage)
+	page := "1"
+	for page != "" {
+		var batch []gitLabProject
+		header, err := getJSON(ctx, p.httpClient, "gitlab", endpoint+"&page="+url.QueryEscape(page), cfg.authorize, &batch)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, batch...)
+		next := strings.TrimSpace(header.Get("X-Next-Page"))
+		if next == page {
+			break
+		}
+		page = next
+	}
+	return out, nil
+}
+
+func (cfg gitLabEnvConfig) authorize(req *http.Request) {
+	req.Header.Set("PRIVATE-TOKEN", cfg.token)
+}
+
+func loadGitLabEnvConfig() (gitLabEnvConfig, error) {
+	token := strings.TrimSpace(os.Getenv(envGitLabToken))
+	if token == "" {
+		return gitLabEnvConfig{}, fmt.Errorf("missing gitlab env vars: %s", envGitLabToken)
+	}
+	baseURLRaw := strings.TrimSpace(os.Getenv(envGitLabBaseURL))
+	if baseURLRaw == "" {
+		baseURLRaw = defaultGitLabBaseURL
+	}
+	baseURL, err := httpapi.ParseBaseURL(envGitLabBaseURL, baseURLRaw)
+	if err != nil {
+		return gitLabEnvConfig{}, err
+	}
+	return gitLabEnvConfig{baseURL: baseURL, token: token}, nil
+}
@@ -0,0 +1,96 @@
+package repodiscovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitLabProvider_ListOrgRepos_IncludesSubgroupsAndPaginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "glpat-test" {
+			t.Fatalf("PRIVATE-TOKEN = %q", got)
+		}
+		if r.URL.EscapedPath() != "/api/v4/groups/acme%2Fplatform/projects" {
+			t.Fatalf("unexpected path: %s", r.URL.EscapedPath())
+		}
+		if r.URL.Query().Get("include_subgroups") != "true" {
+			t.Fatalf("include_subgroups missing: %s", r.URL.RawQuery)
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("X-Next-Page", "2")
+			_, _ = fmt.Fprint(w, `[{"path_with_namespace":"acme/platform/svc-b","ssh_url_to_repo":"git@gitlab.example.com:acme/platform/svc-b.git","http_url_to_repo":"https://gitlab.example.com/acme/platform/svc-b.git"}]`)
+		case "2":
+			w.Header().Set("X-Next-Page", "")
+			_, _ = fmt.Fprint(w, `[{"path_with_namespace":"acme/platform/infra/svc-a","ssh_url_to_repo":"","http_url_to_repo":"https://gitlab.example.com/acme/platform/infra/svc-a.git"}]`)
+		default:
+			t.Fatalf("unexpected page: %q", r.URL.Query().Get("page"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(envGitLabBaseURL, server.URL)
+	t.Setenv(envGitLabToken, "glpat-test")
+
+	repos, err := NewGitLabProvider(server.Client()).ListOrgRepos(context.Background(), "acme/platform")
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("repos len = %d, want 2: %+v", len(repos), repos)
+	}
+	if repos[0].RepoKey != "acme/platform/infra/svc-a" || repos[0].RepoUID != "gitlab.example.com/acme/platform/infra/svc-a" {
+		t.Fatalf("repos[0] = %+v", repos[0])
+	}
+	if repos[0].RemoteURL != "https://gitlab.example.com/acme/platform/infra/svc-a.git" {
+		t.Fatalf("repos[0] remote = %q, want http fallback", repos[0].RemoteURL)
+	}
+	if repos[1].RepoKey != "acme/platform/svc-b" || repos[1].RemoteURL != "git@gitlab.example.com:acme/platform/svc-b.git" {
+		t.Fatalf("repos[1] = %+v", repos[1])
+	}
+}
+
+func TestGitLabProvider_ListOrgRepos_FallsBackToUserProjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/groups/alice/projects":
+			http.NotFound(w, r)
+		case "/api/v4/users/alice/projects":
+			_, _ = fmt.Fprint(w, `[{"ssh_url_to_repo":"git@gitlab.com:alice/dotfiles.git"}]`)
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(envGitLabBaseURL, server.URL)
+	t.Setenv(envGitLabToken, "glpat-test")
+
+	repos, err := NewGitLabProvider(server.Client()).ListOrgRepos(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error: %v", err)
+	}
+	if len(repos) != 1 || repos[0].RepoUID != "gitlab.com/alice/dotfiles" {
+		t.Fatalf("repos = %+v", repos)
+	}
+}
+
+func TestGitLabProvider_CheckAuth(t *testing.T) {
+	t.Setenv(envGitLabToken, "")
+	p := NewGitLabProvider(nil)
+	if err := p.CheckAuth(context.Background()); err == nil || !strings.Contains(err.Error(), envGitLabToken) {
+		t.Fatalf("CheckAuth() without token error = %v, want missing %s", err, envGitLabToken)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(envGitLabBaseURL, server.URL)
+	t.Setenv(envGitLabToken, "bad")
+	if err := NewGitLabProvider(server.Client()).CheckAuth(context.Background()); err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Fatalf("CheckAuth() error = %v, want authentication failed", err)
+	}
+}
@@ -0,0 +1,110 @@
+package repodiscovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
+)
+
+var errHTTPNotFound = errors.New("not found")
+
+// parseAPITime parses an RFC 3339 timestamp; invalid or empty values yield the zero time.
+func parseAPITime(raw string) time.Time {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// sameOrigin reports whether endpoint has the same scheme and host as baseURL.
+// Server-provided pagination URLs are only followed when it does, so credentials never leave the API host.
+func sameOrigin(baseURL string, endpoint string) bool {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(base.Scheme, u.Scheme) && strings.EqualFold(base.Host, u.Host)
+}
+
+// getJSON performs an authenticated GET and decodes the JSON body into out.
+// 404 is reported as errHTTPNotFound so callers can apply fallbacks.
+func getJSON(ctx context.Context, client *http.Client, provider string, endpoint string, authorize func(*http.Request), out any) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build %s request: %w", provider, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if authorize != nil {
+		authorize(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", provider, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// continue
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%s authentication failed: status=%d", provider, resp.StatusCode)
+	case http.StatusNotFound:
+		return nil, errHTTPNotFound
+	default:
+		return nil, fmt.Errorf("%s request failed: status=%d", provider, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("decode %s response: %w", provider, err)
+	}
+	return resp.Header, nil
+}
+
+// repoCollector normalizes discovered remotes and dedupes them by repo_uid.
+type repoCollector struct {
+	byRepoUID map[string]Repo
+}
+
+func newRepoCollector() *repoCollector {
+	return &repoCollector{byRepoUID: map[string]Repo{}}
+}
+
+// add normalizes r.RemoteURL and fills RepoUID/RepoKey; metadata fields are kept as-is.
+func (c *repoCollector) add(r Repo) error {
+	r.RemoteURL = strings.TrimSpace(r.RemoteURL)
+	if r.RemoteURL == "" {
+		return nil
+	}
+	spec, err := repospec.Normalize(r.RemoteURL)
+	if err != nil {
+		return fmt.Errorf("normalize discovered repo %q: %w", r.RemoteURL, err)
+	}
+	r.RepoUID = fmt.Sprintf("%s/%s/%s", spec.Host, spec.Owner, spec.Repo)
+	r.RepoKey = fmt.Sprintf("%s/%s", spec.Owner, spec.Repo)
+	c.byRepoUID[r.RepoUID] = r
+	return nil
+}
+
+func (c *repoCollector) repos() []Repo {
+	repos := make([]Repo, 0, len(c.byRepoUID))
+	for _, r := range c.byRepoUID {
+		repos = append(repos, r)
+	}
+	slices.SortFunc(repos, func(a, b Repo) int {
+		return strings.Compare(a.RepoKey, b.RepoKey)
+	})
+	return repos
+}
@@ -0,0 +1,188 @@
+package repodiscovery
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
+)
+
+// LocalProvider discovers git repositories (bare or non-bare) under a directory tree,
+// e.g. an NFS share or the storage directory of a self-hosted mirror. It never touches the network.
+type LocalProvider struct{}
+
+func NewLocalProvider() *LocalProvider {
+	return &LocalProvider{}
+}
+
+func (p *LocalProvider) Name() string {
+	return "local"
+}
+
+func (p *LocalProvider) CheckAuth(context.Context) error {
+	return nil
+}
+
+// ListOrgRepos walks org (a directory path) and returns every git repository found.
+//
+// Identity is derived from the repository's origin remote when it is a normalizable non-local URL;
+// otherwise the repository path itself is used as a file:// spec, so the last three path segments
+// must follow the <host>/<owner>/<repo>[.git] convention.
+func (p *LocalProvider) ListOrgRepos(ctx context.Context, org string) ([]Repo, error) {
+	org = strings.TrimSpace(org)
+	if org == "" {
+		return nil, fmt.Errorf("org is required")
+	}
+	if org == "~" || strings.HasPrefix(org, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("resolve home dir: %w", err)
+		}
+		org = filepath.Join(home, strings.TrimPrefix(org, "~"))
+	}
+	root, err := filepath.Abs(org)
+	if err != nil {
+		return nil, fmt.Errorf("resolve directory %s: %w", org, err)
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("stat directory %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", root)
+	}
+
+	collector := newRepoCollector()
+	var skipped []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if path == root {
+				return walkErr
+			}
+			// Unreadable subtrees (e.g. permission denied on a share) are skipped.
+			return filepath.SkipDir
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		gitDir, ok := localGitDir(path)
+		if !ok {
+			if path != root && d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		repo, ok := localRepoFromPath(path, gitDir)
+		if !ok {
+			skipped = append(skipped, path)
+			return filepath.SkipDir
+		}
+		if err := collector.add(repo); err != nil {
+			skipped = append(skipped, path)
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk directory %s: %w", root, err)
+	}
+	repos := collector.repos()
+	if len(repos) == 0 && len(skipped) > 0 {
+		return nil, fmt.Errorf("found %d git repo(s) under %s but none matched <host>/<owner>/<repo> (first: %s)", len(skipped), root, skipped[0])
+	}
+	return repos, nil
+}
+
+// localGitDir reports the git directory of path: path itself for bare repos,
+// or path/.git for non-bare repos. Worktrees/submodules with a .git file are ignored.
+func localGitDir(path string) (string, bool) {
+	if st, err := os.Stat(filepath.Join(path, ".git")); err == nil && st.IsDir() && isLocalGitDir(filepath.Join(path, ".git")) {
+		return filepath.Join(path, ".git"), true
+	}
+	if filepath.Base(path) != ".git" && isLocalGitDir(path) {
+		return path, true
+	}
+	return "", false
+}
+
+func isLocalGitDir(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func localRepoFromPath(path string, gitDir string) (Repo, bool) {
+	repo := Repo{DefaultBranch: readLocalHeadBranch(gitDir)}
+	if origin := readLocalOriginURL(filepath.Join(gitDir, "config")); origin != "" && !isLocalRemoteURL(origin) {
+		if _, err := repospec.Normalize(origin); err == nil {
+			// Identity comes from origin; objects are copied from the local path, not the network.
+			repo.RemoteURL = origin
+			repo.CloneURL = "file://" + filepath.ToSlash(path)
+			return repo, true
+		}
+	}
+	spec := "file://" + filepath.ToSlash(path)
+	if _, err := repospec.Normalize(spec); err != nil {
+		return Repo{}, false
+	}
+	repo.RemoteURL = spec
+	return repo, true
+}
+
+func isLocalRemoteURL(remoteURL string) bool {
+	return strings.HasPrefix(remoteURL, "file://") || strings.HasPrefix(remoteURL, "/") || strings.HasPrefix(remoteURL, ".")
+}
+
+func readLocalHeadBranch(gitDir string) string {
+	b, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(b))
+	if !strings.HasPrefix(head, "ref: refs/heads/") {
+		return ""
+	}
+	return strings.TrimPrefix(head, "ref: refs/heads/")
+}
+
+// readLocalOriginURL reads remote.origin.url from a git config file without invoking git.
+func readLocalOriginURL(configPath string) string {
+	f, err := os.Open(configPath)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			section := strings.Join(strings.Fields(strings.Trim(line, "[]")), " ")
+			inOrigin = section == `remote "origin"`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "url") {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package repodiscovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFakeGitDir(t *testing.T, gitDir string, head string, config string) {
+	t.Helper()
+	for _, dir := range []string{"objects", "refs/heads"} {
+		if err := os.MkdirAll(filepath.Join(gitDir, dir), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte(head+"\n"), 0o644); err != nil {
+		t.Fatalf("write HEAD: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "config"), []byte(config), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func TestLocalProvider_ListOrgRepos(t *testing.T) {
+	root := t.TempDir()
+	// Bare repo following <host>/<owner>/<repo>.git without origin.
+	writeFakeGitDir(t, filepath.Join(root, "git.example.com", "team", "api.git"), "ref: refs/heads/main", "[core]\n\tbare = true\n")
+	// Non-bare clone whose origin points to an upstream host.
+	writeFakeGitDir(t, filepath.Join(root, "clones", "web", ".git"), "ref: refs/heads/develop",
+		"[core]\n\tbare = false\n[remote \"upstream\"]\n\turl = git@github.com:other/web.git\n[remote \"origin\"]\n\turl = git@github.com:acme/web.git\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n")
+	// Nested directories inside a repo must not be reported separately.
+	writeFakeGitDir(t, filepath.Join(root, "git.example.com", "team", "api.git", "nested.git"), "ref: refs/heads/main", "")
+	// Plain directories are ignored.
+	if err := os.MkdirAll(filepath.Join(root, "docs", "notes"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	repos, err := NewLocalProvider().ListOrgRepos(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("repos len = %d, want 2: %+v", len(repos), repos)
+	}
+	if repos[0].RepoUID != "github.com/acme/web" || repos[0].RemoteURL != "git@github.com:acme/web.git" || repos[0].DefaultBranch != "develop" {
+		t.Fatalf("repos[0] = %+v", repos[0])
+	}
+	if want := "file://" + filepath.ToSlash(filepath.Join(root, "clones", "web")); repos[0].CloneURL != want {
+		t.Fatalf("repos[0].CloneURL = %q, want %q (clone from the local checkout)", repos[0].CloneURL, want)
+	}
+	wantRemote := "file://" + filepath.ToSlash(filepath.Join(root, "git.example.com", "team", "api.git"))
+	if repos[1].RepoUID != "git.example.com/team/api" || repos[1].RemoteURL != wantRemote || repos[1].CloneURL != "" || repos[1].DefaultBranch != "main" {
+		t.Fatalf("repos[1] = %+v", repos[1])
+	}
+}
+
+func TestLocalProvider_ListOrgRepos_RejectsMissingDirectory(t *testing.T) {
+	_, err := NewLocalProvider().ListOrgRepos(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	if err == nil || !strings.Contains(err.Error(), "stat directory") {
+		t.Fatalf("ListOrgRepos() error = %v, want stat error", err)
+	}
+}
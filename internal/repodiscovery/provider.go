@@ -6,12 +6,25 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
+// Repo is a discovered repository. Metadata fields are best-effort: providers
+// leave them zero when the API does not expose them.
 type Repo struct {
 	RepoUID   string
 	RepoKey   string
 	RemoteURL string
+	// CloneURL is where objects are fetched from when it differs from RemoteURL
+	// (e.g. a local checkout whose origin is a network URL). Empty means RemoteURL.
+	CloneURL string
+
+	Archived      bool
+	Fork          bool
+	Topics        []string
+	Language      string
+	DefaultBranch string
+	PushedAt      time.Time
 }
 
 type Provider interface {
@@ -25,7 +38,11 @@ type ProviderFactory func() Provider
 var (
 	providerRegistryMu sync.RWMutex
 	providerRegistry   = map[string]ProviderFactory{
-		"github": func() Provider { return NewGitHubGHProvider(nil) },
+		"github":           func() Provider { return NewGitHubGHProvider(nil) },
+		"gitlab":           func() Provider { return NewGitLabProvider(nil) },
+		"bitbucket":        func() Provider { return NewBitbucketCloudProvider(nil) },
+		"bitbucket-server": func() Provider { return NewBitbucketServerProvider(nil) },
+		"local":            func() Provider { return NewLocalProvider() },
 	}
 )
 
@@ -0,0 +1,246 @@
+// Package repomanifest reads and writes the declarative repo pool manifest (.kra/repos.yaml).
+package repomanifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/tasuku43/kra/internal/core/repospec"
+)
+
+const (
+	CurrentVersion = 1
+	// RelPath is the root-relative location of the manifest.
+	RelPath = ".kra/repos.yaml"
+)
+
+type Manifest struct {
+	Version int     `yaml:"version"`
+	Repos   []Entry `yaml:"repos"`
+}
+
+// Entry declares one repo. Spec accepts the same formats as `kra repo add`.
+type Entry struct {
+	Spec    string   `yaml:"spec"`
+	Alias   string   `yaml:"alias,omitempty"`
+	BaseRef string   `yaml:"base_ref,omitempty"`
+	Groups  []string `yaml:"groups,omitempty"`
+	// Filter is the partial clone filter used when the bare repo is first cloned (e.g. blob:none).
+	Filter string `yaml:"filter,omitempty"`
+	// Submodules and LFS make `ws add-repo` initialize submodules recursively / pull LFS objects.
+	Submodules bool `yaml:"submodules,omitempty"`
+	LFS        bool `yaml:"lfs,omitempty"`
+}
+
+// Resolved is an Entry with its normalized identity.
+type Resolved struct {
+	Entry
+	RepoUID string
+	RepoKey string
+}
+
+func Path(root string) string {
+	return filepath.Join(root, filepath.FromSlash(RelPath))
+}
+
+// Load reads and validates the manifest at path.
+func Load(path string) (Manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return Parse(b)
+}
+
+// LoadOptional is Load that treats a missing file as an empty manifest.
+func LoadOptional(path string) (Manifest, bool, error) {
+	m, err := Load(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Manifest{Version: CurrentVersion}, false, nil
+		}
+		return Manifest{}, false, err
+	}
+	return m, true, nil
+}
+
+func Parse(b []byte) (Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Manifest{Version: CurrentVersion}, nil
+		}
+		return Manifest{}, fmt.Errorf("parse repo manifest: %w", err)
+	}
+	if m.Version == 0 {
+		m.Version = CurrentVersion
+	}
+	m.normalize()
+	if _, err := m.Resolve(); err != nil {
+		return Manifest{}, err
+	}
+	return m, nil
+}
+
+func (m *Manifest) normalize() {
+	for i := range m.Repos {
+		e := &m.Repos[i]
+		e.Spec = strings.TrimSpace(e.Spec)
+		e.Alias = strings.TrimSpace(e.Alias)
+		// base_ref accepts "main" or "origin/main", like the ws add-repo prompt.
+		if ref := strings.TrimPrefix(strings.TrimSpace(e.BaseRef), "/"); ref != "" && !strings.HasPrefix(ref, "origin/") {
+			e.BaseRef = "origin/" + ref
+		} else {
+			e.BaseRef = ref
+		}
+		e.Filter = strings.TrimSpace(e.Filter)
+		groups := make([]string, 0, len(e.Groups))
+		for _, g := range e.Groups {
+			if g = strings.TrimSpace(g); g != "" && !slices.Contains(groups, g) {
+				groups = append(groups, g)
+			}
+		}
+		if len(groups) == 0 {
+			groups = nil
+		}
+		e.Groups = groups
+	}
+}
+
+// Resolve validates every entry and returns them with normalized identities.
+// Duplicate repos, duplicate aliases and invalid specs/aliases/base refs are errors.
+func (m Manifest) Resolve() ([]Resolved, error) {
+	if m.Version != CurrentVersion {
+		return nil, fmt.Errorf("unsupported repo manifest version: %d (supported: %d)", m.Version, CurrentVersion)
+	}
+	out := make([]Resolved, 0, len(m.Repos))
+	seenUID := map[string]bool{}
+	seenAlias := map[string]string{}
+	for i, e := range m.Repos {
+		if e.Spec == "" {
+			return nil, fmt.Errorf("repos[%d].spec is required", i)
+		}
+		spec, err := repospec.Normalize(e.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("repos[%d].spec: %w", i, err)
+		}
+		r := Resolved{
+			Entry:   e,
+			RepoUID: spec.RepoKey,
+			RepoKey: fmt.Sprintf("%s/%s", spec.Owner, spec.Repo),
+		}
+		if seenUID[r.RepoUID] {
+			return nil, fmt.Errorf("repos[%d]: duplicate repo %s", i, r.RepoUID)
+		}
+		seenUID[r.RepoUID] = true
+		if e.Alias != "" {
+			if strings.ContainsAny(e.Alias, `/\`) || e.Alias == "." || e.Alias == ".." || strings.HasPrefix(e.Alias, ".") {
+				return nil, fmt.Errorf("repos[%d].alias is invalid: %q", i, e.Alias)
+			}
+			if prev, ok := seenAlias[e.Alias]; ok {
+				return nil, fmt.Errorf("repos[%d].alias %q is already used by %s", i, e.Alias, prev)
+			}
+			seenAlias[e.Alias] = r.RepoKey
+		}
+		if e.BaseRef == "origin/" {
+			return nil, fmt.Errorf("repos[%d].base_ref is invalid: %q", i, e.BaseRef)
+		}
+		if err := repospec.ValidateCloneFilter(e.Filter); err != nil {
+			return nil, fmt.Errorf("repos[%d].filter: %w", i, err)
+		}
+		for _, g := range e.Groups {
+			if strings.ContainsAny(g, " \t,") {
+				return nil, fmt.Errorf("repos[%d].groups contains invalid name: %q", i, g)
+			}
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// Lookup returns the entry for repoUID (host/owner/repo).
+func (m Manifest) Lookup(repoUID string) (Entry, bool) {
+	resolved, err := m.Resolve()
+	if err != nil {
+		return Entry{}, false
+	}
+	for _, r := range resolved {
+		if r.RepoUID == repoUID {
+			return r.Entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Group is a named set of repos declared through the entries' groups field.
+type Group struct {
+	Name  string
+	Repos []Resolved
+}
+
+// Groups returns the declared groups sorted by name. Members keep manifest order.
+func (m Manifest) Groups() ([]Group, error) {
+	resolved, err := m.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]*Group{}
+	for _, r := range resolved {
+		for _, g := range r.Groups {
+			if byName[g] == nil {
+				byName[g] = &Group{Name: g}
+			}
+			byName[g].Repos = append(byName[g].Repos, r)
+		}
+	}
+	out := make([]Group, 0, len(byName))
+	for _, g := range byName {
+		out = append(out, *g)
+	}
+	slices.SortFunc(out, func(a, b Group) int { return strings.Compare(a.Name, b.Name) })
+	return out, nil
+}
+
+// FindGroup returns the group named name.
+func (m Manifest) FindGroup(name string) (Group, bool, error) {
+	groups, err := m.Groups()
+	if err != nil {
+		return Group{}, false, err
+	}
+	for _, g := range groups {
+		if g.Name == name {
+			return g, true, nil
+		}
+	}
+	return Group{}, false, nil
+}
+
+func Render(m Manifest) ([]byte, error) {
+	if m.Version == 0 {
+		m.Version = CurrentVersion
+	}
+	if m.Repos == nil {
+		m.Repos = []Entry{}
+	}
+	var buf bytes.Buffer
+	buf.WriteString("# kra repo manifest. Reconcile with: kra repo apply [--prune] [--dry-run]\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return nil, fmt.Errorf("render repo manifest: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("render repo manifest: %w", err)
+	}
+	return buf.Bytes(), nil
+}
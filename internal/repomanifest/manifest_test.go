@@ -0,0 +1,121 @@
+package repomanifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse_NormalizesAndResolves(t *testing.T) {
+	m, err := Parse([]byte(`
+version: 1
+repos:
+  - spec: git@github.com:acme/api.git
+    alias: api-svc
+    base_ref: develop
+    groups: [backend, backend, " core "]
+  - spec: https://gitlab.example.com/acme/platform/web.git
+`))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if got := m.Repos[0].BaseRef; got != "origin/develop" {
+		t.Fatalf("base_ref = %q, want origin/develop", got)
+	}
+	if got := m.Repos[0].Groups; !reflect.DeepEqual(got, []string{"backend", "core"}) {
+		t.Fatalf("groups = %v", got)
+	}
+	resolved, err := m.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if resolved[0].RepoUID != "github.com/acme/api" || resolved[0].RepoKey != "acme/api" {
+		t.Fatalf("resolved[0] = %+v", resolved[0])
+	}
+	if resolved[1].RepoUID != "gitlab.example.com/acme/platform/web" {
+		t.Fatalf("resolved[1] = %+v", resolved[1])
+	}
+	if e, ok := m.Lookup("github.com/acme/api"); !ok || e.Alias != "api-svc" {
+		t.Fatalf("Lookup() = %+v, %t", e, ok)
+	}
+}
+
+func TestParse_RejectsInvalidManifests(t *testing.T) {
+	cases := map[string]string{
+		"unknown field":   "repos:\n  - spec: git@github.com:acme/api.git\n    branch: main\n",
+		"missing spec":    "repos:\n  - alias: api\n",
+		"invalid spec":    "repos:\n  - spec: acme/api\n",
+		"duplicate repo":  "repos:\n  - spec: git@github.com:acme/api.git\n  - spec: https://github.com/acme/api\n",
+		"duplicate alias": "repos:\n  - spec: git@github.com:acme/a.git\n    alias: x\n  - spec: git@github.com:acme/b.git\n    alias: x\n",
+		"alias with path": "repos:\n  - spec: git@github.com:acme/a.git\n    alias: ../x\n",
+		"bad version":     "version: 2\nrepos: []\n",
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(body)); err == nil {
+				t.Fatalf("Parse() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	in := Manifest{Version: CurrentVersion, Repos: []Entry{
+		{Spec: "git@github.com:acme/api.git", Alias: "api", BaseRef: "origin/main", Groups: []string{"backend"}},
+		{Spec: "git@github.com:acme/web.git"},
+	}}
+	b, err := Render(in)
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	if strings.Contains(string(b), "alias: \"\"") {
+		t.Fatalf("empty fields should be omitted:\n%s", b)
+	}
+	path := filepath.Join(t.TempDir(), "repos.yaml")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	out, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestLoadOptional_MissingFile(t *testing.T) {
+	m, found, err := LoadOptional(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || found || len(m.Repos) != 0 {
+		t.Fatalf("LoadOptional() = %+v, %t, %v", m, found, err)
+	}
+}
+
+func TestGroups_SortedByNameWithManifestOrder(t *testing.T) {
+	m, err := Parse([]byte(`
+repos:
+  - spec: git@github.com:acme/web.git
+    groups: [frontend, backend]
+  - spec: git@github.com:acme/api.git
+    groups: [backend]
+  - spec: git@github.com:acme/docs.git
+`))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	groups, err := m.Groups()
+	if err != nil {
+		t.Fatalf("Groups() error: %v", err)
+	}
+	if len(groups) != 2 || groups[0].Name != "backend" || groups[1].Name != "frontend" {
+		t.Fatalf("groups = %+v", groups)
+	}
+	if got := []string{groups[0].Repos[0].RepoKey, groups[0].Repos[1].RepoKey}; !reflect.DeepEqual(got, []string{"acme/web", "acme/api"}) {
+		t.Fatalf("backend members = %v", got)
+	}
+	if _, ok, err := m.FindGroup("missing"); ok || err != nil {
+		t.Fatalf("FindGroup(missing) = %t, %v", ok, err)
+	}
+}
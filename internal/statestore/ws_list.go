@@ -14,12 +14,16 @@ type WorkspaceListItem struct {
 }
 
 type WorkspaceRepo struct {
-	RepoUID   string
-	RepoKey   string
-	Alias     string
-	Branch    string
-	BaseRef   string
-	MissingAt sql.NullInt64
+	RepoUID        string
+	RepoKey        string
+	Alias          string
+	Branch         string
+	BaseRef        string
+	Sparse         []string
+	PullRequestURL string
+	Reference      bool
+	Stack          []string
+	MissingAt      sql.NullInt64
 }
 
 func ListWorkspaces(ctx context.Context, db *sql.DB) ([]WorkspaceListItem, error) {